@@ -0,0 +1,110 @@
+// Command reindex rebuilds the embedded search index from Postgres and writes it to a
+// snapshot file that the product service can load at startup.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"product-service/internal/infrastructure/search"
+	"product-service/internal/ports"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+
+	out := flag.String("out", getEnv("SEARCH_INDEX_PATH", "search-index.gob"), "path of the index snapshot to write")
+	batchSize := flag.Int("batch", 500, "number of products loaded per batch")
+	query := flag.String("query", "", "optional query to run against the new index as a smoke test")
+	flag.Parse()
+
+	db, err := gorm.Open(postgres.Open(getDatabaseDSN()), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Warn),
+	})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	ctx := context.Background()
+	index := search.NewEmbeddedSearchRepository()
+
+	start := time.Now()
+	indexed, err := search.Reindex(ctx, db, index, *batchSize)
+	if err != nil {
+		log.Fatalf("Failed to reindex: %v", err)
+	}
+	log.Printf("Indexed %d products in %s", indexed, time.Since(start).Round(time.Millisecond))
+
+	if err := writeSnapshot(index, *out); err != nil {
+		log.Fatalf("Failed to write index snapshot: %v", err)
+	}
+	log.Printf("Wrote index snapshot to %s", *out)
+
+	if *query != "" {
+		result, err := index.Search(ctx, ports.SearchQuery{
+			Query:  *query,
+			Fuzzy:  true,
+			Facets: []string{search.FacetBrand, search.FacetCategory},
+		})
+		if err != nil {
+			log.Fatalf("Smoke test query failed: %v", err)
+		}
+		fmt.Printf("%q: %d results in %dms\n", *query, result.Total, result.Took)
+		for _, product := range result.Products {
+			fmt.Printf("  %s  %s\n", product.SKU(), product.Name())
+		}
+		for _, suggestion := range result.Suggestions {
+			fmt.Printf("  did you mean %q?\n", suggestion)
+		}
+	}
+}
+
+// writeSnapshot writes to a temporary file first so a running service never loads a
+// partially written index
+func writeSnapshot(index *search.EmbeddedSearchRepository, path string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := index.Save(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func getDatabaseDSN() string {
+	host := getEnv("DB_HOST", "localhost")
+	port := getEnv("DB_PORT", "5432")
+	user := getEnv("DB_USER", "postgres")
+	password := getEnv("DB_PASSWORD", "password")
+	dbname := getEnv("DB_NAME", "product_service")
+	sslmode := getEnv("DB_SSLMODE", "disable")
+
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		host, port, user, password, dbname, sslmode)
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
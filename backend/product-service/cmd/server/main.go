@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -22,6 +23,9 @@ import (
 	"product-service/internal/application/services"
 	"product-service/internal/domain/entities"
 	"product-service/internal/infrastructure/database"
+	"product-service/internal/infrastructure/messaging"
+	"product-service/internal/infrastructure/search"
+	"product-service/internal/infrastructure/storage"
 	"product-service/internal/interfaces/http/handlers"
 	"product-service/internal/interfaces/http/middleware"
 )
@@ -71,12 +75,64 @@ func main() {
 	// Initialize repositories
 	productRepo := database.NewGormProductRepository(db)
 	categoryRepo := database.NewGormCategoryRepository(db)
+	categoryAttributeRepo := database.NewGormCategoryAttributeRepository(db)
 	brandRepo := database.NewGormBrandRepository(db)
 	variantRepo := database.NewGormProductVariantRepository(db)
 	imageRepo := database.NewGormProductImageRepository(db)
 	videoRepo := database.NewGormProductVideoRepository(db)
+	searchAnalyticsRepo := database.NewGormSearchAnalyticsRepository(db)
+	catalogRepo := database.NewGormCatalogRepository(db)
+	revisionRepo := database.NewGormProductRevisionRepository(db)
+	priceListRepo := database.NewGormPriceListRepository(db)
+	saleRepo := database.NewGormSaleRepository(db)
+	bundleRepo := database.NewGormBundleRepository(db)
+	relationRepo := database.NewGormProductRelationRepository(db)
+	recommendationRepo := database.NewGormRecommendationRepository(db)
+	statsRepo := database.NewGormProductStatsRepository(db)
+	reviewRepo := database.NewGormReviewRepository(db)
+	slugRedirectRepo := database.NewGormSlugRedirectRepository(db)
+	sitemapRepo := database.NewGormSitemapRepository(db)
+	feedRepo := database.NewGormFeedRepository(db)
+	transactions := database.NewGormTransactionManager(db)
+
+	// Product search runs on Postgres full-text search, or on the embedded index built
+	// by cmd/reindex
+	switch searchBackend := getEnv("SEARCH_BACKEND", "postgres"); searchBackend {
+	case "postgres":
+	case "embedded":
+		index, err := loadSearchIndex(db, getEnv("SEARCH_INDEX_PATH", "./search-index.gob"))
+		if err != nil {
+			log.Fatalf("Failed to load search index: %v", err)
+		}
+		productRepo = search.NewIndexedProductRepository(productRepo, brandRepo, transactions, index)
+	default:
+		log.Fatalf("Unsupported SEARCH_BACKEND %q", searchBackend)
+	}
+
+	publisher := messaging.NewLogPublisher()
+
+	// Initialize file storage; only the local filesystem backend is available
+	if storageType := getEnv("STORAGE_TYPE", "local"); storageType != "local" {
+		log.Fatalf("Unsupported STORAGE_TYPE %q", storageType)
+	}
+	storagePath := getEnv("STORAGE_PATH", "./uploads")
+	storageBaseURL := getEnv("STORAGE_BASE_URL", "/uploads")
+	fileStorage, err := storage.NewLocalStorage(storagePath, storageBaseURL)
+	if err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
 
 	// Initialize services
+	searchAnalyticsService := services.NewSearchAnalyticsService(searchAnalyticsRepo)
+	defer searchAnalyticsService.Close()
+	statsService := services.NewProductStatsService(productRepo, statsRepo)
+	defer statsService.Close()
+
+	attributeSchemaService := services.NewAttributeSchemaService(categoryRepo, categoryAttributeRepo)
+	revisionService := services.NewRevisionService(revisionRepo, transactions)
+	bundleService := services.NewBundleService(productRepo, variantRepo, bundleRepo, revisionService)
+	suggestionCache := services.NewSuggestionCache()
+
 	productService := services.NewProductService(
 		productRepo,
 		categoryRepo,
@@ -84,13 +140,88 @@ func main() {
 		variantRepo,
 		imageRepo,
 		videoRepo,
+		slugRedirectRepo,
+		attributeSchemaService,
+		searchAnalyticsService,
+		revisionService,
+		bundleService,
+		suggestionCache,
+		publisher,
+	)
+
+	publishInterval, err := time.ParseDuration(getEnv("PUBLISH_SCHEDULER_INTERVAL", "1m"))
+	if err != nil {
+		log.Fatalf("Invalid PUBLISH_SCHEDULER_INTERVAL: %v", err)
+	}
+	publishingScheduler := services.NewPublishingScheduler(productRepo, publisher, publishInterval)
+	defer publishingScheduler.Close()
+
+	variantService := services.NewVariantService(productRepo, variantRepo, revisionService)
+	imageService := services.NewImageService(productRepo, variantRepo, imageRepo, fileStorage, revisionService)
+	videoService := services.NewVideoService(productRepo, videoRepo, fileStorage, revisionService)
+	categoryService := services.NewCategoryService(categoryRepo, slugRedirectRepo, suggestionCache, transactions)
+	pricingService := services.NewPricingService(priceListRepo, saleRepo, productRepo, variantRepo, categoryRepo, getEnv("BASE_CURRENCY", "USD"))
+	saleService := services.NewSaleService(saleRepo, productRepo, variantRepo, categoryRepo, brandRepo)
+	brandService := services.NewBrandService(brandRepo, productRepo, slugRedirectRepo, attributeSchemaService, suggestionCache, transactions)
+	recommendationService := services.NewRecommendationService(productRepo, variantRepo, relationRepo, recommendationRepo)
+	reviewService := services.NewReviewService(productRepo, reviewRepo)
+	orderEventService := services.NewOrderEventService(recommendationService, statsService, reviewService)
+	storefrontURL := getEnv("STOREFRONT_URL", "http://localhost:3000")
+	sitemapService := services.NewSitemapService(sitemapRepo, storefrontURL, getEnv("SITEMAP_BASE_URL", storefrontURL))
+	merchantFeedService := services.NewMerchantFeedService(
+		feedRepo,
+		pricingService,
+		storefrontURL,
+		getEnv("MEDIA_BASE_URL", storefrontURL),
+		getEnv("FEED_TITLE", "Products"),
+		getEnv("FEED_WEIGHT_UNIT", "kg"),
+	)
+
+	catalogService := services.NewCatalogService(
+		productRepo,
+		categoryRepo,
+		brandRepo,
+		variantRepo,
+		imageRepo,
+		catalogRepo,
+		attributeSchemaService,
+		revisionService,
+		bundleService,
+		getEnv("IMPORT_PATH", "./uploads/imports"),
 	)
 
 	// Initialize handlers
-	productHandler := handlers.NewProductHandler(productService)
+	productHandler := handlers.NewProductHandler(productService, pricingService)
+	searchAnalyticsHandler := handlers.NewSearchAnalyticsHandler(searchAnalyticsService)
+	statsHandler := handlers.NewProductStatsHandler(statsService)
+	variantHandler := handlers.NewVariantHandler(variantService)
+	imageHandler := handlers.NewImageHandler(imageService)
+	videoHandler := handlers.NewVideoHandler(videoService)
+	bundleHandler := handlers.NewBundleHandler(bundleService)
+	categoryHandler := handlers.NewCategoryHandler(categoryService, attributeSchemaService)
+	brandHandler := handlers.NewBrandHandler(brandService)
+	catalogHandler := handlers.NewCatalogHandler(catalogService)
+	revisionHandler := handlers.NewRevisionHandler(revisionService, productService)
+	pricingHandler := handlers.NewPricingHandler(pricingService)
+	saleHandler := handlers.NewSaleHandler(saleService)
+	recommendationHandler := handlers.NewRecommendationHandler(recommendationService)
+	reviewHandler := handlers.NewReviewHandler(reviewService)
+	eventHandler := handlers.NewEventHandler(orderEventService)
+	sitemapHandler := handlers.NewSitemapHandler(sitemapService)
+	merchantFeedHandler := handlers.NewMerchantFeedHandler(merchantFeedService)
+
+	eventSigningSecret := getEnv("EVENT_SIGNING_SECRET", "")
+	if eventSigningSecret == "" {
+		log.Printf("Warning: EVENT_SIGNING_SECRET is not set, events from other services will be rejected")
+	}
 
 	// Setup router
-	router := setupRouter(productHandler)
+	router := setupRouter(productHandler, variantHandler, imageHandler, videoHandler, bundleHandler, recommendationHandler, statsHandler, reviewHandler, revisionHandler, pricingHandler, saleHandler, categoryHandler, brandHandler, searchAnalyticsHandler, catalogHandler, eventHandler, sitemapHandler, merchantFeedHandler, eventSigningSecret)
+
+	// Serve locally stored uploads, unless they are served from elsewhere
+	if strings.HasPrefix(storageBaseURL, "/") {
+		router.Static(storageBaseURL, storagePath)
+	}
 
 	// Setup server
 	server := &http.Server{
@@ -152,15 +283,82 @@ func initDatabase() (*gorm.DB, error) {
 	return db, nil
 }
 
+// searchIndexes back the full-text matching of product search. The expressions must
+// match the per-field vectors in internal/infrastructure/database/fulltext.go.
+var searchIndexes = []string{
+	"CREATE INDEX IF NOT EXISTS idx_products_name_gin ON products USING gin(to_tsvector('english', name))",
+	"CREATE INDEX IF NOT EXISTS idx_products_short_desc_gin ON products USING gin(to_tsvector('english', short_desc))",
+	"CREATE INDEX IF NOT EXISTS idx_products_description_gin ON products USING gin(to_tsvector('english', description))",
+	"CREATE INDEX IF NOT EXISTS idx_products_tags_gin ON products USING gin(tags)",
+}
+
+// trigramIndexes back the ILIKE and similarity matching of typeahead suggestions
+var trigramIndexes = []string{
+	"CREATE INDEX IF NOT EXISTS idx_products_name_trgm ON products USING gin(name gin_trgm_ops)",
+	"CREATE INDEX IF NOT EXISTS idx_brands_name_trgm ON brands USING gin(name gin_trgm_ops)",
+	"CREATE INDEX IF NOT EXISTS idx_categories_name_trgm ON categories USING gin(name gin_trgm_ops)",
+}
+
 func autoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+	// pg_trgm backs typo-tolerant typeahead suggestions. Creating it needs privileges
+	// the service's role may lack; suggestions then only match prefixes.
+	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
+		log.Printf("Warning: failed to enable pg_trgm, suggestions will not tolerate typos: %v", err)
+	}
+
+	err := db.AutoMigrate(
 		&entities.Category{},
+		&entities.CategoryAttribute{},
 		&entities.Brand{},
 		&entities.Product{},
 		&entities.ProductVariant{},
 		&entities.ProductImage{},
 		&entities.ProductVideo{},
+		&entities.BundleItem{},
+		&entities.ProductRelation{},
+		&entities.ProductCoOccurrence{},
+		&entities.IngestedOrder{},
+		&entities.ProductStat{},
+		&entities.ProductReview{},
+		&entities.ReviewVote{},
+		&entities.ProductPurchase{},
+		&entities.SlugRedirect{},
+		&entities.SearchLog{},
+		&entities.ImportJob{},
+		&entities.ProductRevision{},
+		&entities.PriceList{},
+		&entities.PriceListEntry{},
+		&entities.Sale{},
 	)
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range searchIndexes {
+		if err := db.Exec(statement).Error; err != nil {
+			return fmt.Errorf("failed to create search index: %w", err)
+		}
+	}
+
+	return createTrigramIndexes(db)
+}
+
+// createTrigramIndexes indexes the names suggestions match, if pg_trgm is installed
+func createTrigramIndexes(db *gorm.DB) error {
+	installed, err := database.TrigramsAvailable(db)
+	if err != nil {
+		return fmt.Errorf("failed to check for pg_trgm: %w", err)
+	}
+	if !installed {
+		return nil
+	}
+
+	for _, statement := range trigramIndexes {
+		if err := db.Exec(statement).Error; err != nil {
+			return fmt.Errorf("failed to create trigram index: %w", err)
+		}
+	}
+	return nil
 }
 
 func seedData(db *gorm.DB) error {
@@ -219,7 +417,27 @@ func seedData(db *gorm.DB) error {
 	return nil
 }
 
-func setupRouter(productHandler *handlers.ProductHandler) *gin.Engine {
+func setupRouter(
+	productHandler *handlers.ProductHandler,
+	variantHandler *handlers.VariantHandler,
+	imageHandler *handlers.ImageHandler,
+	videoHandler *handlers.VideoHandler,
+	bundleHandler *handlers.BundleHandler,
+	recommendationHandler *handlers.RecommendationHandler,
+	statsHandler *handlers.ProductStatsHandler,
+	reviewHandler *handlers.ReviewHandler,
+	revisionHandler *handlers.RevisionHandler,
+	pricingHandler *handlers.PricingHandler,
+	saleHandler *handlers.SaleHandler,
+	categoryHandler *handlers.CategoryHandler,
+	brandHandler *handlers.BrandHandler,
+	searchAnalyticsHandler *handlers.SearchAnalyticsHandler,
+	catalogHandler *handlers.CatalogHandler,
+	eventHandler *handlers.EventHandler,
+	sitemapHandler *handlers.SitemapHandler,
+	merchantFeedHandler *handlers.MerchantFeedHandler,
+	eventSigningSecret string,
+) *gin.Engine {
 	// Set Gin mode
 	if os.Getenv("GIN_MODE") == "" {
 		gin.SetMode(gin.ReleaseMode)
@@ -231,12 +449,14 @@ func setupRouter(productHandler *handlers.ProductHandler) *gin.Engine {
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
 	router.Use(middleware.RequestID())
+	router.Use(middleware.Actor())
 	router.Use(middleware.Logger())
 
 	// CORS configuration
 	config := cors.DefaultConfig()
 	config.AllowAllOrigins = true
-	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization"}
+	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", "If-Match"}
+	config.ExposeHeaders = []string{"ETag", "Last-Modified"}
 	router.Use(cors.New(config))
 
 	// Health check
@@ -251,6 +471,10 @@ func setupRouter(productHandler *handlers.ProductHandler) *gin.Engine {
 	// Swagger documentation
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Sitemaps, served where crawlers look for them
+	router.GET("/sitemap.xml", sitemapHandler.GetSitemapIndex)
+	router.GET("/sitemaps/:name", sitemapHandler.GetSitemapPage)
+
 	// API routes
 	v1 := router.Group("/api/v1")
 	{
@@ -258,7 +482,11 @@ func setupRouter(productHandler *handlers.ProductHandler) *gin.Engine {
 		{
 			products.POST("", productHandler.CreateProduct)
 			products.GET("/search", productHandler.SearchProducts)
+			products.GET("/suggest", productHandler.SuggestProducts)
 			products.GET("/featured", productHandler.GetFeaturedProducts)
+			products.GET("/top-selling", productHandler.GetTopSellingProducts)
+			products.GET("/most-viewed", productHandler.GetMostViewedProducts)
+			products.GET("/trending", productHandler.GetTrendingProducts)
 			products.GET("/category/:category_id", productHandler.GetProductsByCategory)
 			products.GET("/brand/:brand_id", productHandler.GetProductsByBrand)
 			products.GET("/sku/:sku", productHandler.GetProductBySKU)
@@ -267,12 +495,148 @@ func setupRouter(productHandler *handlers.ProductHandler) *gin.Engine {
 			products.PUT("/:id", productHandler.UpdateProduct)
 			products.DELETE("/:id", productHandler.DeleteProduct)
 			products.PUT("/:id/stock", productHandler.UpdateProductStock)
+			products.PUT("/:id/schedule", productHandler.UpdateProductSchedule)
+			products.PUT("/:id/price-tiers", productHandler.UpdateProductPriceTiers)
+			products.POST("/:id/variants/generate", variantHandler.GenerateVariants)
+			products.GET("/:id/variants/resolve", variantHandler.ResolveVariant)
+			products.GET("/:id/variants/matrix", variantHandler.GetVariantMatrix)
+			products.PUT("/:id/variants/:variantId/price-tiers", variantHandler.UpdateVariantPriceTiers)
+			products.POST("/:id/images", imageHandler.UploadProductImages)
+			products.GET("/:id/videos", videoHandler.ListProductVideos)
+			products.POST("/:id/videos", videoHandler.AddProductVideo)
+			products.POST("/:id/videos/upload", videoHandler.UploadProductVideo)
+			products.PUT("/:id/videos/order", videoHandler.ReorderProductVideos)
+			products.DELETE("/:id/videos/:videoId", videoHandler.DeleteProductVideo)
+			products.PUT("/:id/bundle", bundleHandler.SetProductBundle)
+			products.DELETE("/:id/bundle", bundleHandler.RemoveProductBundle)
+			products.GET("/:id/relations", recommendationHandler.ListRelations)
+			products.PUT("/:id/relations/:type", recommendationHandler.SetRelations)
+			products.GET("/:id/recommendations", recommendationHandler.GetRecommendations)
+			products.POST("/:id/views", statsHandler.RecordProductView)
+			products.GET("/:id/reviews", reviewHandler.ListProductReviews)
+			products.POST("/:id/reviews", reviewHandler.SubmitReview)
+			products.GET("/:id/revisions", revisionHandler.ListRevisions)
+			products.GET("/:id/revisions/diff", revisionHandler.DiffRevisions)
+			products.GET("/:id/revisions/:number", revisionHandler.GetRevision)
+			products.POST("/:id/revisions/:number/rollback", revisionHandler.RollbackRevision)
+			products.GET("/:id/price", pricingHandler.ResolvePrice)
+		}
+
+		priceLists := v1.Group("/price-lists")
+		{
+			priceLists.POST("", pricingHandler.CreatePriceList)
+			priceLists.GET("", pricingHandler.ListPriceLists)
+			priceLists.GET("/:id", pricingHandler.GetPriceList)
+			priceLists.PUT("/:id", pricingHandler.UpdatePriceList)
+			priceLists.DELETE("/:id", pricingHandler.DeletePriceList)
+			priceLists.GET("/:id/prices", pricingHandler.ListPrices)
+			priceLists.POST("/:id/prices", pricingHandler.AddPrice)
+			priceLists.PUT("/:id/prices/:priceId", pricingHandler.UpdatePrice)
+			priceLists.DELETE("/:id/prices/:priceId", pricingHandler.DeletePrice)
+		}
+
+		v1.POST("/price-quotes", pricingHandler.QuotePrices)
+
+		sales := v1.Group("/sales")
+		{
+			sales.POST("", saleHandler.CreateSale)
+			sales.GET("", saleHandler.ListSales)
+			sales.GET("/:id", saleHandler.GetSale)
+			sales.PUT("/:id", saleHandler.UpdateSale)
+			sales.DELETE("/:id", saleHandler.DeleteSale)
+		}
+
+		categories := v1.Group("/categories")
+		{
+			categories.POST("", categoryHandler.CreateCategory)
+			categories.GET("", categoryHandler.ListCategories)
+			categories.GET("/tree", categoryHandler.GetCategoryTree)
+			categories.GET("/slug/:slug", categoryHandler.GetCategoryBySlug)
+			categories.GET("/:id", categoryHandler.GetCategory)
+			categories.PUT("/:id", categoryHandler.UpdateCategory)
+			categories.DELETE("/:id", categoryHandler.DeleteCategory)
+			categories.POST("/:id/move", categoryHandler.MoveCategory)
+			categories.GET("/:id/tree", categoryHandler.GetCategorySubTree)
+			categories.GET("/:id/breadcrumbs", categoryHandler.GetCategoryBreadcrumbs)
+			categories.GET("/:id/attributes", categoryHandler.GetAttributeSchema)
+			categories.POST("/:id/attributes", categoryHandler.CreateAttribute)
+			categories.PUT("/:id/attributes/:attributeId", categoryHandler.UpdateAttribute)
+			categories.DELETE("/:id/attributes/:attributeId", categoryHandler.DeleteAttribute)
+		}
+
+		brands := v1.Group("/brands")
+		{
+			brands.POST("", brandHandler.CreateBrand)
+			brands.GET("", brandHandler.ListBrands)
+			brands.GET("/slug/:slug", brandHandler.GetBrandBySlug)
+			brands.GET("/slug/:slug/page", brandHandler.GetBrandPage)
+			brands.GET("/:id", brandHandler.GetBrand)
+			brands.PUT("/:id", brandHandler.UpdateBrand)
+			brands.DELETE("/:id", brandHandler.DeleteBrand)
+			brands.POST("/:id/activate", brandHandler.ActivateBrand)
+			brands.POST("/:id/deactivate", brandHandler.DeactivateBrand)
+		}
+
+		search := v1.Group("/search")
+		{
+			search.GET("/analytics", searchAnalyticsHandler.GetSearchAnalytics)
+			search.POST("/clicks", searchAnalyticsHandler.RecordSearchClick)
+		}
+
+		catalog := v1.Group("/catalog")
+		{
+			catalog.POST("/import", catalogHandler.ImportCatalog)
+			catalog.GET("/import/:id", catalogHandler.GetImportJob)
+			catalog.POST("/import/:id/resume", catalogHandler.ResumeImport)
+			catalog.GET("/export", catalogHandler.ExportCatalog)
+		}
+
+		reviews := v1.Group("/reviews")
+		{
+			reviews.GET("", reviewHandler.ListReviews)
+			reviews.PUT("/:reviewId/moderation", reviewHandler.ModerateReview)
+			reviews.POST("/:reviewId/votes", reviewHandler.VoteReview)
+		}
+
+		// Events grant verified purchases and feed rankings, so only signed ones are accepted
+		v1.POST("/events", middleware.EventSignature(eventSigningSecret), eventHandler.ReceiveEvent)
+
+		// Product feeds
+		feeds := v1.Group("/feeds")
+		{
+			feeds.GET("/google", merchantFeedHandler.GetGoogleFeed)
+			feeds.GET("/google/report", merchantFeedHandler.GetGoogleFeedReport)
 		}
 	}
 
 	return router
 }
 
+// loadSearchIndex loads the embedded search index from its snapshot, or indexes the
+// products in the database when there is no snapshot yet
+func loadSearchIndex(db *gorm.DB, path string) (*search.EmbeddedSearchRepository, error) {
+	index := search.NewEmbeddedSearchRepository()
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		log.Printf("No search index snapshot at %s, indexing products from the database", path)
+		if _, err := search.Reindex(context.Background(), db, index, 500); err != nil {
+			return nil, err
+		}
+		return index, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if err := index.Load(file); err != nil {
+		return nil, err
+	}
+	log.Printf("Loaded %d products into the search index from %s", index.Count(), path)
+	return index, nil
+}
+
 func getDatabaseDSN() string {
 	host := getEnv("DB_HOST", "localhost")
 	port := getEnv("DB_PORT", "5432")
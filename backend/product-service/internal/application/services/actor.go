@@ -0,0 +1,21 @@
+package services
+
+import "context"
+
+// SystemActor is recorded for changes made without an identified user
+const SystemActor = "system"
+
+type actorKey struct{}
+
+// WithActor returns a context carrying the user a change is made on behalf of
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the user a change is made on behalf of, or SystemActor
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return SystemActor
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// AttributeSchemaService manages per-category attribute schemas and validates product
+// attributes against them
+type AttributeSchemaService struct {
+	categoryRepo  repositories.CategoryRepository
+	attributeRepo repositories.CategoryAttributeRepository
+}
+
+// NewAttributeSchemaService creates a new attribute schema service
+func NewAttributeSchemaService(
+	categoryRepo repositories.CategoryRepository,
+	attributeRepo repositories.CategoryAttributeRepository,
+) *AttributeSchemaService {
+	return &AttributeSchemaService{
+		categoryRepo:  categoryRepo,
+		attributeRepo: attributeRepo,
+	}
+}
+
+// CreateCategoryAttributeRequest represents a request to add an attribute to a category's schema
+type CreateCategoryAttributeRequest struct {
+	Name string                 `json:"name" validate:"required"`
+	Type entities.AttributeType `json:"type" validate:"required,oneof=text number enum boolean measure"`
+	UpdateCategoryAttributeRequest
+}
+
+// UpdateCategoryAttributeRequest represents a request to change an attribute definition.
+// The name and type cannot change, since existing product values depend on them.
+type UpdateCategoryAttributeRequest struct {
+	Label         string   `json:"label"`
+	Required      bool     `json:"required"`
+	AllowedValues []string `json:"allowed_values"`
+	Units         []string `json:"units"`
+	Min           *float64 `json:"min"`
+	Max           *float64 `json:"max"`
+	Filterable    bool     `json:"filterable"`
+	SortOrder     int      `json:"sort_order"`
+}
+
+// GetSchema returns the effective schema of a category, including inherited attributes
+func (s *AttributeSchemaService) GetSchema(ctx context.Context, categoryID uuid.UUID) (*entities.AttributeSchema, error) {
+	path, err := s.categoryRepo.GetPath(ctx, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category path: %w", err)
+	}
+	if len(path) == 0 {
+		return nil, errors.New("category not found")
+	}
+
+	ids := make([]uuid.UUID, len(path))
+	for i, category := range path {
+		ids[i] = category.ID
+	}
+
+	definitions, err := s.attributeRepo.GetByCategories(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category attributes: %w", err)
+	}
+
+	return entities.NewAttributeSchema(ids, definitions), nil
+}
+
+// CreateAttribute adds an attribute to a category's schema. A subcategory may define an
+// attribute its ancestors already define, which then overrides the inherited one.
+func (s *AttributeSchemaService) CreateAttribute(ctx context.Context, categoryID uuid.UUID, req *CreateCategoryAttributeRequest) (*entities.CategoryAttribute, error) {
+	category, err := s.categoryRepo.GetByID(ctx, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+	if category == nil {
+		return nil, errors.New("category not found")
+	}
+
+	attribute := entities.NewCategoryAttribute(categoryID, req.Name, req.Type)
+	applyAttributeRequest(attribute, &req.UpdateCategoryAttributeRequest)
+	if err := attribute.Validate(); err != nil {
+		return nil, err
+	}
+
+	exists, err := s.attributeRepo.ExistsByName(ctx, categoryID, attribute.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check attribute existence: %w", err)
+	}
+	if exists {
+		return nil, errors.New("attribute with this name already exists")
+	}
+
+	if err := s.attributeRepo.Create(ctx, attribute); err != nil {
+		return nil, fmt.Errorf("failed to create attribute: %w", err)
+	}
+
+	return attribute, nil
+}
+
+// UpdateAttribute changes an attribute defined directly on a category
+func (s *AttributeSchemaService) UpdateAttribute(ctx context.Context, categoryID, attributeID uuid.UUID, req *UpdateCategoryAttributeRequest) (*entities.CategoryAttribute, error) {
+	attribute, err := s.getCategoryAttribute(ctx, categoryID, attributeID)
+	if err != nil {
+		return nil, err
+	}
+
+	applyAttributeRequest(attribute, req)
+	if err := attribute.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.attributeRepo.Update(ctx, attribute); err != nil {
+		return nil, fmt.Errorf("failed to update attribute: %w", err)
+	}
+
+	return attribute, nil
+}
+
+// DeleteAttribute removes an attribute from a category's schema. Product values are kept
+// and become free-form attributes, unless an ancestor still defines the attribute.
+func (s *AttributeSchemaService) DeleteAttribute(ctx context.Context, categoryID, attributeID uuid.UUID) error {
+	if _, err := s.getCategoryAttribute(ctx, categoryID, attributeID); err != nil {
+		return err
+	}
+
+	if err := s.attributeRepo.Delete(ctx, attributeID); err != nil {
+		return fmt.Errorf("failed to delete attribute: %w", err)
+	}
+
+	return nil
+}
+
+// ValidateAttributes checks product attributes against the schema of a category and
+// returns them normalized. Invalid values are reported as an
+// *entities.AttributeValidationError.
+func (s *AttributeSchemaService) ValidateAttributes(ctx context.Context, categoryID uuid.UUID, attributes map[string]string) (map[string]string, error) {
+	schema, err := s.GetSchema(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return schema.Apply(attributes)
+}
+
+// FilterableAttributes returns the attribute keys to offer as search facets: those marked
+// filterable in the schemas of the given categories, or in any category when none are
+// given
+func (s *AttributeSchemaService) FilterableAttributes(ctx context.Context, categoryIDs []uuid.UUID) ([]string, error) {
+	keys := []string{}
+	seen := make(map[string]bool)
+
+	if len(categoryIDs) == 0 {
+		definitions, err := s.attributeRepo.GetFilterable(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get filterable attributes: %w", err)
+		}
+		for _, definition := range definitions {
+			if !seen[definition.Name] {
+				seen[definition.Name] = true
+				keys = append(keys, definition.Name)
+			}
+		}
+		return keys, nil
+	}
+
+	for _, categoryID := range categoryIDs {
+		schema, err := s.GetSchema(ctx, categoryID)
+		if err != nil {
+			if err.Error() == "category not found" {
+				continue
+			}
+			return nil, err
+		}
+		for _, name := range schema.Filterable() {
+			if !seen[name] {
+				seen[name] = true
+				keys = append(keys, name)
+			}
+		}
+	}
+
+	return keys, nil
+}
+
+// getCategoryAttribute loads an attribute and checks it belongs to the category
+func (s *AttributeSchemaService) getCategoryAttribute(ctx context.Context, categoryID, attributeID uuid.UUID) (*entities.CategoryAttribute, error) {
+	attribute, err := s.attributeRepo.GetByID(ctx, attributeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attribute: %w", err)
+	}
+	if attribute == nil || attribute.CategoryID != categoryID {
+		return nil, errors.New("attribute not found")
+	}
+	return attribute, nil
+}
+
+// applyAttributeRequest copies the mutable fields of a request onto a definition
+func applyAttributeRequest(attribute *entities.CategoryAttribute, req *UpdateCategoryAttributeRequest) {
+	attribute.Label = strings.TrimSpace(req.Label)
+	attribute.Required = req.Required
+	attribute.AllowedValues = req.AllowedValues
+	attribute.Units = req.Units
+	attribute.Min = req.Min
+	attribute.Max = req.Max
+	attribute.Filterable = req.Filterable
+	attribute.SortOrder = req.SortOrder
+	attribute.UpdatedAt = time.Now()
+}
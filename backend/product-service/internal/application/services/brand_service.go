@@ -0,0 +1,385 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// BrandService handles brand business logic
+type BrandService struct {
+	brandRepo        repositories.BrandRepository
+	productRepo      repositories.ProductRepository
+	slugRepo         repositories.SlugRedirectRepository
+	attributeSchemas *AttributeSchemaService
+	suggestions      *SuggestionCache
+	transactions     repositories.TransactionManager
+}
+
+// NewBrandService creates a new brand service
+func NewBrandService(
+	brandRepo repositories.BrandRepository,
+	productRepo repositories.ProductRepository,
+	slugRepo repositories.SlugRedirectRepository,
+	attributeSchemas *AttributeSchemaService,
+	suggestions *SuggestionCache,
+	transactions repositories.TransactionManager,
+) *BrandService {
+	return &BrandService{
+		brandRepo:        brandRepo,
+		productRepo:      productRepo,
+		slugRepo:         slugRepo,
+		attributeSchemas: attributeSchemas,
+		suggestions:      suggestions,
+		transactions:     transactions,
+	}
+}
+
+// CreateBrandRequest represents a create brand request
+type CreateBrandRequest struct {
+	Name            string `json:"name" validate:"required"`
+	Description     string `json:"description"`
+	LogoURL         string `json:"logo_url"`
+	Website         string `json:"website"`
+	SortOrder       int    `json:"sort_order"`
+	MetaTitle       string `json:"meta_title"`
+	MetaDescription string `json:"meta_description"`
+	IsVisible       *bool  `json:"is_visible"`
+}
+
+// UpdateBrandRequest represents an update brand request. Brands are activated and
+// deactivated with SetBrandActive.
+type UpdateBrandRequest struct {
+	Name            string `json:"name" validate:"required"`
+	Description     string `json:"description"`
+	LogoURL         string `json:"logo_url"`
+	Website         string `json:"website"`
+	SortOrder       int    `json:"sort_order"`
+	MetaTitle       string `json:"meta_title"`
+	MetaDescription string `json:"meta_description"`
+	IsVisible       *bool  `json:"is_visible"`
+}
+
+// BrandListResult is a page of brands
+type BrandListResult struct {
+	Brands     []*entities.Brand `json:"brands"`
+	Total      int64             `json:"total"`
+	Page       int               `json:"page"`
+	PageSize   int               `json:"page_size"`
+	TotalPages int               `json:"total_pages"`
+}
+
+// BrandPageRequest represents the product filters of a brand landing page
+type BrandPageRequest struct {
+	CategoryIDs []uuid.UUID       `json:"category_ids"`
+	MinPrice    *float64          `json:"min_price"`
+	MaxPrice    *float64          `json:"max_price"`
+	InStock     *bool             `json:"in_stock"`
+	Attributes  map[string]string `json:"attributes"`
+	Page        int               `json:"page" validate:"min=1"`
+	PageSize    int               `json:"page_size" validate:"min=1,max=100"`
+}
+
+// BrandPage is a brand landing page: the brand with a page of its listed products and
+// facet counts for narrowing them down
+type BrandPage struct {
+	Brand      *entities.Brand                      `json:"brand"`
+	Products   []*entities.Product                  `json:"products"`
+	Facets     map[string][]repositories.FacetValue `json:"facets"`
+	Total      int64                                `json:"total"`
+	Page       int                                  `json:"page"`
+	PageSize   int                                  `json:"page_size"`
+	TotalPages int                                  `json:"total_pages"`
+	HasNext    bool                                 `json:"has_next"`
+	HasPrev    bool                                 `json:"has_prev"`
+}
+
+// CreateBrand creates a new brand
+func (s *BrandService) CreateBrand(ctx context.Context, req *CreateBrandRequest) (*entities.Brand, error) {
+	brand, err := entities.NewBrand(req.Name, req.Description)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkUnique(ctx, brand, nil); err != nil {
+		return nil, err
+	}
+
+	brand.UpdateDisplay(req.LogoURL, req.Website, req.SortOrder)
+	brand.UpdateSEO(req.MetaTitle, req.MetaDescription)
+	if req.IsVisible != nil {
+		brand.SetVisible(*req.IsVisible)
+	}
+
+	if err := s.brandRepo.Create(ctx, brand); err != nil {
+		return nil, fmt.Errorf("failed to create brand: %w", err)
+	}
+	s.suggestions.clear()
+
+	return brand, nil
+}
+
+// GetBrand retrieves a brand by ID
+func (s *BrandService) GetBrand(ctx context.Context, id uuid.UUID) (*entities.Brand, error) {
+	brand, err := s.brandRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get brand: %w", err)
+	}
+	if brand == nil {
+		return nil, errors.New("brand not found")
+	}
+	return brand, nil
+}
+
+// GetBrandBySlug retrieves a brand by slug. A brand found by a slug it used to have is
+// returned too; its Slug then differs from the one asked for.
+func (s *BrandService) GetBrandBySlug(ctx context.Context, slug string) (*entities.Brand, error) {
+	brand, err := s.brandRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get brand: %w", err)
+	}
+	if brand != nil {
+		return brand, nil
+	}
+
+	brandID, err := resolveOldSlug(ctx, s.slugRepo, entities.SlugEntityBrand, slug)
+	if err != nil {
+		return nil, err
+	}
+	if brandID == nil {
+		return nil, errors.New("brand not found")
+	}
+	return s.GetBrand(ctx, *brandID)
+}
+
+// ListBrands lists brands. With activeOnly, only active, visible brands are listed, as
+// shown to shoppers.
+func (s *BrandService) ListBrands(ctx context.Context, activeOnly bool, page, pageSize int) (*BrandListResult, error) {
+	var brands []*entities.Brand
+	var total int64
+
+	if activeOnly {
+		// Visible brands are few enough to page in memory
+		visible, err := s.brandRepo.GetVisible(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list brands: %w", err)
+		}
+		total = int64(len(visible))
+		start := (page - 1) * pageSize
+		if start < len(visible) {
+			end := start + pageSize
+			if end > len(visible) {
+				end = len(visible)
+			}
+			brands = visible[start:end]
+		}
+	} else {
+		var err error
+		brands, err = s.brandRepo.GetPaginated(ctx, pageSize, (page-1)*pageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list brands: %w", err)
+		}
+		total, err = s.brandRepo.Count(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count brands: %w", err)
+		}
+	}
+
+	if brands == nil {
+		brands = []*entities.Brand{}
+	}
+
+	return &BrandListResult{
+		Brands:     brands,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+	}, nil
+}
+
+// UpdateBrand updates a brand's details
+func (s *BrandService) UpdateBrand(ctx context.Context, id uuid.UUID, req *UpdateBrandRequest) (*entities.Brand, error) {
+	brand, err := s.GetBrand(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	oldName, oldSlug := brand.Name, brand.Slug
+	if err := brand.UpdateBasicInfo(req.Name, req.Description); err != nil {
+		return nil, err
+	}
+	if brand.Name != oldName || brand.Slug != oldSlug {
+		if err := s.checkUnique(ctx, brand, &id); err != nil {
+			return nil, err
+		}
+	}
+
+	brand.UpdateDisplay(req.LogoURL, req.Website, req.SortOrder)
+	brand.UpdateSEO(req.MetaTitle, req.MetaDescription)
+	if req.IsVisible != nil {
+		brand.SetVisible(*req.IsVisible)
+	}
+
+	err = s.transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.brandRepo.Update(ctx, brand); err != nil {
+			return fmt.Errorf("failed to update brand: %w", err)
+		}
+		return recordSlugChange(ctx, s.slugRepo, entities.SlugEntityBrand, brand.ID, oldSlug, brand.Slug)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.suggestions.clear()
+
+	return brand, nil
+}
+
+// SetBrandActive activates or deactivates a brand. Deactivated brands keep their
+// products but disappear from storefront listings and landing pages.
+func (s *BrandService) SetBrandActive(ctx context.Context, id uuid.UUID, active bool) (*entities.Brand, error) {
+	brand, err := s.GetBrand(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	brand.SetActive(active)
+	if err := s.brandRepo.Update(ctx, brand); err != nil {
+		return nil, fmt.Errorf("failed to update brand: %w", err)
+	}
+	s.suggestions.clear()
+
+	return brand, nil
+}
+
+// DeleteBrand deletes a brand. A brand that still has products can only be deleted when
+// reassignTo names another brand to move them to. The check, the reassignment and the
+// delete run in one transaction, so a failed delete leaves the products where they were.
+func (s *BrandService) DeleteBrand(ctx context.Context, id uuid.UUID, reassignTo *uuid.UUID) error {
+	if _, err := s.GetBrand(ctx, id); err != nil {
+		return err
+	}
+
+	err := s.transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		hasProducts, err := s.brandRepo.HasProducts(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to check brand products: %w", err)
+		}
+
+		if hasProducts {
+			if reassignTo == nil {
+				return errors.New("brand has products")
+			}
+			if *reassignTo == id {
+				return errors.New("cannot reassign products to the brand being deleted")
+			}
+			target, err := s.brandRepo.GetByID(ctx, *reassignTo)
+			if err != nil {
+				return fmt.Errorf("failed to get brand: %w", err)
+			}
+			if target == nil {
+				return errors.New("reassignment brand not found")
+			}
+
+			if _, err := s.brandRepo.ReassignProducts(ctx, id, target.ID); err != nil {
+				return fmt.Errorf("failed to reassign products: %w", err)
+			}
+		}
+
+		if err := s.brandRepo.Delete(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete brand: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.suggestions.clear()
+
+	return nil
+}
+
+// GetBrandPage builds the landing page of an active, visible brand
+func (s *BrandService) GetBrandPage(ctx context.Context, slug string, req *BrandPageRequest) (*BrandPage, error) {
+	brand, err := s.GetBrandBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if !brand.IsActive || !brand.IsVisible {
+		return nil, errors.New("brand not found")
+	}
+
+	filters := &repositories.ProductFilters{
+		CategoryIDs: req.CategoryIDs,
+		BrandIDs:    []uuid.UUID{brand.ID},
+		MinPrice:    req.MinPrice,
+		MaxPrice:    req.MaxPrice,
+		InStock:     req.InStock,
+		Attributes:  req.Attributes,
+		ListedOnly:  true,
+	}
+
+	hits, err := s.productRepo.Search(ctx, "", filters, req.PageSize, (req.Page-1)*req.PageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get brand products: %w", err)
+	}
+	products := make([]*entities.Product, len(hits))
+	for i, hit := range hits {
+		products[i] = hit.Product
+	}
+
+	total, err := s.productRepo.CountSearch(ctx, "", filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count brand products: %w", err)
+	}
+
+	attributeKeys, err := s.attributeSchemas.FilterableAttributes(ctx, req.CategoryIDs)
+	if err != nil {
+		return nil, err
+	}
+	facets, err := s.productRepo.GetSearchFacets(ctx, "", filters, attributeKeys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute brand facets: %w", err)
+	}
+	// Every product on the page has this brand
+	delete(facets, repositories.FacetBrand)
+
+	totalPages := int((total + int64(req.PageSize) - 1) / int64(req.PageSize))
+
+	return &BrandPage{
+		Brand:      brand,
+		Products:   products,
+		Facets:     facets,
+		Total:      total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: totalPages,
+		HasNext:    req.Page < totalPages,
+		HasPrev:    req.Page > 1,
+	}, nil
+}
+
+// checkUnique rejects a brand whose name or slug is taken by another brand
+func (s *BrandService) checkUnique(ctx context.Context, brand *entities.Brand, excludeID *uuid.UUID) error {
+	existing, err := s.brandRepo.GetByName(ctx, brand.Name)
+	if err != nil {
+		return fmt.Errorf("failed to check brand name: %w", err)
+	}
+	if existing != nil && (excludeID == nil || existing.ID != *excludeID) {
+		return errors.New("brand with this name already exists")
+	}
+
+	existing, err = s.brandRepo.GetBySlug(ctx, brand.Slug)
+	if err != nil {
+		return fmt.Errorf("failed to check brand slug: %w", err)
+	}
+	if existing != nil && (excludeID == nil || existing.ID != *excludeID) {
+		return errors.New("brand with this slug already exists")
+	}
+
+	return nil
+}
@@ -0,0 +1,369 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// BundleService manages bundle products: products sold as one item but built from
+// component products or variants. A bundle's stock, and its price when computed, are
+// derived from its components and kept up to date as the components change.
+type BundleService struct {
+	productRepo repositories.ProductRepository
+	variantRepo repositories.ProductVariantRepository
+	bundleRepo  repositories.BundleRepository
+	revisions   *RevisionService
+}
+
+// NewBundleService creates a new bundle service
+func NewBundleService(
+	productRepo repositories.ProductRepository,
+	variantRepo repositories.ProductVariantRepository,
+	bundleRepo repositories.BundleRepository,
+	revisions *RevisionService,
+) *BundleService {
+	return &BundleService{
+		productRepo: productRepo,
+		variantRepo: variantRepo,
+		bundleRepo:  bundleRepo,
+		revisions:   revisions,
+	}
+}
+
+// BundleRequest represents the request to make a product a bundle
+type BundleRequest struct {
+	Pricing       entities.BundlePricing `json:"pricing" binding:"required"`
+	DiscountType  entities.DiscountType  `json:"discount_type"`
+	DiscountValue float64                `json:"discount_value"`
+	Items         []BundleItemRequest    `json:"items" binding:"required"`
+}
+
+// BundleItemRequest is one component of a bundle request
+type BundleItemRequest struct {
+	ProductID uuid.UUID  `json:"product_id" binding:"required"`
+	VariantID *uuid.UUID `json:"variant_id"`
+	Quantity  int        `json:"quantity" binding:"required"`
+}
+
+// SetBundle makes a product a bundle of the given components, or replaces the
+// components of an existing bundle. Components must be products without variants, or
+// variants; bundles cannot contain other bundles.
+func (s *BundleService) SetBundle(ctx context.Context, productID uuid.UUID, req *BundleRequest) (*entities.Product, error) {
+	product, err := s.getProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Items) == 0 {
+		return nil, errors.New("a bundle needs at least one component")
+	}
+
+	containing, err := s.bundleRepo.GetBundleIDsByComponents(ctx, []uuid.UUID{product.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check bundles: %w", err)
+	}
+	if len(containing) > 0 {
+		return nil, errors.New("a component of another bundle cannot be a bundle")
+	}
+
+	items, err := s.buildItems(ctx, product.ID, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	before := entities.TakeSnapshot(product)
+	if err := product.SetBundle(req.Pricing, req.DiscountType, req.DiscountValue); err != nil {
+		return nil, err
+	}
+	product.DeriveBundle(items)
+	product.UpdatedBy = ActorFromContext(ctx)
+
+	err = s.revisions.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.productRepo.Update(ctx, product); err != nil {
+			return fmt.Errorf("failed to update product: %w", err)
+		}
+		if err := s.bundleRepo.ReplaceItems(ctx, product.ID, items); err != nil {
+			return fmt.Errorf("failed to save bundle components: %w", err)
+		}
+		return s.revisions.Record(ctx, entities.NewProductRevision(product.ID, entities.RevisionEntityProduct, product.ID, entities.RevisionActionUpdate, product.UpdatedBy, before, product))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		product.BundleItems = append(product.BundleItems, *item)
+	}
+	return product, nil
+}
+
+// RemoveBundle turns a bundle back into a regular product. It keeps the stock and price
+// last derived from its components, which can then be managed directly.
+func (s *BundleService) RemoveBundle(ctx context.Context, productID uuid.UUID) (*entities.Product, error) {
+	product, err := s.getProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if !product.IsBundle {
+		return nil, errors.New("product is not a bundle")
+	}
+
+	before := entities.TakeSnapshot(product)
+	product.ClearBundle()
+	product.UpdatedBy = ActorFromContext(ctx)
+
+	err = s.revisions.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.productRepo.Update(ctx, product); err != nil {
+			return fmt.Errorf("failed to update product: %w", err)
+		}
+		if err := s.bundleRepo.ReplaceItems(ctx, product.ID, nil); err != nil {
+			return fmt.Errorf("failed to remove bundle components: %w", err)
+		}
+		return s.revisions.Record(ctx, entities.NewProductRevision(product.ID, entities.RevisionEntityProduct, product.ID, entities.RevisionActionUpdate, product.UpdatedBy, before, product))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// Derive sets a bundle's stock and computed price from its current components,
+// without saving it
+func (s *BundleService) Derive(ctx context.Context, bundle *entities.Product) error {
+	items, err := s.bundleRepo.GetItems(ctx, bundle.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get bundle components: %w", err)
+	}
+	bundle.DeriveBundle(items)
+	return nil
+}
+
+// DeductStock sells quantity of a bundle by deducting its components. The components
+// are locked and deducted in one transaction, so a bundle that cannot be built, or whose
+// deduction fails part-way, takes nothing.
+func (s *BundleService) DeductStock(ctx context.Context, bundle *entities.Product, quantity int) error {
+	if quantity <= 0 {
+		return errors.New("quantity must be positive")
+	}
+
+	actor := ActorFromContext(ctx)
+	return s.revisions.WithinTransaction(ctx, func(ctx context.Context) error {
+		items, err := s.bundleRepo.LockItems(ctx, bundle.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get bundle components: %w", err)
+		}
+		if len(items) == 0 {
+			return errors.New("bundle has no components")
+		}
+
+		for _, item := range items {
+			if err := checkComponentStock(item, item.Quantity*quantity); err != nil {
+				return err
+			}
+		}
+
+		componentIDs := make([]uuid.UUID, 0, len(items))
+		var revisions []*entities.ProductRevision
+		for _, item := range items {
+			needed := item.Quantity * quantity
+			componentIDs = append(componentIDs, item.ProductID)
+
+			if item.Variant != nil {
+				before := entities.TakeSnapshot(item.Variant)
+				if err := item.Variant.DeductStock(needed); err != nil {
+					return err
+				}
+				item.Variant.UpdatedBy = actor
+				if err := s.variantRepo.Update(ctx, item.Variant); err != nil {
+					return fmt.Errorf("failed to update variant: %w", err)
+				}
+				revisions = append(revisions, entities.NewProductRevision(item.ProductID, entities.RevisionEntityVariant, item.Variant.ID, entities.RevisionActionUpdate, actor, before, item.Variant))
+				continue
+			}
+
+			before := entities.TakeSnapshot(item.Product)
+			if err := item.Product.DeductStock(needed); err != nil {
+				return err
+			}
+			item.Product.UpdatedBy = actor
+			if err := s.productRepo.Update(ctx, item.Product); err != nil {
+				return fmt.Errorf("failed to update product: %w", err)
+			}
+			revisions = append(revisions, entities.NewProductRevision(item.ProductID, entities.RevisionEntityProduct, item.ProductID, entities.RevisionActionUpdate, actor, before, item.Product))
+		}
+
+		if err := s.revisions.Record(ctx, revisions...); err != nil {
+			return err
+		}
+		return s.RefreshBundles(ctx, componentIDs...)
+	})
+}
+
+// RefreshBundles re-derives the bundles among productIDs and the bundles containing any
+// of them, after their stock or prices changed. Callers run it in the transaction that
+// changed the components, so the bundles, locked while they are re-derived, commit or
+// roll back together with them.
+func (s *BundleService) RefreshBundles(ctx context.Context, productIDs ...uuid.UUID) error {
+	if len(productIDs) == 0 {
+		return nil
+	}
+
+	return s.revisions.WithinTransaction(ctx, func(ctx context.Context) error {
+		bundleIDs, err := s.bundleRepo.GetBundleIDsByComponents(ctx, productIDs)
+		if err != nil {
+			return fmt.Errorf("failed to find bundles to refresh: %w", err)
+		}
+		bundleIDs = append(bundleIDs, productIDs...)
+
+		bundles, err := s.bundleRepo.LockBundles(ctx, uniqueIDs(bundleIDs))
+		if err != nil {
+			return fmt.Errorf("failed to get bundles to refresh: %w", err)
+		}
+
+		for _, bundle := range bundles {
+			stock, trackStock, price := bundle.StockQuantity, bundle.TrackStock, bundle.Price
+			if err := s.Derive(ctx, bundle); err != nil {
+				return err
+			}
+			if bundle.StockQuantity == stock && bundle.TrackStock == trackStock && bundle.Price == price {
+				continue
+			}
+			if err := s.productRepo.Update(ctx, bundle); err != nil {
+				return fmt.Errorf("failed to refresh bundle %s: %w", bundle.ID, err)
+			}
+		}
+		return nil
+	})
+}
+
+// ComponentOf returns the bundles containing a product, or any of its variants
+func (s *BundleService) ComponentOf(ctx context.Context, productID uuid.UUID) ([]uuid.UUID, error) {
+	ids, err := s.bundleRepo.GetBundleIDsByComponents(ctx, []uuid.UUID{productID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check bundles: %w", err)
+	}
+	return ids, nil
+}
+
+// buildItems validates the components of a bundle request and loads their products
+// and variants
+func (s *BundleService) buildItems(ctx context.Context, bundleID uuid.UUID, reqs []BundleItemRequest) ([]*entities.BundleItem, error) {
+	productIDs := make([]uuid.UUID, 0, len(reqs))
+	var variantIDs []uuid.UUID
+	for _, req := range reqs {
+		productIDs = append(productIDs, req.ProductID)
+		if req.VariantID != nil {
+			variantIDs = append(variantIDs, *req.VariantID)
+		}
+	}
+
+	products, err := s.productRepo.GetByIDs(ctx, uniqueIDs(productIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get components: %w", err)
+	}
+	productsByID := make(map[uuid.UUID]*entities.Product, len(products))
+	for _, product := range products {
+		productsByID[product.ID] = product
+	}
+
+	variantsByID := make(map[uuid.UUID]*entities.ProductVariant)
+	if len(variantIDs) > 0 {
+		variants, err := s.variantRepo.GetByIDs(ctx, uniqueIDs(variantIDs))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get component variants: %w", err)
+		}
+		for _, variant := range variants {
+			variantsByID[variant.ID] = variant
+		}
+	}
+
+	type componentKey struct{ product, variant uuid.UUID }
+	seen := make(map[componentKey]bool, len(reqs))
+	items := make([]*entities.BundleItem, 0, len(reqs))
+	for i, req := range reqs {
+		product := productsByID[req.ProductID]
+		if product == nil {
+			return nil, fmt.Errorf("component product %s not found", req.ProductID)
+		}
+		if product.IsBundle {
+			return nil, fmt.Errorf("component %s is a bundle; bundles cannot contain bundles", product.SKU)
+		}
+
+		key := componentKey{product: product.ID}
+		var variant *entities.ProductVariant
+		if req.VariantID != nil {
+			variant = variantsByID[*req.VariantID]
+			if variant == nil || variant.ProductID != product.ID {
+				return nil, fmt.Errorf("component variant %s not found", *req.VariantID)
+			}
+			key.variant = variant.ID
+		} else if product.HasVariants {
+			return nil, fmt.Errorf("component %s has variants; choose one", product.SKU)
+		}
+		if seen[key] {
+			return nil, errors.New("each component can be listed only once")
+		}
+		seen[key] = true
+
+		item, err := entities.NewBundleItem(bundleID, product.ID, req.VariantID, req.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		item.SortOrder = i
+		item.Product = product
+		item.Variant = variant
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// checkComponentStock checks that a component can give up the quantity a sale needs
+func checkComponentStock(item *entities.BundleItem, quantity int) error {
+	var sku string
+	var err error
+	switch {
+	case item.Variant != nil:
+		variant := *item.Variant
+		sku, err = variant.SKU, variant.DeductStock(quantity)
+	case item.Product != nil && item.VariantID == nil:
+		product := *item.Product
+		sku, err = product.SKU, product.DeductStock(quantity)
+	default:
+		return errors.New("a component of this bundle no longer exists")
+	}
+	if err != nil {
+		return fmt.Errorf("insufficient stock of component %s", sku)
+	}
+	return nil
+}
+
+// getProduct retrieves a product, or a not found error
+func (s *BundleService) getProduct(ctx context.Context, productID uuid.UUID) (*entities.Product, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if product == nil {
+		return nil, errors.New("product not found")
+	}
+	return product, nil
+}
+
+// uniqueIDs returns ids without duplicates, in their first order
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
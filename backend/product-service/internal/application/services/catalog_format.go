@@ -0,0 +1,452 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"product-service/internal/domain/entities"
+)
+
+// CatalogRecord is one row of a catalog import or export file. Rows without a
+// parent_sku describe products; rows with one describe a variant of that product.
+// Empty fields leave the existing value unchanged when upserting.
+type CatalogRecord struct {
+	ParentSKU        string            `json:"parent_sku,omitempty"`
+	SKU              string            `json:"sku"`
+	Barcode          string            `json:"barcode,omitempty"`
+	Name             string            `json:"name,omitempty"`
+	Description      string            `json:"description,omitempty"`
+	ShortDescription string            `json:"short_description,omitempty"`
+	Price            *float64          `json:"price,omitempty"`
+	ComparePrice     *float64          `json:"compare_price,omitempty"`
+	CostPrice        *float64          `json:"cost_price,omitempty"`
+	StockQuantity    *int              `json:"stock_quantity,omitempty"`
+	TrackStock       *bool             `json:"track_stock,omitempty"`
+	AllowBackorder   *bool             `json:"allow_backorder,omitempty"`
+	Weight           *float64          `json:"weight,omitempty"`
+	Length           *float64          `json:"length,omitempty"`
+	Width            *float64          `json:"width,omitempty"`
+	Height           *float64          `json:"height,omitempty"`
+	Category         string            `json:"category,omitempty"` // slug or ID
+	Brand            string            `json:"brand,omitempty"`    // slug, name or ID
+	Status           string            `json:"status,omitempty"`   // draft, active, inactive, archived
+	Visibility       string            `json:"visibility,omitempty"`
+	Featured         *bool             `json:"featured,omitempty"`
+	Tags             []string          `json:"tags,omitempty"`
+	Images           []string          `json:"images,omitempty"` // URLs; the first is primary
+	Attributes       map[string]string `json:"attributes,omitempty"`
+	Slug             string            `json:"slug,omitempty"`
+	MetaTitle        string            `json:"meta_title,omitempty"`
+	MetaDescription  string            `json:"meta_description,omitempty"`
+}
+
+// IsVariant reports whether the record describes a variant
+func (r *CatalogRecord) IsVariant() bool {
+	return r.ParentSKU != ""
+}
+
+// catalogColumns is the CSV header, in export order. In CSV files tags and images are
+// separated by "|" and attributes are written as "key=value|key=value".
+var catalogColumns = []string{
+	"parent_sku", "sku", "barcode", "name", "description", "short_description",
+	"price", "compare_price", "cost_price", "stock_quantity", "track_stock", "allow_backorder",
+	"weight", "length", "width", "height", "category", "brand", "status", "visibility", "featured",
+	"tags", "images", "attributes", "slug", "meta_title", "meta_description",
+}
+
+const (
+	catalogListSeparator = "|"
+	maxJSONLLineSize     = 10 << 20
+)
+
+// catalogDecoder reads catalog records one at a time. Next returns the record and the
+// line it started on, io.EOF at the end of input, an *entities.ImportRowError for a
+// row that cannot be read (decoding may continue), or any other error if the file is
+// unreadable.
+type catalogDecoder interface {
+	Next() (*CatalogRecord, int, error)
+}
+
+// newCatalogDecoder creates a decoder for the given format
+func newCatalogDecoder(format string, r io.Reader) (catalogDecoder, error) {
+	switch format {
+	case entities.CatalogFormatCSV:
+		return newCSVCatalogDecoder(r)
+	case entities.CatalogFormatJSONL:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), maxJSONLLineSize)
+		return &jsonlCatalogDecoder{scanner: scanner}, nil
+	default:
+		return nil, errors.New("format must be csv or jsonl")
+	}
+}
+
+type csvCatalogDecoder struct {
+	reader  *csv.Reader
+	columns []string
+}
+
+func newCSVCatalogDecoder(r io.Reader) (*csvCatalogDecoder, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+
+	known := make(map[string]bool, len(catalogColumns))
+	for _, column := range catalogColumns {
+		known[column] = true
+	}
+
+	columns := make([]string, len(header))
+	hasSKU := false
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		if !known[column] {
+			return nil, fmt.Errorf("unknown CSV column %q", column)
+		}
+		columns[i] = column
+		hasSKU = hasSKU || column == "sku"
+	}
+	if !hasSKU {
+		return nil, errors.New("CSV header must include a sku column")
+	}
+
+	return &csvCatalogDecoder{reader: reader, columns: columns}, nil
+}
+
+func (d *csvCatalogDecoder) Next() (*CatalogRecord, int, error) {
+	row, err := d.reader.Read()
+	if err == io.EOF {
+		return nil, 0, io.EOF
+	}
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, parseErr.StartLine, &entities.ImportRowError{Line: parseErr.StartLine, Message: parseErr.Err.Error()}
+		}
+		return nil, 0, err
+	}
+
+	line, _ := d.reader.FieldPos(0)
+	if len(row) != len(d.columns) {
+		return nil, line, &entities.ImportRowError{
+			Line:    line,
+			Message: fmt.Sprintf("expected %d fields, got %d", len(d.columns), len(row)),
+		}
+	}
+
+	record := &CatalogRecord{}
+	for i, column := range d.columns {
+		if err := setCatalogField(record, column, strings.TrimSpace(row[i])); err != nil {
+			return nil, line, &entities.ImportRowError{Line: line, SKU: record.SKU, Field: column, Message: err.Error()}
+		}
+	}
+
+	return record, line, nil
+}
+
+type jsonlCatalogDecoder struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (d *jsonlCatalogDecoder) Next() (*CatalogRecord, int, error) {
+	for d.scanner.Scan() {
+		d.line++
+		data := bytes.TrimSpace(d.scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+
+		record := &CatalogRecord{}
+		if err := decoder.Decode(record); err != nil {
+			return nil, d.line, &entities.ImportRowError{Line: d.line, Message: "invalid JSON: " + err.Error()}
+		}
+		record.ParentSKU = strings.TrimSpace(record.ParentSKU)
+		record.SKU = strings.TrimSpace(record.SKU)
+
+		return record, d.line, nil
+	}
+
+	if err := d.scanner.Err(); err != nil {
+		return nil, 0, fmt.Errorf("invalid JSON Lines file: %w", err)
+	}
+	return nil, 0, io.EOF
+}
+
+// setCatalogField parses a CSV cell into the record; empty cells are left unset
+func setCatalogField(record *CatalogRecord, column, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	var err error
+	switch column {
+	case "parent_sku":
+		record.ParentSKU = value
+	case "sku":
+		record.SKU = value
+	case "barcode":
+		record.Barcode = value
+	case "name":
+		record.Name = value
+	case "description":
+		record.Description = value
+	case "short_description":
+		record.ShortDescription = value
+	case "price":
+		record.Price, err = parseCatalogFloat(value)
+	case "compare_price":
+		record.ComparePrice, err = parseCatalogFloat(value)
+	case "cost_price":
+		record.CostPrice, err = parseCatalogFloat(value)
+	case "stock_quantity":
+		var n int
+		n, err = strconv.Atoi(value)
+		record.StockQuantity = &n
+	case "track_stock":
+		record.TrackStock, err = parseCatalogBool(value)
+	case "allow_backorder":
+		record.AllowBackorder, err = parseCatalogBool(value)
+	case "weight":
+		record.Weight, err = parseCatalogFloat(value)
+	case "length":
+		record.Length, err = parseCatalogFloat(value)
+	case "width":
+		record.Width, err = parseCatalogFloat(value)
+	case "height":
+		record.Height, err = parseCatalogFloat(value)
+	case "category":
+		record.Category = value
+	case "brand":
+		record.Brand = value
+	case "status":
+		record.Status = value
+	case "visibility":
+		record.Visibility = value
+	case "featured":
+		record.Featured, err = parseCatalogBool(value)
+	case "tags":
+		record.Tags = splitCatalogList(value)
+	case "images":
+		record.Images = splitCatalogList(value)
+	case "attributes":
+		record.Attributes, err = parseCatalogAttributes(value)
+	case "slug":
+		record.Slug = value
+	case "meta_title":
+		record.MetaTitle = value
+	case "meta_description":
+		record.MetaDescription = value
+	}
+
+	if err != nil {
+		return errors.New("invalid value " + strconv.Quote(value))
+	}
+	return nil
+}
+
+func parseCatalogFloat(value string) (*float64, error) {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func parseCatalogBool(value string) (*bool, error) {
+	b, err := strconv.ParseBool(strings.ToLower(value))
+	if err != nil {
+		switch strings.ToLower(value) {
+		case "yes", "y":
+			b, err = true, nil
+		case "no", "n":
+			b, err = false, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func splitCatalogList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, catalogListSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func parseCatalogAttributes(value string) (map[string]string, error) {
+	attributes := make(map[string]string)
+	for _, pair := range splitCatalogList(value) {
+		key, val, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("attribute %q must be key=value", pair)
+		}
+		attributes[key] = strings.TrimSpace(val)
+	}
+	return attributes, nil
+}
+
+// catalogEncoder writes catalog records in a file format
+type catalogEncoder interface {
+	Encode(record *CatalogRecord) error
+	Flush() error
+}
+
+// newCatalogEncoder creates an encoder for the given format, writing the CSV header
+// immediately
+func newCatalogEncoder(format string, w io.Writer) (catalogEncoder, error) {
+	switch format {
+	case entities.CatalogFormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(catalogColumns); err != nil {
+			return nil, err
+		}
+		return &csvCatalogEncoder{writer: writer}, nil
+	case entities.CatalogFormatJSONL:
+		buffered := bufio.NewWriter(w)
+		return &jsonlCatalogEncoder{writer: buffered, encoder: json.NewEncoder(buffered)}, nil
+	default:
+		return nil, errors.New("format must be csv or jsonl")
+	}
+}
+
+type csvCatalogEncoder struct {
+	writer *csv.Writer
+}
+
+func (e *csvCatalogEncoder) Encode(record *CatalogRecord) error {
+	row := make([]string, len(catalogColumns))
+	for i, column := range catalogColumns {
+		row[i] = catalogFieldString(record, column)
+	}
+	return e.writer.Write(row)
+}
+
+func (e *csvCatalogEncoder) Flush() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+type jsonlCatalogEncoder struct {
+	writer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+func (e *jsonlCatalogEncoder) Encode(record *CatalogRecord) error {
+	return e.encoder.Encode(record)
+}
+
+func (e *jsonlCatalogEncoder) Flush() error {
+	return e.writer.Flush()
+}
+
+// catalogFieldString formats a record field as a CSV cell
+func catalogFieldString(record *CatalogRecord, column string) string {
+	formatFloat := func(f *float64) string {
+		if f == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*f, 'f', -1, 64)
+	}
+	formatBool := func(b *bool) string {
+		if b == nil {
+			return ""
+		}
+		return strconv.FormatBool(*b)
+	}
+
+	switch column {
+	case "parent_sku":
+		return record.ParentSKU
+	case "sku":
+		return record.SKU
+	case "barcode":
+		return record.Barcode
+	case "name":
+		return record.Name
+	case "description":
+		return record.Description
+	case "short_description":
+		return record.ShortDescription
+	case "price":
+		return formatFloat(record.Price)
+	case "compare_price":
+		return formatFloat(record.ComparePrice)
+	case "cost_price":
+		return formatFloat(record.CostPrice)
+	case "stock_quantity":
+		if record.StockQuantity == nil {
+			return ""
+		}
+		return strconv.Itoa(*record.StockQuantity)
+	case "track_stock":
+		return formatBool(record.TrackStock)
+	case "allow_backorder":
+		return formatBool(record.AllowBackorder)
+	case "weight":
+		return formatFloat(record.Weight)
+	case "length":
+		return formatFloat(record.Length)
+	case "width":
+		return formatFloat(record.Width)
+	case "height":
+		return formatFloat(record.Height)
+	case "category":
+		return record.Category
+	case "brand":
+		return record.Brand
+	case "status":
+		return record.Status
+	case "visibility":
+		return record.Visibility
+	case "featured":
+		return formatBool(record.Featured)
+	case "tags":
+		return strings.Join(record.Tags, catalogListSeparator)
+	case "images":
+		return strings.Join(record.Images, catalogListSeparator)
+	case "attributes":
+		keys := make([]string, 0, len(record.Attributes))
+		for key := range record.Attributes {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		pairs := make([]string, len(keys))
+		for i, key := range keys {
+			pairs[i] = key + "=" + record.Attributes[key]
+		}
+		return strings.Join(pairs, catalogListSeparator)
+	case "slug":
+		return record.Slug
+	case "meta_title":
+		return record.MetaTitle
+	case "meta_description":
+		return record.MetaDescription
+	}
+	return ""
+}
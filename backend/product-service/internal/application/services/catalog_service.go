@@ -0,0 +1,1293 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+const (
+	catalogImportChunkSize = 100
+	catalogExportBatchSize = 200
+	maxDryRunErrors        = 1000
+)
+
+var productStatusNames = map[entities.ProductStatus]string{
+	entities.ProductStatusDraft:    "draft",
+	entities.ProductStatusActive:   "active",
+	entities.ProductStatusInactive: "inactive",
+	entities.ProductStatusArchived: "archived",
+}
+
+var visibilityNames = map[entities.Visibility]string{
+	entities.VisibilityHidden:   "hidden",
+	entities.VisibilityVisible:  "visible",
+	entities.VisibilityFeatured: "featured",
+}
+
+// CatalogService handles bulk catalog import and export
+type CatalogService struct {
+	productRepo      repositories.ProductRepository
+	categoryRepo     repositories.CategoryRepository
+	brandRepo        repositories.BrandRepository
+	variantRepo      repositories.ProductVariantRepository
+	imageRepo        repositories.ProductImageRepository
+	catalogRepo      repositories.CatalogRepository
+	attributeSchemas *AttributeSchemaService
+	revisions        *RevisionService
+	bundles          *BundleService
+	importDir        string
+
+	mu      sync.Mutex
+	running map[uuid.UUID]bool
+}
+
+// NewCatalogService creates a new catalog service. Import files are kept in importDir
+// so that failed imports can be resumed.
+func NewCatalogService(
+	productRepo repositories.ProductRepository,
+	categoryRepo repositories.CategoryRepository,
+	brandRepo repositories.BrandRepository,
+	variantRepo repositories.ProductVariantRepository,
+	imageRepo repositories.ProductImageRepository,
+	catalogRepo repositories.CatalogRepository,
+	attributeSchemas *AttributeSchemaService,
+	revisions *RevisionService,
+	bundles *BundleService,
+	importDir string,
+) *CatalogService {
+	return &CatalogService{
+		productRepo:      productRepo,
+		categoryRepo:     categoryRepo,
+		brandRepo:        brandRepo,
+		variantRepo:      variantRepo,
+		imageRepo:        imageRepo,
+		catalogRepo:      catalogRepo,
+		attributeSchemas: attributeSchemas,
+		revisions:        revisions,
+		bundles:          bundles,
+		importDir:        importDir,
+		running:          make(map[uuid.UUID]bool),
+	}
+}
+
+// ValidateImport runs an import as a dry run: every row is validated exactly as an
+// applied import would, but nothing is written
+func (s *CatalogService) ValidateImport(ctx context.Context, format, mode string, r io.Reader) (*ImportReport, error) {
+	if mode != entities.ImportModeCreate && mode != entities.ImportModeUpsert {
+		return nil, errors.New("mode must be create or upsert")
+	}
+
+	decoder, err := newCatalogDecoder(format, r)
+	if err != nil {
+		return nil, err
+	}
+
+	importer := s.newCatalogImporter(mode, true)
+	report := &ImportReport{Errors: []entities.ImportRowError{}}
+	for {
+		rows, readErrors, done, err := readCatalogChunk(decoder, catalogImportChunkSize)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := importer.prepare(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+
+		failed := result.failed + len(readErrors)
+		report.TotalRows += len(rows) + len(readErrors)
+		report.FailedRows += failed
+		report.ValidRows += len(rows) - result.failed
+		report.Creates += result.created
+		report.Updates += result.updated
+		for _, rowErr := range mergeRowErrors(readErrors, result.errors) {
+			if len(report.Errors) >= maxDryRunErrors {
+				report.ErrorsTruncated = true
+				break
+			}
+			report.Errors = append(report.Errors, rowErr)
+		}
+
+		if done {
+			return report, nil
+		}
+	}
+}
+
+// StartImport stores the import file, creates a job and applies it in the background
+func (s *CatalogService) StartImport(ctx context.Context, format, mode, fileName string, r io.Reader) (*entities.ImportJob, error) {
+	if err := os.MkdirAll(s.importDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create import directory: %w", err)
+	}
+
+	path := filepath.Join(s.importDir, uuid.NewString()+"."+format)
+	checksum, err := storeImportFile(path, r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store import file: %w", err)
+	}
+
+	totalRows, err := countImportRows(format, path)
+	if err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+
+	job, err := entities.NewImportJob(format, mode, fileName, path, checksum, totalRows)
+	if err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+	job.CreatedBy = ActorFromContext(ctx)
+
+	if err := s.catalogRepo.CreateImportJob(ctx, job); err != nil {
+		os.Remove(path)
+		return nil, fmt.Errorf("failed to create import job: %w", err)
+	}
+
+	if err := s.launchImport(job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// ResumeImport continues a failed or interrupted import after its last committed chunk
+func (s *CatalogService) ResumeImport(ctx context.Context, id uuid.UUID) (*entities.ImportJob, error) {
+	job, err := s.catalogRepo.GetImportJob(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get import job: %w", err)
+	}
+	if job == nil {
+		return nil, errors.New("import job not found")
+	}
+	if !job.CanResume() {
+		return nil, errors.New("import job cannot be resumed")
+	}
+
+	checksum, err := fileChecksum(job.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read import file: %w", err)
+	}
+	if checksum != job.Checksum {
+		return nil, errors.New("import file has changed since the job was created")
+	}
+
+	if err := s.launchImport(job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// GetImportJob gets an import job
+func (s *CatalogService) GetImportJob(ctx context.Context, id uuid.UUID) (*entities.ImportJob, error) {
+	job, err := s.catalogRepo.GetImportJob(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get import job: %w", err)
+	}
+	if job == nil {
+		return nil, errors.New("import job not found")
+	}
+	return job, nil
+}
+
+// ExportCatalog streams every product, followed by its variants, in the import format
+func (s *CatalogService) ExportCatalog(ctx context.Context, format string, w io.Writer) error {
+	encoder, err := newCatalogEncoder(format, w)
+	if err != nil {
+		return err
+	}
+
+	err = s.catalogRepo.StreamProducts(ctx, catalogExportBatchSize, func(products []*entities.Product) error {
+		for _, product := range products {
+			if err := encoder.Encode(productCatalogRecord(product)); err != nil {
+				return err
+			}
+			for i := range product.Variants {
+				if err := encoder.Encode(variantCatalogRecord(product, &product.Variants[i])); err != nil {
+					return err
+				}
+			}
+		}
+		// Flush per batch so the response streams instead of buffering the catalog
+		return encoder.Flush()
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export catalog: %w", err)
+	}
+
+	return encoder.Flush()
+}
+
+// launchImport runs an import in the background unless it is already running here
+func (s *CatalogService) launchImport(job *entities.ImportJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running[job.ID] {
+		return errors.New("import job is already running")
+	}
+	s.running[job.ID] = true
+
+	go func() {
+		defer func() {
+			s.mu.Lock()
+			delete(s.running, job.ID)
+			s.mu.Unlock()
+		}()
+		s.runImport(context.Background(), job)
+	}()
+
+	return nil
+}
+
+// runImport applies an import file chunk by chunk. Each chunk and the job's progress
+// are committed together; on failure the job keeps the progress of the last committed
+// chunk and can be resumed.
+func (s *CatalogService) runImport(ctx context.Context, job *entities.ImportJob) {
+	ctx = WithActor(ctx, job.CreatedBy)
+	fail := func(err error) {
+		job.Fail(err)
+		if updateErr := s.catalogRepo.UpdateImportJob(ctx, job); updateErr != nil {
+			log.Printf("Failed to save import job %s: %v", job.ID, updateErr)
+		}
+	}
+
+	if err := job.Start(); err != nil {
+		fail(err)
+		return
+	}
+	if err := s.catalogRepo.UpdateImportJob(ctx, job); err != nil {
+		log.Printf("Failed to start import job %s: %v", job.ID, err)
+		return
+	}
+
+	file, err := os.Open(job.FilePath)
+	if err != nil {
+		fail(fmt.Errorf("failed to open import file: %w", err))
+		return
+	}
+	defer file.Close()
+
+	decoder, err := newCatalogDecoder(job.Format, file)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	// Skip rows committed by an earlier run
+	for skipped := 0; skipped < job.ProcessedRows; skipped++ {
+		if _, _, err := decoder.Next(); err == io.EOF {
+			break
+		} else if err != nil && !isImportRowError(err) {
+			fail(err)
+			return
+		}
+	}
+
+	importer := s.newCatalogImporter(job.Mode, false)
+	for {
+		rows, readErrors, done, err := readCatalogChunk(decoder, catalogImportChunkSize)
+		if err != nil {
+			fail(err)
+			return
+		}
+
+		result, err := importer.prepare(ctx, rows)
+		if err != nil {
+			fail(err)
+			return
+		}
+
+		// Products changed by someone else since the chunk was prepared are not
+		// overwritten: their rows are reported as failed and the rest is applied again
+		for {
+			previous := *job
+			job.RecordChunk(len(rows)+len(readErrors), result.created, result.updated,
+				result.failed+len(readErrors), mergeRowErrors(readErrors, result.errors))
+			err := s.applyChunk(ctx, job, result)
+			if err == nil {
+				break
+			}
+			*job = previous
+
+			var conflict *repositories.CatalogConflictError
+			if !errors.As(err, &conflict) {
+				fail(fmt.Errorf("failed to apply rows after %d: %w", job.ProcessedRows, err))
+				return
+			}
+			result.dropProducts(conflict.ProductIDs)
+		}
+
+		if done {
+			break
+		}
+	}
+
+	job.Complete()
+	if err := s.catalogRepo.UpdateImportJob(ctx, job); err != nil {
+		log.Printf("Failed to complete import job %s: %v", job.ID, err)
+	}
+}
+
+// applyChunk writes a chunk with the revisions recording its changes, in one transaction
+func (s *CatalogService) applyChunk(ctx context.Context, job *entities.ImportJob, result *catalogChunkResult) error {
+	return s.revisions.WithinTransaction(ctx, func(ctx context.Context) error {
+		imageRevisions, err := s.imageRevisions(ctx, &result.chunk)
+		if err != nil {
+			return err
+		}
+		if err := s.catalogRepo.ApplyImportChunk(ctx, job, &result.chunk); err != nil {
+			return err
+		}
+		if err := s.revisions.Record(ctx, append(result.revisions, imageRevisions...)...); err != nil {
+			return err
+		}
+		return s.bundles.RefreshBundles(ctx, result.changedProductIDs()...)
+	})
+}
+
+// imageRevisions records the chunk's image lists replacing the current images of their
+// products and variants. It reads the current images, so it runs in the transaction
+// that applies the chunk.
+func (s *CatalogService) imageRevisions(ctx context.Context, chunk *repositories.CatalogChunk) ([]*entities.ProductRevision, error) {
+	actor := ActorFromContext(ctx)
+	var revisions []*entities.ProductRevision
+	replace := func(productID uuid.UUID, current, images []*entities.ProductImage) {
+		for _, image := range current {
+			revisions = append(revisions, entities.NewProductRevision(productID, entities.RevisionEntityImage, image.ID, entities.RevisionActionDelete, actor, image, nil))
+		}
+		for _, image := range images {
+			revisions = append(revisions, entities.NewProductRevision(productID, entities.RevisionEntityImage, image.ID, entities.RevisionActionCreate, actor, nil, image))
+		}
+	}
+
+	for productID, images := range chunk.ProductImages {
+		current, err := s.imageRepo.GetByProductID(ctx, productID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get images: %w", err)
+		}
+		replace(productID, current, images)
+	}
+
+	for _, variant := range chunk.Variants {
+		images, ok := chunk.VariantImages[variant.ID]
+		if !ok {
+			continue
+		}
+		current, err := s.imageRepo.GetByVariantID(ctx, variant.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get variant images: %w", err)
+		}
+		replace(variant.ProductID, current, images)
+	}
+
+	return revisions, nil
+}
+
+// catalogRow is a decoded record and the line it started on
+type catalogRow struct {
+	Line   int
+	Record *CatalogRecord
+}
+
+// readCatalogChunk reads up to size rows. Unreadable rows count towards the chunk and
+// are returned as row errors so progress stays aligned with the file.
+func readCatalogChunk(decoder catalogDecoder, size int) ([]catalogRow, []entities.ImportRowError, bool, error) {
+	var rows []catalogRow
+	var readErrors []entities.ImportRowError
+	for len(rows)+len(readErrors) < size {
+		record, line, err := decoder.Next()
+		if err == io.EOF {
+			return rows, readErrors, true, nil
+		}
+		if err != nil {
+			var rowErr *entities.ImportRowError
+			if errors.As(err, &rowErr) {
+				readErrors = append(readErrors, *rowErr)
+				continue
+			}
+			return nil, nil, false, err
+		}
+		rows = append(rows, catalogRow{Line: line, Record: record})
+	}
+	return rows, readErrors, false, nil
+}
+
+func isImportRowError(err error) bool {
+	var rowErr *entities.ImportRowError
+	return errors.As(err, &rowErr)
+}
+
+// mergeRowErrors combines row errors in line order
+func mergeRowErrors(a, b []entities.ImportRowError) []entities.ImportRowError {
+	merged := make([]entities.ImportRowError, 0, len(a)+len(b))
+	merged = append(append(merged, a...), b...)
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].Line < merged[j].Line })
+	return merged
+}
+
+// storeImportFile copies r to path and returns the SHA-256 of its contents
+func storeImportFile(path string, r io.Reader) (string, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(file, hash), r); err != nil {
+		file.Close()
+		os.Remove(path)
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(path)
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func fileChecksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// countImportRows checks that the file can be read and counts its rows
+func countImportRows(format, path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	decoder, err := newCatalogDecoder(format, file)
+	if err != nil {
+		return 0, err
+	}
+
+	rows := 0
+	for {
+		_, _, err := decoder.Next()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil && !isImportRowError(err) {
+			return 0, err
+		}
+		rows++
+	}
+}
+
+// catalogImporter validates records against the catalog and turns them into chunks.
+// Lookups are cached for the whole import so each category or brand is resolved once.
+type catalogImporter struct {
+	s          *CatalogService
+	mode       string
+	dryRun     bool
+	categories map[string]uuid.UUID
+	brands     map[string]uuid.UUID
+	seenSKUs   map[string]int
+	seenSlugs  map[string]int
+
+	// pending holds products prepared but not committed, by SKU. A dry run commits
+	// nothing, so it keeps every product for variant rows further down the file.
+	pending map[string]*entities.Product
+}
+
+// catalogChunkResult is the outcome of preparing one chunk of rows
+type catalogChunkResult struct {
+	chunk   repositories.CatalogChunk
+	created int
+	updated int
+	failed  int
+	errors  []entities.ImportRowError
+
+	inChunk map[uuid.UUID]bool
+	// revisions record the chunk's product and variant changes; image revisions are
+	// made when the chunk is applied
+	revisions []*entities.ProductRevision
+	// applied lists the rows making the chunk's changes, so they can be reported as
+	// failed if their product's changes are dropped
+	applied []catalogAppliedRow
+}
+
+// catalogAppliedRow is a valid row and the product it changes
+type catalogAppliedRow struct {
+	line      int
+	sku       string
+	productID uuid.UUID
+	created   bool
+}
+
+// changedProductIDs returns the products written by the chunk, and the products of the
+// variants it wrote
+func (r *catalogChunkResult) changedProductIDs() []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(r.chunk.Products)+len(r.chunk.Variants))
+	for _, product := range r.chunk.Products {
+		ids = append(ids, product.ID)
+	}
+	for _, variant := range r.chunk.Variants {
+		ids = append(ids, variant.ProductID)
+	}
+	return uniqueIDs(ids)
+}
+
+func (r *catalogChunkResult) addProduct(product *entities.Product) {
+	if !r.inChunk[product.ID] {
+		r.inChunk[product.ID] = true
+		r.chunk.Products = append(r.chunk.Products, product)
+	}
+}
+
+// addRow counts a valid row as a creation or an update of its product
+func (r *catalogChunkResult) addRow(row catalogRow, productID uuid.UUID, created bool) {
+	r.applied = append(r.applied, catalogAppliedRow{line: row.Line, sku: row.Record.SKU, productID: productID, created: created})
+	if created {
+		r.created++
+	} else {
+		r.updated++
+	}
+}
+
+// dropProducts removes the changes to the given products, and to their variants and
+// images, from the chunk and reports the rows that made them as failed
+func (r *catalogChunkResult) dropProducts(productIDs []uuid.UUID) {
+	dropped := make(map[uuid.UUID]bool, len(productIDs))
+	for _, id := range productIDs {
+		dropped[id] = true
+	}
+
+	products := r.chunk.Products[:0]
+	for _, product := range r.chunk.Products {
+		if dropped[product.ID] {
+			delete(r.inChunk, product.ID)
+			delete(r.chunk.ProductImages, product.ID)
+		} else {
+			products = append(products, product)
+		}
+	}
+	r.chunk.Products = products
+
+	variants := r.chunk.Variants[:0]
+	for _, variant := range r.chunk.Variants {
+		if dropped[variant.ProductID] {
+			delete(r.chunk.VariantImages, variant.ID)
+		} else {
+			variants = append(variants, variant)
+		}
+	}
+	r.chunk.Variants = variants
+
+	redirects := r.chunk.SlugRedirects[:0]
+	for _, redirect := range r.chunk.SlugRedirects {
+		if !dropped[redirect.EntityID] {
+			redirects = append(redirects, redirect)
+		}
+	}
+	r.chunk.SlugRedirects = redirects
+
+	revisions := r.revisions[:0]
+	for _, revision := range r.revisions {
+		if revision != nil && !dropped[revision.ProductID] {
+			revisions = append(revisions, revision)
+		}
+	}
+	r.revisions = revisions
+
+	applied := r.applied[:0]
+	for _, row := range r.applied {
+		if !dropped[row.productID] {
+			applied = append(applied, row)
+			continue
+		}
+		if row.created {
+			r.created--
+		} else {
+			r.updated--
+		}
+		r.failed++
+		r.errors = append(r.errors, entities.ImportRowError{Line: row.line, SKU: row.sku, Message: repositories.ErrVersionConflict.Error()})
+	}
+	r.applied = applied
+}
+
+func (s *CatalogService) newCatalogImporter(mode string, dryRun bool) *catalogImporter {
+	return &catalogImporter{
+		s:          s,
+		mode:       mode,
+		dryRun:     dryRun,
+		categories: make(map[string]uuid.UUID),
+		brands:     make(map[string]uuid.UUID),
+		seenSKUs:   make(map[string]int),
+		seenSlugs:  make(map[string]int),
+		pending:    make(map[string]*entities.Product),
+	}
+}
+
+// prepare validates a chunk of rows and builds the changes for the valid ones. Invalid
+// rows are reported and skipped; the returned error is reserved for lookup failures.
+func (i *catalogImporter) prepare(ctx context.Context, rows []catalogRow) (*catalogChunkResult, error) {
+	result := &catalogChunkResult{
+		chunk: repositories.CatalogChunk{
+			ExpectedVersions: make(map[uuid.UUID]int),
+			ProductImages:    make(map[uuid.UUID][]*entities.ProductImage),
+			VariantImages:    make(map[uuid.UUID][]*entities.ProductImage),
+		},
+		inChunk: make(map[uuid.UUID]bool),
+	}
+	if !i.dryRun {
+		i.pending = make(map[string]*entities.Product)
+	}
+
+	// Load every product and variant the chunk refers to in two queries
+	var productSKUs, variantSKUs []string
+	for _, row := range rows {
+		if row.Record.IsVariant() {
+			variantSKUs = append(variantSKUs, row.Record.SKU)
+			productSKUs = append(productSKUs, row.Record.ParentSKU)
+		} else {
+			productSKUs = append(productSKUs, row.Record.SKU)
+		}
+	}
+
+	products, err := i.loadProducts(ctx, productSKUs)
+	if err != nil {
+		return nil, err
+	}
+	// Stored products are only written over the version read here
+	for sku, product := range products {
+		if _, pending := i.pending[sku]; !pending {
+			result.chunk.ExpectedVersions[product.ID] = product.Version
+		}
+	}
+
+	variants := make(map[string]*entities.ProductVariant)
+	if len(variantSKUs) > 0 {
+		existing, err := i.s.variantRepo.GetBySKUs(ctx, variantSKUs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load variants: %w", err)
+		}
+		for _, variant := range existing {
+			variants[variant.SKU] = variant
+		}
+	}
+
+	for _, row := range rows {
+		var rowErr *entities.ImportRowError
+		if row.Record.IsVariant() {
+			rowErr, err = i.prepareVariant(ctx, row, products, variants, result)
+		} else {
+			rowErr, err = i.prepareProduct(ctx, row, products, result)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if rowErr != nil {
+			result.failed++
+			result.errors = append(result.errors, *rowErr)
+		}
+	}
+
+	return result, nil
+}
+
+// loadProducts returns products by SKU, preferring uncommitted ones from this import
+func (i *catalogImporter) loadProducts(ctx context.Context, skus []string) (map[string]*entities.Product, error) {
+	products := make(map[string]*entities.Product, len(skus))
+
+	var missing []string
+	for _, sku := range skus {
+		if product, ok := i.pending[sku]; ok {
+			products[sku] = product
+		} else if sku != "" {
+			missing = append(missing, sku)
+		}
+	}
+
+	if len(missing) > 0 {
+		existing, err := i.s.productRepo.GetBySKUs(ctx, missing)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load products: %w", err)
+		}
+		for _, product := range existing {
+			products[product.SKU] = product
+		}
+	}
+
+	return products, nil
+}
+
+func (i *catalogImporter) prepareProduct(ctx context.Context, row catalogRow, products map[string]*entities.Product, result *catalogChunkResult) (*entities.ImportRowError, error) {
+	rec := row.Record
+	rowErr := func(field string, err error) *entities.ImportRowError {
+		return &entities.ImportRowError{Line: row.Line, SKU: rec.SKU, Field: field, Message: err.Error()}
+	}
+
+	if rowErr := i.checkSKU(row, rowErr); rowErr != nil {
+		return rowErr, nil
+	}
+
+	current := products[rec.SKU]
+	if current != nil && i.mode == entities.ImportModeCreate {
+		return rowErr("sku", errors.New("product with this SKU already exists")), nil
+	}
+
+	// Work on a copy so a row that fails half-way leaves the loaded product untouched
+	var product *entities.Product
+	if current == nil {
+		if rec.Price == nil {
+			return rowErr("price", errors.New("price is required")), nil
+		}
+		if rec.Category == "" {
+			return rowErr("category", errors.New("category is required")), nil
+		}
+		categoryID, found, err := i.resolveCategory(ctx, rec.Category)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return rowErr("category", errors.New("category not found")), nil
+		}
+
+		product, err = entities.NewProduct(rec.SKU, rec.Name, rec.Description, *rec.Price, categoryID)
+		if err != nil {
+			return rowErr("", err), nil
+		}
+	} else {
+		copied := *current
+		copied.Attributes = copyStringMap(current.Attributes)
+		product = &copied
+
+		if rec.Category != "" {
+			categoryID, found, err := i.resolveCategory(ctx, rec.Category)
+			if err != nil {
+				return nil, err
+			}
+			if !found {
+				return rowErr("category", errors.New("category not found")), nil
+			}
+			if err := product.SetCategory(categoryID); err != nil {
+				return rowErr("category", err), nil
+			}
+		}
+	}
+
+	field, err := i.applyProductRecord(ctx, product, rec)
+	if err != nil {
+		var lookupErr *catalogLookupError
+		if errors.As(err, &lookupErr) {
+			return nil, lookupErr.err
+		}
+		return rowErr(field, err), nil
+	}
+
+	// Attributes are checked against the category schema as the product API does: on
+	// creation, and when the record sets attributes or moves the product
+	if current == nil || rec.Attributes != nil || product.CategoryID != current.CategoryID {
+		attributes, err := i.s.attributeSchemas.ValidateAttributes(ctx, product.CategoryID, product.Attributes)
+		if err != nil {
+			if strings.HasPrefix(err.Error(), "failed to") {
+				return nil, err
+			}
+			return rowErr("attributes", err), nil
+		}
+		product.Attributes = attributes
+	}
+
+	if current == nil || product.Slug != current.Slug {
+		if first, ok := i.seenSlugs[product.Slug]; ok {
+			return rowErr("slug", fmt.Errorf("slug %q is already used on line %d", product.Slug, first)), nil
+		}
+		exists, err := i.s.productRepo.ExistsBySlug(ctx, product.Slug)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check slug existence: %w", err)
+		}
+		if exists {
+			return rowErr("slug", fmt.Errorf("slug %q is already in use", product.Slug)), nil
+		}
+		i.seenSlugs[product.Slug] = row.Line
+		if current != nil {
+			result.chunk.SlugRedirects = append(result.chunk.SlugRedirects, entities.NewSlugRedirect(entities.SlugEntityProduct, product.ID, current.Slug))
+		}
+	}
+
+	if len(rec.Images) > 0 {
+		images, err := buildCatalogImages(rec.Images, product.Name, &product.ID, nil)
+		if err != nil {
+			return rowErr("images", err), nil
+		}
+		result.chunk.ProductImages[product.ID] = images
+	}
+
+	actor := ActorFromContext(ctx)
+	action := entities.RevisionActionUpdate
+	if current == nil {
+		action = entities.RevisionActionCreate
+		product.CreatedBy = actor
+	}
+	product.UpdatedBy = actor
+
+	products[rec.SKU] = product
+	i.pending[rec.SKU] = product
+	result.addProduct(product)
+	result.addRow(row, product.ID, current == nil)
+	result.revisions = append(result.revisions, entities.NewProductRevision(product.ID, entities.RevisionEntityProduct, product.ID, action, actor, current, product))
+
+	return nil, nil
+}
+
+// applyProductRecord applies the record's fields through the same entity methods the
+// product API uses. Fields left empty keep the product's current value.
+func (i *catalogImporter) applyProductRecord(ctx context.Context, p *entities.Product, rec *CatalogRecord) (string, error) {
+	err := p.UpdateBasicInfo(
+		stringOr(rec.Name, p.Name),
+		stringOr(rec.Description, p.Description),
+		stringOr(rec.ShortDescription, p.ShortDesc),
+	)
+	if err != nil {
+		return "name", err
+	}
+	if rec.Slug != "" {
+		p.Slug = rec.Slug
+	}
+
+	err = p.UpdatePricing(
+		floatOr(rec.Price, p.Price),
+		floatOr(rec.ComparePrice, p.ComparePrice),
+		floatOr(rec.CostPrice, p.CostPrice),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	err = p.UpdateInventory(
+		intOr(rec.StockQuantity, p.StockQuantity),
+		boolOr(rec.TrackStock, p.TrackStock),
+		boolOr(rec.AllowBackorder, p.AllowBackorder),
+	)
+	if err != nil {
+		return "stock_quantity", err
+	}
+
+	err = p.UpdatePhysicalProperties(
+		floatOr(rec.Weight, p.Weight),
+		floatOr(rec.Length, p.Length),
+		floatOr(rec.Width, p.Width),
+		floatOr(rec.Height, p.Height),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	if rec.MetaTitle != "" || rec.MetaDescription != "" || rec.Tags != nil {
+		tags := p.Tags
+		if rec.Tags != nil {
+			tags = rec.Tags
+		}
+		p.UpdateSEO(stringOr(rec.MetaTitle, p.MetaTitle), stringOr(rec.MetaDescription, p.MetaDesc), tags)
+	}
+
+	if rec.Brand != "" {
+		brandID, found, err := i.resolveBrand(ctx, rec.Brand)
+		if err != nil {
+			return "", &catalogLookupError{err: err}
+		}
+		if !found {
+			return "brand", errors.New("brand not found")
+		}
+		p.SetBrand(&brandID)
+	}
+
+	if rec.Status != "" {
+		status, err := parseProductStatus(rec.Status)
+		if err != nil {
+			return "status", err
+		}
+		p.SetStatus(status)
+	}
+
+	if rec.Visibility != "" {
+		visibility, err := parseVisibility(rec.Visibility)
+		if err != nil {
+			return "visibility", err
+		}
+		p.SetVisibility(visibility)
+	}
+
+	if rec.Featured != nil {
+		p.SetFeatured(*rec.Featured)
+	}
+
+	// Attributes in the file replace the product's attributes
+	if rec.Attributes != nil {
+		p.Attributes = make(map[string]string, len(rec.Attributes))
+		for key, value := range rec.Attributes {
+			p.AddAttribute(key, value)
+		}
+	}
+
+	return "", nil
+}
+
+func (i *catalogImporter) prepareVariant(ctx context.Context, row catalogRow, products map[string]*entities.Product, variants map[string]*entities.ProductVariant, result *catalogChunkResult) (*entities.ImportRowError, error) {
+	rec := row.Record
+	rowErr := func(field string, err error) *entities.ImportRowError {
+		return &entities.ImportRowError{Line: row.Line, SKU: rec.SKU, Field: field, Message: err.Error()}
+	}
+
+	if rowErr := i.checkSKU(row, rowErr); rowErr != nil {
+		return rowErr, nil
+	}
+
+	parent := products[rec.ParentSKU]
+	if parent == nil {
+		return rowErr("parent_sku", errors.New("parent product not found")), nil
+	}
+	if parent.IsBundle {
+		return rowErr("parent_sku", errors.New("a bundle cannot have variants")), nil
+	}
+
+	current := variants[rec.SKU]
+	if current != nil && i.mode == entities.ImportModeCreate {
+		return rowErr("sku", errors.New("variant with this SKU already exists")), nil
+	}
+	if current != nil && current.ProductID != parent.ID {
+		return rowErr("parent_sku", errors.New("variant belongs to a different product")), nil
+	}
+
+	var variant *entities.ProductVariant
+	if current == nil {
+		var err error
+		variant, err = entities.NewProductVariant(parent.ID, rec.SKU, rec.Attributes)
+		if err != nil {
+			return rowErr("attributes", err), nil
+		}
+	} else {
+		copied := *current
+		copied.Attributes = copyStringMap(current.Attributes)
+		variant = &copied
+	}
+
+	attributes := variant.Attributes
+	if rec.Attributes != nil {
+		attributes = rec.Attributes
+	}
+	if err := variant.UpdateBasicInfo(rec.SKU, stringOr(rec.Barcode, variant.Barcode), attributes); err != nil {
+		return rowErr("attributes", err), nil
+	}
+
+	err := variant.UpdatePricing(
+		floatPtrOr(rec.Price, variant.Price),
+		floatPtrOr(rec.ComparePrice, variant.ComparePrice),
+		floatPtrOr(rec.CostPrice, variant.CostPrice),
+	)
+	if err != nil {
+		return rowErr("", err), nil
+	}
+
+	err = variant.UpdateInventory(
+		intOr(rec.StockQuantity, variant.StockQuantity),
+		boolOr(rec.TrackStock, variant.TrackStock),
+		boolOr(rec.AllowBackorder, variant.AllowBackorder),
+	)
+	if err != nil {
+		return rowErr("stock_quantity", err), nil
+	}
+
+	err = variant.UpdatePhysicalProperties(
+		floatPtrOr(rec.Weight, variant.Weight),
+		floatPtrOr(rec.Length, variant.Length),
+		floatPtrOr(rec.Width, variant.Width),
+		floatPtrOr(rec.Height, variant.Height),
+	)
+	if err != nil {
+		return rowErr("", err), nil
+	}
+
+	if len(rec.Images) > 0 {
+		images, err := buildCatalogImages(rec.Images, parent.Name, nil, &variant.ID)
+		if err != nil {
+			return rowErr("images", err), nil
+		}
+		result.chunk.VariantImages[variant.ID] = images
+	}
+
+	actor := ActorFromContext(ctx)
+	action := entities.RevisionActionUpdate
+	if current == nil {
+		action = entities.RevisionActionCreate
+		variant.CreatedBy = actor
+	}
+	variant.UpdatedBy = actor
+
+	variants[rec.SKU] = variant
+	result.chunk.Variants = append(result.chunk.Variants, variant)
+	result.revisions = append(result.revisions, entities.NewProductRevision(parent.ID, entities.RevisionEntityVariant, variant.ID, action, actor, current, variant))
+	if !parent.HasVariants {
+		parent.HasVariants = true
+		parent.UpdatedAt = time.Now()
+		result.addProduct(parent)
+	}
+	result.addRow(row, parent.ID, current == nil)
+
+	return nil, nil
+}
+
+// checkSKU rejects rows without a SKU or repeating one from earlier in the file
+func (i *catalogImporter) checkSKU(row catalogRow, rowErr func(string, error) *entities.ImportRowError) *entities.ImportRowError {
+	sku := row.Record.SKU
+	if sku == "" {
+		return rowErr("sku", errors.New("SKU is required"))
+	}
+	if first, ok := i.seenSKUs[sku]; ok {
+		return rowErr("sku", fmt.Errorf("duplicate SKU, first used on line %d", first))
+	}
+	i.seenSKUs[sku] = row.Line
+	return nil
+}
+
+// resolveCategory finds a category by ID or slug
+func (i *catalogImporter) resolveCategory(ctx context.Context, ref string) (uuid.UUID, bool, error) {
+	if id, ok := i.categories[ref]; ok {
+		return id, true, nil
+	}
+
+	var category *entities.Category
+	var err error
+	if id, parseErr := uuid.Parse(ref); parseErr == nil {
+		category, err = i.s.categoryRepo.GetByID(ctx, id)
+	} else {
+		category, err = i.s.categoryRepo.GetBySlug(ctx, strings.ToLower(ref))
+	}
+	if err != nil {
+		return uuid.Nil, false, fmt.Errorf("failed to get category: %w", err)
+	}
+	if category == nil {
+		return uuid.Nil, false, nil
+	}
+
+	i.categories[ref] = category.ID
+	return category.ID, true, nil
+}
+
+// resolveBrand finds a brand by ID, slug or name
+func (i *catalogImporter) resolveBrand(ctx context.Context, ref string) (uuid.UUID, bool, error) {
+	if id, ok := i.brands[ref]; ok {
+		return id, true, nil
+	}
+
+	var brand *entities.Brand
+	var err error
+	if id, parseErr := uuid.Parse(ref); parseErr == nil {
+		brand, err = i.s.brandRepo.GetByID(ctx, id)
+	} else {
+		brand, err = i.s.brandRepo.GetBySlug(ctx, strings.ToLower(ref))
+		if err == nil && brand == nil {
+			brand, err = i.s.brandRepo.GetByName(ctx, ref)
+		}
+	}
+	if err != nil {
+		return uuid.Nil, false, fmt.Errorf("failed to get brand: %w", err)
+	}
+	if brand == nil {
+		return uuid.Nil, false, nil
+	}
+
+	i.brands[ref] = brand.ID
+	return brand.ID, true, nil
+}
+
+// catalogLookupError wraps a repository failure met while applying a record, so it is
+// not mistaken for a problem with the row
+type catalogLookupError struct {
+	err error
+}
+
+func (e *catalogLookupError) Error() string { return e.err.Error() }
+
+// buildCatalogImages creates images for a product or variant; the first is primary
+func buildCatalogImages(urls []string, altText string, productID, variantID *uuid.UUID) ([]*entities.ProductImage, error) {
+	images := make([]*entities.ProductImage, 0, len(urls))
+	for position, url := range urls {
+		image, err := entities.NewProductImage(url, altText, productID, variantID)
+		if err != nil {
+			return nil, err
+		}
+		image.SetSortOrder(position)
+		image.SetPrimary(position == 0)
+		images = append(images, image)
+	}
+	return images, nil
+}
+
+// productCatalogRecord converts a product into an export record
+func productCatalogRecord(p *entities.Product) *CatalogRecord {
+	record := &CatalogRecord{
+		SKU:              p.SKU,
+		Name:             p.Name,
+		Description:      p.Description,
+		ShortDescription: p.ShortDesc,
+		Price:            &p.Price,
+		ComparePrice:     &p.ComparePrice,
+		CostPrice:        &p.CostPrice,
+		StockQuantity:    &p.StockQuantity,
+		TrackStock:       &p.TrackStock,
+		AllowBackorder:   &p.AllowBackorder,
+		Weight:           &p.Weight,
+		Length:           &p.Length,
+		Width:            &p.Width,
+		Height:           &p.Height,
+		Category:         p.CategoryID.String(),
+		Status:           productStatusNames[p.Status],
+		Visibility:       visibilityNames[p.Visibility],
+		Featured:         &p.Featured,
+		Tags:             p.Tags,
+		Attributes:       p.Attributes,
+		Slug:             p.Slug,
+		MetaTitle:        p.MetaTitle,
+		MetaDescription:  p.MetaDesc,
+	}
+
+	if p.Category != nil {
+		record.Category = p.Category.Slug
+	}
+	if p.Brand != nil {
+		record.Brand = p.Brand.Slug
+	} else if p.BrandID != nil {
+		record.Brand = p.BrandID.String()
+	}
+	for _, image := range p.Images {
+		record.Images = append(record.Images, image.URL)
+	}
+
+	return record
+}
+
+// variantCatalogRecord converts a variant into an export record. Unset overrides stay
+// empty so the variant keeps inheriting them from its product.
+func variantCatalogRecord(p *entities.Product, v *entities.ProductVariant) *CatalogRecord {
+	record := &CatalogRecord{
+		ParentSKU:      p.SKU,
+		SKU:            v.SKU,
+		Barcode:        v.Barcode,
+		Price:          v.Price,
+		ComparePrice:   v.ComparePrice,
+		CostPrice:      v.CostPrice,
+		StockQuantity:  &v.StockQuantity,
+		TrackStock:     &v.TrackStock,
+		AllowBackorder: &v.AllowBackorder,
+		Weight:         v.Weight,
+		Length:         v.Length,
+		Width:          v.Width,
+		Height:         v.Height,
+		Attributes:     v.Attributes,
+	}
+	for _, image := range v.Images {
+		record.Images = append(record.Images, image.URL)
+	}
+	return record
+}
+
+func parseProductStatus(value string) (entities.ProductStatus, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for status, name := range productStatusNames {
+		if value == name || value == strconv.Itoa(int(status)) {
+			return status, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown status %q", value)
+}
+
+func parseVisibility(value string) (entities.Visibility, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for visibility, name := range visibilityNames {
+		if value == name || value == strconv.Itoa(int(visibility)) {
+			return visibility, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown visibility %q", value)
+}
+
+func copyStringMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	copied := make(map[string]string, len(m))
+	for k, v := range m {
+		copied[k] = v
+	}
+	return copied
+}
+
+func stringOr(value, fallback string) string {
+	if value != "" {
+		return value
+	}
+	return fallback
+}
+
+func floatOr(value *float64, fallback float64) float64 {
+	if value != nil {
+		return *value
+	}
+	return fallback
+}
+
+func floatPtrOr(value, fallback *float64) *float64 {
+	if value != nil {
+		return value
+	}
+	return fallback
+}
+
+func intOr(value *int, fallback int) int {
+	if value != nil {
+		return *value
+	}
+	return fallback
+}
+
+func boolOr(value *bool, fallback bool) bool {
+	if value != nil {
+		return *value
+	}
+	return fallback
+}
+
+// ImportReport is the result of a dry-run import
+type ImportReport struct {
+	TotalRows       int                       `json:"total_rows"`
+	ValidRows       int                       `json:"valid_rows"`
+	FailedRows      int                       `json:"failed_rows"`
+	Creates         int                       `json:"creates"`
+	Updates         int                       `json:"updates"`
+	Errors          []entities.ImportRowError `json:"errors"`
+	ErrorsTruncated bool                      `json:"errors_truncated"`
+}
@@ -0,0 +1,494 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// CategoryService handles category business logic
+type CategoryService struct {
+	categoryRepo repositories.CategoryRepository
+	slugRepo     repositories.SlugRedirectRepository
+	suggestions  *SuggestionCache
+	transactions repositories.TransactionManager
+}
+
+// NewCategoryService creates a new category service
+func NewCategoryService(categoryRepo repositories.CategoryRepository, slugRepo repositories.SlugRedirectRepository, suggestions *SuggestionCache, transactions repositories.TransactionManager) *CategoryService {
+	return &CategoryService{
+		categoryRepo: categoryRepo,
+		slugRepo:     slugRepo,
+		suggestions:  suggestions,
+		transactions: transactions,
+	}
+}
+
+// CreateCategoryRequest represents a create category request
+type CreateCategoryRequest struct {
+	Name            string     `json:"name" validate:"required"`
+	Description     string     `json:"description"`
+	ParentID        *uuid.UUID `json:"parent_id"`
+	ImageURL        string     `json:"image_url"`
+	IconURL         string     `json:"icon_url"`
+	Color           string     `json:"color"`
+	SortOrder       int        `json:"sort_order"`
+	MetaTitle       string     `json:"meta_title"`
+	MetaDescription string     `json:"meta_description"`
+	IsActive        *bool      `json:"is_active"`
+	IsVisible       *bool      `json:"is_visible"`
+}
+
+// UpdateCategoryRequest represents an update category request. The parent is changed
+// with MoveCategory.
+type UpdateCategoryRequest struct {
+	Name            string `json:"name" validate:"required"`
+	Description     string `json:"description"`
+	ImageURL        string `json:"image_url"`
+	IconURL         string `json:"icon_url"`
+	Color           string `json:"color"`
+	SortOrder       int    `json:"sort_order"`
+	MetaTitle       string `json:"meta_title"`
+	MetaDescription string `json:"meta_description"`
+	IsActive        *bool  `json:"is_active"`
+	IsVisible       *bool  `json:"is_visible"`
+}
+
+// MoveCategoryRequest represents a request to move a category and its subtree
+type MoveCategoryRequest struct {
+	ParentID  *uuid.UUID `json:"parent_id"` // null moves the category to the top level
+	SortOrder *int       `json:"sort_order"`
+}
+
+// CategoryBreadcrumb is one step of the path from the root to a category
+type CategoryBreadcrumb struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	Slug string    `json:"slug"`
+	Path string    `json:"path"`
+}
+
+// CreateCategory creates a new category
+func (s *CategoryService) CreateCategory(ctx context.Context, req *CreateCategoryRequest) (*entities.Category, error) {
+	category, err := entities.NewCategory(req.Name, req.Description, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := s.categoryRepo.ExistsBySlug(ctx, category.Slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check slug existence: %w", err)
+	}
+	if exists {
+		return nil, errors.New("category with this slug already exists")
+	}
+
+	category.UpdateDisplay(req.ImageURL, req.IconURL, req.Color, req.SortOrder)
+	category.UpdateSEO(req.MetaTitle, req.MetaDescription)
+	if req.IsActive != nil {
+		category.SetActive(*req.IsActive)
+	}
+	if req.IsVisible != nil {
+		category.SetVisible(*req.IsVisible)
+	}
+
+	// The parent's path is locked so a concurrent move of it cannot leave the new
+	// category with a stale Path
+	err = s.transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.categoryRepo.LockHierarchy(ctx, nil, req.ParentID); err != nil {
+			return fmt.Errorf("failed to lock category tree: %w", err)
+		}
+		ancestors, err := s.parentPath(ctx, req.ParentID)
+		if err != nil {
+			return err
+		}
+		if err := placeCategory(category, ancestors); err != nil {
+			return err
+		}
+		if err := s.categoryRepo.Create(ctx, category); err != nil {
+			return fmt.Errorf("failed to create category: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.suggestions.clear()
+
+	return category, nil
+}
+
+// GetCategory retrieves a category by ID
+func (s *CategoryService) GetCategory(ctx context.Context, id uuid.UUID) (*entities.Category, error) {
+	category, err := s.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+	if category == nil {
+		return nil, errors.New("category not found")
+	}
+	return category, nil
+}
+
+// GetCategoryBySlug retrieves a category by slug. A category found by a slug it used to
+// have is returned too; its Slug then differs from the one asked for.
+func (s *CategoryService) GetCategoryBySlug(ctx context.Context, slug string) (*entities.Category, error) {
+	category, err := s.categoryRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+	if category != nil {
+		return category, nil
+	}
+
+	categoryID, err := resolveOldSlug(ctx, s.slugRepo, entities.SlugEntityCategory, slug)
+	if err != nil {
+		return nil, err
+	}
+	if categoryID == nil {
+		return nil, errors.New("category not found")
+	}
+	return s.GetCategory(ctx, *categoryID)
+}
+
+// ListCategories lists categories as a flat list, parents first
+func (s *CategoryService) ListCategories(ctx context.Context, activeOnly bool) ([]*entities.Category, error) {
+	var categories []*entities.Category
+	var err error
+	if activeOnly {
+		categories, err = s.categoryRepo.GetActive(ctx)
+	} else {
+		categories, err = s.categoryRepo.GetAll(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	return categories, nil
+}
+
+// UpdateCategory updates a category. Renaming changes the slug, so the paths of all
+// descendants are rewritten in the same transaction. The subtree is locked first, so a
+// concurrent move or rename cannot interleave with the rewrite.
+func (s *CategoryService) UpdateCategory(ctx context.Context, id uuid.UUID, req *UpdateCategoryRequest) (*entities.Category, error) {
+	var category *entities.Category
+	err := s.transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.categoryRepo.LockHierarchy(ctx, &id, nil); err != nil {
+			return fmt.Errorf("failed to lock category tree: %w", err)
+		}
+		subtree, err := s.categoryRepo.GetSubTree(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get category tree: %w", err)
+		}
+		if len(subtree) == 0 {
+			return errors.New("category not found")
+		}
+		category = subtree[0]
+		oldSlug := category.Slug
+
+		if err := category.UpdateBasicInfo(req.Name, req.Description); err != nil {
+			return err
+		}
+		category.UpdateDisplay(req.ImageURL, req.IconURL, req.Color, req.SortOrder)
+		category.UpdateSEO(req.MetaTitle, req.MetaDescription)
+		if req.IsActive != nil {
+			category.SetActive(*req.IsActive)
+		}
+		if req.IsVisible != nil {
+			category.SetVisible(*req.IsVisible)
+		}
+
+		if category.Slug == oldSlug {
+			if err := s.categoryRepo.Update(ctx, category); err != nil {
+				return fmt.Errorf("failed to update category: %w", err)
+			}
+			return nil
+		}
+
+		exists, err := s.categoryRepo.ExistsBySlug(ctx, category.Slug)
+		if err != nil {
+			return fmt.Errorf("failed to check slug existence: %w", err)
+		}
+		if exists {
+			return errors.New("category with this slug already exists")
+		}
+
+		// Ancestors are not locked: moving or renaming one locks its subtree, which
+		// includes this category, so their paths cannot change under us
+		ancestors, err := s.parentPath(ctx, category.ParentID)
+		if err != nil {
+			return err
+		}
+		if err := placeSubtree(subtree, ancestors); err != nil {
+			return err
+		}
+		if err := s.categoryRepo.UpdateTree(ctx, subtree); err != nil {
+			return fmt.Errorf("failed to update category: %w", err)
+		}
+		return recordSlugChange(ctx, s.slugRepo, entities.SlugEntityCategory, category.ID, oldSlug, category.Slug)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.suggestions.clear()
+
+	return category, nil
+}
+
+// DeleteCategory deletes a category that has no subcategories and no products
+func (s *CategoryService) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	category, err := s.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get category: %w", err)
+	}
+	if category == nil {
+		return errors.New("category not found")
+	}
+
+	hasChildren, err := s.categoryRepo.HasChildren(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to check subcategories: %w", err)
+	}
+	if hasChildren {
+		return errors.New("category has subcategories")
+	}
+
+	hasProducts, err := s.categoryRepo.HasProducts(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to check products: %w", err)
+	}
+	if hasProducts {
+		return errors.New("category has products")
+	}
+
+	if err := s.categoryRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+	s.suggestions.clear()
+
+	return nil
+}
+
+// MoveCategory moves a category, with all its descendants, under a new parent. Level
+// and Path are recomputed for the whole subtree and saved in one transaction. The
+// subtree and the new parent's path are locked before they are read, so two concurrent
+// moves cannot together create a cycle. Moving a category under itself or one of its
+// descendants is rejected.
+func (s *CategoryService) MoveCategory(ctx context.Context, id uuid.UUID, req *MoveCategoryRequest) (*repositories.CategoryTree, error) {
+	var subtree, ancestors []*entities.Category
+	err := s.transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.categoryRepo.LockHierarchy(ctx, &id, req.ParentID); err != nil {
+			return fmt.Errorf("failed to lock category tree: %w", err)
+		}
+
+		var err error
+		subtree, err = s.categoryRepo.GetSubTree(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get category: %w", err)
+		}
+		if len(subtree) == 0 {
+			return errors.New("category not found")
+		}
+
+		ancestors, err = s.parentPath(ctx, req.ParentID)
+		if err != nil {
+			return err
+		}
+		for _, ancestor := range ancestors {
+			if ancestor.ID == id {
+				return errors.New("cannot move a category into its own subtree")
+			}
+		}
+
+		if err := placeSubtree(subtree, ancestors); err != nil {
+			return err
+		}
+		if req.SortOrder != nil {
+			category := subtree[0]
+			category.UpdateDisplay(category.ImageURL, category.IconURL, category.Color, *req.SortOrder)
+		}
+
+		if err := s.categoryRepo.UpdateTree(ctx, subtree); err != nil {
+			return fmt.Errorf("failed to move category: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	trees := buildCategoryTrees(subtree, func(c *entities.Category) bool { return c.ID == id }, ancestorNames(ancestors))
+	return trees[0], nil
+}
+
+// GetCategoryTree returns the category hierarchy. With activeOnly, inactive categories
+// are left out together with everything below them.
+func (s *CategoryService) GetCategoryTree(ctx context.Context, activeOnly bool) ([]*repositories.CategoryTree, error) {
+	categories, err := s.ListCategories(ctx, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildCategoryTrees(categories, func(c *entities.Category) bool { return c.ParentID == nil }, nil), nil
+}
+
+// GetCategorySubTree returns the hierarchy below a category
+func (s *CategoryService) GetCategorySubTree(ctx context.Context, id uuid.UUID) (*repositories.CategoryTree, error) {
+	subtree, err := s.categoryRepo.GetSubTree(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category tree: %w", err)
+	}
+	if len(subtree) == 0 {
+		return nil, errors.New("category not found")
+	}
+
+	ancestors, err := s.parentPath(ctx, subtree[0].ParentID)
+	if err != nil {
+		return nil, err
+	}
+
+	trees := buildCategoryTrees(subtree, func(c *entities.Category) bool { return c.ID == id }, ancestorNames(ancestors))
+	return trees[0], nil
+}
+
+// GetBreadcrumbs returns the path from the top level down to a category
+func (s *CategoryService) GetBreadcrumbs(ctx context.Context, id uuid.UUID) ([]*CategoryBreadcrumb, error) {
+	path, err := s.categoryRepo.GetPath(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category path: %w", err)
+	}
+	if len(path) == 0 {
+		return nil, errors.New("category not found")
+	}
+
+	breadcrumbs := make([]*CategoryBreadcrumb, len(path))
+	slugs := make([]string, 0, len(path))
+	for i, category := range path {
+		slugs = append(slugs, category.Slug)
+		breadcrumbs[i] = &CategoryBreadcrumb{
+			ID:   category.ID,
+			Name: category.Name,
+			Slug: category.Slug,
+			Path: strings.Join(slugs, "/"),
+		}
+	}
+	return breadcrumbs, nil
+}
+
+// parentPath loads a prospective parent and its ancestors, root first. A nil parent
+// means the top level.
+func (s *CategoryService) parentPath(ctx context.Context, parentID *uuid.UUID) ([]*entities.Category, error) {
+	if parentID == nil {
+		return nil, nil
+	}
+
+	ancestors, err := s.categoryRepo.GetPath(ctx, *parentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parent category: %w", err)
+	}
+	if len(ancestors) == 0 {
+		return nil, errors.New("parent category not found")
+	}
+	return ancestors, nil
+}
+
+// placeCategory sets a category's parent, Level and Path from its new ancestors (root
+// first, parent last). Level and Path come from the ancestor chain itself, so stale
+// values stored on the ancestors are not propagated.
+func placeCategory(category *entities.Category, ancestors []*entities.Category) error {
+	if len(ancestors) == 0 {
+		if err := category.SetParent(nil, 0); err != nil {
+			return err
+		}
+		category.UpdatePath("")
+		return nil
+	}
+
+	parent := ancestors[len(ancestors)-1]
+	parentID := parent.ID
+	if err := category.SetParent(&parentID, len(ancestors)-1); err != nil {
+		return err
+	}
+
+	slugs := make([]string, len(ancestors))
+	for i, ancestor := range ancestors {
+		slugs[i] = ancestor.Slug
+	}
+	category.UpdatePath(strings.Join(slugs, "/"))
+	return nil
+}
+
+// placeSubtree places the subtree's root under its new ancestors and recomputes Level
+// and Path for every descendant. subtree must be ordered parents-first.
+func placeSubtree(subtree []*entities.Category, ancestors []*entities.Category) error {
+	root := subtree[0]
+	if err := placeCategory(root, ancestors); err != nil {
+		return err
+	}
+
+	placed := map[uuid.UUID]*entities.Category{root.ID: root}
+	for _, category := range subtree[1:] {
+		parent := placed[*category.ParentID]
+		if parent == nil {
+			return fmt.Errorf("failed to place category %s: parent is outside the subtree", category.ID)
+		}
+		if err := category.SetParent(category.ParentID, parent.Level); err != nil {
+			return err
+		}
+		category.UpdatePath(parent.Path)
+		placed[category.ID] = category
+	}
+	return nil
+}
+
+// buildCategoryTrees assembles categories into trees below the categories isRoot picks.
+// Categories whose parent is not in the list are left out. basePath holds the names of
+// the roots' ancestors.
+func buildCategoryTrees(categories []*entities.Category, isRoot func(*entities.Category) bool, basePath []string) []*repositories.CategoryTree {
+	children := make(map[uuid.UUID][]*entities.Category)
+	var roots []*entities.Category
+	for _, category := range categories {
+		if isRoot(category) {
+			roots = append(roots, category)
+		} else if category.ParentID != nil {
+			children[*category.ParentID] = append(children[*category.ParentID], category)
+		}
+	}
+
+	visited := make(map[uuid.UUID]bool)
+	var build func(category *entities.Category, level int, path []string) *repositories.CategoryTree
+	build = func(category *entities.Category, level int, path []string) *repositories.CategoryTree {
+		visited[category.ID] = true
+		path = append(append([]string{}, path...), category.Name)
+		node := &repositories.CategoryTree{
+			Category: category,
+			Children: []*repositories.CategoryTree{},
+			Level:    level,
+			Path:     path,
+		}
+		for _, child := range children[category.ID] {
+			if !visited[child.ID] {
+				node.Children = append(node.Children, build(child, level+1, path))
+			}
+		}
+		return node
+	}
+
+	trees := make([]*repositories.CategoryTree, 0, len(roots))
+	for _, root := range roots {
+		trees = append(trees, build(root, len(basePath), basePath))
+	}
+	return trees
+}
+
+func ancestorNames(ancestors []*entities.Category) []string {
+	names := make([]string, len(ancestors))
+	for i, ancestor := range ancestors {
+		names[i] = ancestor.Name
+	}
+	return names
+}
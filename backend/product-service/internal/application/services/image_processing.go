@@ -0,0 +1,515 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+// maxImagePixels bounds the size of images decoded, so a small file that declares huge
+// dimensions cannot exhaust memory
+const maxImagePixels = 50_000_000
+
+// imageFormats maps the MIME types of supported images to file extensions
+var imageFormats = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+}
+
+// ImageRenditionSpec is a resized copy made of every uploaded image, fitting within
+// MaxWidth x MaxHeight. Images are never enlarged.
+type ImageRenditionSpec struct {
+	Name      string
+	MaxWidth  int
+	MaxHeight int
+}
+
+// DefaultImageRenditions are the renditions made of uploaded product images
+var DefaultImageRenditions = []ImageRenditionSpec{
+	{Name: "thumbnail", MaxWidth: 150, MaxHeight: 150},
+	{Name: "medium", MaxWidth: 600, MaxHeight: 600},
+	{Name: "zoom", MaxWidth: 1600, MaxHeight: 1600},
+}
+
+// processedImage is an upload with its metadata stripped and orientation applied
+type processedImage struct {
+	Data     []byte
+	MimeType string
+	Image    image.Image
+}
+
+// processImage sniffs the real type of an uploaded file, rejects anything but a
+// supported image, and strips EXIF, XMP and other metadata. JPEGs with an EXIF
+// orientation are rotated upright first, as the orientation is lost with the metadata.
+func processImage(data []byte) (*processedImage, error) {
+	mimeType := http.DetectContentType(data)
+	if _, ok := imageFormats[mimeType]; !ok {
+		if len(mimeType) > 6 && mimeType[:6] == "image/" {
+			return nil, fmt.Errorf("unsupported image type %s", mimeType)
+		}
+		return nil, errors.New("file is not an image")
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %w", err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxImagePixels {
+		return nil, fmt.Errorf("image dimensions %dx%d are not supported", config.Width, config.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %w", err)
+	}
+
+	result := &processedImage{MimeType: mimeType, Image: img}
+	switch mimeType {
+	case "image/jpeg":
+		if orientation := jpegOrientation(data); orientation > 1 {
+			result.Image = orientImage(img, orientation)
+			var buf bytes.Buffer
+			if err := jpeg.Encode(&buf, result.Image, &jpeg.Options{Quality: 92}); err != nil {
+				return nil, fmt.Errorf("failed to encode image: %w", err)
+			}
+			result.Data = buf.Bytes()
+		} else {
+			result.Data, err = stripJPEGMetadata(data)
+		}
+	case "image/png":
+		result.Data, err = stripPNGMetadata(data)
+	case "image/gif":
+		result.Data, err = stripGIFMetadata(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %w", err)
+	}
+
+	return result, nil
+}
+
+// renditionFormat is the MIME type renditions of an image are encoded in: JPEG for
+// JPEGs, PNG otherwise, to keep transparency
+func renditionFormat(mimeType string) string {
+	if mimeType == "image/jpeg" {
+		return "image/jpeg"
+	}
+	return "image/png"
+}
+
+// encodeImage encodes an image as JPEG or PNG
+func encodeImage(img image.Image, mimeType string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch mimeType {
+	case "image/jpeg":
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85})
+	case "image/png":
+		err = png.Encode(&buf, img)
+	case "image/gif":
+		err = gif.Encode(&buf, img, nil)
+	default:
+		err = fmt.Errorf("cannot encode %s", mimeType)
+	}
+	return buf.Bytes(), err
+}
+
+// fitWithin scales width x height down to fit within maxWidth x maxHeight, keeping the
+// aspect ratio. Sizes that already fit are returned unchanged.
+func fitWithin(width, height, maxWidth, maxHeight int) (int, int) {
+	if width <= maxWidth && height <= maxHeight {
+		return width, height
+	}
+
+	scale := float64(maxWidth) / float64(width)
+	if s := float64(maxHeight) / float64(height); s < scale {
+		scale = s
+	}
+
+	w := int(float64(width)*scale + 0.5)
+	h := int(float64(height)*scale + 0.5)
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+	return w, h
+}
+
+// resizeImage scales an image down to width x height by averaging the source pixels
+// each destination pixel covers
+func resizeImage(src image.Image, width, height int) *image.NRGBA {
+	bounds := src.Bounds()
+	rgba := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	if width == bounds.Dx() && height == bounds.Dy() {
+		copy(dst.Pix, rgba.Pix)
+		return dst
+	}
+
+	xScale := float64(bounds.Dx()) / float64(width)
+	yScale := float64(bounds.Dy()) / float64(height)
+
+	for y := 0; y < height; y++ {
+		y0 := int(float64(y) * yScale)
+		y1 := int(float64(y+1) * yScale)
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < width; x++ {
+			x0 := int(float64(x) * xScale)
+			x1 := int(float64(x+1) * xScale)
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			// Average colour channels weighted by alpha, so transparent pixels do not
+			// darken the edges of opaque ones
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				offset := rgba.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					alpha := uint64(rgba.Pix[offset+3])
+					r += uint64(rgba.Pix[offset]) * alpha
+					g += uint64(rgba.Pix[offset+1]) * alpha
+					b += uint64(rgba.Pix[offset+2]) * alpha
+					a += alpha
+					n++
+					offset += 4
+				}
+			}
+
+			var c color.NRGBA
+			if a > 0 {
+				c = color.NRGBA{R: uint8(r / a), G: uint8(g / a), B: uint8(b / a), A: uint8(a / n)}
+			}
+			dst.SetNRGBA(x, y, c)
+		}
+	}
+
+	return dst
+}
+
+// orientImage applies an EXIF orientation (2-8) to an image, so it displays upright
+// without the orientation tag
+func orientImage(src image.Image, orientation int) image.Image {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // mirrored horizontally
+				dx, dy = w-1-x, y
+			case 3: // rotated 180
+				dx, dy = w-1-x, h-1-y
+			case 4: // mirrored vertically
+				dx, dy = x, h-1-y
+			case 5: // transposed
+				dx, dy = y, x
+			case 6: // rotated 90 clockwise
+				dx, dy = h-1-y, x
+			case 7: // transversed
+				dx, dy = h-1-y, w-1-x
+			case 8: // rotated 90 counter-clockwise
+				dx, dy = y, w-1-x
+			default:
+				dx, dy = x, y
+			}
+			dst.Set(dx, dy, src.At(bounds.Min.X+x, bounds.Min.Y+y))
+		}
+	}
+
+	return dst
+}
+
+// jpegOrientation reads the EXIF orientation of a JPEG, or 1 (upright) when it has none
+func jpegOrientation(data []byte) int {
+	orientation := 1
+	walkJPEGSegments(data, func(marker byte, segment []byte) bool {
+		if marker != 0xE1 || len(segment) < 14 || !bytes.HasPrefix(segment[4:], []byte("Exif\x00\x00")) {
+			return true
+		}
+		if o := exifOrientation(segment[10:]); o >= 1 && o <= 8 {
+			orientation = o
+		}
+		return false
+	})
+	return orientation
+}
+
+// exifOrientation reads the orientation tag from the first IFD of a TIFF structure
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 0
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	offset := int(order.Uint32(tiff[4:8]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 0
+	}
+
+	count := int(order.Uint16(tiff[offset:]))
+	for i := 0; i < count; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+	return 0
+}
+
+// jpegMetadataMarkers are the JPEG segments dropped when stripping metadata: comments,
+// EXIF and XMP (APP1) and IPTC (APP13). JFIF, ICC profiles and Adobe colour transforms
+// are kept as they affect how the image displays.
+var jpegMetadataMarkers = map[byte]bool{
+	0xE1: true,
+	0xED: true,
+	0xFE: true,
+}
+
+// stripJPEGMetadata removes metadata segments from a JPEG without re-encoding it
+func stripJPEGMetadata(data []byte) ([]byte, error) {
+	out := make([]byte, 0, len(data))
+	out = append(out, 0xFF, 0xD8)
+
+	err := walkJPEGSegments(data, func(marker byte, segment []byte) bool {
+		if !jpegMetadataMarkers[marker] {
+			out = append(out, segment...)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// walkJPEGSegments calls fn with every segment of a JPEG after the start of image
+// marker, including its marker bytes. The last segment is the start of scan with the
+// rest of the file. fn returns false to stop early.
+func walkJPEGSegments(data []byte, fn func(marker byte, segment []byte) bool) error {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return errors.New("not a JPEG")
+	}
+
+	pos := 2
+	for pos < len(data) {
+		if data[pos] != 0xFF {
+			return errors.New("malformed JPEG")
+		}
+		// Skip fill bytes
+		for pos+1 < len(data) && data[pos+1] == 0xFF {
+			pos++
+		}
+		if pos+1 >= len(data) {
+			return errors.New("malformed JPEG")
+		}
+		marker := data[pos+1]
+
+		// Markers without a length
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+			if fn != nil && !fn(marker, data[pos:pos+2]) {
+				return nil
+			}
+			pos += 2
+			continue
+		}
+		if marker == 0xD9 {
+			if fn != nil {
+				fn(marker, data[pos:pos+2])
+			}
+			return nil
+		}
+
+		if pos+4 > len(data) {
+			return errors.New("malformed JPEG")
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return errors.New("malformed JPEG")
+		}
+
+		// Entropy-coded data follows the start of scan; pass the rest through
+		if marker == 0xDA {
+			if fn != nil {
+				fn(marker, data[pos:])
+			}
+			return nil
+		}
+
+		if fn != nil && !fn(marker, data[pos:end]) {
+			return nil
+		}
+		pos = end
+	}
+
+	return nil
+}
+
+// pngMetadataChunks are the PNG chunks dropped when stripping metadata
+var pngMetadataChunks = map[string]bool{
+	"eXIf": true,
+	"tEXt": true,
+	"zTXt": true,
+	"iTXt": true,
+	"tIME": true,
+}
+
+// stripPNGMetadata removes metadata chunks from a PNG without re-encoding it
+func stripPNGMetadata(data []byte) ([]byte, error) {
+	const signature = "\x89PNG\r\n\x1a\n"
+	if len(data) < len(signature) || string(data[:len(signature)]) != signature {
+		return nil, errors.New("not a PNG")
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, signature...)
+
+	pos := len(signature)
+	for pos < len(data) {
+		if pos+8 > len(data) {
+			return nil, errors.New("malformed PNG")
+		}
+		length := int(binary.BigEndian.Uint32(data[pos:]))
+		chunkType := string(data[pos+4 : pos+8])
+		end := pos + 12 + length
+		if length < 0 || end > len(data) {
+			return nil, errors.New("malformed PNG")
+		}
+
+		if !pngMetadataChunks[chunkType] {
+			out = append(out, data[pos:end]...)
+		}
+		pos = end
+
+		if chunkType == "IEND" {
+			break
+		}
+	}
+
+	return out, nil
+}
+
+// gifLoopApplications are the GIF application extensions kept when stripping metadata:
+// they hold an animation's loop count, not metadata
+var gifLoopApplications = map[string]bool{
+	"NETSCAPE2.0": true,
+	"ANIMEXTS1.0": true,
+}
+
+// stripGIFMetadata removes comment extensions, and application extensions other than
+// animation looping (such as XMP), from a GIF without re-encoding it
+func stripGIFMetadata(data []byte) ([]byte, error) {
+	const screenEnd = 13
+	if len(data) < screenEnd || (string(data[:6]) != "GIF87a" && string(data[:6]) != "GIF89a") {
+		return nil, errors.New("not a GIF")
+	}
+
+	pos := screenEnd
+	if flags := data[10]; flags&0x80 != 0 {
+		pos += 3 << ((flags & 0x07) + 1)
+	}
+	if pos > len(data) {
+		return nil, errors.New("malformed GIF")
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:pos]...)
+
+	for pos < len(data) {
+		start := pos
+		switch data[pos] {
+		case 0x3B: // trailer
+			return append(out, data[pos]), nil
+
+		case 0x2C: // image descriptor, optional local color table, LZW code size, data
+			if pos+10 > len(data) {
+				return nil, errors.New("malformed GIF")
+			}
+			pos += 10
+			if flags := data[start+9]; flags&0x80 != 0 {
+				pos += 3 << ((flags & 0x07) + 1)
+			}
+			end, err := skipGIFSubBlocks(data, pos+1)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, data[start:end]...)
+			pos = end
+
+		case 0x21: // extension
+			if pos+2 > len(data) {
+				return nil, errors.New("malformed GIF")
+			}
+			end, err := skipGIFSubBlocks(data, pos+2)
+			if err != nil {
+				return nil, err
+			}
+			keep := true
+			switch data[pos+1] {
+			case 0xFE: // comment
+				keep = false
+			case 0xFF: // application
+				keep = pos+14 <= end && data[pos+2] == 11 && gifLoopApplications[string(data[pos+3:pos+14])]
+			}
+			if keep {
+				out = append(out, data[start:end]...)
+			}
+			pos = end
+
+		default:
+			return nil, errors.New("malformed GIF")
+		}
+	}
+
+	return nil, errors.New("malformed GIF")
+}
+
+// skipGIFSubBlocks returns the position after the sub-blocks starting at pos and the
+// empty block terminating them
+func skipGIFSubBlocks(data []byte, pos int) (int, error) {
+	for pos < len(data) {
+		size := int(data[pos])
+		pos++
+		if size == 0 {
+			return pos, nil
+		}
+		pos += size
+	}
+	return 0, errors.New("malformed GIF")
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/google/uuid"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+	"product-service/internal/domain/storage"
+)
+
+// MaxImageUploadSize is the largest image file accepted, in bytes
+const MaxImageUploadSize = 20 << 20
+
+// ImageService handles product image uploads: it validates files, strips their
+// metadata, makes resized renditions and stores them
+type ImageService struct {
+	productRepo repositories.ProductRepository
+	variantRepo repositories.ProductVariantRepository
+	imageRepo   repositories.ProductImageRepository
+	storage     storage.Storage
+	renditions  []ImageRenditionSpec
+	revisions   *RevisionService
+}
+
+// NewImageService creates a new image service
+func NewImageService(
+	productRepo repositories.ProductRepository,
+	variantRepo repositories.ProductVariantRepository,
+	imageRepo repositories.ProductImageRepository,
+	storage storage.Storage,
+	revisions *RevisionService,
+) *ImageService {
+	return &ImageService{
+		productRepo: productRepo,
+		variantRepo: variantRepo,
+		imageRepo:   imageRepo,
+		storage:     storage,
+		renditions:  DefaultImageRenditions,
+		revisions:   revisions,
+	}
+}
+
+// ImageUpload is one uploaded image file
+type ImageUpload struct {
+	FileName string
+	Data     []byte
+}
+
+// ImageUploadOptions are applied to every image of an upload
+type ImageUploadOptions struct {
+	VariantID *uuid.UUID
+	AltText   string
+}
+
+// RejectedImage is an uploaded file that was not stored, and why
+type RejectedImage struct {
+	FileName string `json:"file_name"`
+	Error    string `json:"error"`
+}
+
+// ImageUploadResult reports what an upload stored. Duplicates are existing images of
+// the product with the same content as an uploaded file, and the image stored for a file
+// that appears more than once in the upload, once for each repeat.
+type ImageUploadResult struct {
+	Created    []*entities.ProductImage `json:"created"`
+	Duplicates []*entities.ProductImage `json:"duplicates"`
+	Rejected   []RejectedImage          `json:"rejected"`
+}
+
+// UploadImages stores images of a product, or of one of its variants. Files that are
+// not supported images are rejected individually; files already uploaded to the
+// product, by content hash, are reported as duplicates rather than stored again.
+func (s *ImageService) UploadImages(ctx context.Context, productID uuid.UUID, uploads []ImageUpload, opts ImageUploadOptions) (*ImageUploadResult, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if product == nil {
+		return nil, errors.New("product not found")
+	}
+
+	if opts.VariantID != nil {
+		variant, err := s.variantRepo.GetByID(ctx, *opts.VariantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get variant: %w", err)
+		}
+		if variant == nil || variant.ProductID != product.ID {
+			return nil, errors.New("variant not found")
+		}
+	}
+
+	count, err := s.countImages(ctx, product.ID, opts.VariantID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImageUploadResult{
+		Created:    []*entities.ProductImage{},
+		Duplicates: []*entities.ProductImage{},
+		Rejected:   []RejectedImage{},
+	}
+	// Outcomes by content hash, so a file repeated in the upload is reported like the
+	// first copy: as a duplicate of the image it became, or rejected for the same reason
+	seen := make(map[string]*entities.ProductImage)
+	rejected := make(map[string]string)
+	actor := ActorFromContext(ctx)
+
+	for _, upload := range uploads {
+		if len(upload.Data) == 0 {
+			result.Rejected = append(result.Rejected, RejectedImage{FileName: upload.FileName, Error: "file is empty"})
+			continue
+		}
+		if len(upload.Data) > MaxImageUploadSize {
+			result.Rejected = append(result.Rejected, RejectedImage{FileName: upload.FileName, Error: fmt.Sprintf("file is larger than %d MB", MaxImageUploadSize>>20)})
+			continue
+		}
+
+		sum := sha256.Sum256(upload.Data)
+		hash := hex.EncodeToString(sum[:])
+		if image := seen[hash]; image != nil {
+			result.Duplicates = append(result.Duplicates, image)
+			continue
+		}
+		if reason, ok := rejected[hash]; ok {
+			result.Rejected = append(result.Rejected, RejectedImage{FileName: upload.FileName, Error: reason})
+			continue
+		}
+
+		existing, err := s.imageRepo.GetByContentHash(ctx, product.ID, opts.VariantID, hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check for duplicate images: %w", err)
+		}
+		if existing != nil {
+			seen[hash] = existing
+			result.Duplicates = append(result.Duplicates, existing)
+			continue
+		}
+
+		processed, err := processImage(upload.Data)
+		if err != nil {
+			rejected[hash] = err.Error()
+			result.Rejected = append(result.Rejected, RejectedImage{FileName: upload.FileName, Error: err.Error()})
+			continue
+		}
+
+		image, err := s.storeImage(ctx, product.ID, opts, hash, processed)
+		if err != nil {
+			return nil, err
+		}
+
+		image.SetSortOrder(int(count) + len(result.Created))
+		image.SetPrimary(count == 0 && len(result.Created) == 0)
+		image.CreatedBy = actor
+		image.UpdatedBy = actor
+		seen[hash] = image
+		result.Created = append(result.Created, image)
+	}
+
+	if len(result.Created) == 0 {
+		return result, nil
+	}
+
+	err = s.revisions.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.imageRepo.CreateBulk(ctx, result.Created); err != nil {
+			return fmt.Errorf("failed to create images: %w", err)
+		}
+
+		revisions := make([]*entities.ProductRevision, 0, len(result.Created))
+		for _, image := range result.Created {
+			revisions = append(revisions, entities.NewProductRevision(product.ID, entities.RevisionEntityImage, image.ID, entities.RevisionActionCreate, actor, nil, image))
+		}
+		return s.revisions.Record(ctx, revisions...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// storeImage writes an image and its renditions to storage, keyed by content hash so
+// the same file uploaded to several products is stored once, and returns the image
+func (s *ImageService) storeImage(ctx context.Context, productID uuid.UUID, opts ImageUploadOptions, hash string, processed *processedImage) (*entities.ProductImage, error) {
+	dir := path.Join("images", hash[:2], hash)
+	originalKey := path.Join(dir, "original."+imageFormats[processed.MimeType])
+
+	if err := s.putOnce(ctx, originalKey, processed.Data, processed.MimeType); err != nil {
+		return nil, err
+	}
+
+	bounds := processed.Image.Bounds()
+	renditionType := renditionFormat(processed.MimeType)
+	renditions := make([]entities.ImageRendition, 0, len(s.renditions))
+	for _, spec := range s.renditions {
+		width, height := fitWithin(bounds.Dx(), bounds.Dy(), spec.MaxWidth, spec.MaxHeight)
+		key := path.Join(dir, spec.Name+"."+imageFormats[renditionType])
+
+		exists, err := s.storage.Exists(ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check stored image: %w", err)
+		}
+		if !exists {
+			data, err := encodeImage(resizeImage(processed.Image, width, height), renditionType)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode %s rendition: %w", spec.Name, err)
+			}
+			if err := s.storage.Put(ctx, key, bytes.NewReader(data), renditionType); err != nil {
+				return nil, fmt.Errorf("failed to store %s rendition: %w", spec.Name, err)
+			}
+		}
+
+		renditions = append(renditions, entities.ImageRendition{
+			Name:   spec.Name,
+			URL:    s.storage.URL(key),
+			Width:  width,
+			Height: height,
+		})
+	}
+
+	image, err := entities.NewProductImage(s.storage.URL(originalKey), opts.AltText, &productID, opts.VariantID)
+	if err != nil {
+		return nil, err
+	}
+	image.UpdateProperties(bounds.Dx(), bounds.Dy(), int64(len(processed.Data)), processed.MimeType)
+	image.SetStorage(originalKey, hash, renditions)
+
+	return image, nil
+}
+
+// putOnce stores a file unless it is already stored
+func (s *ImageService) putOnce(ctx context.Context, key string, data []byte, contentType string) error {
+	exists, err := s.storage.Exists(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to check stored image: %w", err)
+	}
+	if exists {
+		return nil
+	}
+
+	if err := s.storage.Put(ctx, key, bytes.NewReader(data), contentType); err != nil {
+		return fmt.Errorf("failed to store image: %w", err)
+	}
+	return nil
+}
+
+// countImages counts the images of a product, or of a variant
+func (s *ImageService) countImages(ctx context.Context, productID uuid.UUID, variantID *uuid.UUID) (int64, error) {
+	var count int64
+	var err error
+	if variantID != nil {
+		count, err = s.imageRepo.CountByVariantID(ctx, *variantID)
+	} else {
+		count, err = s.imageRepo.CountByProductID(ctx, productID)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to count images: %w", err)
+	}
+	return count, nil
+}
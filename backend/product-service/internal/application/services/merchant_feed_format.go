@@ -0,0 +1,111 @@
+package services
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+// Merchant feed file formats
+const (
+	MerchantFeedFormatXML = "xml"
+	MerchantFeedFormatTSV = "tsv"
+)
+
+// merchantFeedNamespace is the XML namespace of Google's product attributes
+const merchantFeedNamespace = "http://base.google.com/ns/1.0"
+
+// merchantFeedColumns are the TSV header, in the order items are written
+var merchantFeedColumns = []string{
+	"id", "title", "description", "link", "image_link", "availability", "price", "sale_price",
+	"brand", "gtin", "identifier_exists", "item_group_id", "shipping_weight",
+}
+
+// merchantFeedEncoder writes feed items in a file format
+type merchantFeedEncoder interface {
+	Encode(item *MerchantFeedItem) error
+	Flush() error
+	// Close ends the document and flushes it
+	Close() error
+}
+
+// newMerchantFeedEncoder creates an encoder for the given format, writing the document
+// header immediately
+func newMerchantFeedEncoder(format string, w io.Writer, title, link string) (merchantFeedEncoder, error) {
+	buffered := bufio.NewWriter(w)
+	switch format {
+	case MerchantFeedFormatXML:
+		buffered.WriteString(xml.Header)
+		buffered.WriteString(`<rss version="2.0" xmlns:g="` + merchantFeedNamespace + `"><channel>`)
+		writeXMLElement(buffered, "title", title)
+		writeXMLElement(buffered, "link", link)
+		writeXMLElement(buffered, "description", title+" product feed")
+		return &xmlMerchantFeedEncoder{writer: buffered, encoder: xml.NewEncoder(buffered)}, nil
+	case MerchantFeedFormatTSV:
+		buffered.WriteString(strings.Join(merchantFeedColumns, "\t") + "\n")
+		return &tsvMerchantFeedEncoder{writer: buffered}, nil
+	default:
+		return nil, errors.New("format must be xml or tsv")
+	}
+}
+
+// writeXMLElement writes an element with escaped text content
+func writeXMLElement(w *bufio.Writer, name, text string) {
+	w.WriteString("<" + name + ">")
+	xml.EscapeText(w, []byte(text))
+	w.WriteString("</" + name + ">")
+}
+
+type xmlMerchantFeedEncoder struct {
+	writer  *bufio.Writer
+	encoder *xml.Encoder
+}
+
+func (e *xmlMerchantFeedEncoder) Encode(item *MerchantFeedItem) error {
+	return e.encoder.Encode(item)
+}
+
+func (e *xmlMerchantFeedEncoder) Flush() error {
+	if err := e.encoder.Flush(); err != nil {
+		return err
+	}
+	return e.writer.Flush()
+}
+
+func (e *xmlMerchantFeedEncoder) Close() error {
+	if err := e.encoder.Flush(); err != nil {
+		return err
+	}
+	e.writer.WriteString("</channel></rss>\n")
+	return e.writer.Flush()
+}
+
+// tsvMerchantFeedEncoder writes one item per line. TSV feeds have no quoting, so tabs
+// and line breaks inside values are replaced by spaces.
+type tsvMerchantFeedEncoder struct {
+	writer *bufio.Writer
+}
+
+func (e *tsvMerchantFeedEncoder) Encode(item *MerchantFeedItem) error {
+	values := []string{
+		item.ID, item.Title, item.Description, item.Link, item.ImageLink, item.Availability, item.Price, item.SalePrice,
+		item.Brand, item.GTIN, item.IdentifierExists, item.ItemGroupID, item.ShippingWeight,
+	}
+	for i, value := range values {
+		values[i] = tsvValueReplacer.Replace(value)
+	}
+	_, err := e.writer.WriteString(strings.Join(values, "\t") + "\n")
+	return err
+}
+
+func (e *tsvMerchantFeedEncoder) Flush() error {
+	return e.writer.Flush()
+}
+
+func (e *tsvMerchantFeedEncoder) Close() error {
+	return e.writer.Flush()
+}
+
+var tsvValueReplacer = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
@@ -0,0 +1,460 @@
+package services
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+const (
+	// merchantFeedBatchSize is the number of products loaded, priced and written at a time
+	merchantFeedBatchSize = 200
+
+	// Google Merchant Center length limits
+	merchantIDMaxLength          = 50
+	merchantTitleMaxLength       = 150
+	merchantDescriptionMaxLength = 5000
+)
+
+// Merchant feed availability values
+const (
+	MerchantInStock    = "in_stock"
+	MerchantOutOfStock = "out_of_stock"
+	MerchantBackorder  = "backorder"
+)
+
+// MerchantFeedService builds the Google Merchant Center product feed from the listed
+// catalog. A product without variants is one item; a product with variants is one item
+// per active variant, grouped by the product's SKU. Prices are in the base currency with
+// running sales applied, as checkout charges them.
+type MerchantFeedService struct {
+	feedRepo      repositories.FeedRepository
+	pricing       *PricingService
+	storefrontURL string
+	mediaURL      string
+	title         string
+	weightUnit    string
+}
+
+// NewMerchantFeedService creates a new merchant feed service. Links point at product
+// pages under storefrontURL; relative image URLs are resolved against mediaURL. Weights
+// are in weightUnit, one of the units Merchant Center accepts (kg, g, lb or oz).
+func NewMerchantFeedService(
+	feedRepo repositories.FeedRepository,
+	pricing *PricingService,
+	storefrontURL string,
+	mediaURL string,
+	title string,
+	weightUnit string,
+) *MerchantFeedService {
+	return &MerchantFeedService{
+		feedRepo:      feedRepo,
+		pricing:       pricing,
+		storefrontURL: strings.TrimSuffix(storefrontURL, "/"),
+		mediaURL:      strings.TrimSuffix(mediaURL, "/"),
+		title:         title,
+		weightUnit:    weightUnit,
+	}
+}
+
+// MerchantFeedItem is one product or variant in the feed, with values formatted as
+// Merchant Center expects them
+type MerchantFeedItem struct {
+	XMLName          xml.Name `xml:"item" json:"-"`
+	ID               string   `xml:"g:id" json:"id"`
+	Title            string   `xml:"g:title" json:"title"`
+	Description      string   `xml:"g:description" json:"description"`
+	Link             string   `xml:"g:link" json:"link"`
+	ImageLink        string   `xml:"g:image_link" json:"image_link"`
+	Availability     string   `xml:"g:availability" json:"availability"`
+	Price            string   `xml:"g:price" json:"price"`
+	SalePrice        string   `xml:"g:sale_price,omitempty" json:"sale_price,omitempty"`
+	Brand            string   `xml:"g:brand,omitempty" json:"brand,omitempty"`
+	GTIN             string   `xml:"g:gtin,omitempty" json:"gtin,omitempty"`
+	IdentifierExists string   `xml:"g:identifier_exists,omitempty" json:"identifier_exists,omitempty"`
+	ItemGroupID      string   `xml:"g:item_group_id,omitempty" json:"item_group_id,omitempty"`
+	ShippingWeight   string   `xml:"g:shipping_weight,omitempty" json:"shipping_weight,omitempty"`
+}
+
+// MerchantFeedIssues lists what is wrong with one feed item. Items with errors are left
+// out of the feed; warnings are written with the item.
+type MerchantFeedIssues struct {
+	ID        string   `json:"id"`
+	ProductID string   `json:"product_id"`
+	VariantID string   `json:"variant_id,omitempty"`
+	Errors    []string `json:"errors,omitempty"`
+	Warnings  []string `json:"warnings,omitempty"`
+}
+
+// MerchantFeedReport is the outcome of validating every feed item. Items lists only
+// the items with errors or warnings.
+type MerchantFeedReport struct {
+	TotalItems        int                   `json:"total_items"`
+	ValidItems        int                   `json:"valid_items"`
+	InvalidItems      int                   `json:"invalid_items"`
+	ItemsWithWarnings int                   `json:"items_with_warnings"`
+	Items             []*MerchantFeedIssues `json:"items"`
+	GeneratedAt       time.Time             `json:"generated_at"`
+}
+
+// GetFeedVersion returns the state of the catalog the feed is built from, which
+// identifies the feed for caching
+func (s *MerchantFeedService) GetFeedVersion(ctx context.Context) (*repositories.FeedVersion, error) {
+	version, err := s.feedRepo.GetFeedVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed version: %w", err)
+	}
+	return version, nil
+}
+
+// WriteFeed streams the valid items of the feed in the given format
+func (s *MerchantFeedService) WriteFeed(ctx context.Context, format string, w io.Writer) error {
+	encoder, err := newMerchantFeedEncoder(format, w, s.title, s.storefrontURL)
+	if err != nil {
+		return err
+	}
+
+	err = s.eachItem(ctx, func(item *MerchantFeedItem, issues *MerchantFeedIssues) error {
+		if len(issues.Errors) > 0 {
+			return nil
+		}
+		return encoder.Encode(item)
+	}, encoder.Flush)
+	if err != nil {
+		return fmt.Errorf("failed to write feed: %w", err)
+	}
+
+	return encoder.Close()
+}
+
+// BuildReport validates every feed item and reports the ones with problems
+func (s *MerchantFeedService) BuildReport(ctx context.Context) (*MerchantFeedReport, error) {
+	report := &MerchantFeedReport{Items: []*MerchantFeedIssues{}}
+	err := s.eachItem(ctx, func(item *MerchantFeedItem, issues *MerchantFeedIssues) error {
+		report.TotalItems++
+		if len(issues.Errors) > 0 {
+			report.InvalidItems++
+		} else {
+			report.ValidItems++
+		}
+		if len(issues.Warnings) > 0 {
+			report.ItemsWithWarnings++
+		}
+		if len(issues.Errors) > 0 || len(issues.Warnings) > 0 {
+			report.Items = append(report.Items, issues)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build feed report: %w", err)
+	}
+
+	report.GeneratedAt = time.Now()
+	return report, nil
+}
+
+// eachItem maps the listed catalog to feed items batch by batch, calling fn for each
+// item and afterBatch, if set, once a batch is done
+func (s *MerchantFeedService) eachItem(ctx context.Context, fn func(*MerchantFeedItem, *MerchantFeedIssues) error, afterBatch func() error) error {
+	now := time.Now()
+	return s.feedRepo.StreamListedProducts(ctx, merchantFeedBatchSize, func(products []*entities.Product) error {
+		if err := s.pricing.ApplyPrices(ctx, products, PriceContext{}); err != nil {
+			return err
+		}
+
+		for _, product := range products {
+			if product.HasVariants && len(product.Variants) == 0 {
+				issues := &MerchantFeedIssues{ID: product.SKU, ProductID: product.ID.String()}
+				issues.Errors = append(issues.Errors, "product has variants but none is active")
+				if err := fn(&MerchantFeedItem{ID: product.SKU}, issues); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if len(product.Variants) == 0 {
+				item, issues := s.productItem(product, now)
+				if err := fn(item, issues); err != nil {
+					return err
+				}
+				continue
+			}
+
+			for i := range product.Variants {
+				item, issues := s.variantItem(product, &product.Variants[i], now)
+				if err := fn(item, issues); err != nil {
+					return err
+				}
+			}
+		}
+
+		if afterBatch != nil {
+			return afterBatch()
+		}
+		return nil
+	})
+}
+
+// productItem maps a product without variants to a feed item
+func (s *MerchantFeedService) productItem(product *entities.Product, now time.Time) (*MerchantFeedItem, *MerchantFeedIssues) {
+	issues := &MerchantFeedIssues{ID: product.SKU, ProductID: product.ID.String()}
+
+	availability := MerchantOutOfStock
+	if product.InAvailabilityWindow(now) {
+		availability = stockAvailability(product.IsInStock(), product.AllowBackorder)
+	}
+
+	salePrice := 0.0
+	if product.IsOnSale {
+		salePrice = product.SalePrice
+	}
+
+	item := s.buildItem(issues, product, merchantItemFields{
+		title:        product.Name,
+		link:         s.productLink(product, ""),
+		images:       product.Images,
+		availability: availability,
+		price:        product.Price,
+		salePrice:    salePrice,
+		// Products keep no barcode of their own, unlike variants
+		gtin:   product.Attributes["gtin"],
+		weight: product.Weight,
+	})
+	return item, issues
+}
+
+// variantItem maps an active variant to a feed item in its product's group
+func (s *MerchantFeedService) variantItem(product *entities.Product, variant *entities.ProductVariant, now time.Time) (*MerchantFeedItem, *MerchantFeedIssues) {
+	issues := &MerchantFeedIssues{ID: variant.SKU, ProductID: product.ID.String(), VariantID: variant.ID.String()}
+
+	availability := MerchantOutOfStock
+	if product.InAvailabilityWindow(now) {
+		availability = stockAvailability(variant.IsInStock(), variant.AllowBackorder)
+	}
+
+	price := variant.GetEffectivePrice(product.Price)
+	salePrice := 0.0
+	if variant.IsOnSale {
+		salePrice = variant.SalePrice
+	}
+
+	images := variant.Images
+	if len(images) == 0 {
+		images = product.Images
+	}
+
+	item := s.buildItem(issues, product, merchantItemFields{
+		title:        variantTitle(product.Name, variant.Attributes),
+		link:         s.productLink(product, variant.SKU),
+		images:       images,
+		availability: availability,
+		price:        price,
+		salePrice:    salePrice,
+		gtin:         variant.Barcode,
+		weight:       variant.GetEffectiveWeight(product.Weight),
+	})
+	item.ItemGroupID = product.SKU
+	return item, issues
+}
+
+// merchantItemFields are the values that differ between product and variant items
+type merchantItemFields struct {
+	title        string
+	link         string
+	images       []entities.ProductImage
+	availability string
+	price        float64
+	salePrice    float64
+	gtin         string
+	weight       float64
+}
+
+// buildItem formats and validates the fields of a feed item, recording errors for
+// missing or invalid required fields and warnings for values that were adjusted
+func (s *MerchantFeedService) buildItem(issues *MerchantFeedIssues, product *entities.Product, fields merchantItemFields) *MerchantFeedItem {
+	fail := func(format string, args ...interface{}) {
+		issues.Errors = append(issues.Errors, fmt.Sprintf(format, args...))
+	}
+	warn := func(format string, args ...interface{}) {
+		issues.Warnings = append(issues.Warnings, fmt.Sprintf(format, args...))
+	}
+
+	item := &MerchantFeedItem{ID: issues.ID, Availability: fields.availability}
+
+	switch {
+	case item.ID == "":
+		fail("id is required")
+	case utf8.RuneCountInString(item.ID) > merchantIDMaxLength:
+		fail("id is longer than %d characters", merchantIDMaxLength)
+	}
+
+	item.Title = strings.TrimSpace(fields.title)
+	if item.Title == "" {
+		fail("title is required")
+	} else if title, cut := truncateRunes(item.Title, merchantTitleMaxLength); cut {
+		item.Title = title
+		warn("title was cut to %d characters", merchantTitleMaxLength)
+	}
+
+	item.Description = strings.TrimSpace(product.Description)
+	if item.Description == "" {
+		item.Description = strings.TrimSpace(product.ShortDesc)
+	}
+	if item.Description == "" {
+		fail("description is required")
+	} else if description, cut := truncateRunes(item.Description, merchantDescriptionMaxLength); cut {
+		item.Description = description
+		warn("description was cut to %d characters", merchantDescriptionMaxLength)
+	}
+
+	if product.Slug == "" {
+		fail("link is required, but the product has no slug")
+	} else {
+		item.Link = fields.link
+	}
+
+	if len(fields.images) == 0 {
+		fail("image_link is required, but the product has no images")
+	} else if imageLink, ok := s.absoluteURL(fields.images[0].URL); ok {
+		item.ImageLink = imageLink
+	} else {
+		fail("image_link %q is not an absolute http(s) URL", fields.images[0].URL)
+	}
+
+	if fields.price <= 0 {
+		fail("price must be greater than 0")
+	} else {
+		item.Price = s.formatPrice(fields.price)
+	}
+	if fields.salePrice > 0 && fields.salePrice < fields.price {
+		item.SalePrice = s.formatPrice(fields.salePrice)
+	}
+
+	if fields.availability == MerchantBackorder {
+		warn("backorder items need an availability_date, which the catalog does not record")
+	}
+
+	if product.Brand != nil {
+		item.Brand = product.Brand.Name
+	}
+
+	if gtin := strings.TrimSpace(fields.gtin); gtin != "" {
+		if isValidGTIN(gtin) {
+			item.GTIN = gtin
+		} else {
+			warn("gtin %q is not a valid GTIN and was left out", gtin)
+		}
+	}
+	if item.GTIN == "" && item.Brand == "" {
+		item.IdentifierExists = "no"
+		warn("no gtin or brand; identifier_exists is set to no")
+	}
+
+	if fields.weight > 0 {
+		item.ShippingWeight = fmt.Sprintf("%g %s", fields.weight, s.weightUnit)
+	}
+
+	return item
+}
+
+// productLink links to a product's storefront page, preselecting a variant if given
+func (s *MerchantFeedService) productLink(product *entities.Product, variantSKU string) string {
+	link := s.storefrontURL + "/products/" + url.PathEscape(product.Slug)
+	if variantSKU != "" {
+		link += "?variant=" + url.QueryEscape(variantSKU)
+	}
+	return link
+}
+
+// absoluteURL resolves a stored image URL, which is relative for locally stored files,
+// to an absolute http(s) URL
+func (s *MerchantFeedService) absoluteURL(raw string) (string, bool) {
+	if strings.HasPrefix(raw, "/") && !strings.HasPrefix(raw, "//") {
+		raw = s.mediaURL + raw
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", false
+	}
+	return parsed.String(), true
+}
+
+// formatPrice formats an amount in the base currency as Merchant Center expects it
+func (s *MerchantFeedService) formatPrice(amount float64) string {
+	return fmt.Sprintf("%.2f %s", roundPrice(amount), s.pricing.BaseCurrency())
+}
+
+// stockAvailability maps stock to a Merchant Center availability value
+func stockAvailability(inStock, allowBackorder bool) string {
+	switch {
+	case inStock:
+		return MerchantInStock
+	case allowBackorder:
+		return MerchantBackorder
+	default:
+		return MerchantOutOfStock
+	}
+}
+
+// variantTitle names a variant after its product and attribute values, in attribute
+// name order so the title is stable
+func variantTitle(productName string, attributes map[string]string) string {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	values := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if value := strings.TrimSpace(attributes[key]); value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return productName
+	}
+	return productName + " - " + strings.Join(values, " / ")
+}
+
+// truncateRunes cuts s to at most max characters, reporting whether it was cut
+func truncateRunes(s string, max int) (string, bool) {
+	if utf8.RuneCountInString(s) <= max {
+		return s, false
+	}
+	return string([]rune(s)[:max]), true
+}
+
+// isValidGTIN checks that a GTIN-8, -12, -13 or -14 is all digits and that its check
+// digit matches
+func isValidGTIN(gtin string) bool {
+	switch len(gtin) {
+	case 8, 12, 13, 14:
+	default:
+		return false
+	}
+
+	sum := 0
+	for i := len(gtin) - 1; i >= 0; i-- {
+		c := gtin[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		digit := int(c - '0')
+		// Weights alternate 1, 3, 1, ... from the check digit leftwards
+		if (len(gtin)-1-i)%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return sum%10 == 0
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"product-service/internal/domain/events"
+)
+
+// OrderPlaced is an order as product-service sees it: which products were bought, when,
+// and by whom if the customer is known
+type OrderPlaced struct {
+	OrderID   string
+	UserID    *uuid.UUID
+	Items     []OrderLine
+	OrderedAt time.Time
+}
+
+// OrderLine is one line of a placed order
+type OrderLine struct {
+	ProductID uuid.UUID
+	VariantID *uuid.UUID
+	Quantity  int
+}
+
+// orderCreatedData is the payload of an order.created event from order-service
+type orderCreatedData struct {
+	OrderID string `json:"orderId"`
+	UserID  string `json:"userId"`
+	Items   []struct {
+		ProductID string `json:"productId"`
+		VariantID string `json:"variantId"`
+		Quantity  int    `json:"quantity"`
+	} `json:"items"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// OrderEventService consumes events from order-service
+type OrderEventService struct {
+	recommendations *RecommendationService
+	stats           *ProductStatsService
+	reviews         *ReviewService
+}
+
+// NewOrderEventService creates a new order event service
+func NewOrderEventService(recommendations *RecommendationService, stats *ProductStatsService, reviews *ReviewService) *OrderEventService {
+	return &OrderEventService{
+		recommendations: recommendations,
+		stats:           stats,
+		reviews:         reviews,
+	}
+}
+
+// HandleEvent processes an event, returning false for event types product-service
+// does not consume. Events may be delivered more than once; each order is counted once.
+func (s *OrderEventService) HandleEvent(ctx context.Context, event *events.Event) (bool, error) {
+	switch event.EventType {
+	case events.OrderCreated:
+		order, err := parseOrderCreated(event)
+		if err != nil {
+			return true, err
+		}
+		// The order is recorded for recommendations first; that is what later
+		// deliveries are recognised by, and sales are counted against it
+		if err := s.recommendations.RecordOrder(ctx, order); err != nil {
+			return true, err
+		}
+		if err := s.stats.RecordOrder(ctx, order); err != nil {
+			return true, err
+		}
+		return true, s.reviews.RecordOrder(ctx, order)
+	}
+	return false, nil
+}
+
+// parseOrderCreated reads the order from an order.created event
+func parseOrderCreated(event *events.Event) (*OrderPlaced, error) {
+	raw, err := json.Marshal(event.Data)
+	if err != nil {
+		return nil, errors.New("invalid order event data")
+	}
+	var data orderCreatedData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, errors.New("invalid order event data")
+	}
+	if strings.TrimSpace(data.OrderID) == "" {
+		return nil, errors.New("order ID is required")
+	}
+
+	order := &OrderPlaced{
+		OrderID:   data.OrderID,
+		Items:     make([]OrderLine, 0, len(data.Items)),
+		OrderedAt: event.Timestamp,
+	}
+	if createdAt, err := time.Parse(time.RFC3339, data.CreatedAt); err == nil {
+		order.OrderedAt = createdAt
+	}
+	if order.OrderedAt.IsZero() {
+		order.OrderedAt = time.Now()
+	}
+	if data.UserID != "" {
+		userID, err := uuid.Parse(data.UserID)
+		if err != nil {
+			return nil, errors.New("invalid user ID in order")
+		}
+		order.UserID = &userID
+	}
+
+	for i, item := range data.Items {
+		productID, err := uuid.Parse(item.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid product ID in order item %d", i+1)
+		}
+		line := OrderLine{ProductID: productID, Quantity: item.Quantity}
+		if item.VariantID != "" {
+			variantID, err := uuid.Parse(item.VariantID)
+			if err != nil {
+				return nil, fmt.Errorf("invalid variant ID in order item %d", i+1)
+			}
+			line.VariantID = &variantID
+		}
+		order.Items = append(order.Items, line)
+	}
+
+	return order, nil
+}
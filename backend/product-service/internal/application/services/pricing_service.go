@@ -0,0 +1,729 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// PricingService manages price lists and resolves the price a shopper pays. Product and
+// variant prices are in the base currency and are the fallback when no price list
+// entry applies. Running sales are applied on top of the resolved price.
+type PricingService struct {
+	priceListRepo repositories.PriceListRepository
+	saleRepo      repositories.SaleRepository
+	productRepo   repositories.ProductRepository
+	variantRepo   repositories.ProductVariantRepository
+	categoryRepo  repositories.CategoryRepository
+	baseCurrency  string
+}
+
+// NewPricingService creates a new pricing service
+func NewPricingService(
+	priceListRepo repositories.PriceListRepository,
+	saleRepo repositories.SaleRepository,
+	productRepo repositories.ProductRepository,
+	variantRepo repositories.ProductVariantRepository,
+	categoryRepo repositories.CategoryRepository,
+	baseCurrency string,
+) *PricingService {
+	return &PricingService{
+		priceListRepo: priceListRepo,
+		saleRepo:      saleRepo,
+		productRepo:   productRepo,
+		variantRepo:   variantRepo,
+		categoryRepo:  categoryRepo,
+		baseCurrency:  entities.NormalizeCurrency(baseCurrency),
+	}
+}
+
+// PriceContext is who a price is resolved for. An empty currency means the base currency.
+type PriceContext struct {
+	Currency      string `json:"currency"`
+	Channel       string `json:"channel"`
+	CustomerGroup string `json:"customer_group"`
+}
+
+// PriceListRequest represents a create or update price list request
+type PriceListRequest struct {
+	Name          string `json:"name" validate:"required"`
+	Currency      string `json:"currency" validate:"required,len=3"`
+	Channel       string `json:"channel"`
+	CustomerGroup string `json:"customer_group"`
+	IsActive      *bool  `json:"is_active"`
+}
+
+// PriceListEntryRequest represents a request to set a product or variant price
+type PriceListEntryRequest struct {
+	ProductID uuid.UUID  `json:"product_id" validate:"required"`
+	VariantID *uuid.UUID `json:"variant_id"`
+	UpdatePriceListEntryRequest
+}
+
+// UpdatePriceListEntryRequest represents a request to change a price list entry
+type UpdatePriceListEntryRequest struct {
+	Price        float64    `json:"price" validate:"min=0"`
+	ComparePrice *float64   `json:"compare_price" validate:"omitempty,min=0"`
+	ValidFrom    *time.Time `json:"valid_from"`
+	ValidUntil   *time.Time `json:"valid_until"`
+}
+
+// QuoteRequest asks for the price of a list of SKUs and quantities
+type QuoteRequest struct {
+	Lines []QuoteLineRequest `json:"lines" validate:"required,min=1"`
+}
+
+// QuoteLineRequest is one SKU, of a product or a variant, and the quantity wanted
+type QuoteLineRequest struct {
+	SKU      string `json:"sku" validate:"required"`
+	Quantity int    `json:"qty" validate:"required,min=1"`
+}
+
+// QuoteLine is the price of one line of a quote. RegularPrice is the unit price of a
+// single unit; Tier is the quantity break that lowered it, if any.
+type QuoteLine struct {
+	SKU          string              `json:"sku"`
+	ProductID    uuid.UUID           `json:"product_id"`
+	VariantID    *uuid.UUID          `json:"variant_id,omitempty"`
+	Quantity     int                 `json:"qty"`
+	RegularPrice float64             `json:"regular_price"`
+	UnitPrice    float64             `json:"unit_price"`
+	LineTotal    float64             `json:"line_total"`
+	Tier         *entities.PriceTier `json:"tier,omitempty"`
+	SaleID       *uuid.UUID          `json:"sale_id,omitempty"`
+}
+
+// Quote is the price of a list of SKUs, in the base currency
+type Quote struct {
+	Lines    []QuoteLine `json:"lines"`
+	Total    float64     `json:"total"`
+	Currency string      `json:"currency"`
+}
+
+// PriceListEntryListResult is a page of price list entries
+type PriceListEntryListResult struct {
+	Entries    []*entities.PriceListEntry `json:"entries"`
+	Total      int64                      `json:"total"`
+	Page       int                        `json:"page"`
+	PageSize   int                        `json:"page_size"`
+	TotalPages int                        `json:"total_pages"`
+}
+
+// BaseCurrency returns the currency of product and variant prices
+func (s *PricingService) BaseCurrency() string {
+	return s.baseCurrency
+}
+
+// CreatePriceList creates a price list. There is at most one list per currency, channel
+// and customer group.
+func (s *PricingService) CreatePriceList(ctx context.Context, req *PriceListRequest) (*entities.PriceList, error) {
+	list, err := entities.NewPriceList(req.Name, req.Currency, req.Channel, req.CustomerGroup)
+	if err != nil {
+		return nil, err
+	}
+	if req.IsActive != nil {
+		list.SetActive(*req.IsActive)
+	}
+
+	if err := s.checkKeyUnique(ctx, list, nil); err != nil {
+		return nil, err
+	}
+
+	if err := s.priceListRepo.Create(ctx, list); err != nil {
+		return nil, fmt.Errorf("failed to create price list: %w", err)
+	}
+
+	return list, nil
+}
+
+// GetPriceList retrieves a price list by ID
+func (s *PricingService) GetPriceList(ctx context.Context, id uuid.UUID) (*entities.PriceList, error) {
+	list, err := s.priceListRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price list: %w", err)
+	}
+	if list == nil {
+		return nil, errors.New("price list not found")
+	}
+	return list, nil
+}
+
+// ListPriceLists lists all price lists
+func (s *PricingService) ListPriceLists(ctx context.Context) ([]*entities.PriceList, error) {
+	lists, err := s.priceListRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list price lists: %w", err)
+	}
+	if lists == nil {
+		lists = []*entities.PriceList{}
+	}
+	return lists, nil
+}
+
+// UpdatePriceList changes a price list's name, key and active state
+func (s *PricingService) UpdatePriceList(ctx context.Context, id uuid.UUID, req *PriceListRequest) (*entities.PriceList, error) {
+	list, err := s.GetPriceList(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := list.Update(req.Name, req.Currency, req.Channel, req.CustomerGroup); err != nil {
+		return nil, err
+	}
+	if req.IsActive != nil {
+		list.SetActive(*req.IsActive)
+	}
+
+	if err := s.checkKeyUnique(ctx, list, &id); err != nil {
+		return nil, err
+	}
+
+	if err := s.priceListRepo.Update(ctx, list); err != nil {
+		return nil, fmt.Errorf("failed to update price list: %w", err)
+	}
+
+	return list, nil
+}
+
+// DeletePriceList deletes a price list with all its prices
+func (s *PricingService) DeletePriceList(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.GetPriceList(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.priceListRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete price list: %w", err)
+	}
+
+	return nil
+}
+
+// AddPrice adds a product or variant price to a price list
+func (s *PricingService) AddPrice(ctx context.Context, priceListID uuid.UUID, req *PriceListEntryRequest) (*entities.PriceListEntry, error) {
+	if _, err := s.GetPriceList(ctx, priceListID); err != nil {
+		return nil, err
+	}
+
+	product, err := s.productRepo.GetByID(ctx, req.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if product == nil {
+		return nil, errors.New("product not found")
+	}
+
+	if req.VariantID != nil {
+		variant, err := s.variantRepo.GetByID(ctx, *req.VariantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get variant: %w", err)
+		}
+		if variant == nil || variant.ProductID != product.ID {
+			return nil, errors.New("variant not found")
+		}
+	}
+
+	entry := entities.NewPriceListEntry(priceListID, product.ID, req.VariantID)
+	if err := entry.SetPrice(req.Price, req.ComparePrice, req.ValidFrom, req.ValidUntil); err != nil {
+		return nil, err
+	}
+
+	if err := s.priceListRepo.CreateEntry(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to create price: %w", err)
+	}
+
+	return entry, nil
+}
+
+// ListPrices lists the prices in a price list
+func (s *PricingService) ListPrices(ctx context.Context, priceListID uuid.UUID, page, pageSize int) (*PriceListEntryListResult, error) {
+	if _, err := s.GetPriceList(ctx, priceListID); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.priceListRepo.GetEntries(ctx, priceListID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prices: %w", err)
+	}
+	total, err := s.priceListRepo.CountEntries(ctx, priceListID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count prices: %w", err)
+	}
+
+	if entries == nil {
+		entries = []*entities.PriceListEntry{}
+	}
+
+	return &PriceListEntryListResult{
+		Entries:    entries,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+	}, nil
+}
+
+// UpdatePrice changes a price list entry
+func (s *PricingService) UpdatePrice(ctx context.Context, priceListID, entryID uuid.UUID, req *UpdatePriceListEntryRequest) (*entities.PriceListEntry, error) {
+	entry, err := s.getEntry(ctx, priceListID, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := entry.SetPrice(req.Price, req.ComparePrice, req.ValidFrom, req.ValidUntil); err != nil {
+		return nil, err
+	}
+
+	if err := s.priceListRepo.UpdateEntry(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to update price: %w", err)
+	}
+
+	return entry, nil
+}
+
+// DeletePrice removes a price list entry
+func (s *PricingService) DeletePrice(ctx context.Context, priceListID, entryID uuid.UUID) error {
+	if _, err := s.getEntry(ctx, priceListID, entryID); err != nil {
+		return err
+	}
+
+	if err := s.priceListRepo.DeleteEntry(ctx, entryID); err != nil {
+		return fmt.Errorf("failed to delete price: %w", err)
+	}
+
+	return nil
+}
+
+// ResolvePrice resolves the price of a product, or of one of its variants, for a
+// shopper. The best (lowest) applicable price list price wins, with variant prices
+// taking precedence over product prices; the base price is the fallback. The running
+// sale that lowers that price the most is then applied.
+func (s *PricingService) ResolvePrice(ctx context.Context, productID uuid.UUID, variantID *uuid.UUID, pc PriceContext) (*entities.ResolvedPrice, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if product == nil {
+		return nil, errors.New("product not found")
+	}
+
+	var variant *entities.ProductVariant
+	if variantID != nil {
+		variant, err = s.variantRepo.GetByID(ctx, *variantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get variant: %w", err)
+		}
+		if variant == nil || variant.ProductID != product.ID {
+			return nil, errors.New("variant not found")
+		}
+	}
+
+	pc, err = s.normalizeContext(pc)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.findApplicable(ctx, []uuid.UUID{product.ID}, pc)
+	if err != nil {
+		return nil, err
+	}
+
+	sales, err := s.findRunningSales(ctx, []*entities.Product{product})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.resolve(product, variant, entries, sales, pc), nil
+}
+
+// ApplyPrices fills in the resolved price of each product in the shopper's currency and
+// customer group, and the sale price of each product and its loaded variants, so
+// product reads show the prices in effect at the time
+func (s *PricingService) ApplyPrices(ctx context.Context, products []*entities.Product, pc PriceContext) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	pc, err := s.normalizeContext(pc)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]uuid.UUID, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+	}
+
+	entries, err := s.findApplicable(ctx, ids, pc)
+	if err != nil {
+		return err
+	}
+
+	sales, err := s.findRunningSales(ctx, products)
+	if err != nil {
+		return err
+	}
+
+	for _, product := range products {
+		product.ResolvedPrice = s.resolve(product, nil, entries, sales, pc)
+		s.applySales(product, sales)
+	}
+
+	return nil
+}
+
+// applySales sets the sale price of a product and its loaded variants, in the base
+// currency
+func (s *PricingService) applySales(product *entities.Product, sales *runningSales) {
+	if sale, price := sales.best(product, nil, product.Price, s.baseCurrency, s.baseCurrency); sale != nil {
+		product.SetSalePrice(price, sale.EndsAt)
+	} else {
+		product.DiscountPercentage = product.GetDiscountPercentage()
+	}
+
+	for i := range product.Variants {
+		variant := &product.Variants[i]
+		regular := variant.GetEffectivePrice(product.Price)
+		if sale, price := sales.best(product, variant, regular, s.baseCurrency, s.baseCurrency); sale != nil {
+			variant.IsOnSale = true
+			variant.SalePrice = price
+		}
+	}
+}
+
+// Quote prices a list of SKUs and quantities in the base currency. Each unit costs the
+// product or variant price, lowered by the quantity break for the total quantity of that
+// SKU in the quote and then by the best running sale.
+func (s *PricingService) Quote(ctx context.Context, req *QuoteRequest) (*Quote, error) {
+	if len(req.Lines) == 0 {
+		return nil, errors.New("at least one line is required")
+	}
+
+	quantities := make(map[string]int)
+	var skus []string
+	for _, line := range req.Lines {
+		if strings.TrimSpace(line.SKU) == "" {
+			return nil, errors.New("SKU is required")
+		}
+		if line.Quantity < 1 {
+			return nil, fmt.Errorf("quantity of %s must be at least 1", line.SKU)
+		}
+		sku := strings.TrimSpace(line.SKU)
+		if _, ok := quantities[sku]; !ok {
+			skus = append(skus, sku)
+		}
+		quantities[sku] += line.Quantity
+	}
+
+	products, variants, err := s.findBySKUs(ctx, skus)
+	if err != nil {
+		return nil, err
+	}
+
+	quoted := make([]*entities.Product, 0, len(products))
+	productsBySKU := make(map[string]*entities.Product, len(products))
+	for _, product := range products {
+		quoted = append(quoted, product)
+		productsBySKU[product.SKU] = product
+	}
+	sales, err := s.findRunningSales(ctx, quoted)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	quote := &Quote{Lines: make([]QuoteLine, 0, len(req.Lines)), Currency: s.baseCurrency}
+	for _, lineReq := range req.Lines {
+		sku := strings.TrimSpace(lineReq.SKU)
+		line := QuoteLine{SKU: sku, Quantity: lineReq.Quantity}
+
+		variant := variants[sku]
+		product := productsBySKU[sku]
+		if variant != nil {
+			product = products[variant.ProductID]
+		}
+		if product == nil {
+			return nil, fmt.Errorf("unknown SKU %s", sku)
+		}
+		if !product.IsListed() || !product.InAvailabilityWindow(now) || (variant != nil && !variant.IsActive) {
+			return nil, fmt.Errorf("SKU %s is not available", sku)
+		}
+
+		line.ProductID = product.ID
+		if variant != nil {
+			line.VariantID = &variant.ID
+			line.RegularPrice = variant.GetEffectivePrice(product.Price)
+			line.UnitPrice, line.Tier = variant.GetEffectiveUnitPrice(product.Price, product.PriceTiers, quantities[sku])
+		} else {
+			line.RegularPrice = product.Price
+			line.UnitPrice, line.Tier = product.GetUnitPrice(quantities[sku])
+		}
+
+		if sale, price := sales.best(product, variant, line.UnitPrice, s.baseCurrency, s.baseCurrency); sale != nil {
+			line.UnitPrice = price
+			line.SaleID = &sale.ID
+		}
+
+		line.LineTotal = roundPrice(line.UnitPrice * float64(line.Quantity))
+		quote.Total += line.LineTotal
+		quote.Lines = append(quote.Lines, line)
+	}
+	quote.Total = roundPrice(quote.Total)
+
+	return quote, nil
+}
+
+// findBySKUs loads the variants with the given SKUs, keyed by SKU, and the products
+// with the other SKUs and the parents of the variants, keyed by ID
+func (s *PricingService) findBySKUs(ctx context.Context, skus []string) (map[uuid.UUID]*entities.Product, map[string]*entities.ProductVariant, error) {
+	variantList, err := s.variantRepo.GetBySKUs(ctx, skus)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get variants: %w", err)
+	}
+
+	variants := make(map[string]*entities.ProductVariant, len(variantList))
+	var parentIDs []uuid.UUID
+	for _, variant := range variantList {
+		variants[variant.SKU] = variant
+		parentIDs = append(parentIDs, variant.ProductID)
+	}
+
+	var productSKUs []string
+	for _, sku := range skus {
+		if variants[sku] == nil {
+			productSKUs = append(productSKUs, sku)
+		}
+	}
+
+	products := make(map[uuid.UUID]*entities.Product)
+	if len(productSKUs) > 0 {
+		list, err := s.productRepo.GetBySKUs(ctx, productSKUs)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get products: %w", err)
+		}
+		for _, product := range list {
+			products[product.ID] = product
+		}
+	}
+	if len(parentIDs) > 0 {
+		list, err := s.productRepo.GetByIDs(ctx, parentIDs)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get products: %w", err)
+		}
+		for _, product := range list {
+			products[product.ID] = product
+		}
+	}
+
+	return products, variants, nil
+}
+
+// roundPrice rounds an amount to cents
+func roundPrice(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// resolve picks the price of a product or variant from the applicable entries and
+// applies the best running sale to it
+func (s *PricingService) resolve(product *entities.Product, variant *entities.ProductVariant, entries []*entities.PriceListEntry, sales *runningSales, pc PriceContext) *entities.ResolvedPrice {
+	resolved := s.resolveListPrice(product, variant, entries, pc)
+
+	if sale, price := sales.best(product, variant, resolved.Amount, resolved.Currency, s.baseCurrency); sale != nil {
+		resolved.RegularAmount = resolved.Amount
+		resolved.Amount = price
+		resolved.IsOnSale = true
+		resolved.SaleID = &sale.ID
+		resolved.SaleEndsAt = &sale.EndsAt
+	}
+
+	return resolved
+}
+
+// resolveListPrice picks the price of a product or variant from the applicable entries,
+// falling back to the base price
+func (s *PricingService) resolveListPrice(product *entities.Product, variant *entities.ProductVariant, entries []*entities.PriceListEntry, pc PriceContext) *entities.ResolvedPrice {
+	var best, bestProductLevel *entities.PriceListEntry
+	for _, entry := range entries {
+		if entry.ProductID != product.ID {
+			continue
+		}
+		switch {
+		case entry.VariantID == nil:
+			if betterEntry(entry, bestProductLevel) {
+				bestProductLevel = entry
+			}
+		case variant != nil && *entry.VariantID == variant.ID:
+			if betterEntry(entry, best) {
+				best = entry
+			}
+		}
+	}
+	if best == nil {
+		best = bestProductLevel
+	}
+
+	resolved := &entities.ResolvedPrice{ProductID: product.ID}
+	if variant != nil {
+		resolved.VariantID = &variant.ID
+	}
+
+	if best == nil {
+		resolved.Amount = product.Price
+		resolved.ComparePrice = product.ComparePrice
+		if variant != nil {
+			resolved.Amount = variant.GetEffectivePrice(product.Price)
+			resolved.ComparePrice = variant.GetEffectiveComparePrice(product.ComparePrice)
+		}
+		resolved.Currency = s.baseCurrency
+		resolved.Source = entities.PriceSourceBase
+		return resolved
+	}
+
+	resolved.Amount = best.Price
+	if best.ComparePrice != nil {
+		resolved.ComparePrice = *best.ComparePrice
+	}
+	resolved.Currency = pc.Currency
+	resolved.Source = entities.PriceSourcePriceList
+	resolved.PriceListID = &best.PriceListID
+	resolved.ValidUntil = best.ValidUntil
+	return resolved
+}
+
+// betterEntry reports whether entry beats current: a lower price, or the same price
+// from a more narrowly targeted list
+func betterEntry(entry, current *entities.PriceListEntry) bool {
+	if current == nil {
+		return true
+	}
+	if entry.Price != current.Price {
+		return entry.Price < current.Price
+	}
+	return entry.PriceList != nil && current.PriceList != nil &&
+		entry.PriceList.Specificity() > current.PriceList.Specificity()
+}
+
+// findApplicable loads the entries that apply now in a pricing context
+func (s *PricingService) findApplicable(ctx context.Context, productIDs []uuid.UUID, pc PriceContext) ([]*entities.PriceListEntry, error) {
+	entries, err := s.priceListRepo.FindApplicable(ctx, productIDs, pc.Currency, pc.Channel, pc.CustomerGroup, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to find prices: %w", err)
+	}
+	return entries, nil
+}
+
+// runningSales are the sales running for a set of products, with the ancestors of each
+// of their categories
+type runningSales struct {
+	sales     []*entities.Sale
+	ancestors map[uuid.UUID]map[uuid.UUID]bool // category ID -> the category and its ancestors
+}
+
+// best returns the sale that lowers a product's or variant's price the most, with the
+// sale price, or nil when no running sale lowers it
+func (r *runningSales) best(product *entities.Product, variant *entities.ProductVariant, price float64, currency, baseCurrency string) (*entities.Sale, float64) {
+	var best *entities.Sale
+	bestPrice := price
+	for _, sale := range r.sales {
+		if !sale.AppliesTo(product, variant, r.ancestors[product.CategoryID]) {
+			continue
+		}
+		if salePrice, ok := sale.Apply(price, currency, baseCurrency); ok && salePrice < bestPrice {
+			best = sale
+			bestPrice = salePrice
+		}
+	}
+	return best, bestPrice
+}
+
+// findRunningSales loads the sales running now for products, their variants, the
+// categories they are in (including parent categories) and their brands
+func (s *PricingService) findRunningSales(ctx context.Context, products []*entities.Product) (*runningSales, error) {
+	result := &runningSales{ancestors: make(map[uuid.UUID]map[uuid.UUID]bool)}
+
+	var productIDs, categoryIDs, brandIDs []uuid.UUID
+	seenCategories := make(map[uuid.UUID]bool)
+	seenBrands := make(map[uuid.UUID]bool)
+	for _, product := range products {
+		productIDs = append(productIDs, product.ID)
+
+		if product.BrandID != nil && !seenBrands[*product.BrandID] {
+			seenBrands[*product.BrandID] = true
+			brandIDs = append(brandIDs, *product.BrandID)
+		}
+
+		if product.CategoryID == uuid.Nil {
+			continue
+		}
+		if _, ok := result.ancestors[product.CategoryID]; ok {
+			continue
+		}
+		path, err := s.categoryRepo.GetPath(ctx, product.CategoryID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get category path: %w", err)
+		}
+		ancestors := map[uuid.UUID]bool{product.CategoryID: true}
+		for _, category := range path {
+			ancestors[category.ID] = true
+		}
+		result.ancestors[product.CategoryID] = ancestors
+		for id := range ancestors {
+			if !seenCategories[id] {
+				seenCategories[id] = true
+				categoryIDs = append(categoryIDs, id)
+			}
+		}
+	}
+
+	sales, err := s.saleRepo.FindRunning(ctx, productIDs, categoryIDs, brandIDs, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sales: %w", err)
+	}
+	result.sales = sales
+
+	return result, nil
+}
+
+// normalizeContext validates a pricing context and fills in the base currency
+func (s *PricingService) normalizeContext(pc PriceContext) (PriceContext, error) {
+	pc.Currency = entities.NormalizeCurrency(pc.Currency)
+	if pc.Currency == "" {
+		pc.Currency = s.baseCurrency
+	}
+	if !entities.IsValidCurrency(pc.Currency) {
+		return pc, errors.New("currency must be a three-letter ISO 4217 code")
+	}
+	pc.Channel = entities.NormalizePriceKey(pc.Channel)
+	pc.CustomerGroup = entities.NormalizePriceKey(pc.CustomerGroup)
+	return pc, nil
+}
+
+// getEntry loads an entry and checks it belongs to the price list
+func (s *PricingService) getEntry(ctx context.Context, priceListID, entryID uuid.UUID) (*entities.PriceListEntry, error) {
+	entry, err := s.priceListRepo.GetEntry(ctx, entryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price: %w", err)
+	}
+	if entry == nil || entry.PriceListID != priceListID {
+		return nil, errors.New("price not found")
+	}
+	return entry, nil
+}
+
+// checkKeyUnique rejects a price list whose key is taken by another list
+func (s *PricingService) checkKeyUnique(ctx context.Context, list *entities.PriceList, excludeID *uuid.UUID) error {
+	existing, err := s.priceListRepo.GetByKey(ctx, list.Currency, list.Channel, list.CustomerGroup)
+	if err != nil {
+		return fmt.Errorf("failed to check price list key: %w", err)
+	}
+	if existing != nil && (excludeID == nil || existing.ID != *excludeID) {
+		return errors.New("price list for this currency, channel and customer group already exists")
+	}
+	return nil
+}
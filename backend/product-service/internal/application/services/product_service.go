@@ -4,9 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"product-service/internal/domain/entities"
+	"product-service/internal/domain/events"
 	"product-service/internal/domain/repositories"
 )
 
@@ -18,6 +22,14 @@ type ProductService struct {
 	variantRepo  repositories.ProductVariantRepository
 	imageRepo    repositories.ProductImageRepository
 	videoRepo    repositories.ProductVideoRepository
+	slugRepo     repositories.SlugRedirectRepository
+	suggestions  *SuggestionCache
+	
+	attributeSchemas *AttributeSchemaService
+	searchAnalytics  *SearchAnalyticsService
+	revisions        *RevisionService
+	bundles          *BundleService
+	publisher        events.Publisher
 }
 
 // NewProductService creates a new product service
@@ -28,6 +40,13 @@ func NewProductService(
 	variantRepo repositories.ProductVariantRepository,
 	imageRepo repositories.ProductImageRepository,
 	videoRepo repositories.ProductVideoRepository,
+	slugRepo repositories.SlugRedirectRepository,
+	attributeSchemas *AttributeSchemaService,
+	searchAnalytics *SearchAnalyticsService,
+	revisions *RevisionService,
+	bundles *BundleService,
+	suggestions *SuggestionCache,
+	publisher events.Publisher,
 ) *ProductService {
 	return &ProductService{
 		productRepo:  productRepo,
@@ -36,6 +55,14 @@ func NewProductService(
 		variantRepo:  variantRepo,
 		imageRepo:    imageRepo,
 		videoRepo:    videoRepo,
+		slugRepo:     slugRepo,
+		suggestions:  suggestions,
+		
+		attributeSchemas: attributeSchemas,
+		searchAnalytics:  searchAnalytics,
+		revisions:        revisions,
+		bundles:          bundles,
+		publisher:        publisher,
 	}
 }
 
@@ -104,8 +131,14 @@ func (s *ProductService) CreateProduct(ctx context.Context, req *CreateProductRe
 		product.Tags = req.Tags
 	}
 	
-	if len(req.Attributes) > 0 {
-		product.Attributes = req.Attributes
+	// Validate attributes against the category schema, which also catches missing
+	// required attributes
+	attributes, err := s.attributeSchemas.ValidateAttributes(ctx, req.CategoryID, req.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	if len(attributes) > 0 {
+		product.Attributes = attributes
 	}
 	
 	// Set inventory settings
@@ -116,11 +149,26 @@ func (s *ProductService) CreateProduct(ctx context.Context, req *CreateProductRe
 		}
 	}
 	
+	// New products are drafts, published by the scheduler when PublishAt comes
+	if err := product.SetSchedule(req.PublishAt, req.AvailableFrom, req.AvailableUntil); err != nil {
+		return nil, err
+	}
+	
+	actor := ActorFromContext(ctx)
+	product.CreatedBy = actor
+	product.UpdatedBy = actor
+	
 	// Save product
-	err = s.productRepo.Create(ctx, product)
+	err = s.revisions.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.productRepo.Create(ctx, product); err != nil {
+			return fmt.Errorf("failed to save product: %w", err)
+		}
+		return s.revisions.Record(ctx, entities.NewProductRevision(product.ID, entities.RevisionEntityProduct, product.ID, entities.RevisionActionCreate, actor, nil, product))
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to save product: %w", err)
+		return nil, err
 	}
+	s.suggestions.clear()
 	
 	// Increment category product count
 	err = s.categoryRepo.IncrementProductCount(ctx, req.CategoryID)
@@ -163,13 +211,25 @@ func (s *ProductService) GetProductBySKU(ctx context.Context, sku string, includ
 	return product, nil
 }
 
-// GetProductBySlug retrieves a product by slug
+// GetProductBySlug retrieves a product by slug. A product found by a slug it used to
+// have is returned too; its Slug then differs from the one asked for.
 func (s *ProductService) GetProductBySlug(ctx context.Context, slug string, includeRelated bool) (*entities.Product, error) {
 	product, err := s.productRepo.GetBySlug(ctx, slug)
 	if err != nil {
 		return nil, err
 	}
 	
+	if product == nil {
+		productID, err := resolveOldSlug(ctx, s.slugRepo, entities.SlugEntityProduct, slug)
+		if err != nil || productID == nil {
+			return nil, err
+		}
+		product, err = s.productRepo.GetByID(ctx, *productID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	
 	if product != nil && includeRelated {
 		return s.productRepo.LoadComplete(ctx, product.ID)
 	}
@@ -177,8 +237,10 @@ func (s *ProductService) GetProductBySlug(ctx context.Context, slug string, incl
 	return product, nil
 }
 
-// UpdateProduct updates an existing product
-func (s *ProductService) UpdateProduct(ctx context.Context, id uuid.UUID, req *UpdateProductRequest) (*entities.Product, error) {
+// UpdateProduct updates an existing product. When version is given, the update only
+// applies to that version of the product and fails with repositories.ErrVersionConflict
+// otherwise.
+func (s *ProductService) UpdateProduct(ctx context.Context, id uuid.UUID, version *int, req *UpdateProductRequest) (*entities.Product, error) {
 	// Get existing product
 	product, err := s.productRepo.GetByID(ctx, id)
 	if err != nil {
@@ -187,10 +249,15 @@ func (s *ProductService) UpdateProduct(ctx context.Context, id uuid.UUID, req *U
 	if product == nil {
 		return nil, errors.New("product not found")
 	}
+	if version != nil && *version != product.Version {
+		return nil, repositories.ErrVersionConflict
+	}
 	
-	// Store old category and brand for count updates
+	// Store old category and brand for count updates, and the old state for history
 	oldCategoryID := product.CategoryID
 	oldBrandID := product.BrandID
+	oldSlug := product.Slug
+	before := entities.TakeSnapshot(product)
 	
 	// Update basic info
 	if req.Name != "" || req.Description != "" || req.ShortDescription != "" {
@@ -260,14 +327,29 @@ func (s *ProductService) UpdateProduct(ctx context.Context, id uuid.UUID, req *U
 		product.UpdateSEO(req.MetaTitle, req.MetaDescription, req.Tags)
 	}
 	
-	// Update attributes
+	// Update attributes, validating the merged set against the schema of the product's
+	// category whenever either changes
 	if len(req.Attributes) > 0 {
 		for key, value := range req.Attributes {
+			// Schema keys are matched case-insensitively, so drop differently cased copies
+			for existing := range product.Attributes {
+				if existing != key && strings.EqualFold(existing, key) {
+					product.RemoveAttribute(existing)
+				}
+			}
 			product.AddAttribute(key, value)
 		}
 	}
+	if len(req.Attributes) > 0 || product.CategoryID != oldCategoryID {
+		attributes, err := s.attributeSchemas.ValidateAttributes(ctx, product.CategoryID, product.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		product.Attributes = attributes
+	}
 	
 	// Update status and visibility
+	oldStatus := product.Status
 	if req.Status != nil {
 		product.SetStatus(*req.Status)
 	}
@@ -280,51 +362,411 @@ func (s *ProductService) UpdateProduct(ctx context.Context, id uuid.UUID, req *U
 		product.SetFeatured(*req.Featured)
 	}
 	
+	// A bundle's stock, and computed price, come from its components
+	if product.IsBundle {
+		if err := s.bundles.Derive(ctx, product); err != nil {
+			return nil, err
+		}
+	}
+	
+	// Renaming changes the slug, which must stay unique
+	if product.Slug != oldSlug {
+		existing, err := s.productRepo.GetBySlug(ctx, product.Slug)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check slug: %w", err)
+		}
+		if existing != nil && existing.ID != product.ID {
+			return nil, errors.New("slug is taken by another product")
+		}
+	}
+	
 	// Save product
-	err = s.productRepo.Update(ctx, product)
+	actor := ActorFromContext(ctx)
+	product.UpdatedBy = actor
+	err = s.revisions.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.productRepo.Update(ctx, product); err != nil {
+			if errors.Is(err, repositories.ErrVersionConflict) {
+				return err
+			}
+			return fmt.Errorf("failed to update product: %w", err)
+		}
+		if err := recordSlugChange(ctx, s.slugRepo, entities.SlugEntityProduct, product.ID, oldSlug, product.Slug); err != nil {
+			return err
+		}
+		if !product.IsBundle {
+			if err := s.bundles.RefreshBundles(ctx, product.ID); err != nil {
+				return err
+			}
+		}
+		return s.revisions.Record(ctx, entities.NewProductRevision(product.ID, entities.RevisionEntityProduct, product.ID, entities.RevisionActionUpdate, actor, before, product))
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to update product: %w", err)
+		return nil, err
 	}
+	s.suggestions.clear()
 	
-	// Update category counts if category changed
-	if req.CategoryID != uuid.Nil && req.CategoryID != oldCategoryID {
-		// Decrement old category count
-		err = s.categoryRepo.DecrementProductCount(ctx, oldCategoryID)
+	if product.Status != oldStatus {
+		publishStatusChange(ctx, s.publisher, product, oldStatus, statusChangeManual)
+	}
+	
+	// Update category and brand counts if they changed
+	s.moveProductCounts(ctx, oldCategoryID, product.CategoryID, oldBrandID, product.BrandID)
+	
+	return product, nil
+}
+
+// RollbackProduct restores the state a revision left a product, variant, image or video in.
+// The rollback is itself recorded as a new revision, in the same transaction.
+func (s *ProductService) RollbackProduct(ctx context.Context, productID uuid.UUID, number int) (*entities.ProductRevision, error) {
+	target, err := s.revisions.GetRevision(ctx, productID, number)
+	if err != nil {
+		return nil, err
+	}
+	if target.Action == entities.RevisionActionDelete {
+		return nil, errors.New("cannot roll back to a deletion")
+	}
+	
+	var revision *entities.ProductRevision
+	switch target.EntityType {
+	case entities.RevisionEntityProduct:
+		revision, err = s.rollbackProductFields(ctx, target)
+	case entities.RevisionEntityVariant:
+		revision, err = s.rollbackVariant(ctx, target)
+	case entities.RevisionEntityImage:
+		revision, err = s.rollbackImage(ctx, target)
+	case entities.RevisionEntityVideo:
+		revision, err = s.rollbackVideo(ctx, target)
+	default:
+		return nil, fmt.Errorf("unknown revision entity %q", target.EntityType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if revision == nil {
+		return nil, errors.New("record already matches this revision")
+	}
+	
+	return revision, nil
+}
+
+func (s *ProductService) rollbackProductFields(ctx context.Context, target *entities.ProductRevision) (*entities.ProductRevision, error) {
+	product, err := s.productRepo.GetByID(ctx, target.EntityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if product == nil {
+		return nil, errors.New("product not found")
+	}
+	
+	restored := &entities.Product{}
+	if err := target.Restore(restored); err != nil {
+		return nil, fmt.Errorf("failed to restore revision: %w", err)
+	}
+	restored.ID = product.ID
+	restored.Version = product.Version
+	restored.HasVariants = product.HasVariants
+	restored.IsBundle = product.IsBundle
+	restored.BundlePricing = product.BundlePricing
+	restored.BundleDiscountType = product.BundleDiscountType
+	restored.BundleDiscountValue = product.BundleDiscountValue
+	restored.ReviewCount = product.ReviewCount
+	restored.AverageRating = product.AverageRating
+	restored.CreatedAt = product.CreatedAt
+	restored.CreatedBy = product.CreatedBy
+	restored.UpdatedAt = time.Now()
+	restored.UpdatedBy = ActorFromContext(ctx)
+	
+	if restored.Slug != product.Slug {
+		existing, err := s.productRepo.GetBySlug(ctx, restored.Slug)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check slug: %w", err)
+		}
+		if existing != nil && existing.ID != product.ID {
+			return nil, errors.New("slug is taken by another product")
+		}
+	}
+	if restored.CategoryID != product.CategoryID {
+		category, err := s.categoryRepo.GetByID(ctx, restored.CategoryID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get category: %w", err)
+		}
+		if category == nil {
+			return nil, errors.New("category of this revision no longer exists")
+		}
+	}
+	if restored.BrandID != nil && (product.BrandID == nil || *restored.BrandID != *product.BrandID) {
+		brand, err := s.brandRepo.GetByID(ctx, *restored.BrandID)
 		if err != nil {
+			return nil, fmt.Errorf("failed to get brand: %w", err)
+		}
+		if brand == nil {
+			return nil, errors.New("brand of this revision no longer exists")
+		}
+	}
+	
+	if restored.IsBundle {
+		if err := s.bundles.Derive(ctx, restored); err != nil {
+			return nil, err
+		}
+	}
+	
+	revision := entities.NewProductRevision(product.ID, entities.RevisionEntityProduct, product.ID, entities.RevisionActionRollback, restored.UpdatedBy, product, restored)
+	if len(revision.Changes) == 0 {
+		return nil, nil
+	}
+	revision.RolledBackTo = &target.Number
+	
+	err = s.revisions.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.productRepo.Update(ctx, restored); err != nil {
+			return fmt.Errorf("failed to update product: %w", err)
+		}
+		if err := recordSlugChange(ctx, s.slugRepo, entities.SlugEntityProduct, restored.ID, product.Slug, restored.Slug); err != nil {
+			return err
+		}
+		if !restored.IsBundle {
+			if err := s.bundles.RefreshBundles(ctx, restored.ID); err != nil {
+				return err
+			}
+		}
+		return s.revisions.Record(ctx, revision)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.suggestions.clear()
+	
+	if restored.Status != product.Status {
+		publishStatusChange(ctx, s.publisher, restored, product.Status, statusChangeRollback)
+	}
+	s.moveProductCounts(ctx, product.CategoryID, restored.CategoryID, product.BrandID, restored.BrandID)
+	
+	return revision, nil
+}
+
+func (s *ProductService) rollbackVariant(ctx context.Context, target *entities.ProductRevision) (*entities.ProductRevision, error) {
+	variant, err := s.variantRepo.GetByID(ctx, target.EntityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get variant: %w", err)
+	}
+	if variant == nil || variant.ProductID != target.ProductID {
+		return nil, errors.New("variant not found")
+	}
+	
+	restored := &entities.ProductVariant{}
+	if err := target.Restore(restored); err != nil {
+		return nil, fmt.Errorf("failed to restore revision: %w", err)
+	}
+	restored.ID = variant.ID
+	restored.ProductID = variant.ProductID
+	restored.CreatedAt = variant.CreatedAt
+	restored.CreatedBy = variant.CreatedBy
+	restored.UpdatedAt = time.Now()
+	restored.UpdatedBy = ActorFromContext(ctx)
+	
+	if restored.SKU != variant.SKU {
+		existing, err := s.variantRepo.GetBySKU(ctx, restored.SKU)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check variant SKU: %w", err)
+		}
+		if existing != nil && existing.ID != variant.ID {
+			return nil, errors.New("SKU is taken by another variant")
+		}
+	}
+	
+	revision := entities.NewProductRevision(variant.ProductID, entities.RevisionEntityVariant, variant.ID, entities.RevisionActionRollback, restored.UpdatedBy, variant, restored)
+	if len(revision.Changes) == 0 {
+		return nil, nil
+	}
+	revision.RolledBackTo = &target.Number
+	
+	err = s.revisions.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.variantRepo.Update(ctx, restored); err != nil {
+			return fmt.Errorf("failed to update variant: %w", err)
+		}
+		return s.revisions.Record(ctx, revision)
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return revision, nil
+}
+
+func (s *ProductService) rollbackImage(ctx context.Context, target *entities.ProductRevision) (*entities.ProductRevision, error) {
+	image, err := s.imageRepo.GetByID(ctx, target.EntityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get image: %w", err)
+	}
+	if image == nil {
+		return nil, errors.New("image not found")
+	}
+	
+	restored := &entities.ProductImage{}
+	if err := target.Restore(restored); err != nil {
+		return nil, fmt.Errorf("failed to restore revision: %w", err)
+	}
+	restored.ID = image.ID
+	restored.ProductID = image.ProductID
+	restored.VariantID = image.VariantID
+	restored.CreatedAt = image.CreatedAt
+	restored.CreatedBy = image.CreatedBy
+	restored.UpdatedAt = time.Now()
+	restored.UpdatedBy = ActorFromContext(ctx)
+	
+	revision := entities.NewProductRevision(target.ProductID, entities.RevisionEntityImage, image.ID, entities.RevisionActionRollback, restored.UpdatedBy, image, restored)
+	if len(revision.Changes) == 0 {
+		return nil, nil
+	}
+	revision.RolledBackTo = &target.Number
+	
+	err = s.revisions.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.imageRepo.Update(ctx, restored); err != nil {
+			return fmt.Errorf("failed to update image: %w", err)
+		}
+		return s.revisions.Record(ctx, revision)
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return revision, nil
+}
+
+func (s *ProductService) rollbackVideo(ctx context.Context, target *entities.ProductRevision) (*entities.ProductRevision, error) {
+	video, err := s.videoRepo.GetByID(ctx, target.EntityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get video: %w", err)
+	}
+	if video == nil || video.ProductID != target.ProductID {
+		return nil, errors.New("video not found")
+	}
+	
+	restored := &entities.ProductVideo{}
+	if err := target.Restore(restored); err != nil {
+		return nil, fmt.Errorf("failed to restore revision: %w", err)
+	}
+	restored.ID = video.ID
+	restored.ProductID = video.ProductID
+	restored.StorageKey = video.StorageKey
+	restored.CreatedAt = video.CreatedAt
+	restored.CreatedBy = video.CreatedBy
+	restored.UpdatedAt = time.Now()
+	restored.UpdatedBy = ActorFromContext(ctx)
+	
+	revision := entities.NewProductRevision(target.ProductID, entities.RevisionEntityVideo, video.ID, entities.RevisionActionRollback, restored.UpdatedBy, video, restored)
+	if len(revision.Changes) == 0 {
+		return nil, nil
+	}
+	revision.RolledBackTo = &target.Number
+	
+	err = s.revisions.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.videoRepo.Update(ctx, restored); err != nil {
+			return fmt.Errorf("failed to update video: %w", err)
+		}
+		return s.revisions.Record(ctx, revision)
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return revision, nil
+}
+
+// moveProductCounts moves a product's contribution to category and brand product counts
+func (s *ProductService) moveProductCounts(ctx context.Context, oldCategoryID, newCategoryID uuid.UUID, oldBrandID, newBrandID *uuid.UUID) {
+	if newCategoryID != oldCategoryID {
+		// Decrement old category count
+		if err := s.categoryRepo.DecrementProductCount(ctx, oldCategoryID); err != nil {
 			fmt.Printf("Warning: failed to decrement old category product count: %v\n", err)
 		}
 		
 		// Increment new category count
-		err = s.categoryRepo.IncrementProductCount(ctx, req.CategoryID)
-		if err != nil {
+		if err := s.categoryRepo.IncrementProductCount(ctx, newCategoryID); err != nil {
 			fmt.Printf("Warning: failed to increment new category product count: %v\n", err)
 		}
 	}
 	
-	// Update brand counts if brand changed
-	if req.BrandID != nil {
-		if oldBrandID != nil && *req.BrandID != *oldBrandID {
-			// Decrement old brand count
-			err = s.brandRepo.DecrementProductCount(ctx, *oldBrandID)
-			if err != nil {
-				fmt.Printf("Warning: failed to decrement old brand product count: %v\n", err)
-			}
+	if oldBrandID != nil && newBrandID != nil && *oldBrandID == *newBrandID {
+		return
+	}
+	if oldBrandID != nil {
+		if err := s.brandRepo.DecrementProductCount(ctx, *oldBrandID); err != nil {
+			fmt.Printf("Warning: failed to decrement old brand product count: %v\n", err)
 		}
-		
-		if oldBrandID == nil || *req.BrandID != *oldBrandID {
-			// Increment new brand count
-			err = s.brandRepo.IncrementProductCount(ctx, *req.BrandID)
-			if err != nil {
-				fmt.Printf("Warning: failed to increment new brand product count: %v\n", err)
-			}
+	}
+	if newBrandID != nil {
+		if err := s.brandRepo.IncrementProductCount(ctx, *newBrandID); err != nil {
+			fmt.Printf("Warning: failed to increment new brand product count: %v\n", err)
+		}
+	}
+}
+
+// UpdateSchedule replaces a product's publishing schedule. Only drafts can be given a
+// publish time.
+func (s *ProductService) UpdateSchedule(ctx context.Context, id uuid.UUID, req *ProductScheduleRequest) (*entities.Product, error) {
+	product, err := s.productRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if product == nil {
+		return nil, errors.New("product not found")
+	}
+	
+	if req.PublishAt != nil && product.Status != entities.ProductStatusDraft {
+		return nil, errors.New("only draft products can be scheduled for publishing")
+	}
+	
+	before := entities.TakeSnapshot(product)
+	if err := product.SetSchedule(req.PublishAt, req.AvailableFrom, req.AvailableUntil); err != nil {
+		return nil, err
+	}
+	
+	product.UpdatedBy = ActorFromContext(ctx)
+	err = s.revisions.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.productRepo.Update(ctx, product); err != nil {
+			return fmt.Errorf("failed to update product: %w", err)
+		}
+		return s.revisions.Record(ctx, entities.NewProductRevision(product.ID, entities.RevisionEntityProduct, product.ID, entities.RevisionActionUpdate, product.UpdatedBy, before, product))
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return product, nil
+}
+
+// SetPriceTiers replaces a product's quantity breaks. An empty list removes them.
+func (s *ProductService) SetPriceTiers(ctx context.Context, id uuid.UUID, req *PriceTiersRequest) (*entities.Product, error) {
+	product, err := s.productRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if product == nil {
+		return nil, errors.New("product not found")
+	}
+	
+	before := entities.TakeSnapshot(product)
+	if err := product.SetPriceTiers(req.Tiers); err != nil {
+		return nil, err
+	}
+	
+	product.UpdatedBy = ActorFromContext(ctx)
+	err = s.revisions.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.productRepo.Update(ctx, product); err != nil {
+			return fmt.Errorf("failed to update product: %w", err)
 		}
+		return s.revisions.Record(ctx, entities.NewProductRevision(product.ID, entities.RevisionEntityProduct, product.ID, entities.RevisionActionUpdate, product.UpdatedBy, before, product))
+	})
+	if err != nil {
+		return nil, err
 	}
 	
 	return product, nil
 }
 
-// DeleteProduct deletes a product
-func (s *ProductService) DeleteProduct(ctx context.Context, id uuid.UUID) error {
+// DeleteProduct deletes a product. When version is given, only that version of the
+// product is deleted, as in UpdateProduct.
+func (s *ProductService) DeleteProduct(ctx context.Context, id uuid.UUID, version *int) error {
 	// Get product to access category and brand info
 	product, err := s.productRepo.GetByID(ctx, id)
 	if err != nil {
@@ -333,12 +775,32 @@ func (s *ProductService) DeleteProduct(ctx context.Context, id uuid.UUID) error
 	if product == nil {
 		return errors.New("product not found")
 	}
+	if version != nil && *version != product.Version {
+		return repositories.ErrVersionConflict
+	}
+	
+	bundleIDs, err := s.bundles.ComponentOf(ctx, product.ID)
+	if err != nil {
+		return err
+	}
+	if len(bundleIDs) > 0 {
+		return errors.New("product is a component of a bundle")
+	}
 	
 	// Delete product
-	err = s.productRepo.Delete(ctx, id)
+	err = s.revisions.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.productRepo.Delete(ctx, id, product.Version); err != nil {
+			if errors.Is(err, repositories.ErrVersionConflict) {
+				return err
+			}
+			return fmt.Errorf("failed to delete product: %w", err)
+		}
+		return s.revisions.Record(ctx, entities.NewProductRevision(product.ID, entities.RevisionEntityProduct, product.ID, entities.RevisionActionDelete, ActorFromContext(ctx), product, nil))
+	})
 	if err != nil {
-		return fmt.Errorf("failed to delete product: %w", err)
+		return err
 	}
+	s.suggestions.clear()
 	
 	// Decrement category product count
 	err = s.categoryRepo.DecrementProductCount(ctx, product.CategoryID)
@@ -359,6 +821,8 @@ func (s *ProductService) DeleteProduct(ctx context.Context, id uuid.UUID) error
 
 // SearchProducts searches for products with filters
 func (s *ProductService) SearchProducts(ctx context.Context, req *SearchProductsRequest) (*repositories.ProductSearchResult, error) {
+	start := time.Now()
+	
 	// Build filters
 	filters := &repositories.ProductFilters{
 		CategoryIDs: req.CategoryIDs,
@@ -372,6 +836,7 @@ func (s *ProductService) SearchProducts(ctx context.Context, req *SearchProducts
 		Tags:        req.Tags,
 		Attributes:  req.Attributes,
 		HasVariants: req.HasVariants,
+		IsBundle:    req.IsBundle,
 		CreatedFrom: req.CreatedFrom,
 		CreatedTo:   req.CreatedTo,
 	}
@@ -380,24 +845,60 @@ func (s *ProductService) SearchProducts(ctx context.Context, req *SearchProducts
 	offset := (req.Page - 1) * req.PageSize
 	
 	// Search products
-	products, err := s.productRepo.Search(ctx, req.Query, filters, req.PageSize, offset)
+	hits, err := s.productRepo.Search(ctx, req.Query, filters, req.PageSize, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search products: %w", err)
 	}
 	
+	products := make([]*entities.Product, len(hits))
+	var highlights map[string]repositories.ProductHighlight
+	for i, hit := range hits {
+		products[i] = hit.Product
+		if hit.Highlights.Name != "" || hit.Highlights.Description != "" {
+			if highlights == nil {
+				highlights = make(map[string]repositories.ProductHighlight, len(hits))
+			}
+			highlights[hit.Product.ID.String()] = hit.Highlights
+		}
+	}
+	
 	// Get total count
 	total, err := s.productRepo.CountSearch(ctx, req.Query, filters)
 	if err != nil {
 		return nil, fmt.Errorf("failed to count search results: %w", err)
 	}
 	
+	// Get facet counts for the filter sidebar
+	var facets map[string][]repositories.FacetValue
+	if req.IncludeFacets {
+		// Only attributes the category schemas mark as filterable become facets
+		attributeKeys, err := s.attributeSchemas.FilterableAttributes(ctx, req.CategoryIDs)
+		if err != nil {
+			return nil, err
+		}
+		facets, err = s.productRepo.GetSearchFacets(ctx, req.Query, filters, attributeKeys)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute search facets: %w", err)
+		}
+	}
+	
 	// Calculate pagination info
 	totalPages := int((total + int64(req.PageSize) - 1) / int64(req.PageSize))
 	hasNext := req.Page < totalPages
 	hasPrev := req.Page > 1
 	
+	// Log the first page only so paging through results is not counted as new searches;
+	// clients keep the search ID from page one when reporting clicks
+	var searchID string
+	if s.searchAnalytics != nil && req.Page == 1 {
+		searchID = s.searchAnalytics.RecordSearch(req.Query, filters, total, time.Since(start)).String()
+	}
+	
 	return &repositories.ProductSearchResult{
+		SearchID:   searchID,
 		Products:   products,
+		Highlights: highlights,
+		Facets:     facets,
 		Total:      total,
 		Page:       req.Page,
 		PageSize:   req.PageSize,
@@ -407,6 +908,28 @@ func (s *ProductService) SearchProducts(ctx context.Context, req *SearchProducts
 	}, nil
 }
 
+// SuggestProducts returns typeahead completions for product, brand and category names.
+// Results are cached briefly per normalized query so repeated keystrokes stay fast.
+func (s *ProductService) SuggestProducts(ctx context.Context, query string, limit int) ([]*repositories.Suggestion, error) {
+	normalized := strings.ToLower(strings.Join(strings.Fields(query), " "))
+	if normalized == "" {
+		return []*repositories.Suggestion{}, nil
+	}
+	
+	key := strconv.Itoa(limit) + ":" + normalized
+	if suggestions, ok := s.suggestions.get(key); ok {
+		return suggestions, nil
+	}
+	
+	suggestions, err := s.productRepo.Suggest(ctx, normalized, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suggestions: %w", err)
+	}
+	s.suggestions.set(key, suggestions)
+	
+	return suggestions, nil
+}
+
 // GetProductsByCategory gets products by category
 func (s *ProductService) GetProductsByCategory(ctx context.Context, categoryID uuid.UUID, page, pageSize int) ([]*entities.Product, error) {
 	offset := (page - 1) * pageSize
@@ -424,6 +947,29 @@ func (s *ProductService) GetFeaturedProducts(ctx context.Context, limit int) ([]
 	return s.productRepo.GetFeatured(ctx, limit, 0)
 }
 
+// GetRankedProducts ranks listed products by their views and sales over the last days,
+// optionally within a category
+func (s *ProductService) GetRankedProducts(ctx context.Context, ranking ProductRanking, limit, days int, categoryID *uuid.UUID) ([]*entities.Product, error) {
+	var (
+		products []*entities.Product
+		err      error
+	)
+	switch ranking {
+	case RankingTopSelling:
+		products, err = s.productRepo.GetTopSelling(ctx, limit, days, categoryID)
+	case RankingMostViewed:
+		products, err = s.productRepo.GetMostViewed(ctx, limit, days, categoryID)
+	case RankingTrending:
+		products, err = s.productRepo.GetTrending(ctx, limit, days, categoryID)
+	default:
+		return nil, errors.New("ranking must be top_selling, most_viewed or trending")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to rank products: %w", err)
+	}
+	return products, nil
+}
+
 // UpdateProductStock updates product stock
 func (s *ProductService) UpdateProductStock(ctx context.Context, id uuid.UUID, quantity int) error {
 	product, err := s.productRepo.GetByID(ctx, id)
@@ -434,15 +980,47 @@ func (s *ProductService) UpdateProductStock(ctx context.Context, id uuid.UUID, q
 		return errors.New("product not found")
 	}
 	
+	// Selling a bundle takes stock from its components; it has no stock of its own to add to
+	if product.IsBundle {
+		if quantity > 0 {
+			return errors.New("stock of a bundle is derived from its components")
+		}
+		return s.bundles.DeductStock(ctx, product, -quantity)
+	}
+	
+	before := entities.TakeSnapshot(product)
 	if quantity < 0 {
-		return product.DeductStock(-quantity)
+		err = product.DeductStock(-quantity)
 	} else {
-		return product.AddStock(quantity)
+		err = product.AddStock(quantity)
+	}
+	if err != nil {
+		return err
 	}
+	
+	product.UpdatedBy = ActorFromContext(ctx)
+	return s.revisions.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.productRepo.Update(ctx, product); err != nil {
+			return fmt.Errorf("failed to update product: %w", err)
+		}
+		if err := s.bundles.RefreshBundles(ctx, product.ID); err != nil {
+			return err
+		}
+		return s.revisions.Record(ctx, entities.NewProductRevision(product.ID, entities.RevisionEntityProduct, product.ID, entities.RevisionActionUpdate, product.UpdatedBy, before, product))
+	})
 }
 
 // Request and response types
 
+// ProductRanking names a ranking of products by their view and sale stats
+type ProductRanking string
+
+const (
+	RankingTopSelling ProductRanking = "top_selling"
+	RankingMostViewed ProductRanking = "most_viewed"
+	RankingTrending   ProductRanking = "trending"
+)
+
 // CreateProductRequest represents a create product request
 type CreateProductRequest struct {
 	SKU              string            `json:"sku" validate:"required"`
@@ -463,6 +1041,9 @@ type CreateProductRequest struct {
 	Height           float64           `json:"height" validate:"min=0"`
 	Tags             []string          `json:"tags"`
 	Attributes       map[string]string `json:"attributes"`
+	PublishAt        *time.Time        `json:"publish_at"`
+	AvailableFrom    *time.Time        `json:"available_from"`
+	AvailableUntil   *time.Time        `json:"available_until"`
 }
 
 // UpdateProductRequest represents an update product request
@@ -491,22 +1072,37 @@ type UpdateProductRequest struct {
 	Featured         *bool                    `json:"featured"`
 }
 
+// ProductScheduleRequest replaces a product's publishing schedule; omitted or null
+// dates are cleared
+type ProductScheduleRequest struct {
+	PublishAt      *time.Time `json:"publish_at"`
+	AvailableFrom  *time.Time `json:"available_from"`
+	AvailableUntil *time.Time `json:"available_until"`
+}
+
+// PriceTiersRequest replaces the quantity breaks of a product or variant
+type PriceTiersRequest struct {
+	Tiers []entities.PriceTier `json:"tiers"`
+}
+
 // SearchProductsRequest represents a search products request
 type SearchProductsRequest struct {
-	Query       string                   `json:"query"`
-	CategoryIDs []uuid.UUID              `json:"category_ids"`
-	BrandIDs    []uuid.UUID              `json:"brand_ids"`
-	MinPrice    *float64                 `json:"min_price"`
-	MaxPrice    *float64                 `json:"max_price"`
-	InStock     *bool                    `json:"in_stock"`
-	Featured    *bool                    `json:"featured"`
-	Status      *entities.ProductStatus  `json:"status"`
-	Visibility  *entities.Visibility     `json:"visibility"`
-	Tags        []string                 `json:"tags"`
-	Attributes  map[string]string        `json:"attributes"`
-	HasVariants *bool                    `json:"has_variants"`
-	CreatedFrom *string                  `json:"created_from"`
-	CreatedTo   *string                  `json:"created_to"`
-	Page        int                      `json:"page" validate:"min=1"`
-	PageSize    int                      `json:"page_size" validate:"min=1,max=100"`
+	Query         string                  `json:"query"`
+	CategoryIDs   []uuid.UUID             `json:"category_ids"`
+	BrandIDs      []uuid.UUID             `json:"brand_ids"`
+	MinPrice      *float64                `json:"min_price"`
+	MaxPrice      *float64                `json:"max_price"`
+	InStock       *bool                   `json:"in_stock"`
+	Featured      *bool                   `json:"featured"`
+	Status        *entities.ProductStatus `json:"status"`
+	Visibility    *entities.Visibility    `json:"visibility"`
+	Tags          []string                `json:"tags"`
+	Attributes    map[string]string       `json:"attributes"`
+	HasVariants   *bool                   `json:"has_variants"`
+	IsBundle      *bool                   `json:"is_bundle"`
+	CreatedFrom   *string                 `json:"created_from"`
+	CreatedTo     *string                 `json:"created_to"`
+	IncludeFacets bool                    `json:"include_facets"`
+	Page          int                     `json:"page" validate:"min=1"`
+	PageSize      int                     `json:"page_size" validate:"min=1,max=100"`
 }
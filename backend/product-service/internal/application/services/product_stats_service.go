@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+const (
+	productViewFlushPeriod  = 10 * time.Second
+	productViewFlushTimeout = 10 * time.Second
+	productViewMaxPending   = 10000
+)
+
+// productViewKey identifies the bucket a pending view is counted in
+type productViewKey struct {
+	productID uuid.UUID
+	bucket    time.Time
+}
+
+// ProductStatsService counts product views and sales in daily buckets, which products
+// are ranked by. Views are summed in memory and written periodically by a background
+// worker, so recording one never waits on the database; views of products beyond
+// productViewMaxPending distinct ones between writes are dropped (with a log line).
+type ProductStatsService struct {
+	productRepo repositories.ProductRepository
+	statsRepo   repositories.ProductStatsRepository
+	mu          sync.Mutex
+	pending     map[productViewKey]int64
+	stop        chan struct{}
+	done        chan struct{}
+	closed      bool
+}
+
+// NewProductStatsService creates a product stats service and starts its view writer
+func NewProductStatsService(productRepo repositories.ProductRepository, statsRepo repositories.ProductStatsRepository) *ProductStatsService {
+	s := &ProductStatsService{
+		productRepo: productRepo,
+		statsRepo:   statsRepo,
+		pending:     make(map[productViewKey]int64),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// RecordView counts a view of a product
+func (s *ProductStatsService) RecordView(productID uuid.UUID) error {
+	if productID == uuid.Nil {
+		return errors.New("product ID is required")
+	}
+
+	key := productViewKey{productID: productID, bucket: entities.StatBucket(time.Now())}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	if _, ok := s.pending[key]; !ok && len(s.pending) >= productViewMaxPending {
+		log.Println("Warning: too many pending product views, dropping view")
+		return nil
+	}
+	s.pending[key]++
+	return nil
+}
+
+// RecordOrder counts the units of each product in an order as sold on the day it was
+// placed. An order whose sales were counted before is ignored.
+func (s *ProductStatsService) RecordOrder(ctx context.Context, order *OrderPlaced) error {
+	bucket := entities.StatBucket(order.OrderedAt)
+	byProduct := make(map[uuid.UUID]*entities.ProductStat)
+	stats := make([]*entities.ProductStat, 0, len(order.Items))
+	for _, item := range order.Items {
+		stat := byProduct[item.ProductID]
+		if stat == nil {
+			stat = &entities.ProductStat{ProductID: item.ProductID, Bucket: bucket, Orders: 1}
+			byProduct[item.ProductID] = stat
+			stats = append(stats, stat)
+		}
+		if item.Quantity > 0 {
+			stat.UnitsSold += int64(item.Quantity)
+		}
+	}
+
+	if _, err := s.statsRepo.RecordOrderSales(ctx, order.OrderID, stats); err != nil {
+		return fmt.Errorf("failed to record order sales: %w", err)
+	}
+	return nil
+}
+
+// Close stops counting views and waits for pending ones to be written
+func (s *ProductStatsService) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.stop)
+	s.mu.Unlock()
+
+	<-s.done
+}
+
+// run writes pending views periodically, and once more when the service is closed
+func (s *ProductStatsService) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(productViewFlushPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.flushViews()
+		case <-s.stop:
+			s.flushViews()
+			return
+		}
+	}
+}
+
+// flushViews writes the views counted since the last flush. Views of products that do
+// not exist are discarded, so made-up IDs do not pile up in the stats.
+func (s *ProductStatsService) flushViews() {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[productViewKey]int64)
+	s.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), productViewFlushTimeout)
+	defer cancel()
+
+	ids := make([]uuid.UUID, 0, len(pending))
+	for key := range pending {
+		ids = append(ids, key.productID)
+	}
+	products, err := s.productRepo.GetByIDs(ctx, uniqueIDs(ids))
+	if err != nil {
+		log.Printf("Warning: failed to write %d product view counts: %v", len(pending), err)
+		return
+	}
+	exists := make(map[uuid.UUID]bool, len(products))
+	for _, product := range products {
+		exists[product.ID] = true
+	}
+
+	stats := make([]*entities.ProductStat, 0, len(pending))
+	for key, views := range pending {
+		if exists[key.productID] {
+			stats = append(stats, &entities.ProductStat{ProductID: key.productID, Bucket: key.bucket, Views: views})
+		}
+	}
+	if err := s.statsRepo.AddStats(ctx, stats); err != nil {
+		log.Printf("Warning: failed to write %d product view counts: %v", len(stats), err)
+	}
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/events"
+	"product-service/internal/domain/repositories"
+)
+
+const (
+	publishingBatchSize  = 100
+	publishingRunTimeout = 30 * time.Second
+)
+
+// Reasons given in status-change events
+const (
+	statusChangeManual            = "manual"
+	statusChangeRollback          = "rollback"
+	statusChangeScheduledPublish  = "scheduled_publish"
+	statusChangeAvailabilityEnded = "availability_ended"
+)
+
+// PublishingScheduler publishes drafts when their publish time comes and unpublishes
+// products when their availability window ends. Every replica runs one; the repository
+// claims due rows with SKIP LOCKED, so each product changes status, and its event is
+// emitted, exactly once.
+type PublishingScheduler struct {
+	productRepo repositories.ProductRepository
+	publisher   events.Publisher
+	interval    time.Duration
+	stop        chan struct{}
+	done        chan struct{}
+	closeOnce   sync.Once
+}
+
+// PublishingRunResult reports what one scheduler run changed
+type PublishingRunResult struct {
+	Published   int `json:"published"`
+	Unpublished int `json:"unpublished"`
+}
+
+// NewPublishingScheduler creates a publishing scheduler and starts it, checking for due
+// products every interval
+func NewPublishingScheduler(productRepo repositories.ProductRepository, publisher events.Publisher, interval time.Duration) *PublishingScheduler {
+	s := &PublishingScheduler{
+		productRepo: productRepo,
+		publisher:   publisher,
+		interval:    interval,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// RunOnce applies every transition that is due now
+func (s *PublishingScheduler) RunOnce(ctx context.Context) (*PublishingRunResult, error) {
+	result := &PublishingRunResult{}
+	now := time.Now()
+
+	published, err := s.drain(ctx, func() ([]*entities.Product, error) {
+		return s.productRepo.PublishDue(ctx, now, publishingBatchSize)
+	}, entities.ProductStatusDraft, statusChangeScheduledPublish)
+	result.Published = published
+	if err != nil {
+		return result, err
+	}
+
+	unpublished, err := s.drain(ctx, func() ([]*entities.Product, error) {
+		return s.productRepo.UnpublishExpired(ctx, now, publishingBatchSize)
+	}, entities.ProductStatusActive, statusChangeAvailabilityEnded)
+	result.Unpublished = unpublished
+	return result, err
+}
+
+// Close stops the scheduler and waits for a running pass to finish
+func (s *PublishingScheduler) Close() {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+	})
+	<-s.done
+}
+
+// drain claims batches until none are left, emitting an event per changed product
+func (s *PublishingScheduler) drain(ctx context.Context, claim func() ([]*entities.Product, error), from entities.ProductStatus, reason string) (int, error) {
+	total := 0
+	for {
+		products, err := claim()
+		if err != nil {
+			return total, err
+		}
+		for _, product := range products {
+			publishStatusChange(ctx, s.publisher, product, from, reason)
+		}
+		total += len(products)
+		if len(products) < publishingBatchSize {
+			return total, nil
+		}
+	}
+}
+
+func (s *PublishingScheduler) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.runPass()
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *PublishingScheduler) runPass() {
+	ctx, cancel := context.WithTimeout(context.Background(), publishingRunTimeout)
+	defer cancel()
+
+	result, err := s.RunOnce(ctx)
+	if err != nil {
+		log.Printf("Warning: publishing scheduler run failed: %v", err)
+	}
+	if result.Published > 0 || result.Unpublished > 0 {
+		log.Printf("Publishing scheduler: published %d, unpublished %d products", result.Published, result.Unpublished)
+	}
+}
+
+// publishStatusChange emits a status-change event. Failures are logged rather than
+// returned because the status change itself is already saved.
+func publishStatusChange(ctx context.Context, publisher events.Publisher, product *entities.Product, from entities.ProductStatus, reason string) {
+	if publisher == nil {
+		return
+	}
+
+	event := events.NewEvent(events.ProductStatusChanged, map[string]interface{}{
+		"productId":      product.ID,
+		"sku":            product.SKU,
+		"previousStatus": productStatusNames[from],
+		"status":         productStatusNames[product.Status],
+		"reason":         reason,
+		"publishedAt":    product.PublishedAt,
+		"availableFrom":  product.AvailableFrom,
+		"availableUntil": product.AvailableUntil,
+	})
+	if err := publisher.Publish(ctx, event); err != nil {
+		log.Printf("Warning: failed to publish status change of product %s: %v", product.ID, err)
+	}
+}
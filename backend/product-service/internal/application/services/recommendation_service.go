@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// maxCoOccurrenceProducts caps the products of one order counted as bought together;
+// pairs grow with the square of the products, and huge orders say little about which
+// products go together
+const maxCoOccurrenceProducts = 50
+
+// RecommendationService manages hand-picked product relations and recommends products,
+// either from those relations or from what customers bought together
+type RecommendationService struct {
+	productRepo        repositories.ProductRepository
+	variantRepo        repositories.ProductVariantRepository
+	relationRepo       repositories.ProductRelationRepository
+	recommendationRepo repositories.RecommendationRepository
+}
+
+// NewRecommendationService creates a new recommendation service
+func NewRecommendationService(
+	productRepo repositories.ProductRepository,
+	variantRepo repositories.ProductVariantRepository,
+	relationRepo repositories.ProductRelationRepository,
+	recommendationRepo repositories.RecommendationRepository,
+) *RecommendationService {
+	return &RecommendationService{
+		productRepo:        productRepo,
+		variantRepo:        variantRepo,
+		relationRepo:       relationRepo,
+		recommendationRepo: recommendationRepo,
+	}
+}
+
+// SetRelationsRequest represents the request to replace a product's relations of a type
+type SetRelationsRequest struct {
+	ProductIDs []uuid.UUID `json:"product_ids"` // in display order; empty removes them all
+}
+
+// Recommendations are the products recommended for a product
+type Recommendations struct {
+	Type     entities.RelationType `json:"type"`
+	Products []*entities.Product   `json:"products"`
+}
+
+// ListRelations retrieves the hand-picked relations of a product, of every type
+func (s *RecommendationService) ListRelations(ctx context.Context, productID uuid.UUID) ([]*entities.ProductRelation, error) {
+	if _, err := s.getProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	relations, err := s.relationRepo.GetByProduct(ctx, productID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get relations: %w", err)
+	}
+	return relations, nil
+}
+
+// SetRelations replaces a product's relations of one type with the given products, in
+// the given order
+func (s *RecommendationService) SetRelations(ctx context.Context, productID uuid.UUID, relationType entities.RelationType, req *SetRelationsRequest) ([]*entities.ProductRelation, error) {
+	if !entities.IsValidRelationType(relationType) {
+		return nil, errors.New("relation type must be related, up_sell, cross_sell or accessory")
+	}
+	product, err := s.getProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	related, err := s.productRepo.GetByIDs(ctx, uniqueIDs(req.ProductIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get related products: %w", err)
+	}
+	relatedByID := make(map[uuid.UUID]*entities.Product, len(related))
+	for _, p := range related {
+		relatedByID[p.ID] = p
+	}
+
+	actor := ActorFromContext(ctx)
+	seen := make(map[uuid.UUID]bool, len(req.ProductIDs))
+	relations := make([]*entities.ProductRelation, 0, len(req.ProductIDs))
+	for i, id := range req.ProductIDs {
+		if seen[id] {
+			return nil, errors.New("each related product can be listed only once")
+		}
+		seen[id] = true
+		if relatedByID[id] == nil {
+			return nil, fmt.Errorf("related product %s not found", id)
+		}
+
+		relation, err := entities.NewProductRelation(product.ID, id, relationType, i)
+		if err != nil {
+			return nil, err
+		}
+		relation.CreatedBy = actor
+		relations = append(relations, relation)
+	}
+
+	if err := s.relationRepo.ReplaceRelations(ctx, product.ID, relationType, relations); err != nil {
+		return nil, fmt.Errorf("failed to save relations: %w", err)
+	}
+
+	for _, relation := range relations {
+		relation.RelatedProduct = relatedByID[relation.RelatedProductID]
+	}
+	return relations, nil
+}
+
+// GetRecommendations recommends up to limit products for a product: its hand-picked
+// relations of a type, or the products most often bought with it. Only products that
+// can be bought now are recommended.
+func (s *RecommendationService) GetRecommendations(ctx context.Context, productID uuid.UUID, relationType entities.RelationType, limit int) (*Recommendations, error) {
+	if relationType == "" {
+		relationType = entities.RelationTypeRelated
+	}
+	if relationType != entities.RecommendationFrequentlyBoughtTogether && !entities.IsValidRelationType(relationType) {
+		return nil, errors.New("recommendation type must be related, up_sell, cross_sell, accessory or frequently_bought_together")
+	}
+	if _, err := s.getProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	var candidates []*entities.Product
+	if relationType == entities.RecommendationFrequentlyBoughtTogether {
+		// Fetch extra pairs so that filtering out unavailable products still fills the list
+		pairs, err := s.recommendationRepo.GetFrequentlyBoughtWith(ctx, productID, limit*3)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get frequently bought together products: %w", err)
+		}
+		ids := make([]uuid.UUID, len(pairs))
+		for i, pair := range pairs {
+			ids[i] = pair.OtherProductID
+		}
+		candidates, err = s.productsInOrder(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		relations, err := s.relationRepo.GetByProduct(ctx, productID, relationType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get relations: %w", err)
+		}
+		for _, relation := range relations {
+			if relation.RelatedProduct != nil {
+				candidates = append(candidates, relation.RelatedProduct)
+			}
+		}
+	}
+
+	products := make([]*entities.Product, 0, limit)
+	for _, candidate := range candidates {
+		if len(products) == limit {
+			break
+		}
+		available, err := s.isAvailable(ctx, candidate)
+		if err != nil {
+			return nil, err
+		}
+		if available {
+			products = append(products, candidate)
+		}
+	}
+
+	return &Recommendations{Type: relationType, Products: products}, nil
+}
+
+// RecordOrder counts the products of an order as bought together. An order recorded
+// before is ignored.
+func (s *RecommendationService) RecordOrder(ctx context.Context, order *OrderPlaced) error {
+	productIDs := make([]uuid.UUID, 0, len(order.Items))
+	for _, item := range order.Items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+	productIDs = uniqueIDs(productIDs)
+	if len(productIDs) > maxCoOccurrenceProducts {
+		productIDs = productIDs[:maxCoOccurrenceProducts]
+	}
+
+	if _, err := s.recommendationRepo.RecordOrder(ctx, order.OrderID, productIDs, order.OrderedAt); err != nil {
+		return fmt.Errorf("failed to record order: %w", err)
+	}
+	return nil
+}
+
+// isAvailable checks if a product can be bought now. A product with variants has no
+// stock of its own, so it is available when listed and one of its variants can be bought.
+func (s *RecommendationService) isAvailable(ctx context.Context, product *entities.Product) (bool, error) {
+	if !product.HasVariants {
+		return product.IsAvailable(), nil
+	}
+	if !product.IsListed() || !product.InAvailabilityWindow(time.Now()) {
+		return false, nil
+	}
+
+	variants, err := s.variantRepo.GetByProductID(ctx, product.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get variants: %w", err)
+	}
+	for _, variant := range variants {
+		if variant.CanPurchase(1) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// productsInOrder retrieves products by ID, in the order of ids, skipping missing ones
+func (s *RecommendationService) productsInOrder(ctx context.Context, ids []uuid.UUID) ([]*entities.Product, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	products, err := s.productRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products: %w", err)
+	}
+	byID := make(map[uuid.UUID]*entities.Product, len(products))
+	for _, product := range products {
+		byID[product.ID] = product
+	}
+
+	ordered := make([]*entities.Product, 0, len(ids))
+	for _, id := range ids {
+		if product := byID[id]; product != nil {
+			ordered = append(ordered, product)
+		}
+	}
+	return ordered, nil
+}
+
+// getProduct retrieves a product, or a not found error
+func (s *RecommendationService) getProduct(ctx context.Context, productID uuid.UUID) (*entities.Product, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if product == nil {
+		return nil, errors.New("product not found")
+	}
+	return product, nil
+}
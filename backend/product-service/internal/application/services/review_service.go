@@ -0,0 +1,434 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// Spam heuristics. Each signal adds to a review's spam score; a review scoring
+// reviewSpamThreshold or more is flagged as spam instead of waiting for moderation.
+const (
+	reviewSpamThreshold       = 3
+	reviewIPWindow            = time.Hour
+	reviewIPMaxReviews        = 3
+	reviewDuplicateWindow     = 30 * 24 * time.Hour
+	reviewDuplicateMinLength  = 20
+	reviewShoutingMinLetters  = 20
+	reviewRepeatedCharsLength = 6
+)
+
+// reviewBotMarkers are user agent fragments of scripts and crawlers, not browsers
+var reviewBotMarkers = []string{
+	"bot", "crawler", "spider", "curl", "wget", "python", "go-http-client", "java/",
+	"httpclient", "okhttp", "headless", "scrapy", "postman",
+}
+
+// ReviewService manages product reviews: submission with spam checks, moderation,
+// helpful votes and verified purchases
+type ReviewService struct {
+	productRepo repositories.ProductRepository
+	reviewRepo  repositories.ReviewRepository
+}
+
+// NewReviewService creates a new review service
+func NewReviewService(productRepo repositories.ProductRepository, reviewRepo repositories.ReviewRepository) *ReviewService {
+	return &ReviewService{
+		productRepo: productRepo,
+		reviewRepo:  reviewRepo,
+	}
+}
+
+// SubmitReviewRequest represents a request to review a product
+type SubmitReviewRequest struct {
+	Rating        int    `json:"rating" validate:"required,min=1,max=5"`
+	Title         string `json:"title"`
+	Content       string `json:"content"`
+	IsRecommended bool   `json:"is_recommended"`
+}
+
+// ReviewSource is where a review was submitted from
+type ReviewSource struct {
+	IPAddress string
+	UserAgent string
+}
+
+// ModerateReviewRequest represents a moderator's decision on a review
+type ModerateReviewRequest struct {
+	Status entities.ReviewStatus `json:"status" validate:"required"`
+	Note   string                `json:"note"`
+}
+
+// ReviewVoteRequest represents a shopper's helpful vote on a review
+type ReviewVoteRequest struct {
+	Helpful bool `json:"helpful"`
+}
+
+// ReviewListResult is a page of reviews
+type ReviewListResult struct {
+	Reviews    []*entities.ProductReview `json:"reviews"`
+	Total      int64                     `json:"total"`
+	Page       int                       `json:"page"`
+	PageSize   int                       `json:"page_size"`
+	TotalPages int                       `json:"total_pages"`
+}
+
+// ProductReviewsResult is a page of a product's approved reviews with its rating summary
+type ProductReviewsResult struct {
+	ReviewListResult
+	AverageRating float64       `json:"average_rating"`
+	ReviewCount   int           `json:"review_count"`
+	Histogram     map[int]int64 `json:"histogram"` // approved reviews by rating, 1 to 5
+}
+
+// SubmitReview submits the signed-in user's review of a product. Reviews wait for
+// moderation, unless the spam checks flag them as spam.
+func (s *ReviewService) SubmitReview(ctx context.Context, productID uuid.UUID, req *SubmitReviewRequest, source ReviewSource) (*entities.ProductReview, error) {
+	userID, err := reviewerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.getProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.reviewRepo.GetByProductAndUser(ctx, productID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing review: %w", err)
+	}
+	if existing != nil {
+		return nil, errors.New("you have already reviewed this product")
+	}
+
+	review, err := entities.NewProductReview(productID, userID, req.Rating, req.Title, req.Content, req.IsRecommended)
+	if err != nil {
+		return nil, err
+	}
+	review.IPAddress = source.IPAddress
+	review.UserAgent = truncate(source.UserAgent, 500)
+
+	review.IsVerified, err = s.reviewRepo.HasPurchased(ctx, userID, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check purchase: %w", err)
+	}
+
+	score, reasons, err := s.spamSignals(ctx, review)
+	if err != nil {
+		return nil, err
+	}
+	if score >= reviewSpamThreshold {
+		review.FlagSpam(score, reasons)
+	} else {
+		review.SpamScore = score
+		review.SpamReasons = reasons
+	}
+
+	if err := s.reviewRepo.Create(ctx, review); err != nil {
+		return nil, fmt.Errorf("failed to create review: %w", err)
+	}
+
+	return review, nil
+}
+
+// ListProductReviews lists a product's approved reviews with its rating summary
+func (s *ReviewService) ListProductReviews(ctx context.Context, productID uuid.UUID, filters *repositories.ReviewFilters, page, pageSize int) (*ProductReviewsResult, error) {
+	product, err := s.getProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	approved := entities.ReviewStatusApproved
+	filters.ProductID = &productID
+	filters.Status = &approved
+
+	list, err := s.listReviews(ctx, filters, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	histogram, err := s.reviewRepo.GetRatingHistogram(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rating histogram: %w", err)
+	}
+
+	return &ProductReviewsResult{
+		ReviewListResult: *list,
+		AverageRating:    product.AverageRating,
+		ReviewCount:      product.ReviewCount,
+		Histogram:        histogram,
+	}, nil
+}
+
+// ListReviews lists reviews of any status for moderation, pending ones by default
+func (s *ReviewService) ListReviews(ctx context.Context, filters *repositories.ReviewFilters, page, pageSize int) (*ReviewListResult, error) {
+	if filters.Status == nil {
+		pending := entities.ReviewStatusPending
+		filters.Status = &pending
+	} else if !entities.IsValidReviewStatus(*filters.Status) {
+		return nil, errors.New("review status must be pending, approved, rejected or spam")
+	}
+
+	return s.listReviews(ctx, filters, page, pageSize)
+}
+
+// ModerateReview approves, rejects or marks a review as spam, updating the product's
+// rating with it
+func (s *ReviewService) ModerateReview(ctx context.Context, reviewID uuid.UUID, req *ModerateReviewRequest) (*entities.ProductReview, error) {
+	review, err := s.getReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := review.Moderate(req.Status, strings.TrimSpace(req.Note), ActorFromContext(ctx)); err != nil {
+		return nil, err
+	}
+
+	if err := s.reviewRepo.Update(ctx, review); err != nil {
+		return nil, fmt.Errorf("failed to update review: %w", err)
+	}
+
+	return review, nil
+}
+
+// VoteReview records whether the signed-in user found an approved review helpful,
+// replacing their earlier vote
+func (s *ReviewService) VoteReview(ctx context.Context, reviewID uuid.UUID, req *ReviewVoteRequest) (*entities.ProductReview, error) {
+	userID, err := reviewerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	review, err := s.getReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if !review.IsApproved() {
+		return nil, errors.New("review not found")
+	}
+	if review.UserID == userID {
+		return nil, errors.New("you cannot vote on your own review")
+	}
+
+	now := time.Now()
+	vote := &entities.ReviewVote{
+		ReviewID:  reviewID,
+		UserID:    userID,
+		Helpful:   req.Helpful,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.reviewRepo.Vote(ctx, vote); err != nil {
+		return nil, fmt.Errorf("failed to record vote: %w", err)
+	}
+
+	return s.getReview(ctx, reviewID)
+}
+
+// RecordOrder records the products of an order as bought by its customer, verifying
+// their reviews of them
+func (s *ReviewService) RecordOrder(ctx context.Context, order *OrderPlaced) error {
+	if order.UserID == nil {
+		return nil
+	}
+
+	productIDs := make([]uuid.UUID, 0, len(order.Items))
+	for _, item := range order.Items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+
+	purchases := make([]*entities.ProductPurchase, 0, len(productIDs))
+	for _, productID := range uniqueIDs(productIDs) {
+		purchases = append(purchases, &entities.ProductPurchase{
+			UserID:      *order.UserID,
+			ProductID:   productID,
+			OrderID:     order.OrderID,
+			PurchasedAt: order.OrderedAt,
+		})
+	}
+
+	if err := s.reviewRepo.RecordPurchases(ctx, purchases); err != nil {
+		return fmt.Errorf("failed to record purchases: %w", err)
+	}
+	return nil
+}
+
+// spamSignals scores a new review for signs of spam, returning the score and the reasons
+// behind it
+func (s *ReviewService) spamSignals(ctx context.Context, review *entities.ProductReview) (int, []string, error) {
+	score := 0
+	var reasons []string
+	add := func(points int, reason string) {
+		score += points
+		reasons = append(reasons, reason)
+	}
+
+	userAgent := strings.ToLower(review.UserAgent)
+	if userAgent == "" {
+		add(1, "no user agent")
+	} else {
+		for _, marker := range reviewBotMarkers {
+			if strings.Contains(userAgent, marker) {
+				add(3, "submitted by an automated client")
+				break
+			}
+		}
+	}
+
+	if review.IPAddress != "" {
+		recent, err := s.reviewRepo.CountByIPSince(ctx, review.IPAddress, time.Now().Add(-reviewIPWindow))
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to check recent reviews: %w", err)
+		}
+		if recent >= reviewIPMaxReviews {
+			add(2, "many recent reviews from the same IP address")
+		}
+	}
+
+	if len(review.Content) >= reviewDuplicateMinLength {
+		duplicates, err := s.reviewRepo.CountByContentSince(ctx, review.Content, time.Now().Add(-reviewDuplicateWindow))
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to check duplicate reviews: %w", err)
+		}
+		if duplicates > 0 {
+			add(2, "same text as another review")
+		}
+	}
+
+	text := review.Title + " " + review.Content
+	lower := strings.ToLower(text)
+	switch links := strings.Count(lower, "http://") + strings.Count(lower, "https://") + strings.Count(lower, "www."); {
+	case links > 1:
+		add(2, "contains links")
+	case links == 1:
+		add(1, "contains a link")
+	}
+
+	if isShouting(text) {
+		add(1, "mostly capital letters")
+	}
+
+	if hasRepeatedChars(text, reviewRepeatedCharsLength) {
+		add(1, "repeated characters")
+	}
+
+	return score, reasons, nil
+}
+
+// listReviews lists a page of reviews matching the filters
+func (s *ReviewService) listReviews(ctx context.Context, filters *repositories.ReviewFilters, page, pageSize int) (*ReviewListResult, error) {
+	switch filters.Sort {
+	case "", repositories.ReviewSortNewest, repositories.ReviewSortOldest, repositories.ReviewSortHighest,
+		repositories.ReviewSortLowest, repositories.ReviewSortMostHelpful:
+	default:
+		return nil, errors.New("sort must be newest, oldest, highest, lowest or most_helpful")
+	}
+	if filters.Rating != nil && (*filters.Rating < 1 || *filters.Rating > 5) {
+		return nil, errors.New("rating must be between 1 and 5")
+	}
+
+	reviews, err := s.reviewRepo.List(ctx, filters, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reviews: %w", err)
+	}
+	total, err := s.reviewRepo.Count(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count reviews: %w", err)
+	}
+
+	if reviews == nil {
+		reviews = []*entities.ProductReview{}
+	}
+
+	return &ReviewListResult{
+		Reviews:    reviews,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+	}, nil
+}
+
+// getProduct retrieves a product, or a not found error
+func (s *ReviewService) getProduct(ctx context.Context, productID uuid.UUID) (*entities.Product, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if product == nil {
+		return nil, errors.New("product not found")
+	}
+	return product, nil
+}
+
+// getReview retrieves a review, or a not found error
+func (s *ReviewService) getReview(ctx context.Context, reviewID uuid.UUID) (*entities.ProductReview, error) {
+	review, err := s.reviewRepo.GetByID(ctx, reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review: %w", err)
+	}
+	if review == nil {
+		return nil, errors.New("review not found")
+	}
+	return review, nil
+}
+
+// reviewerFromContext returns the signed-in user reviewing or voting
+func reviewerFromContext(ctx context.Context) (uuid.UUID, error) {
+	userID, err := uuid.Parse(ActorFromContext(ctx))
+	if err != nil {
+		return uuid.Nil, errors.New("a signed-in user is required")
+	}
+	return userID, nil
+}
+
+// isShouting checks if most letters of a long enough text are capitals
+func isShouting(text string) bool {
+	letters, upper := 0, 0
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+	}
+	return letters >= reviewShoutingMinLetters && upper*10 > letters*7
+}
+
+// hasRepeatedChars checks if a text repeats one character n or more times in a row
+func hasRepeatedChars(text string, n int) bool {
+	var last rune
+	run := 0
+	for _, r := range text {
+		if r == last && !unicode.IsSpace(r) {
+			run++
+			if run >= n {
+				return true
+			}
+		} else {
+			last = r
+			run = 1
+		}
+	}
+	return false
+}
+
+// truncate shortens s to at most max bytes without splitting a character
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max]
+}
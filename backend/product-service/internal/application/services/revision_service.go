@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// RevisionService records and reports the revision history of products. Rolling back
+// is done by ProductService, which owns the side effects of changing a product.
+type RevisionService struct {
+	revisionRepo repositories.ProductRevisionRepository
+	transactions repositories.TransactionManager
+}
+
+// NewRevisionService creates a new revision service
+func NewRevisionService(revisionRepo repositories.ProductRevisionRepository, transactions repositories.TransactionManager) *RevisionService {
+	return &RevisionService{revisionRepo: revisionRepo, transactions: transactions}
+}
+
+// RevisionListResult is a page of revisions, newest first
+type RevisionListResult struct {
+	Revisions  []*entities.ProductRevision `json:"revisions"`
+	Total      int64                       `json:"total"`
+	Page       int                         `json:"page"`
+	PageSize   int                         `json:"page_size"`
+	TotalPages int                         `json:"total_pages"`
+}
+
+// RevisionDiff lists the fields that differ between two revisions of the same record
+type RevisionDiff struct {
+	From       int                     `json:"from"`
+	To         int                     `json:"to"`
+	EntityType entities.RevisionEntity `json:"entity_type"`
+	EntityID   uuid.UUID               `json:"entity_id"`
+	Changes    []entities.FieldChange  `json:"changes"`
+}
+
+// WithinTransaction runs change in a transaction. Changes save their revisions with
+// Record inside it, so a change and the revisions recording it are saved together or
+// not at all.
+func (s *RevisionService) WithinTransaction(ctx context.Context, change func(ctx context.Context) error) error {
+	return s.transactions.WithinTransaction(ctx, change)
+}
+
+// Record stores revisions, of one product or several; nil revisions (no-op changes) are
+// skipped. Called with the context of WithinTransaction, it fails the change with it.
+func (s *RevisionService) Record(ctx context.Context, revisions ...*entities.ProductRevision) error {
+	pending := make([]*entities.ProductRevision, 0, len(revisions))
+	for _, revision := range revisions {
+		if revision != nil {
+			pending = append(pending, revision)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if err := s.revisionRepo.Create(ctx, pending...); err != nil {
+		return fmt.Errorf("failed to record revisions: %w", err)
+	}
+	return nil
+}
+
+// ListRevisions lists a product's revisions, newest first. History outlives the
+// product, so deleted products can still be audited.
+func (s *RevisionService) ListRevisions(ctx context.Context, productID uuid.UUID, page, pageSize int) (*RevisionListResult, error) {
+	revisions, err := s.revisionRepo.GetByProductID(ctx, productID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", err)
+	}
+	total, err := s.revisionRepo.CountByProductID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count revisions: %w", err)
+	}
+
+	if revisions == nil {
+		revisions = []*entities.ProductRevision{}
+	}
+
+	return &RevisionListResult{
+		Revisions:  revisions,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+	}, nil
+}
+
+// GetRevision retrieves one of a product's revisions by number
+func (s *RevisionService) GetRevision(ctx context.Context, productID uuid.UUID, number int) (*entities.ProductRevision, error) {
+	revision, err := s.revisionRepo.GetByNumber(ctx, productID, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revision: %w", err)
+	}
+	if revision == nil {
+		return nil, errors.New("revision not found")
+	}
+	return revision, nil
+}
+
+// DiffRevisions compares the states two revisions left a record in. Both must be
+// revisions of the same product, variant or image.
+func (s *RevisionService) DiffRevisions(ctx context.Context, productID uuid.UUID, from, to int) (*RevisionDiff, error) {
+	fromRevision, err := s.GetRevision(ctx, productID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRevision, err := s.GetRevision(ctx, productID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	if fromRevision.EntityType != toRevision.EntityType || fromRevision.EntityID != toRevision.EntityID {
+		return nil, errors.New("revisions belong to different records")
+	}
+
+	return &RevisionDiff{
+		From:       from,
+		To:         to,
+		EntityType: toRevision.EntityType,
+		EntityID:   toRevision.EntityID,
+		Changes:    entities.DiffSnapshots(fromRevision.Snapshot, toRevision.Snapshot),
+	}, nil
+}
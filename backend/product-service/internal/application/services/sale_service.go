@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// SaleService manages time-boxed sales on products, variants, categories and brands.
+// Sale prices are worked out on read by PricingService.
+type SaleService struct {
+	saleRepo     repositories.SaleRepository
+	productRepo  repositories.ProductRepository
+	variantRepo  repositories.ProductVariantRepository
+	categoryRepo repositories.CategoryRepository
+	brandRepo    repositories.BrandRepository
+}
+
+// NewSaleService creates a new sale service
+func NewSaleService(
+	saleRepo repositories.SaleRepository,
+	productRepo repositories.ProductRepository,
+	variantRepo repositories.ProductVariantRepository,
+	categoryRepo repositories.CategoryRepository,
+	brandRepo repositories.BrandRepository,
+) *SaleService {
+	return &SaleService{
+		saleRepo:     saleRepo,
+		productRepo:  productRepo,
+		variantRepo:  variantRepo,
+		categoryRepo: categoryRepo,
+		brandRepo:    brandRepo,
+	}
+}
+
+// SaleRequest represents a create or update sale request. Percent discounts are a
+// percentage off; fixed discounts are an amount off in the base currency.
+type SaleRequest struct {
+	Name          string                `json:"name" validate:"required"`
+	Scope         entities.SaleScope    `json:"scope" validate:"required"`
+	TargetID      uuid.UUID             `json:"target_id" validate:"required"`
+	VariantID     *uuid.UUID            `json:"variant_id"`
+	DiscountType  entities.DiscountType `json:"discount_type" validate:"required"`
+	DiscountValue float64               `json:"discount_value" validate:"required,gt=0"`
+	StartsAt      time.Time             `json:"starts_at" validate:"required"`
+	EndsAt        time.Time             `json:"ends_at" validate:"required"`
+	IsActive      *bool                 `json:"is_active"`
+}
+
+// SaleListResult is a page of sales
+type SaleListResult struct {
+	Sales      []*entities.Sale `json:"sales"`
+	Total      int64            `json:"total"`
+	Page       int              `json:"page"`
+	PageSize   int              `json:"page_size"`
+	TotalPages int              `json:"total_pages"`
+}
+
+// CreateSale creates a sale
+func (s *SaleService) CreateSale(ctx context.Context, req *SaleRequest) (*entities.Sale, error) {
+	sale, err := entities.NewSale(req.Name, req.Scope, req.TargetID, req.VariantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyRequest(ctx, sale, req); err != nil {
+		return nil, err
+	}
+
+	actor := ActorFromContext(ctx)
+	sale.CreatedBy = actor
+	sale.UpdatedBy = actor
+
+	if err := s.saleRepo.Create(ctx, sale); err != nil {
+		return nil, fmt.Errorf("failed to create sale: %w", err)
+	}
+
+	return sale, nil
+}
+
+// GetSale retrieves a sale by ID
+func (s *SaleService) GetSale(ctx context.Context, id uuid.UUID) (*entities.Sale, error) {
+	sale, err := s.saleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sale: %w", err)
+	}
+	if sale == nil {
+		return nil, errors.New("sale not found")
+	}
+	return sale, nil
+}
+
+// ListSales lists sales matching filters
+func (s *SaleService) ListSales(ctx context.Context, filters repositories.SaleFilters, page, pageSize int) (*SaleListResult, error) {
+	switch filters.Status {
+	case "", "scheduled", "running", "ended":
+	default:
+		return nil, errors.New("status must be scheduled, running or ended")
+	}
+	if filters.Scope != "" && !entities.IsValidSaleScope(filters.Scope) {
+		return nil, errors.New("scope must be product, category or brand")
+	}
+
+	sales, err := s.saleRepo.List(ctx, filters, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sales: %w", err)
+	}
+	total, err := s.saleRepo.Count(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count sales: %w", err)
+	}
+
+	if sales == nil {
+		sales = []*entities.Sale{}
+	}
+
+	return &SaleListResult{
+		Sales:      sales,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+	}, nil
+}
+
+// UpdateSale changes a sale
+func (s *SaleService) UpdateSale(ctx context.Context, id uuid.UUID, req *SaleRequest) (*entities.Sale, error) {
+	sale, err := s.GetSale(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sale.Rename(req.Name); err != nil {
+		return nil, err
+	}
+	if err := sale.SetTarget(req.Scope, req.TargetID, req.VariantID); err != nil {
+		return nil, err
+	}
+	if err := s.applyRequest(ctx, sale, req); err != nil {
+		return nil, err
+	}
+	sale.UpdatedBy = ActorFromContext(ctx)
+
+	if err := s.saleRepo.Update(ctx, sale); err != nil {
+		return nil, fmt.Errorf("failed to update sale: %w", err)
+	}
+
+	return sale, nil
+}
+
+// DeleteSale deletes a sale
+func (s *SaleService) DeleteSale(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.GetSale(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.saleRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete sale: %w", err)
+	}
+
+	return nil
+}
+
+// applyRequest sets a sale's discount and active state and checks its target exists
+func (s *SaleService) applyRequest(ctx context.Context, sale *entities.Sale, req *SaleRequest) error {
+	if err := sale.SetDiscount(req.DiscountType, req.DiscountValue, req.StartsAt, req.EndsAt); err != nil {
+		return err
+	}
+	if req.IsActive != nil {
+		sale.SetActive(*req.IsActive)
+	}
+
+	return s.checkTarget(ctx, sale)
+}
+
+// checkTarget checks the product, variant, category or brand a sale targets exists
+func (s *SaleService) checkTarget(ctx context.Context, sale *entities.Sale) error {
+	switch sale.Scope {
+	case entities.SaleScopeProduct:
+		product, err := s.productRepo.GetByID(ctx, sale.TargetID)
+		if err != nil {
+			return fmt.Errorf("failed to get product: %w", err)
+		}
+		if product == nil {
+			return errors.New("product not found")
+		}
+		if sale.VariantID != nil {
+			variant, err := s.variantRepo.GetByID(ctx, *sale.VariantID)
+			if err != nil {
+				return fmt.Errorf("failed to get variant: %w", err)
+			}
+			if variant == nil || variant.ProductID != product.ID {
+				return errors.New("variant not found")
+			}
+		}
+	case entities.SaleScopeCategory:
+		category, err := s.categoryRepo.GetByID(ctx, sale.TargetID)
+		if err != nil {
+			return fmt.Errorf("failed to get category: %w", err)
+		}
+		if category == nil {
+			return errors.New("category not found")
+		}
+	case entities.SaleScopeBrand:
+		brand, err := s.brandRepo.GetByID(ctx, sale.TargetID)
+		if err != nil {
+			return fmt.Errorf("failed to get brand: %w", err)
+		}
+		if brand == nil {
+			return errors.New("brand not found")
+		}
+	}
+	return nil
+}
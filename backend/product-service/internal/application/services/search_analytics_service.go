@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+const (
+	searchAnalyticsQueueSize    = 10000
+	searchAnalyticsBatchSize    = 200
+	searchAnalyticsFlushPeriod  = 2 * time.Second
+	searchAnalyticsFlushTimeout = 10 * time.Second
+)
+
+// SearchAnalyticsService records searches and clicks asynchronously and reports on them.
+// Recording never blocks the request path: events are queued and written in batches by
+// a background worker, and are dropped (with a log line) if the queue is full.
+type SearchAnalyticsService struct {
+	analyticsRepo repositories.SearchAnalyticsRepository
+	events        chan searchAnalyticsEvent
+	done          chan struct{}
+	mu            sync.RWMutex
+	closed        bool
+}
+
+// searchAnalyticsEvent is either a new search log or a click on an earlier search
+type searchAnalyticsEvent struct {
+	log   *entities.SearchLog
+	click *searchClick
+}
+
+type searchClick struct {
+	searchID  uuid.UUID
+	productID uuid.UUID
+	position  int
+	clickedAt time.Time
+}
+
+// NewSearchAnalyticsService creates a search analytics service and starts its writer
+func NewSearchAnalyticsService(analyticsRepo repositories.SearchAnalyticsRepository) *SearchAnalyticsService {
+	s := &SearchAnalyticsService{
+		analyticsRepo: analyticsRepo,
+		events:        make(chan searchAnalyticsEvent, searchAnalyticsQueueSize),
+		done:          make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// RecordSearch queues a search for logging and returns the search ID clients report
+// clicks against
+func (s *SearchAnalyticsService) RecordSearch(query string, filters *repositories.ProductFilters, resultCount int64, latency time.Duration) uuid.UUID {
+	searchLog := entities.NewSearchLog(query, searchFiltersJSON(filters), resultCount, latency)
+	s.enqueue(searchAnalyticsEvent{log: searchLog})
+	return searchLog.ID
+}
+
+// RecordClick queues the product a shopper opened from a search's results
+func (s *SearchAnalyticsService) RecordClick(ctx context.Context, req *RecordSearchClickRequest) error {
+	if req.SearchID == uuid.Nil || req.ProductID == uuid.Nil {
+		return errors.New("search ID and product ID are required")
+	}
+	if req.Position < 0 {
+		return errors.New("click position cannot be negative")
+	}
+
+	s.enqueue(searchAnalyticsEvent{click: &searchClick{
+		searchID:  req.SearchID,
+		productID: req.ProductID,
+		position:  req.Position,
+		clickedAt: time.Now(),
+	}})
+	return nil
+}
+
+// GetAnalytics reports top terms, zero-result queries, click-through rate and average
+// result counts for searches in [from, to)
+func (s *SearchAnalyticsService) GetAnalytics(ctx context.Context, from, to time.Time, limit int) (*repositories.SearchAnalyticsReport, error) {
+	if !from.Before(to) {
+		return nil, errors.New("analytics period start must be before its end")
+	}
+
+	summary, err := s.analyticsRepo.GetSummary(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get search summary: %w", err)
+	}
+
+	topTerms, err := s.analyticsRepo.GetTopTerms(ctx, from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top search terms: %w", err)
+	}
+
+	zeroResults, err := s.analyticsRepo.GetZeroResultTerms(ctx, from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get zero-result queries: %w", err)
+	}
+
+	return &repositories.SearchAnalyticsReport{
+		From:              from,
+		To:                to,
+		Summary:           summary,
+		TopTerms:          topTerms,
+		ZeroResultQueries: zeroResults,
+	}, nil
+}
+
+// Close stops accepting events and waits for queued events to be written
+func (s *SearchAnalyticsService) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.events)
+	s.mu.Unlock()
+
+	<-s.done
+}
+
+func (s *SearchAnalyticsService) enqueue(event searchAnalyticsEvent) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return
+	}
+
+	select {
+	case s.events <- event:
+	default:
+		log.Println("Warning: search analytics queue is full, dropping event")
+	}
+}
+
+// run batches search logs and writes them periodically. Clicks flush pending logs
+// first so the search they refer to is already stored.
+func (s *SearchAnalyticsService) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(searchAnalyticsFlushPeriod)
+	defer ticker.Stop()
+
+	pending := make([]*entities.SearchLog, 0, searchAnalyticsBatchSize)
+	flush := func() {
+		if len(pending) == 0 {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), searchAnalyticsFlushTimeout)
+		defer cancel()
+		if err := s.analyticsRepo.CreateBulk(ctx, pending); err != nil {
+			log.Printf("Warning: failed to write %d search logs: %v", len(pending), err)
+		}
+		pending = make([]*entities.SearchLog, 0, searchAnalyticsBatchSize)
+	}
+
+	for {
+		select {
+		case event, ok := <-s.events:
+			if !ok {
+				flush()
+				return
+			}
+			if event.log != nil {
+				pending = append(pending, event.log)
+				if len(pending) >= searchAnalyticsBatchSize {
+					flush()
+				}
+				continue
+			}
+
+			flush()
+			click := event.click
+			ctx, cancel := context.WithTimeout(context.Background(), searchAnalyticsFlushTimeout)
+			if err := s.analyticsRepo.RecordClick(ctx, click.searchID, click.productID, click.position, click.clickedAt); err != nil {
+				log.Printf("Warning: failed to record search click: %v", err)
+			}
+			cancel()
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// searchFiltersJSON encodes only the filters that are set, so the stored JSON shows what
+// the shopper actually narrowed by
+func searchFiltersJSON(filters *repositories.ProductFilters) string {
+	if filters == nil {
+		return "{}"
+	}
+
+	data, err := json.Marshal(filters)
+	if err != nil {
+		return "{}"
+	}
+
+	var all map[string]interface{}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return "{}"
+	}
+
+	active := make(map[string]interface{}, len(all))
+	for key, value := range all {
+		switch v := value.(type) {
+		case nil:
+			continue
+		case []interface{}:
+			if len(v) == 0 {
+				continue
+			}
+		case map[string]interface{}:
+			if len(v) == 0 {
+				continue
+			}
+		}
+		active[key] = value
+	}
+
+	data, err = json.Marshal(active)
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
+
+// RecordSearchClickRequest represents a click on a product in search results
+type RecordSearchClickRequest struct {
+	SearchID  uuid.UUID `json:"search_id" validate:"required"`
+	ProductID uuid.UUID `json:"product_id" validate:"required"`
+	Position  int       `json:"position"`
+}
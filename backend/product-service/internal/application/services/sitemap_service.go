@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// sitemapPageSize is the number of URLs in one sitemap page, well below the protocol's
+// limit of 50,000
+const sitemapPageSize = 10000
+
+// sitemapNamespace is the XML namespace of the sitemap protocol
+const sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// sitemapSections lists the entity types with sitemap pages and the storefront path
+// their pages live under
+var sitemapSections = []struct {
+	entityType entities.SlugEntityType
+	name       string
+	path       string
+}{
+	{entities.SlugEntityProduct, "products", "/products/"},
+	{entities.SlugEntityCategory, "categories", "/categories/"},
+}
+
+// SitemapService builds sitemaps of the storefront's product and category pages
+type SitemapService struct {
+	sitemapRepo   repositories.SitemapRepository
+	storefrontURL string
+	sitemapURL    string
+}
+
+// NewSitemapService creates a new sitemap service. Page URLs point at storefrontURL;
+// the index points at the sitemap pages under sitemapURL.
+func NewSitemapService(sitemapRepo repositories.SitemapRepository, storefrontURL, sitemapURL string) *SitemapService {
+	return &SitemapService{
+		sitemapRepo:   sitemapRepo,
+		storefrontURL: strings.TrimSuffix(storefrontURL, "/"),
+		sitemapURL:    strings.TrimSuffix(sitemapURL, "/"),
+	}
+}
+
+// SitemapIndex is a sitemap index document listing sitemap pages
+type SitemapIndex struct {
+	XMLName  xml.Name            `xml:"sitemapindex"`
+	Xmlns    string              `xml:"xmlns,attr"`
+	Sitemaps []SitemapIndexEntry `xml:"sitemap"`
+}
+
+// SitemapIndexEntry is a sitemap page in a sitemap index
+type SitemapIndexEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// SitemapURLSet is a sitemap page document listing storefront pages
+type SitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	URLs    []SitemapURL `xml:"url"`
+}
+
+// SitemapURL is a storefront page in a sitemap page
+type SitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// GetSitemapIndex lists the sitemap pages of every section, each with the latest update
+// time of the pages it lists
+func (s *SitemapService) GetSitemapIndex(ctx context.Context) (*SitemapIndex, error) {
+	index := &SitemapIndex{Xmlns: sitemapNamespace, Sitemaps: []SitemapIndexEntry{}}
+	for _, section := range sitemapSections {
+		lastMods, err := s.sitemapRepo.GetPageLastMods(ctx, section.entityType, sitemapPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s sitemap pages: %w", section.name, err)
+		}
+		for i, lastMod := range lastMods {
+			index.Sitemaps = append(index.Sitemaps, SitemapIndexEntry{
+				Loc:     fmt.Sprintf("%s/sitemaps/%s-%d.xml", s.sitemapURL, section.name, i+1),
+				LastMod: formatLastMod(lastMod),
+			})
+		}
+	}
+	return index, nil
+}
+
+// GetSitemapPage builds a sitemap page by its name in the index, such as products-1.xml
+func (s *SitemapService) GetSitemapPage(ctx context.Context, name string) (*SitemapURLSet, error) {
+	base := strings.TrimSuffix(name, ".xml")
+	dash := strings.LastIndex(base, "-")
+	if base == name || dash < 0 {
+		return nil, errors.New("sitemap not found")
+	}
+	page, err := strconv.Atoi(base[dash+1:])
+	if err != nil || page < 1 {
+		return nil, errors.New("sitemap not found")
+	}
+
+	for _, section := range sitemapSections {
+		if section.name != base[:dash] {
+			continue
+		}
+
+		entries, err := s.sitemapRepo.GetEntries(ctx, section.entityType, sitemapPageSize, (page-1)*sitemapPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s sitemap: %w", section.name, err)
+		}
+		if len(entries) == 0 && page > 1 {
+			return nil, errors.New("sitemap not found")
+		}
+
+		urlSet := &SitemapURLSet{Xmlns: sitemapNamespace, URLs: make([]SitemapURL, len(entries))}
+		for i, entry := range entries {
+			urlSet.URLs[i] = SitemapURL{
+				Loc:     s.storefrontURL + section.path + url.PathEscape(entry.Slug),
+				LastMod: formatLastMod(entry.UpdatedAt),
+			}
+		}
+		return urlSet, nil
+	}
+	return nil, errors.New("sitemap not found")
+}
+
+// formatLastMod formats a time in the W3C datetime format sitemaps use
+func formatLastMod(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
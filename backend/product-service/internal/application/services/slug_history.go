@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// recordSlugChange records the slug an entity gave up, so links to it can be redirected
+// to the new one. It runs in the transaction that saves the entity, so a rename never
+// commits without its redirect.
+func recordSlugChange(ctx context.Context, slugRedirects repositories.SlugRedirectRepository, entityType entities.SlugEntityType, entityID uuid.UUID, oldSlug, newSlug string) error {
+	if oldSlug == "" || oldSlug == newSlug {
+		return nil
+	}
+	if err := slugRedirects.Record(ctx, entities.NewSlugRedirect(entityType, entityID, oldSlug)); err != nil {
+		return fmt.Errorf("failed to record old slug: %w", err)
+	}
+	return nil
+}
+
+// resolveOldSlug returns the ID of the entity that gave up a slug, or nil if no entity
+// of the type ever had it
+func resolveOldSlug(ctx context.Context, slugRedirects repositories.SlugRedirectRepository, entityType entities.SlugEntityType, slug string) (*uuid.UUID, error) {
+	redirect, err := slugRedirects.Resolve(ctx, entityType, slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve slug: %w", err)
+	}
+	if redirect == nil {
+		return nil, nil
+	}
+	return &redirect.EntityID, nil
+}
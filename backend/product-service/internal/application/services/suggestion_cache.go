@@ -0,0 +1,87 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"product-service/internal/domain/repositories"
+)
+
+const (
+	suggestionCacheTTL  = time.Minute
+	suggestionCacheSize = 5000
+)
+
+// SuggestionCache is an in-memory TTL cache for typeahead results. Shoppers type the
+// same short prefixes over and over, so most keystrokes are answered without a query.
+// Product, brand and category writes all change suggestions, so their services share
+// one cache and clear it.
+type SuggestionCache struct {
+	mu         sync.RWMutex
+	entries    map[string]suggestionCacheEntry
+	ttl        time.Duration
+	maxEntries int
+}
+
+type suggestionCacheEntry struct {
+	suggestions []*repositories.Suggestion
+	expiresAt   time.Time
+}
+
+// NewSuggestionCache creates a suggestion cache with the default TTL and size
+func NewSuggestionCache() *SuggestionCache {
+	return newSuggestionCache(suggestionCacheTTL, suggestionCacheSize)
+}
+
+func newSuggestionCache(ttl time.Duration, maxEntries int) *SuggestionCache {
+	return &SuggestionCache{
+		entries:    make(map[string]suggestionCacheEntry),
+		ttl:        ttl,
+		maxEntries: maxEntries,
+	}
+}
+
+func (c *SuggestionCache) get(key string) ([]*repositories.Suggestion, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.suggestions, true
+}
+
+func (c *SuggestionCache) set(key string, suggestions []*repositories.Suggestion) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= c.maxEntries {
+		now := time.Now()
+		for k, entry := range c.entries {
+			if now.After(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		// Still full: drop arbitrary entries rather than grow without bound
+		for k := range c.entries {
+			if len(c.entries) < c.maxEntries {
+				break
+			}
+			delete(c.entries, k)
+		}
+	}
+
+	c.entries[key] = suggestionCacheEntry{
+		suggestions: suggestions,
+		expiresAt:   time.Now().Add(c.ttl),
+	}
+}
+
+// clear drops all cached results, e.g. after the catalog changes
+func (c *SuggestionCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]suggestionCacheEntry)
+}
@@ -0,0 +1,548 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// VariantService handles product variant operations
+type VariantService struct {
+	productRepo repositories.ProductRepository
+	variantRepo repositories.ProductVariantRepository
+	revisions   *RevisionService
+}
+
+// NewVariantService creates a new variant service
+func NewVariantService(
+	productRepo repositories.ProductRepository,
+	variantRepo repositories.ProductVariantRepository,
+	revisions *RevisionService,
+) *VariantService {
+	return &VariantService{
+		productRepo: productRepo,
+		variantRepo: variantRepo,
+		revisions:   revisions,
+	}
+}
+
+// GenerateVariantsRequest represents a request to generate a product's variant matrix
+type GenerateVariantsRequest struct {
+	Options    []entities.VariantOption   `json:"options" validate:"required,min=1"`
+	SKUPattern string                     `json:"sku_pattern"`
+	Overrides  []entities.VariantOverride `json:"overrides"`
+	Exclusions []map[string]string        `json:"exclusions"`
+}
+
+// GenerateVariantsResult reports what a matrix generation changed
+type GenerateVariantsResult struct {
+	Created     []*entities.ProductVariant `json:"created"`
+	Kept        int                        `json:"kept"`
+	Deactivated int                        `json:"deactivated"`
+	Variants    []*entities.ProductVariant `json:"variants"`
+}
+
+// GenerateVariants creates a variant for every combination of the option axes.
+// Existing variants are kept, with their stock and pricing, when they match a
+// combination: exactly, or on the options they share after an axis was added or
+// removed. Variants matching no combination are deactivated rather than deleted.
+func (s *VariantService) GenerateVariants(ctx context.Context, productID uuid.UUID, req *GenerateVariantsRequest) (*GenerateVariantsResult, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if product == nil {
+		return nil, errors.New("product not found")
+	}
+	if product.IsBundle {
+		return nil, errors.New("a bundle cannot have variants")
+	}
+
+	matrix := &entities.VariantMatrix{
+		Options:    req.Options,
+		SKUPattern: req.SKUPattern,
+		Overrides:  req.Overrides,
+		Exclusions: req.Exclusions,
+	}
+	if err := matrix.Validate(); err != nil {
+		return nil, err
+	}
+
+	combinations := matrix.Combinations()
+	if len(combinations) == 0 {
+		return nil, errors.New("every combination is excluded")
+	}
+
+	existing, err := s.variantRepo.GetByProductID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get variants: %w", err)
+	}
+	before := make(map[uuid.UUID]map[string]interface{}, len(existing))
+	for _, variant := range existing {
+		before[variant.ID] = entities.TakeSnapshot(variant)
+	}
+
+	claimed := matchVariants(existing, combinations)
+	result := &GenerateVariantsResult{Created: []*entities.ProductVariant{}}
+	var updated []*entities.ProductVariant
+	seenSKUs := make(map[string]bool)
+
+	for i, combination := range combinations {
+		if variant := claimed[i]; variant != nil {
+			changed := false
+			if !sameAttributes(variant.Attributes, combination) {
+				if err := variant.UpdateBasicInfo(variant.SKU, variant.Barcode, combination); err != nil {
+					return nil, err
+				}
+				changed = true
+			}
+			if !variant.IsActive {
+				variant.SetActive(true)
+				changed = true
+			}
+			if variant.SortOrder != i {
+				variant.SetSortOrder(i)
+				changed = true
+			}
+			if changed {
+				updated = append(updated, variant)
+			}
+			seenSKUs[variant.SKU] = true
+			result.Kept++
+			result.Variants = append(result.Variants, variant)
+			continue
+		}
+
+		sku := matrix.SKU(product.SKU, combination)
+		if seenSKUs[sku] {
+			return nil, fmt.Errorf("SKU pattern generates duplicate SKU %q", sku)
+		}
+		seenSKUs[sku] = true
+
+		variant, err := entities.NewProductVariant(product.ID, sku, combination)
+		if err != nil {
+			return nil, err
+		}
+		if err := variant.UpdatePricing(matrix.Price(product.Price, combination), nil, nil); err != nil {
+			return nil, err
+		}
+		if err := variant.UpdatePhysicalProperties(matrix.Weight(combination), nil, nil, nil); err != nil {
+			return nil, err
+		}
+		if err := variant.UpdateInventory(0, product.TrackStock, product.AllowBackorder); err != nil {
+			return nil, err
+		}
+		variant.SetSortOrder(i)
+
+		result.Created = append(result.Created, variant)
+		result.Variants = append(result.Variants, variant)
+	}
+
+	// Generated SKUs must not collide with variants outside this matrix
+	if len(result.Created) > 0 {
+		skus := make([]string, len(result.Created))
+		for i, variant := range result.Created {
+			skus[i] = variant.SKU
+		}
+		taken, err := s.variantRepo.GetBySKUs(ctx, skus)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check variant SKUs: %w", err)
+		}
+		if len(taken) > 0 {
+			return nil, fmt.Errorf("SKU %q is already used by another variant", taken[0].SKU)
+		}
+	}
+
+	kept := make(map[uuid.UUID]bool, len(claimed))
+	for _, variant := range claimed {
+		if variant != nil {
+			kept[variant.ID] = true
+		}
+	}
+	for _, variant := range existing {
+		if kept[variant.ID] || !variant.IsActive {
+			continue
+		}
+		variant.SetActive(false)
+		variant.SetDefault(false)
+		updated = append(updated, variant)
+		result.Deactivated++
+	}
+
+	if defaultVariant := ensureDefaultVariant(result.Variants); defaultVariant != nil && !isNewVariant(result.Created, defaultVariant) {
+		updated = append(updated, defaultVariant)
+	}
+
+	actor := ActorFromContext(ctx)
+	for _, variant := range result.Created {
+		variant.CreatedBy = actor
+		variant.UpdatedBy = actor
+	}
+	for _, variant := range updated {
+		variant.UpdatedBy = actor
+	}
+
+	revisions := make([]*entities.ProductRevision, 0, len(result.Created)+len(updated))
+	for _, variant := range result.Created {
+		revisions = append(revisions, entities.NewProductRevision(product.ID, entities.RevisionEntityVariant, variant.ID, entities.RevisionActionCreate, actor, nil, variant))
+	}
+	recorded := make(map[uuid.UUID]bool, len(updated))
+	for _, variant := range updated {
+		if recorded[variant.ID] {
+			continue
+		}
+		recorded[variant.ID] = true
+		revisions = append(revisions, entities.NewProductRevision(product.ID, entities.RevisionEntityVariant, variant.ID, entities.RevisionActionUpdate, actor, before[variant.ID], variant))
+	}
+
+	err = s.revisions.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.variantRepo.CreateBulk(ctx, result.Created); err != nil {
+			return fmt.Errorf("failed to create variants: %w", err)
+		}
+		if len(updated) > 0 {
+			if err := s.variantRepo.UpdateBulk(ctx, updated); err != nil {
+				return fmt.Errorf("failed to update variants: %w", err)
+			}
+		}
+
+		if !product.HasVariants {
+			product.HasVariants = true
+			product.UpdatedAt = time.Now()
+			if err := s.productRepo.Update(ctx, product); err != nil {
+				return fmt.Errorf("failed to update product: %w", err)
+			}
+		}
+
+		return s.revisions.Record(ctx, revisions...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ResolvedVariant is the variant matching a shopper's option picks, with the prices
+// and weight it inherits from its product filled in
+type ResolvedVariant struct {
+	Variant      *entities.ProductVariant `json:"variant"`
+	Price        float64                  `json:"price"`
+	ComparePrice float64                  `json:"compare_price"`
+	Weight       float64                  `json:"weight"`
+	InStock      bool                     `json:"in_stock"`
+	Purchasable  bool                     `json:"purchasable"`
+	PriceTiers   []entities.PriceTier     `json:"price_tiers,omitempty"`
+}
+
+// VariantAvailabilityMatrix reports which option values can still be bought, and
+// which values of the other options each one can be combined with
+type VariantAvailabilityMatrix struct {
+	ProductID uuid.UUID                   `json:"product_id"`
+	Options   []VariantOptionAvailability `json:"options"`
+	Variants  []VariantAvailability       `json:"variants"`
+}
+
+// VariantOptionAvailability lists the values of one option
+type VariantOptionAvailability struct {
+	Name   string                     `json:"name"`
+	Values []VariantValueAvailability `json:"values"`
+}
+
+// VariantValueAvailability tells whether any purchasable variant has this value, and
+// for every other option the values purchasable together with it
+type VariantValueAvailability struct {
+	Value      string              `json:"value"`
+	Available  bool                `json:"available"`
+	Compatible map[string][]string `json:"compatible"`
+}
+
+// VariantAvailability describes one variant in the matrix
+type VariantAvailability struct {
+	ID          uuid.UUID         `json:"id"`
+	SKU         string            `json:"sku"`
+	Attributes  map[string]string `json:"attributes"`
+	Price       float64           `json:"price"`
+	Purchasable bool              `json:"purchasable"`
+}
+
+// ResolveVariant finds the active variant matching the selected option values. Option
+// names and values match case-insensitively.
+func (s *VariantService) ResolveVariant(ctx context.Context, productID uuid.UUID, selection map[string]string) (*ResolvedVariant, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if product == nil {
+		return nil, errors.New("product not found")
+	}
+
+	if len(selection) == 0 {
+		return nil, errors.New("at least one option must be selected")
+	}
+
+	variants, err := s.variantRepo.GetByAttributes(ctx, productID, selection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get variants: %w", err)
+	}
+
+	var matches []*entities.ProductVariant
+	for _, variant := range variants {
+		if variant.IsActive {
+			matches = append(matches, variant)
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, errors.New("variant not found")
+	}
+	if len(matches) > 1 {
+		return nil, fmt.Errorf("selection is incomplete, also choose %s", strings.Join(missingOptions(matches, selection), ", "))
+	}
+
+	variant := matches[0]
+	return &ResolvedVariant{
+		Variant:      variant,
+		Price:        variant.GetEffectivePrice(product.Price),
+		ComparePrice: variant.GetEffectiveComparePrice(product.ComparePrice),
+		Weight:       variant.GetEffectiveWeight(product.Weight),
+		InStock:      variant.IsInStock(),
+		Purchasable:  product.IsListed() && product.InAvailabilityWindow(time.Now()) && variant.CanPurchase(1),
+		PriceTiers:   variant.GetEffectivePriceTiers(product.PriceTiers),
+	}, nil
+}
+
+// SetPriceTiers replaces a variant's own quantity breaks. An empty list removes them, so
+// the variant falls back to the product's breaks when it uses the product price.
+func (s *VariantService) SetPriceTiers(ctx context.Context, productID, variantID uuid.UUID, req *PriceTiersRequest) (*entities.ProductVariant, error) {
+	variant, err := s.variantRepo.GetByID(ctx, variantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get variant: %w", err)
+	}
+	if variant == nil || variant.ProductID != productID {
+		return nil, errors.New("variant not found")
+	}
+
+	before := entities.TakeSnapshot(variant)
+	if err := variant.SetPriceTiers(req.Tiers); err != nil {
+		return nil, err
+	}
+
+	variant.UpdatedBy = ActorFromContext(ctx)
+	err = s.revisions.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.variantRepo.Update(ctx, variant); err != nil {
+			return fmt.Errorf("failed to update variant: %w", err)
+		}
+		return s.revisions.Record(ctx, entities.NewProductRevision(productID, entities.RevisionEntityVariant, variant.ID, entities.RevisionActionUpdate, variant.UpdatedBy, before, variant))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return variant, nil
+}
+
+// GetVariantMatrix reports the purchasable combinations of a product's active
+// variants, so storefronts can grey out option values that cannot be bought
+func (s *VariantService) GetVariantMatrix(ctx context.Context, productID uuid.UUID) (*VariantAvailabilityMatrix, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if product == nil {
+		return nil, errors.New("product not found")
+	}
+
+	variants, err := s.variantRepo.GetActiveByProductID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get variants: %w", err)
+	}
+
+	// Options are sorted by name; values keep the order of the variants, which follows
+	// the axis order they were generated in
+	var names []string
+	values := make(map[string][]string)
+	seen := make(map[string]bool)
+	for _, variant := range variants {
+		for name, value := range variant.Attributes {
+			if _, ok := values[name]; !ok {
+				names = append(names, name)
+				values[name] = nil
+			}
+			if key := name + "\x00" + value; !seen[key] {
+				seen[key] = true
+				values[name] = append(values[name], value)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	// compatible[name][value][other] holds the values of other found on purchasable
+	// variants with name=value
+	compatible := make(map[string]map[string]map[string]bool)
+	matrix := &VariantAvailabilityMatrix{
+		ProductID: productID,
+		Options:   []VariantOptionAvailability{},
+		Variants:  []VariantAvailability{},
+	}
+	orderable := product.IsListed() && product.InAvailabilityWindow(time.Now())
+	for _, variant := range variants {
+		purchasable := orderable && variant.CanPurchase(1)
+		matrix.Variants = append(matrix.Variants, VariantAvailability{
+			ID:          variant.ID,
+			SKU:         variant.SKU,
+			Attributes:  variant.Attributes,
+			Price:       variant.GetEffectivePrice(product.Price),
+			Purchasable: purchasable,
+		})
+		if !purchasable {
+			continue
+		}
+
+		for name, value := range variant.Attributes {
+			if compatible[name] == nil {
+				compatible[name] = make(map[string]map[string]bool)
+			}
+			if compatible[name][value] == nil {
+				compatible[name][value] = make(map[string]bool)
+			}
+			for other, otherValue := range variant.Attributes {
+				if other != name {
+					compatible[name][value][other+"\x00"+otherValue] = true
+				}
+			}
+		}
+	}
+
+	for _, name := range names {
+		option := VariantOptionAvailability{Name: name}
+		for _, value := range values[name] {
+			pairs, available := compatible[name][value]
+			availability := VariantValueAvailability{
+				Value:      value,
+				Available:  available,
+				Compatible: make(map[string][]string),
+			}
+			for _, other := range names {
+				if other == name {
+					continue
+				}
+				availability.Compatible[other] = []string{}
+				for _, otherValue := range values[other] {
+					if pairs[other+"\x00"+otherValue] {
+						availability.Compatible[other] = append(availability.Compatible[other], otherValue)
+					}
+				}
+			}
+			option.Values = append(option.Values, availability)
+		}
+		matrix.Options = append(matrix.Options, option)
+	}
+
+	return matrix, nil
+}
+
+// missingOptions lists the options the matching variants have that were not selected
+func missingOptions(variants []*entities.ProductVariant, selection map[string]string) []string {
+	selected := make(map[string]bool, len(selection))
+	for name := range selection {
+		selected[strings.ToLower(name)] = true
+	}
+
+	seen := make(map[string]bool)
+	var missing []string
+	for _, variant := range variants {
+		for name := range variant.Attributes {
+			if !selected[strings.ToLower(name)] && !seen[name] {
+				seen[name] = true
+				missing = append(missing, name)
+			}
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// matchVariants pairs existing variants with combinations. Exact matches are claimed
+// first so each variant keeps its own combination; the rest are matched on the options
+// they have in common with a combination, which carries stock across axis changes.
+func matchVariants(existing []*entities.ProductVariant, combinations []map[string]string) []*entities.ProductVariant {
+	claimed := make([]*entities.ProductVariant, len(combinations))
+	used := make(map[uuid.UUID]bool, len(existing))
+
+	for _, matches := range []func(map[string]string, map[string]string) bool{sameAttributes, compatibleAttributes} {
+		for i, combination := range combinations {
+			if claimed[i] != nil {
+				continue
+			}
+			for _, variant := range existing {
+				if !used[variant.ID] && matches(variant.Attributes, combination) {
+					claimed[i] = variant
+					used[variant.ID] = true
+					break
+				}
+			}
+		}
+	}
+
+	return claimed
+}
+
+func sameAttributes(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if b[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// compatibleAttributes checks that a and b share at least one option and agree on all
+// the options they share
+func compatibleAttributes(a, b map[string]string) bool {
+	shared := 0
+	for key, value := range a {
+		if other, ok := b[key]; ok {
+			if other != value {
+				return false
+			}
+			shared++
+		}
+	}
+	return shared > 0
+}
+
+// ensureDefaultVariant makes the first active variant the default when no active
+// variant is, and returns the variant it changed
+func ensureDefaultVariant(variants []*entities.ProductVariant) *entities.ProductVariant {
+	for _, variant := range variants {
+		if variant.IsActive && variant.IsDefault {
+			return nil
+		}
+	}
+	for _, variant := range variants {
+		if variant.IsActive {
+			variant.SetDefault(true)
+			return variant
+		}
+	}
+	return nil
+}
+
+func isNewVariant(created []*entities.ProductVariant, variant *entities.ProductVariant) bool {
+	for _, v := range created {
+		if v == variant {
+			return true
+		}
+	}
+	return false
+}
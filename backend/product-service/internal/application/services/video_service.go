@@ -0,0 +1,284 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+
+	"github.com/google/uuid"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+	"product-service/internal/domain/storage"
+)
+
+// MaxVideoUploadSize is the largest video file accepted, in bytes
+const MaxVideoUploadSize = 200 << 20
+
+// videoFormats maps the detected content types of accepted video uploads to the
+// content type they are stored with and their file extension
+var videoFormats = map[string]struct{ mimeType, ext string }{
+	"video/mp4":       {"video/mp4", "mp4"},
+	"video/webm":      {"video/webm", "webm"},
+	"application/ogg": {"video/ogg", "ogv"},
+}
+
+// VideoService manages product videos: linked YouTube, Vimeo and other videos, and
+// video files uploaded to file storage
+type VideoService struct {
+	productRepo repositories.ProductRepository
+	videoRepo   repositories.ProductVideoRepository
+	storage     storage.Storage
+	revisions   *RevisionService
+}
+
+// NewVideoService creates a new video service
+func NewVideoService(
+	productRepo repositories.ProductRepository,
+	videoRepo repositories.ProductVideoRepository,
+	storage storage.Storage,
+	revisions *RevisionService,
+) *VideoService {
+	return &VideoService{
+		productRepo: productRepo,
+		videoRepo:   videoRepo,
+		storage:     storage,
+		revisions:   revisions,
+	}
+}
+
+// AddVideoRequest represents the request to link a video to a product
+type AddVideoRequest struct {
+	URL          string `json:"url" binding:"required"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+// VideoUpload is one uploaded video file
+type VideoUpload struct {
+	FileName    string
+	Reader      io.Reader
+	Title       string
+	Description string
+}
+
+// ReorderVideosRequest represents the request to reorder the videos of a product
+type ReorderVideosRequest struct {
+	VideoIDs []uuid.UUID `json:"video_ids" binding:"required"`
+}
+
+// ListVideos retrieves the videos of a product in display order
+func (s *VideoService) ListVideos(ctx context.Context, productID uuid.UUID) ([]*entities.ProductVideo, error) {
+	if _, err := s.getProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	videos, err := s.videoRepo.GetByProductID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get videos: %w", err)
+	}
+	return videos, nil
+}
+
+// AddVideo links a video to a product by URL. YouTube and Vimeo URLs are normalised
+// and given embed and thumbnail URLs; the type is detected from the URL.
+func (s *VideoService) AddVideo(ctx context.Context, productID uuid.UUID, req *AddVideoRequest) (*entities.ProductVideo, error) {
+	product, err := s.getProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	video, err := entities.NewProductVideo(product.ID, req.URL, req.Title)
+	if err != nil {
+		return nil, err
+	}
+	if err := video.UpdateInfo(video.URL, req.Title, req.Description, req.ThumbnailURL); err != nil {
+		return nil, err
+	}
+
+	if err := s.create(ctx, video); err != nil {
+		return nil, err
+	}
+	return video, nil
+}
+
+// UploadVideo stores an MP4, WebM or Ogg video file and adds it to a product. The file
+// is streamed to storage rather than held in memory.
+func (s *VideoService) UploadVideo(ctx context.Context, productID uuid.UUID, upload VideoUpload) (*entities.ProductVideo, error) {
+	product, err := s.getProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReaderSize(upload.Reader, 512)
+	head, err := reader.Peek(512)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read video: %w", err)
+	}
+	if len(head) == 0 {
+		return nil, errors.New("file is empty")
+	}
+	format, ok := videoFormats[http.DetectContentType(head)]
+	if !ok {
+		return nil, errors.New("file is not an MP4, WebM or Ogg video")
+	}
+
+	videoID := uuid.New()
+	key := path.Join("videos", videoID.String(), "original."+format.ext)
+	counter := &countingReader{reader: io.LimitReader(reader, MaxVideoUploadSize+1)}
+	if err := s.storage.Put(ctx, key, counter, format.mimeType); err != nil {
+		return nil, fmt.Errorf("failed to store video: %w", err)
+	}
+	if counter.count > MaxVideoUploadSize {
+		s.deleteStored(ctx, key)
+		return nil, fmt.Errorf("file is larger than %d MB", MaxVideoUploadSize>>20)
+	}
+
+	video, err := entities.NewUploadedProductVideo(product.ID, s.storage.URL(key), key, upload.Title)
+	if err != nil {
+		s.deleteStored(ctx, key)
+		return nil, err
+	}
+	video.ID = videoID
+	video.Description = upload.Description
+	video.UpdateProperties(0, counter.count, format.mimeType)
+
+	if err := s.create(ctx, video); err != nil {
+		s.deleteStored(ctx, key)
+		return nil, err
+	}
+	return video, nil
+}
+
+// ReorderVideos sets the display order of a product's videos; videoIDs must list each
+// of its videos exactly once
+func (s *VideoService) ReorderVideos(ctx context.Context, productID uuid.UUID, videoIDs []uuid.UUID) ([]*entities.ProductVideo, error) {
+	videos, err := s.ListVideos(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	positions := make(map[uuid.UUID]int, len(videoIDs))
+	for i, id := range videoIDs {
+		if _, ok := positions[id]; ok {
+			return nil, errors.New("video IDs must list every video exactly once")
+		}
+		positions[id] = i
+	}
+	if len(positions) != len(videos) {
+		return nil, errors.New("video IDs must list every video exactly once")
+	}
+	for _, video := range videos {
+		if _, ok := positions[video.ID]; !ok {
+			return nil, errors.New("video IDs must list every video exactly once")
+		}
+	}
+
+	actor := ActorFromContext(ctx)
+	var revisions []*entities.ProductRevision
+	ordered := make([]*entities.ProductVideo, len(videos))
+	for _, video := range videos {
+		position := positions[video.ID]
+		if video.SortOrder != position {
+			before := entities.TakeSnapshot(video)
+			video.SetSortOrder(position)
+			video.UpdatedBy = actor
+			revisions = append(revisions, entities.NewProductRevision(productID, entities.RevisionEntityVideo, video.ID, entities.RevisionActionUpdate, actor, before, video))
+		}
+		ordered[position] = video
+	}
+
+	err = s.revisions.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.videoRepo.ReorderVideos(ctx, productID, videoIDs); err != nil {
+			return fmt.Errorf("failed to reorder videos: %w", err)
+		}
+		return s.revisions.Record(ctx, revisions...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ordered, nil
+}
+
+// DeleteVideo removes a video from a product, along with its file if it was uploaded
+func (s *VideoService) DeleteVideo(ctx context.Context, productID, videoID uuid.UUID) error {
+	video, err := s.videoRepo.GetByID(ctx, videoID)
+	if err != nil {
+		return fmt.Errorf("failed to get video: %w", err)
+	}
+	if video == nil || video.ProductID != productID {
+		return errors.New("video not found")
+	}
+
+	err = s.revisions.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.videoRepo.Delete(ctx, video.ID); err != nil {
+			return fmt.Errorf("failed to delete video: %w", err)
+		}
+		return s.revisions.Record(ctx, entities.NewProductRevision(productID, entities.RevisionEntityVideo, video.ID, entities.RevisionActionDelete, ActorFromContext(ctx), video, nil))
+	})
+	if err != nil {
+		return err
+	}
+	if video.StorageKey != "" {
+		s.deleteStored(ctx, video.StorageKey)
+	}
+
+	return nil
+}
+
+// create saves a new video at the end of its product's videos
+func (s *VideoService) create(ctx context.Context, video *entities.ProductVideo) error {
+	count, err := s.videoRepo.CountByProductID(ctx, video.ProductID)
+	if err != nil {
+		return fmt.Errorf("failed to count videos: %w", err)
+	}
+
+	actor := ActorFromContext(ctx)
+	video.SetSortOrder(int(count))
+	video.CreatedBy = actor
+	video.UpdatedBy = actor
+
+	return s.revisions.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.videoRepo.Create(ctx, video); err != nil {
+			return fmt.Errorf("failed to create video: %w", err)
+		}
+		return s.revisions.Record(ctx, entities.NewProductRevision(video.ProductID, entities.RevisionEntityVideo, video.ID, entities.RevisionActionCreate, actor, nil, video))
+	})
+}
+
+// getProduct retrieves a product, or a not found error
+func (s *VideoService) getProduct(ctx context.Context, productID uuid.UUID) (*entities.Product, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if product == nil {
+		return nil, errors.New("product not found")
+	}
+	return product, nil
+}
+
+// deleteStored removes a stored video file; failures only leave an orphaned file, so
+// they are logged rather than returned
+func (s *VideoService) deleteStored(ctx context.Context, key string) {
+	if err := s.storage.Delete(ctx, key); err != nil {
+		fmt.Printf("Warning: failed to delete stored video %s: %v\n", key, err)
+	}
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	return n, err
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Category represents a node in the product category hierarchy
+type Category struct {
+	id        CategoryID
+	name      string
+	slug      string
+	parentID  *CategoryID
+	level     int
+	path      string
+	isActive  bool
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+// Factory method for creating new categories
+func NewCategory(name, slug string, parent *Category) (*Category, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, errors.New("category name is required")
+	}
+
+	if strings.TrimSpace(slug) == "" {
+		return nil, errors.New("category slug is required")
+	}
+
+	category := &Category{
+		id:        NewCategoryID(),
+		name:      strings.TrimSpace(name),
+		slug:      strings.TrimSpace(slug),
+		isActive:  true,
+		createdAt: time.Now().UTC(),
+		updatedAt: time.Now().UTC(),
+	}
+
+	category.moveUnder(parent)
+
+	return category, nil
+}
+
+// Business methods
+func (c *Category) Rename(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return errors.New("category name is required")
+	}
+
+	c.name = strings.TrimSpace(name)
+	c.updatedAt = time.Now().UTC()
+
+	return nil
+}
+
+func (c *Category) MoveTo(parent *Category) error {
+	if parent != nil && parent.id == c.id {
+		return errors.New("category cannot be its own parent")
+	}
+
+	c.moveUnder(parent)
+	c.updatedAt = time.Now().UTC()
+
+	return nil
+}
+
+func (c *Category) Activate()   { c.isActive = true; c.updatedAt = time.Now().UTC() }
+func (c *Category) Deactivate() { c.isActive = false; c.updatedAt = time.Now().UTC() }
+
+func (c *Category) moveUnder(parent *Category) {
+	if parent == nil {
+		c.parentID = nil
+		c.level = 0
+		c.path = c.slug
+		return
+	}
+
+	parentID := parent.id
+	c.parentID = &parentID
+	c.level = parent.level + 1
+	c.path = parent.path + "/" + c.slug
+}
+
+// Getters (following encapsulation principles)
+func (c *Category) ID() CategoryID        { return c.id }
+func (c *Category) Name() string          { return c.name }
+func (c *Category) Slug() string          { return c.slug }
+func (c *Category) ParentID() *CategoryID { return c.parentID }
+func (c *Category) Level() int            { return c.level }
+func (c *Category) Path() string          { return c.path }
+func (c *Category) IsActive() bool        { return c.isActive }
+func (c *Category) IsRoot() bool          { return c.parentID == nil }
+func (c *Category) CreatedAt() time.Time  { return c.createdAt }
+func (c *Category) UpdatedAt() time.Time  { return c.updatedAt }
+
+// Domain events
+type CategoryEvent interface {
+	EventType() string
+	AggregateID() CategoryID
+	OccurredAt() time.Time
+}
+
+type CategoryMovedEvent struct {
+	categoryID  CategoryID
+	oldParentID *CategoryID
+	newParentID *CategoryID
+	occurredAt  time.Time
+}
+
+func (e CategoryMovedEvent) EventType() string       { return "CategoryMoved" }
+func (e CategoryMovedEvent) AggregateID() CategoryID { return e.categoryID }
+func (e CategoryMovedEvent) OccurredAt() time.Time   { return e.occurredAt }
@@ -0,0 +1,178 @@
+package entities
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BundlePricing is how the price of a bundle is set
+type BundlePricing string
+
+const (
+	// BundlePricingFixed keeps the price set on the bundle product
+	BundlePricingFixed BundlePricing = "fixed"
+	// BundlePricingComputed prices a bundle at the sum of its components, less a discount
+	BundlePricingComputed BundlePricing = "computed"
+)
+
+// BundleItem is one component of a bundle: a product, or one of its variants, and how
+// many of it go into one bundle
+type BundleItem struct {
+	ID        uuid.UUID       `json:"id" gorm:"type:uuid;primary_key"`
+	BundleID  uuid.UUID       `json:"bundle_id" gorm:"type:uuid;not null;index"`
+	ProductID uuid.UUID       `json:"product_id" gorm:"type:uuid;not null;index"`
+	Product   *Product        `json:"product,omitempty" gorm:"foreignKey:ProductID"`
+	VariantID *uuid.UUID      `json:"variant_id,omitempty" gorm:"type:uuid"`
+	Variant   *ProductVariant `json:"variant,omitempty" gorm:"foreignKey:VariantID"`
+	Quantity  int             `json:"quantity" gorm:"not null"`
+	SortOrder int             `json:"sort_order" gorm:"default:0"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// NewBundleItem creates a bundle component
+func NewBundleItem(bundleID, productID uuid.UUID, variantID *uuid.UUID, quantity int) (*BundleItem, error) {
+	if productID == uuid.Nil {
+		return nil, errors.New("component product ID is required")
+	}
+	if productID == bundleID {
+		return nil, errors.New("a bundle cannot contain itself")
+	}
+	if quantity <= 0 {
+		return nil, errors.New("component quantity must be positive")
+	}
+
+	return &BundleItem{
+		ID:        uuid.New(),
+		BundleID:  bundleID,
+		ProductID: productID,
+		VariantID: variantID,
+		Quantity:  quantity,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+// stock returns the stock of the component and whether it limits how many bundles can
+// be built. Components that are missing or inactive limit the bundle to zero.
+func (i *BundleItem) stock() (quantity int, limited bool) {
+	switch {
+	case i.Variant != nil:
+		if !i.Variant.IsActive {
+			return 0, true
+		}
+		return i.Variant.StockQuantity, i.Variant.TrackStock && !i.Variant.AllowBackorder
+	case i.Product != nil && i.VariantID == nil:
+		return i.Product.StockQuantity, i.Product.TrackStock && !i.Product.AllowBackorder
+	}
+	return 0, true
+}
+
+// unitPrice returns the price of one of the component
+func (i *BundleItem) unitPrice() float64 {
+	if i.Product == nil {
+		return 0
+	}
+	if i.Variant != nil {
+		return i.Variant.GetEffectivePrice(i.Product.Price)
+	}
+	return i.Product.Price
+}
+
+// IsValidBundlePricing checks if a bundle pricing mode is known
+func IsValidBundlePricing(pricing BundlePricing) bool {
+	return pricing == BundlePricingFixed || pricing == BundlePricingComputed
+}
+
+// SetBundle makes the product a bundle priced the given way. Computed bundles take a
+// discount off the sum of their components: a percentage, or a fixed amount.
+func (p *Product) SetBundle(pricing BundlePricing, discountType DiscountType, discountValue float64) error {
+	if p.HasVariants {
+		return errors.New("a product with variants cannot be a bundle")
+	}
+	if !IsValidBundlePricing(pricing) {
+		return errors.New("bundle pricing must be fixed or computed")
+	}
+
+	if pricing == BundlePricingFixed {
+		discountType, discountValue = "", 0
+	} else if discountValue != 0 || discountType != "" {
+		switch discountType {
+		case DiscountTypePercent:
+			if discountValue < 0 || discountValue > 100 {
+				return errors.New("percent discount must be between 0 and 100")
+			}
+		case DiscountTypeFixed:
+			if discountValue < 0 {
+				return errors.New("discount cannot be negative")
+			}
+		default:
+			return errors.New("discount type must be percent or fixed")
+		}
+	}
+
+	p.IsBundle = true
+	p.BundlePricing = pricing
+	p.BundleDiscountType = discountType
+	p.BundleDiscountValue = discountValue
+	p.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// ClearBundle turns a bundle back into a product with its own stock and price
+func (p *Product) ClearBundle() {
+	p.IsBundle = false
+	p.BundlePricing = ""
+	p.BundleDiscountType = ""
+	p.BundleDiscountValue = 0
+	p.BundleItems = nil
+	p.UpdatedAt = time.Now()
+}
+
+// DeriveBundle sets a bundle's stock to the number of bundles its components can build,
+// and, for computed pricing, its price to the discounted sum of its components. Each
+// item must have its Product, and its Variant if any, loaded. A bundle whose components
+// are all unlimited does not track stock.
+func (p *Product) DeriveBundle(items []*BundleItem) {
+	buildable := -1
+	sum := 0.0
+	for _, item := range items {
+		sum += item.unitPrice() * float64(item.Quantity)
+
+		stock, limited := item.stock()
+		if !limited {
+			continue
+		}
+		if stock < 0 {
+			stock = 0
+		}
+		if n := stock / item.Quantity; buildable < 0 || n < buildable {
+			buildable = n
+		}
+	}
+
+	p.AllowBackorder = false
+	if buildable < 0 {
+		p.TrackStock = false
+		p.StockQuantity = 0
+	} else {
+		p.TrackStock = true
+		p.StockQuantity = buildable
+	}
+
+	if p.BundlePricing == BundlePricingComputed {
+		price := sum
+		switch p.BundleDiscountType {
+		case DiscountTypePercent:
+			price = sum * (1 - p.BundleDiscountValue/100)
+		case DiscountTypeFixed:
+			price = math.Max(sum-p.BundleDiscountValue, 0)
+		}
+		p.Price = math.Round(price*100) / 100
+	}
+	p.UpdatedAt = time.Now()
+}
@@ -0,0 +1,169 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Catalog file formats supported by import and export
+const (
+	CatalogFormatCSV   = "csv"
+	CatalogFormatJSONL = "jsonl"
+)
+
+// Import modes
+const (
+	ImportModeCreate = "create" // rows whose SKU already exists are rejected
+	ImportModeUpsert = "upsert" // rows whose SKU already exists update that product or variant
+)
+
+// maxImportJobErrors caps the row errors stored on a job
+const maxImportJobErrors = 1000
+
+// ImportJobStatus represents the state of a catalog import
+type ImportJobStatus string
+
+const (
+	ImportJobStatusPending   ImportJobStatus = "pending"
+	ImportJobStatusRunning   ImportJobStatus = "running"
+	ImportJobStatusCompleted ImportJobStatus = "completed"
+	ImportJobStatusFailed    ImportJobStatus = "failed"
+)
+
+// ImportRowError describes why a row of an import file was rejected
+type ImportRowError struct {
+	Line    int    `json:"line"`
+	SKU     string `json:"sku,omitempty"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// Error implements the error interface
+func (e *ImportRowError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("line %d: %s: %s", e.Line, e.Field, e.Message)
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// ImportJob tracks an applied catalog import. Rows are committed in chunks, and
+// ProcessedRows always matches what has been committed, so a failed or interrupted
+// job resumes by skipping that many rows of the stored file.
+type ImportJob struct {
+	ID       uuid.UUID       `json:"id" gorm:"type:uuid;primary_key"`
+	Format   string          `json:"format" gorm:"not null"`
+	Mode     string          `json:"mode" gorm:"not null"`
+	Status   ImportJobStatus `json:"status" gorm:"not null;index"`
+	FileName string          `json:"file_name"`
+	FilePath string          `json:"-"`
+	Checksum string          `json:"checksum"` // SHA-256 of the uploaded file
+
+	// Progress
+	TotalRows     int `json:"total_rows"`
+	ProcessedRows int `json:"processed_rows"`
+	CreatedCount  int `json:"created_count"`
+	UpdatedCount  int `json:"updated_count"`
+	FailedCount   int `json:"failed_count"`
+
+	// Errors
+	RowErrors []ImportRowError `json:"row_errors" gorm:"type:jsonb;serializer:json"`
+	LastError string           `json:"last_error,omitempty"`
+
+	// Timestamps
+	StartedAt   *time.Time `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	CreatedBy   string     `json:"created_by"`
+}
+
+// NewImportJob creates a pending import job for a stored import file
+func NewImportJob(format, mode, fileName, filePath, checksum string, totalRows int) (*ImportJob, error) {
+	if format != CatalogFormatCSV && format != CatalogFormatJSONL {
+		return nil, errors.New("format must be csv or jsonl")
+	}
+
+	if mode != ImportModeCreate && mode != ImportModeUpsert {
+		return nil, errors.New("mode must be create or upsert")
+	}
+
+	if filePath == "" {
+		return nil, errors.New("import file path is required")
+	}
+
+	if totalRows < 0 {
+		return nil, errors.New("total rows cannot be negative")
+	}
+
+	return &ImportJob{
+		ID:        uuid.New(),
+		Format:    format,
+		Mode:      mode,
+		Status:    ImportJobStatusPending,
+		FileName:  fileName,
+		FilePath:  filePath,
+		Checksum:  checksum,
+		TotalRows: totalRows,
+		RowErrors: []ImportRowError{},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+// Start marks the job as running
+func (j *ImportJob) Start() error {
+	if j.Status == ImportJobStatusCompleted {
+		return errors.New("import job already completed")
+	}
+
+	now := time.Now()
+	if j.StartedAt == nil {
+		j.StartedAt = &now
+	}
+	j.Status = ImportJobStatusRunning
+	j.LastError = ""
+	j.UpdatedAt = now
+
+	return nil
+}
+
+// RecordChunk records the outcome of a committed chunk of rows
+func (j *ImportJob) RecordChunk(rows, created, updated, failed int, rowErrors []ImportRowError) {
+	j.ProcessedRows += rows
+	j.CreatedCount += created
+	j.UpdatedCount += updated
+	j.FailedCount += failed
+
+	if room := maxImportJobErrors - len(j.RowErrors); room > 0 {
+		if len(rowErrors) > room {
+			rowErrors = rowErrors[:room]
+		}
+		j.RowErrors = append(j.RowErrors, rowErrors...)
+	}
+
+	j.UpdatedAt = time.Now()
+}
+
+// Complete marks the job as finished
+func (j *ImportJob) Complete() {
+	now := time.Now()
+	j.Status = ImportJobStatusCompleted
+	j.CompletedAt = &now
+	j.UpdatedAt = now
+}
+
+// Fail marks the job as failed; it can be resumed from ProcessedRows
+func (j *ImportJob) Fail(err error) {
+	j.Status = ImportJobStatusFailed
+	j.LastError = err.Error()
+	j.UpdatedAt = time.Now()
+}
+
+// CanResume checks if the job stopped before finishing. Running jobs qualify too,
+// since a job left running by a crashed process would otherwise never finish.
+func (j *ImportJob) CanResume() bool {
+	return j.Status == ImportJobStatusFailed || j.Status == ImportJobStatusRunning || j.Status == ImportJobStatusPending
+}
@@ -0,0 +1,364 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AttributeType determines how an attribute value is validated
+type AttributeType string
+
+const (
+	AttributeTypeText    AttributeType = "text"
+	AttributeTypeNumber  AttributeType = "number"
+	AttributeTypeEnum    AttributeType = "enum"
+	AttributeTypeBoolean AttributeType = "boolean"
+	AttributeTypeMeasure AttributeType = "measure" // a number with a unit, e.g. "15.6 in"
+)
+
+var attributeNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_]*$`)
+var measurePattern = regexp.MustCompile(`^([-+]?[0-9]*\.?[0-9]+)\s*([^\s0-9].*)$`)
+
+// CategoryAttribute defines one attribute in a category's schema. Products in the
+// category and in all its subcategories must follow it; a subcategory may redefine an
+// attribute of the same name to tighten or replace it.
+type CategoryAttribute struct {
+	ID         uuid.UUID     `json:"id" gorm:"type:uuid;primary_key"`
+	CategoryID uuid.UUID     `json:"category_id" gorm:"type:uuid;not null;uniqueIndex:idx_category_attributes_name"`
+	Name       string        `json:"name" gorm:"not null;uniqueIndex:idx_category_attributes_name"` // key in Product.Attributes
+	Label      string        `json:"label"`
+	Type       AttributeType `json:"type" gorm:"not null;default:'text'"`
+
+	// Constraints
+	Required      bool     `json:"required" gorm:"default:false"`
+	AllowedValues []string `json:"allowed_values,omitempty" gorm:"type:jsonb;serializer:json"` // enum values
+	Units         []string `json:"units,omitempty" gorm:"type:jsonb;serializer:json"`          // measure units; bare numbers take the first
+	Min           *float64 `json:"min,omitempty"`
+	Max           *float64 `json:"max,omitempty"`
+
+	// Search
+	Filterable bool `json:"filterable" gorm:"default:false"`
+	SortOrder  int  `json:"sort_order" gorm:"default:0"`
+
+	// Timestamps
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewCategoryAttribute creates a new category attribute definition. Constraints are set
+// on the returned value, after which Validate checks the whole definition.
+func NewCategoryAttribute(categoryID uuid.UUID, name string, attrType AttributeType) *CategoryAttribute {
+	if attrType == "" {
+		attrType = AttributeTypeText
+	}
+
+	return &CategoryAttribute{
+		ID:         uuid.New(),
+		CategoryID: categoryID,
+		Name:       strings.ToLower(strings.TrimSpace(name)),
+		Type:       attrType,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+}
+
+// Validate checks the definition itself is consistent
+func (a *CategoryAttribute) Validate() error {
+	if !attributeNamePattern.MatchString(a.Name) {
+		return errors.New("attribute name must be lowercase letters, digits and underscores")
+	}
+
+	switch a.Type {
+	case AttributeTypeText, AttributeTypeNumber, AttributeTypeBoolean:
+	case AttributeTypeEnum:
+		if len(a.AllowedValues) == 0 {
+			return errors.New("enum attribute requires allowed values")
+		}
+	case AttributeTypeMeasure:
+		if len(a.Units) == 0 {
+			return errors.New("measure attribute requires at least one unit")
+		}
+	default:
+		return fmt.Errorf("invalid attribute type %q", a.Type)
+	}
+
+	seen := make(map[string]bool, len(a.AllowedValues))
+	for i, value := range a.AllowedValues {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return errors.New("allowed values cannot be empty")
+		}
+		if seen[strings.ToLower(value)] {
+			return fmt.Errorf("duplicate allowed value %q", value)
+		}
+		seen[strings.ToLower(value)] = true
+		a.AllowedValues[i] = value
+	}
+
+	for i, unit := range a.Units {
+		a.Units[i] = strings.TrimSpace(unit)
+		if a.Units[i] == "" {
+			return errors.New("units cannot be empty")
+		}
+	}
+
+	if a.Min != nil && a.Max != nil && *a.Min > *a.Max {
+		return errors.New("min cannot be greater than max")
+	}
+
+	return nil
+}
+
+// DisplayLabel returns the label, falling back to the attribute name
+func (a *CategoryAttribute) DisplayLabel() string {
+	if a.Label != "" {
+		return a.Label
+	}
+	return a.Name
+}
+
+// NormalizeValue validates a raw value against the definition and returns it in
+// canonical form: numbers without padding, booleans as "true"/"false", enum values
+// with their defined casing and measures as "<number> <unit>".
+func (a *CategoryAttribute) NormalizeValue(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", errors.New("value cannot be empty")
+	}
+
+	switch a.Type {
+	case AttributeTypeNumber:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "", errors.New("must be a number")
+		}
+		if err := a.checkRange(number); err != nil {
+			return "", err
+		}
+		return strconv.FormatFloat(number, 'f', -1, 64), nil
+
+	case AttributeTypeBoolean:
+		switch strings.ToLower(value) {
+		case "true", "yes", "1":
+			return "true", nil
+		case "false", "no", "0":
+			return "false", nil
+		}
+		return "", errors.New("must be true or false")
+
+	case AttributeTypeEnum:
+		for _, allowed := range a.AllowedValues {
+			if strings.EqualFold(allowed, value) {
+				return allowed, nil
+			}
+		}
+		return "", fmt.Errorf("must be one of: %s", strings.Join(a.AllowedValues, ", "))
+
+	case AttributeTypeMeasure:
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			if err := a.checkRange(number); err != nil {
+				return "", err
+			}
+			return strconv.FormatFloat(number, 'f', -1, 64) + " " + a.Units[0], nil
+		}
+		match := measurePattern.FindStringSubmatch(value)
+		if match == nil {
+			return "", fmt.Errorf("must be a number followed by a unit (%s)", strings.Join(a.Units, ", "))
+		}
+		number, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return "", errors.New("must be a number followed by a unit")
+		}
+		unit := ""
+		for _, allowed := range a.Units {
+			if strings.EqualFold(allowed, strings.TrimSpace(match[2])) {
+				unit = allowed
+				break
+			}
+		}
+		if unit == "" {
+			return "", fmt.Errorf("unit must be one of: %s", strings.Join(a.Units, ", "))
+		}
+		if err := a.checkRange(number); err != nil {
+			return "", err
+		}
+		return strconv.FormatFloat(number, 'f', -1, 64) + " " + unit, nil
+	}
+
+	if len(a.AllowedValues) > 0 {
+		for _, allowed := range a.AllowedValues {
+			if strings.EqualFold(allowed, value) {
+				return allowed, nil
+			}
+		}
+		return "", fmt.Errorf("must be one of: %s", strings.Join(a.AllowedValues, ", "))
+	}
+	return value, nil
+}
+
+// checkRange enforces Min and Max on numeric values
+func (a *CategoryAttribute) checkRange(number float64) error {
+	if a.Min != nil && number < *a.Min {
+		return fmt.Errorf("must be at least %g", *a.Min)
+	}
+	if a.Max != nil && number > *a.Max {
+		return fmt.Errorf("must be at most %g", *a.Max)
+	}
+	return nil
+}
+
+// AttributeSchema is the effective attribute schema of a category: its own attributes
+// plus those inherited from its ancestors, where the definition closest to the
+// category wins.
+type AttributeSchema struct {
+	CategoryID uuid.UUID            `json:"category_id"`
+	Attributes []*CategoryAttribute `json:"attributes"`
+}
+
+// NewAttributeSchema merges attribute definitions along a category path. path lists
+// the category's ancestors from the root down, ending with the category itself.
+func NewAttributeSchema(path []uuid.UUID, definitions []*CategoryAttribute) *AttributeSchema {
+	depth := make(map[uuid.UUID]int, len(path))
+	for i, id := range path {
+		depth[id] = i
+	}
+
+	byName := make(map[string]*CategoryAttribute)
+	order := make([]string, 0, len(definitions))
+	for _, definition := range definitions {
+		d, ok := depth[definition.CategoryID]
+		if !ok {
+			continue
+		}
+		current, exists := byName[definition.Name]
+		if !exists {
+			order = append(order, definition.Name)
+		}
+		if !exists || d > depth[current.CategoryID] {
+			byName[definition.Name] = definition
+		}
+	}
+
+	schema := &AttributeSchema{Attributes: make([]*CategoryAttribute, 0, len(order))}
+	if len(path) > 0 {
+		schema.CategoryID = path[len(path)-1]
+	}
+	for _, name := range order {
+		schema.Attributes = append(schema.Attributes, byName[name])
+	}
+	sortAttributes(schema.Attributes)
+
+	return schema
+}
+
+// Get finds an attribute by name, ignoring case
+func (s *AttributeSchema) Get(name string) *CategoryAttribute {
+	for _, attribute := range s.Attributes {
+		if strings.EqualFold(attribute.Name, strings.TrimSpace(name)) {
+			return attribute
+		}
+	}
+	return nil
+}
+
+// Filterable returns the names of the attributes offered as search facets
+func (s *AttributeSchema) Filterable() []string {
+	var names []string
+	for _, attribute := range s.Attributes {
+		if attribute.Filterable {
+			names = append(names, attribute.Name)
+		}
+	}
+	return names
+}
+
+// Apply validates product attributes against the schema. It returns the attributes
+// with schema keys and values normalized, or an *AttributeValidationError listing every
+// invalid field. Attributes not in the schema are kept as free-form text.
+func (s *AttributeSchema) Apply(attributes map[string]string) (map[string]string, error) {
+	result := make(map[string]string, len(attributes))
+	var fieldErrors []FieldError
+
+	for key, value := range attributes {
+		definition := s.Get(key)
+		if definition == nil {
+			result[key] = value
+			continue
+		}
+		normalized, err := definition.NormalizeValue(value)
+		if err != nil {
+			fieldErrors = append(fieldErrors, FieldError{Field: "attributes." + definition.Name, Message: err.Error()})
+			continue
+		}
+		result[definition.Name] = normalized
+	}
+
+	for _, definition := range s.Attributes {
+		if !definition.Required {
+			continue
+		}
+		if _, ok := result[definition.Name]; ok {
+			continue
+		}
+		if hasFieldError(fieldErrors, "attributes."+definition.Name) {
+			continue
+		}
+		fieldErrors = append(fieldErrors, FieldError{Field: "attributes." + definition.Name, Message: "is required"})
+	}
+
+	if len(fieldErrors) > 0 {
+		sortFieldErrors(fieldErrors)
+		return nil, &AttributeValidationError{Fields: fieldErrors}
+	}
+	return result, nil
+}
+
+// FieldError describes a validation failure of a single request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// AttributeValidationError reports product attributes that do not match the schema
+type AttributeValidationError struct {
+	Fields []FieldError `json:"fields"`
+}
+
+func (e *AttributeValidationError) Error() string {
+	parts := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		parts[i] = field.Field + " " + field.Message
+	}
+	return "invalid attributes: " + strings.Join(parts, "; ")
+}
+
+func hasFieldError(fieldErrors []FieldError, field string) bool {
+	for _, fieldError := range fieldErrors {
+		if fieldError.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+func sortFieldErrors(fieldErrors []FieldError) {
+	sort.Slice(fieldErrors, func(i, j int) bool {
+		return fieldErrors[i].Field < fieldErrors[j].Field
+	})
+}
+
+func sortAttributes(attributes []*CategoryAttribute) {
+	sort.SliceStable(attributes, func(i, j int) bool {
+		if attributes[i].SortOrder != attributes[j].SortOrder {
+			return attributes[i].SortOrder < attributes[j].SortOrder
+		}
+		return attributes[i].Name < attributes[j].Name
+	})
+}
@@ -0,0 +1,184 @@
+package entities
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// PriceSource tells where a resolved price came from
+type PriceSource string
+
+const (
+	PriceSourceBase      PriceSource = "base"       // the product or variant price
+	PriceSourcePriceList PriceSource = "price_list" // a price list entry
+)
+
+// PriceList holds prices in one currency for one sales channel and customer group. An
+// empty channel or group means the list applies to every channel or group.
+type PriceList struct {
+	ID            uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	Name          string    `json:"name" gorm:"not null"`
+	Currency      string    `json:"currency" gorm:"size:3;not null;uniqueIndex:idx_price_lists_key"`
+	Channel       string    `json:"channel" gorm:"not null;default:'';uniqueIndex:idx_price_lists_key"`
+	CustomerGroup string    `json:"customer_group" gorm:"not null;default:'';uniqueIndex:idx_price_lists_key"`
+	IsActive      bool      `json:"is_active" gorm:"default:true"`
+
+	// Timestamps
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PriceListEntry is the price of a product, or of one of its variants, in a price list.
+// A missing bound leaves the validity open on that side.
+type PriceListEntry struct {
+	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primary_key"`
+	PriceListID  uuid.UUID  `json:"price_list_id" gorm:"type:uuid;not null;index"`
+	ProductID    uuid.UUID  `json:"product_id" gorm:"type:uuid;not null;index"`
+	VariantID    *uuid.UUID `json:"variant_id,omitempty" gorm:"type:uuid;index"`
+	Price        float64    `json:"price" gorm:"not null"`
+	ComparePrice *float64   `json:"compare_price,omitempty"`
+	ValidFrom    *time.Time `json:"valid_from,omitempty"`
+	ValidUntil   *time.Time `json:"valid_until,omitempty"`
+
+	PriceList *PriceList `json:"price_list,omitempty" gorm:"foreignKey:PriceListID"`
+
+	// Timestamps
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ResolvedPrice is the price a shopper pays in a given currency, channel and customer
+// group, with where it came from. While a sale runs, Amount is the sale price and
+// RegularAmount the price before the sale.
+type ResolvedPrice struct {
+	ProductID     uuid.UUID   `json:"product_id"`
+	VariantID     *uuid.UUID  `json:"variant_id,omitempty"`
+	Amount        float64     `json:"amount"`
+	ComparePrice  float64     `json:"compare_price,omitempty"`
+	Currency      string      `json:"currency"`
+	Source        PriceSource `json:"source"`
+	PriceListID   *uuid.UUID  `json:"price_list_id,omitempty"`
+	ValidUntil    *time.Time  `json:"valid_until,omitempty"`
+	IsOnSale      bool        `json:"is_on_sale"`
+	RegularAmount float64     `json:"regular_amount,omitempty"`
+	SaleID        *uuid.UUID  `json:"sale_id,omitempty"`
+	SaleEndsAt    *time.Time  `json:"sale_ends_at,omitempty"`
+}
+
+// NewPriceList creates a new, active price list
+func NewPriceList(name, currency, channel, customerGroup string) (*PriceList, error) {
+	list := &PriceList{
+		ID:        uuid.New(),
+		IsActive:  true,
+		CreatedAt: time.Now(),
+	}
+	if err := list.Update(name, currency, channel, customerGroup); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Update changes the name and key of a price list
+func (l *PriceList) Update(name, currency, channel, customerGroup string) error {
+	if strings.TrimSpace(name) == "" {
+		return errors.New("price list name is required")
+	}
+
+	currency = NormalizeCurrency(currency)
+	if !currencyPattern.MatchString(currency) {
+		return errors.New("currency must be a three-letter ISO 4217 code")
+	}
+
+	l.Name = strings.TrimSpace(name)
+	l.Currency = currency
+	l.Channel = NormalizePriceKey(channel)
+	l.CustomerGroup = NormalizePriceKey(customerGroup)
+	l.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// SetActive sets whether the price list is used for price resolution
+func (l *PriceList) SetActive(active bool) {
+	l.IsActive = active
+	l.UpdatedAt = time.Now()
+}
+
+// Specificity ranks how narrowly the list is targeted: a list for one channel and
+// group is more specific than a list for everyone
+func (l *PriceList) Specificity() int {
+	specificity := 0
+	if l.CustomerGroup != "" {
+		specificity += 2
+	}
+	if l.Channel != "" {
+		specificity++
+	}
+	return specificity
+}
+
+// NewPriceListEntry creates a price list entry for a product, or for one of its
+// variants when variantID is set
+func NewPriceListEntry(priceListID, productID uuid.UUID, variantID *uuid.UUID) *PriceListEntry {
+	return &PriceListEntry{
+		ID:          uuid.New(),
+		PriceListID: priceListID,
+		ProductID:   productID,
+		VariantID:   variantID,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+}
+
+// SetPrice sets the entry's price, optional compare-at price and validity window
+func (e *PriceListEntry) SetPrice(price float64, comparePrice *float64, validFrom, validUntil *time.Time) error {
+	if price < 0 {
+		return errors.New("price cannot be negative")
+	}
+	if comparePrice != nil && *comparePrice < 0 {
+		return errors.New("compare price cannot be negative")
+	}
+	if validFrom != nil && validUntil != nil && !validFrom.Before(*validUntil) {
+		return errors.New("valid from must be before valid until")
+	}
+
+	e.Price = price
+	e.ComparePrice = comparePrice
+	e.ValidFrom = validFrom
+	e.ValidUntil = validUntil
+	e.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// IsValidAt checks if at falls within [ValidFrom, ValidUntil)
+func (e *PriceListEntry) IsValidAt(at time.Time) bool {
+	if e.ValidFrom != nil && at.Before(*e.ValidFrom) {
+		return false
+	}
+	if e.ValidUntil != nil && !at.Before(*e.ValidUntil) {
+		return false
+	}
+	return true
+}
+
+// NormalizeCurrency upper-cases and trims a currency code
+func NormalizeCurrency(currency string) string {
+	return strings.ToUpper(strings.TrimSpace(currency))
+}
+
+// NormalizePriceKey lower-cases and trims a sales channel or customer group
+func NormalizePriceKey(key string) string {
+	return strings.ToLower(strings.TrimSpace(key))
+}
+
+// IsValidCurrency checks if a currency is a three-letter ISO 4217 code
+func IsValidCurrency(currency string) bool {
+	return currencyPattern.MatchString(NormalizeCurrency(currency))
+}
@@ -0,0 +1,64 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// PriceTier is a quantity break: from MinQuantity units up, each unit costs Price. A tier
+// runs until the next tier's MinQuantity; below the first tier the regular price applies.
+type PriceTier struct {
+	MinQuantity int     `json:"min_quantity"`
+	Price       float64 `json:"price"`
+}
+
+// NormalizePriceTiers validates quantity breaks and returns them sorted by quantity
+func NormalizePriceTiers(tiers []PriceTier) ([]PriceTier, error) {
+	if len(tiers) == 0 {
+		return nil, nil
+	}
+
+	sorted := make([]PriceTier, len(tiers))
+	copy(sorted, tiers)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].MinQuantity < sorted[j].MinQuantity
+	})
+
+	for i, tier := range sorted {
+		if tier.MinQuantity < 1 {
+			return nil, errors.New("tier minimum quantity must be at least 1")
+		}
+		if tier.Price < 0 {
+			return nil, errors.New("tier price cannot be negative")
+		}
+		if i > 0 && sorted[i-1].MinQuantity == tier.MinQuantity {
+			return nil, fmt.Errorf("duplicate tier for quantity %d", tier.MinQuantity)
+		}
+	}
+
+	return sorted, nil
+}
+
+// FindPriceTier returns the tier that applies to a quantity, or nil when the quantity is
+// below every tier. tiers must be sorted by quantity.
+func FindPriceTier(tiers []PriceTier, quantity int) *PriceTier {
+	var found *PriceTier
+	for i := range tiers {
+		if tiers[i].MinQuantity > quantity {
+			break
+		}
+		found = &tiers[i]
+	}
+	return found
+}
+
+// tieredPrice applies the tier for a quantity to a regular unit price. A tier never
+// raises the price, e.g. above a lower variant price override.
+func tieredPrice(price float64, tiers []PriceTier, quantity int) (float64, *PriceTier) {
+	tier := FindPriceTier(tiers, quantity)
+	if tier == nil || tier.Price > price {
+		return price, nil
+	}
+	return tier.Price, tier
+}
@@ -20,6 +20,7 @@ type Product struct {
 	Price         float64 `json:"price" gorm:"not null"`
 	ComparePrice  float64 `json:"compare_price"`
 	CostPrice     float64 `json:"cost_price"`
+	PriceTiers    []PriceTier `json:"price_tiers,omitempty" gorm:"type:jsonb;serializer:json"` // quantity breaks
 	
 	// Inventory
 	StockQuantity int  `json:"stock_quantity" gorm:"default:0"`
@@ -37,13 +38,19 @@ type Product struct {
 	MetaTitle   string            `json:"meta_title"`
 	MetaDesc    string            `json:"meta_description"`
 	Tags        []string          `json:"tags" gorm:"type:text[]"`
-	Attributes  map[string]string `json:"attributes" gorm:"type:jsonb"`
+	Attributes  map[string]string `json:"attributes" gorm:"type:jsonb;serializer:json"`
 	
 	// Status and visibility
 	Status      ProductStatus `json:"status" gorm:"default:1"`
 	Visibility  Visibility    `json:"visibility" gorm:"default:1"`
 	Featured    bool          `json:"featured" gorm:"default:false"`
 	
+	// Publishing schedule
+	PublishAt      *time.Time `json:"publish_at" gorm:"index"` // when a draft goes live
+	PublishedAt    *time.Time `json:"published_at"`
+	AvailableFrom  *time.Time `json:"available_from"`
+	AvailableUntil *time.Time `json:"available_until" gorm:"index"` // unpublished once passed
+	
 	// Relationships
 	CategoryID  uuid.UUID `json:"category_id" gorm:"type:uuid"`
 	Category    *Category `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
@@ -58,10 +65,30 @@ type Product struct {
 	HasVariants bool             `json:"has_variants" gorm:"default:false"`
 	Variants    []ProductVariant `json:"variants,omitempty" gorm:"foreignKey:ProductID"`
 	
+	// Bundles are built from component products or variants; their stock, and price
+	// when computed, are derived from the components
+	IsBundle            bool          `json:"is_bundle" gorm:"default:false;index"`
+	BundlePricing       BundlePricing `json:"bundle_pricing,omitempty" gorm:"size:20"`
+	BundleDiscountType  DiscountType  `json:"bundle_discount_type,omitempty" gorm:"size:20"`
+	BundleDiscountValue float64       `json:"bundle_discount_value,omitempty"`
+	BundleItems         []BundleItem  `json:"bundle_items,omitempty" gorm:"foreignKey:BundleID;constraint:OnDelete:CASCADE"`
+	
 	// Reviews and ratings
 	ReviewCount   int     `json:"review_count" gorm:"default:0"`
 	AverageRating float64 `json:"average_rating" gorm:"default:0"`
 	
+	// ResolvedPrice is filled in on read for a requested currency and customer group
+	ResolvedPrice *ResolvedPrice `json:"resolved_price,omitempty" gorm:"-"`
+	
+	// Sale pricing is filled in on read from the sales running at the time
+	IsOnSale           bool       `json:"is_on_sale" gorm:"-"`
+	SalePrice          float64    `json:"sale_price,omitempty" gorm:"-"`
+	SaleEndsAt         *time.Time `json:"sale_ends_at,omitempty" gorm:"-"`
+	DiscountPercentage float64    `json:"discount_percentage" gorm:"-"`
+	
+	// Version is incremented by every write, for optimistic concurrency control
+	Version int `json:"version" gorm:"not null;default:1"`
+	
 	// Timestamps
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at"`
@@ -117,6 +144,7 @@ func NewProduct(sku, name, description string, price float64, categoryID uuid.UU
 		Status:      ProductStatusDraft,
 		Visibility:  VisibilityHidden,
 		TrackStock:  true,
+		Version:     1,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
@@ -206,10 +234,50 @@ func (p *Product) UpdateSEO(metaTitle, metaDesc string, tags []string) {
 	p.UpdatedAt = time.Now()
 }
 
-// SetStatus updates product status
+// SetStatus updates product status. Activating a product records when it was first
+// published and consumes any pending publish schedule.
 func (p *Product) SetStatus(status ProductStatus) {
+	now := time.Now()
+	if status == ProductStatusActive {
+		if p.PublishedAt == nil {
+			p.PublishedAt = &now
+		}
+		p.PublishAt = nil
+	}
+	
 	p.Status = status
+	p.UpdatedAt = now
+}
+
+// SetSchedule sets when a draft is published and the window in which the product can
+// be bought; nil clears a date
+func (p *Product) SetSchedule(publishAt, availableFrom, availableUntil *time.Time) error {
+	if availableFrom != nil && availableUntil != nil && !availableFrom.Before(*availableUntil) {
+		return errors.New("available from must be before available until")
+	}
+	if publishAt != nil && availableUntil != nil && !publishAt.Before(*availableUntil) {
+		return errors.New("publish time must be before available until")
+	}
+	
+	p.PublishAt = publishAt
+	p.AvailableFrom = availableFrom
+	p.AvailableUntil = availableUntil
 	p.UpdatedAt = time.Now()
+	
+	return nil
+}
+
+// InAvailabilityWindow checks if at falls within [AvailableFrom, AvailableUntil)
+func (p *Product) InAvailabilityWindow(at time.Time) bool {
+	if p.AvailableFrom != nil && at.Before(*p.AvailableFrom) {
+		return false
+	}
+	
+	if p.AvailableUntil != nil && !at.Before(*p.AvailableUntil) {
+		return false
+	}
+	
+	return true
 }
 
 // SetVisibility updates product visibility
@@ -260,13 +328,23 @@ func (p *Product) RemoveAttribute(key string) {
 	}
 }
 
+// IsListed checks if product is active and visible, regardless of stock. Products
+// with variants are purchasable when listed and one of their variants has stock.
+func (p *Product) IsListed() bool {
+	if p.Status != ProductStatusActive {
+		return false
+	}
+	
+	return p.Visibility != VisibilityHidden
+}
+
 // IsAvailable checks if product is available for purchase
 func (p *Product) IsAvailable() bool {
-	if p.Status != ProductStatusActive {
+	if !p.IsListed() {
 		return false
 	}
 	
-	if p.Visibility == VisibilityHidden {
+	if !p.InAvailabilityWindow(time.Now()) {
 		return false
 	}
 	
@@ -352,13 +430,57 @@ func (p *Product) UpdateRating(averageRating float64, reviewCount int) error {
 	return nil
 }
 
-// GetDiscountPercentage calculates discount percentage
+// SetPriceTiers replaces the product's quantity breaks
+func (p *Product) SetPriceTiers(tiers []PriceTier) error {
+	normalized, err := NormalizePriceTiers(tiers)
+	if err != nil {
+		return err
+	}
+	
+	p.PriceTiers = normalized
+	p.UpdatedAt = time.Now()
+	
+	return nil
+}
+
+// GetUnitPrice returns the unit price when buying quantity units, and the tier that
+// applied, if any
+func (p *Product) GetUnitPrice(quantity int) (float64, *PriceTier) {
+	return tieredPrice(p.Price, p.PriceTiers, quantity)
+}
+
+// SetSalePrice marks the product as on sale at salePrice until endsAt
+func (p *Product) SetSalePrice(salePrice float64, endsAt time.Time) {
+	p.IsOnSale = salePrice < p.Price
+	p.SalePrice = salePrice
+	p.SaleEndsAt = &endsAt
+	p.DiscountPercentage = p.GetDiscountPercentage()
+}
+
+// GetFinalPrice returns the price the product sells for: the sale price while on sale,
+// otherwise the regular price
+func (p *Product) GetFinalPrice() float64 {
+	if p.IsOnSale {
+		return p.SalePrice
+	}
+	
+	return p.Price
+}
+
+// GetDiscountPercentage calculates the discount of the final price off the compare
+// price, or off the regular price when there is no higher compare price
 func (p *Product) GetDiscountPercentage() float64 {
-	if p.ComparePrice <= 0 || p.Price >= p.ComparePrice {
+	reference := p.Price
+	if p.ComparePrice > reference {
+		reference = p.ComparePrice
+	}
+	
+	final := p.GetFinalPrice()
+	if reference <= 0 || final >= reference {
 		return 0
 	}
 	
-	return ((p.ComparePrice - p.Price) / p.ComparePrice) * 100
+	return ((reference - final) / reference) * 100
 }
 
 // GetProfitMargin calculates profit margin
@@ -0,0 +1,86 @@
+package entities
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RelationType is the kind of link between two products
+type RelationType string
+
+const (
+	RelationTypeRelated   RelationType = "related"
+	RelationTypeUpSell    RelationType = "up_sell"    // a better, pricier alternative
+	RelationTypeCrossSell RelationType = "cross_sell" // something to buy alongside
+	RelationTypeAccessory RelationType = "accessory"
+
+	// RecommendationFrequentlyBoughtTogether is computed from orders rather than
+	// managed by hand
+	RecommendationFrequentlyBoughtTogether RelationType = "frequently_bought_together"
+)
+
+// ProductRelation is a hand-picked link from a product to another, shown in the order
+// of SortOrder among the relations of its type
+type ProductRelation struct {
+	ID               uuid.UUID    `json:"id" gorm:"type:uuid;primary_key"`
+	ProductID        uuid.UUID    `json:"product_id" gorm:"type:uuid;not null;uniqueIndex:idx_product_relations_link"`
+	Product          *Product     `json:"-" gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE"`
+	RelatedProductID uuid.UUID    `json:"related_product_id" gorm:"type:uuid;not null;uniqueIndex:idx_product_relations_link"`
+	RelatedProduct   *Product     `json:"related_product,omitempty" gorm:"foreignKey:RelatedProductID;constraint:OnDelete:CASCADE"`
+	Type             RelationType `json:"type" gorm:"size:20;not null;uniqueIndex:idx_product_relations_link"`
+	SortOrder        int          `json:"sort_order" gorm:"default:0"`
+	CreatedAt        time.Time    `json:"created_at"`
+	CreatedBy        string       `json:"created_by"`
+}
+
+// NewProductRelation creates a link from a product to another
+func NewProductRelation(productID, relatedProductID uuid.UUID, relationType RelationType, sortOrder int) (*ProductRelation, error) {
+	if !IsValidRelationType(relationType) {
+		return nil, errors.New("relation type must be related, up_sell, cross_sell or accessory")
+	}
+	if relatedProductID == uuid.Nil {
+		return nil, errors.New("related product ID is required")
+	}
+	if relatedProductID == productID {
+		return nil, errors.New("a product cannot be related to itself")
+	}
+
+	return &ProductRelation{
+		ID:               uuid.New(),
+		ProductID:        productID,
+		RelatedProductID: relatedProductID,
+		Type:             relationType,
+		SortOrder:        sortOrder,
+		CreatedAt:        time.Now(),
+	}, nil
+}
+
+// IsValidRelationType checks if a relation type can be managed by hand
+func IsValidRelationType(relationType RelationType) bool {
+	switch relationType {
+	case RelationTypeRelated, RelationTypeUpSell, RelationTypeCrossSell, RelationTypeAccessory:
+		return true
+	}
+	return false
+}
+
+// ProductCoOccurrence counts the orders in which two products were bought together.
+// Each pair is stored in both directions so either product can look up the other.
+type ProductCoOccurrence struct {
+	ProductID      uuid.UUID `json:"product_id" gorm:"type:uuid;primaryKey;index:idx_co_occurrences_rank,priority:1"`
+	OtherProductID uuid.UUID `json:"other_product_id" gorm:"type:uuid;primaryKey"`
+	Count          int64     `json:"count" gorm:"not null;default:0;index:idx_co_occurrences_rank,priority:2,sort:desc"`
+	LastOrderedAt  time.Time `json:"last_ordered_at"`
+}
+
+// IngestedOrder records an order whose lines have been counted, so an order event
+// delivered twice is counted once. SalesCountedAt is set once the order's sales are
+// added to the product stats.
+type IngestedOrder struct {
+	OrderID        string     `json:"order_id" gorm:"primaryKey;size:100"`
+	ItemCount      int        `json:"item_count"`
+	IngestedAt     time.Time  `json:"ingested_at"`
+	SalesCountedAt *time.Time `json:"sales_counted_at"`
+}
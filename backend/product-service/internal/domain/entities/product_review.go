@@ -0,0 +1,170 @@
+package entities
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReviewStatus represents the moderation status of a review
+type ReviewStatus string
+
+const (
+	ReviewStatusPending  ReviewStatus = "pending"
+	ReviewStatusApproved ReviewStatus = "approved"
+	ReviewStatusRejected ReviewStatus = "rejected"
+	ReviewStatusSpam     ReviewStatus = "spam"
+)
+
+const (
+	maxReviewTitleLength   = 255
+	maxReviewContentLength = 10000
+)
+
+// ProductReview is a shopper's rating and review of a product. Only approved reviews
+// are shown to shoppers and count towards the product's rating.
+type ProductReview struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	ProductID uuid.UUID `json:"product_id" gorm:"type:uuid;not null;uniqueIndex:idx_product_reviews_author,priority:1;index:idx_product_reviews_listing,priority:1"`
+	Product   *Product  `json:"-" gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_product_reviews_author,priority:2"`
+
+	// Review content
+	Rating        int    `json:"rating" gorm:"not null;check:rating >= 1 AND rating <= 5"`
+	Title         string `json:"title" gorm:"size:255"`
+	Content       string `json:"content" gorm:"type:text"`
+	IsRecommended bool   `json:"is_recommended" gorm:"default:false"`
+
+	// IsVerified is set when the author bought the product
+	IsVerified bool `json:"is_verified" gorm:"default:false"`
+
+	// Moderation
+	Status         ReviewStatus `json:"status" gorm:"size:20;not null;default:'pending';index:idx_product_reviews_listing,priority:2"`
+	SpamScore      int          `json:"spam_score" gorm:"default:0"`
+	SpamReasons    []string     `json:"spam_reasons,omitempty" gorm:"type:text[]"`
+	ModerationNote string       `json:"moderation_note,omitempty" gorm:"size:500"`
+	ModeratedBy    string       `json:"moderated_by,omitempty" gorm:"size:100"`
+	ModeratedAt    *time.Time   `json:"moderated_at,omitempty"`
+
+	// Helpful votes, counted from ReviewVote
+	HelpfulCount   int `json:"helpful_count" gorm:"default:0"`
+	UnhelpfulCount int `json:"unhelpful_count" gorm:"default:0"`
+
+	// Where the review was submitted from, for spam checks; never shown
+	UserAgent string `json:"-" gorm:"size:500"`
+	IPAddress string `json:"-" gorm:"size:45;index"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ReviewVote is a shopper's vote on whether a review was helpful; each shopper has one
+// vote per review, which they can change
+type ReviewVote struct {
+	ReviewID  uuid.UUID      `json:"review_id" gorm:"type:uuid;primaryKey"`
+	Review    *ProductReview `json:"-" gorm:"foreignKey:ReviewID;constraint:OnDelete:CASCADE"`
+	UserID    uuid.UUID      `json:"user_id" gorm:"type:uuid;primaryKey"`
+	Helpful   bool           `json:"helpful"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// ProductPurchase records that a user bought a product, for verified-purchase reviews
+type ProductPurchase struct {
+	UserID      uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey"`
+	ProductID   uuid.UUID `json:"product_id" gorm:"type:uuid;primaryKey"`
+	OrderID     string    `json:"order_id" gorm:"size:100"`
+	PurchasedAt time.Time `json:"purchased_at"`
+}
+
+// NewProductReview creates a new pending review
+func NewProductReview(productID, userID uuid.UUID, rating int, title, content string, recommended bool) (*ProductReview, error) {
+	if userID == uuid.Nil {
+		return nil, errors.New("user ID is required")
+	}
+
+	review := &ProductReview{
+		ID:            uuid.New(),
+		ProductID:     productID,
+		UserID:        userID,
+		Rating:        rating,
+		Title:         strings.TrimSpace(title),
+		Content:       strings.TrimSpace(content),
+		IsRecommended: recommended,
+		Status:        ReviewStatusPending,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+
+	if err := review.Validate(); err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
+
+// Validate validates the review content
+func (r *ProductReview) Validate() error {
+	if r.Rating < 1 || r.Rating > 5 {
+		return errors.New("rating must be between 1 and 5")
+	}
+
+	if len(r.Title) > maxReviewTitleLength {
+		return errors.New("review title cannot exceed 255 characters")
+	}
+
+	if len(r.Content) > maxReviewContentLength {
+		return errors.New("review content cannot exceed 10000 characters")
+	}
+
+	if r.Title == "" && r.Content == "" {
+		return errors.New("review title or content is required")
+	}
+
+	return nil
+}
+
+// FlagSpam marks the review as suspected spam for the given reasons; a moderator can
+// still approve it
+func (r *ProductReview) FlagSpam(score int, reasons []string) {
+	r.SpamScore = score
+	r.SpamReasons = reasons
+	r.Status = ReviewStatusSpam
+	r.UpdatedAt = time.Now()
+}
+
+// Moderate sets the review's status as decided by a moderator
+func (r *ProductReview) Moderate(status ReviewStatus, note, moderator string) error {
+	if !IsValidReviewStatus(status) {
+		return errors.New("review status must be pending, approved, rejected or spam")
+	}
+
+	if len(note) > 500 {
+		return errors.New("moderation note cannot exceed 500 characters")
+	}
+
+	now := time.Now()
+	r.Status = status
+	r.ModerationNote = note
+	r.ModeratedBy = moderator
+	r.ModeratedAt = &now
+	r.UpdatedAt = now
+
+	return nil
+}
+
+// IsApproved checks if the review is shown to shoppers
+func (r *ProductReview) IsApproved() bool {
+	return r.Status == ReviewStatusApproved
+}
+
+// IsValidReviewStatus checks if a review status is valid
+func IsValidReviewStatus(status ReviewStatus) bool {
+	switch status {
+	case ReviewStatusPending, ReviewStatusApproved, ReviewStatusRejected, ReviewStatusSpam:
+		return true
+	}
+	return false
+}
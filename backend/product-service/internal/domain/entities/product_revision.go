@@ -0,0 +1,172 @@
+package entities
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RevisionEntity identifies what kind of record a revision captures
+type RevisionEntity string
+
+const (
+	RevisionEntityProduct RevisionEntity = "product"
+	RevisionEntityVariant RevisionEntity = "variant"
+	RevisionEntityImage   RevisionEntity = "image"
+	RevisionEntityVideo   RevisionEntity = "video"
+)
+
+// RevisionAction describes the change a revision records
+type RevisionAction string
+
+const (
+	RevisionActionCreate   RevisionAction = "create"
+	RevisionActionUpdate   RevisionAction = "update"
+	RevisionActionDelete   RevisionAction = "delete"
+	RevisionActionRollback RevisionAction = "rollback"
+)
+
+// revisionIgnoredFields are left out of snapshots: identity, bookkeeping, relations and
+// values derived from other records, none of which a rollback should restore
+var revisionIgnoredFields = map[string]bool{
+	"id":                  true,
+	"product_id":          true,
+	"variant_id":          true,
+	"created_at":          true,
+	"updated_at":          true,
+	"deleted_at":          true,
+	"created_by":          true,
+	"updated_by":          true,
+	"version":             true,
+	"product":             true,
+	"category":            true,
+	"brand":               true,
+	"images":              true,
+	"videos":              true,
+	"variants":            true,
+	"bundle_items":        true,
+	"has_variants":        true,
+	"review_count":        true,
+	"average_rating":      true,
+	"resolved_price":      true,
+	"is_on_sale":          true,
+	"sale_price":          true,
+	"sale_ends_at":        true,
+	"discount_percentage": true,
+}
+
+// FieldChange is one field that differs between two states
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// ProductRevision is an immutable record of one change to a product, or to one of its
+// variants, images or videos. Revisions are numbered per product, in order.
+type ProductRevision struct {
+	ID         uuid.UUID      `json:"id" gorm:"type:uuid;primary_key"`
+	ProductID  uuid.UUID      `json:"product_id" gorm:"type:uuid;not null;uniqueIndex:idx_product_revisions_number"`
+	Number     int            `json:"number" gorm:"not null;uniqueIndex:idx_product_revisions_number"`
+	EntityType RevisionEntity `json:"entity_type" gorm:"not null"`
+	EntityID   uuid.UUID      `json:"entity_id" gorm:"type:uuid;not null;index"`
+	Action     RevisionAction `json:"action" gorm:"not null"`
+	Actor      string         `json:"actor" gorm:"index"`
+
+	// Changes lists the fields this revision changed; Snapshot is the full state after
+	// the change (empty for deletions) and is what a rollback restores
+	Changes  []FieldChange          `json:"changes" gorm:"type:jsonb;serializer:json"`
+	Snapshot map[string]interface{} `json:"snapshot" gorm:"type:jsonb;serializer:json"`
+
+	// RolledBackTo is the revision number a rollback restored
+	RolledBackTo *int `json:"rolled_back_to,omitempty"`
+
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+}
+
+// NewProductRevision records a change of an entity from before to after; either may be
+// nil for creations and deletions. Returns nil when nothing changed.
+func NewProductRevision(productID uuid.UUID, entityType RevisionEntity, entityID uuid.UUID, action RevisionAction, actor string, before, after interface{}) *ProductRevision {
+	from := TakeSnapshot(before)
+	to := TakeSnapshot(after)
+
+	changes := DiffSnapshots(from, to)
+	if len(changes) == 0 && action == RevisionActionUpdate {
+		return nil
+	}
+
+	return &ProductRevision{
+		ID:         uuid.New(),
+		ProductID:  productID,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		Actor:      actor,
+		Changes:    changes,
+		Snapshot:   to,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// Restore copies the revision's snapshot into target, which should be a fresh value of
+// the revision's entity type
+func (r *ProductRevision) Restore(target interface{}) error {
+	data, err := json.Marshal(r.Snapshot)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+// TakeSnapshot captures the state of an entity as its JSON fields, without the fields
+// revisions ignore. A nil entity has an empty snapshot.
+func TakeSnapshot(entity interface{}) map[string]interface{} {
+	snapshot := make(map[string]interface{})
+	if entity == nil {
+		return snapshot
+	}
+	if value := reflect.ValueOf(entity); value.Kind() == reflect.Ptr && value.IsNil() {
+		return snapshot
+	}
+
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return snapshot
+	}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return snapshot
+	}
+
+	for field := range snapshot {
+		if revisionIgnoredFields[field] {
+			delete(snapshot, field)
+		}
+	}
+
+	return snapshot
+}
+
+// DiffSnapshots lists the fields that differ between two snapshots, sorted by field
+func DiffSnapshots(from, to map[string]interface{}) []FieldChange {
+	changes := []FieldChange{}
+
+	for field, value := range to {
+		if previous, ok := from[field]; !ok || !reflect.DeepEqual(previous, value) {
+			changes = append(changes, FieldChange{Field: field, From: from[field], To: value})
+		}
+	}
+	for field, value := range from {
+		if _, ok := to[field]; !ok {
+			changes = append(changes, FieldChange{Field: field, From: value})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+
+	return changes
+}
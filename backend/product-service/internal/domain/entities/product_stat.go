@@ -0,0 +1,38 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Trending weighs recent activity over old: a day's views and sales count half as much
+// every TrendingHalfLifeDays, and a unit sold counts as much as TrendingSaleWeight views
+const (
+	TrendingHalfLifeDays = 3
+	TrendingSaleWeight   = 20
+)
+
+// ProductStat counts the views and sales of a product in one day (UTC), the bucket
+// rankings over a number of days are summed from
+type ProductStat struct {
+	ProductID uuid.UUID `json:"product_id" gorm:"type:uuid;primaryKey"`
+	Bucket    time.Time `json:"bucket" gorm:"primaryKey;index"`
+	Views     int64     `json:"views" gorm:"not null;default:0"`
+	UnitsSold int64     `json:"units_sold" gorm:"not null;default:0"`
+	Orders    int64     `json:"orders" gorm:"not null;default:0"`
+}
+
+// StatBucket returns the start of the bucket a moment is counted in
+func StatBucket(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// StatWindowStart returns the first bucket of a window of days ending with today's
+func StatWindowStart(now time.Time, days int) time.Time {
+	if days < 1 {
+		days = 1
+	}
+	return StatBucket(now).AddDate(0, 0, -(days - 1))
+}
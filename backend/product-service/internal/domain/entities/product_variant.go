@@ -16,15 +16,16 @@ type ProductVariant struct {
 	
 	// Variant identification
 	SKU     string `json:"sku" gorm:"uniqueIndex;not null"`
-	Barcode string `json:"barcode" gorm:"uniqueIndex"`
+	Barcode string `json:"barcode" gorm:"uniqueIndex:idx_product_variants_barcode,where:barcode <> ''"`
 	
 	// Variant attributes (e.g., Size: "L", Color: "Red")
-	Attributes map[string]string `json:"attributes" gorm:"type:jsonb"`
+	Attributes map[string]string `json:"attributes" gorm:"type:jsonb;serializer:json"`
 	
 	// Pricing (can override product pricing)
 	Price        *float64 `json:"price"`         // If nil, uses product price
 	ComparePrice *float64 `json:"compare_price"` // If nil, uses product compare price
 	CostPrice    *float64 `json:"cost_price"`    // If nil, uses product cost price
+	PriceTiers   []PriceTier `json:"price_tiers,omitempty" gorm:"type:jsonb;serializer:json"` // If empty, see GetEffectivePriceTiers
 	
 	// Inventory
 	StockQuantity  int  `json:"stock_quantity" gorm:"default:0"`
@@ -47,6 +48,10 @@ type ProductVariant struct {
 	// Display
 	SortOrder int `json:"sort_order" gorm:"default:0"`
 	
+	// Sale pricing is filled in on read from the sales running at the time
+	IsOnSale  bool    `json:"is_on_sale" gorm:"-"`
+	SalePrice float64 `json:"sale_price,omitempty" gorm:"-"`
+	
 	// Timestamps
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at"`
@@ -187,6 +192,38 @@ func (v *ProductVariant) GetEffectivePrice(productPrice float64) float64 {
 	return productPrice
 }
 
+// SetPriceTiers replaces the variant's quantity breaks
+func (v *ProductVariant) SetPriceTiers(tiers []PriceTier) error {
+	normalized, err := NormalizePriceTiers(tiers)
+	if err != nil {
+		return err
+	}
+	
+	v.PriceTiers = normalized
+	v.UpdatedAt = time.Now()
+	
+	return nil
+}
+
+// GetEffectivePriceTiers returns the quantity breaks of the variant: its own, or the
+// product's when it has none and uses the product price. Product tiers are not applied
+// to a variant with its own price, as they are priced against the product price.
+func (v *ProductVariant) GetEffectivePriceTiers(productTiers []PriceTier) []PriceTier {
+	if len(v.PriceTiers) > 0 {
+		return v.PriceTiers
+	}
+	if v.Price != nil {
+		return nil
+	}
+	return productTiers
+}
+
+// GetEffectiveUnitPrice returns the unit price when buying quantity units of the
+// variant, and the tier that applied, if any
+func (v *ProductVariant) GetEffectiveUnitPrice(productPrice float64, productTiers []PriceTier, quantity int) (float64, *PriceTier) {
+	return tieredPrice(v.GetEffectivePrice(productPrice), v.GetEffectivePriceTiers(productTiers), quantity)
+}
+
 // GetEffectiveComparePrice returns the effective compare price
 func (v *ProductVariant) GetEffectiveComparePrice(productComparePrice float64) float64 {
 	if v.ComparePrice != nil {
@@ -328,6 +365,11 @@ type ProductImage struct {
 	FileSize int64  `json:"file_size"`
 	MimeType string `json:"mime_type"`
 	
+	// Storage
+	StorageKey  string           `json:"storage_key"`                      // key of the original in file storage
+	ContentHash string           `json:"content_hash" gorm:"size:64;index"` // SHA-256 of the uploaded file
+	Renditions  []ImageRendition `json:"renditions,omitempty" gorm:"type:jsonb;serializer:json"`
+	
 	// Display
 	SortOrder int  `json:"sort_order" gorm:"default:0"`
 	IsPrimary bool `json:"is_primary" gorm:"default:false"`
@@ -340,6 +382,14 @@ type ProductImage struct {
 	UpdatedBy string     `json:"updated_by"`
 }
 
+// ImageRendition is a resized copy of an image, such as a thumbnail
+type ImageRendition struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
 // NewProductImage creates a new product image
 func NewProductImage(url, altText string, productID *uuid.UUID, variantID *uuid.UUID) (*ProductImage, error) {
 	if strings.TrimSpace(url) == "" {
@@ -387,6 +437,14 @@ func (i *ProductImage) UpdateProperties(width, height int, fileSize int64, mimeT
 	i.UpdatedAt = time.Now()
 }
 
+// SetStorage records where the image is stored, its content hash and its renditions
+func (i *ProductImage) SetStorage(storageKey, contentHash string, renditions []ImageRendition) {
+	i.StorageKey = storageKey
+	i.ContentHash = contentHash
+	i.Renditions = renditions
+	i.UpdatedAt = time.Now()
+}
+
 // SetPrimary sets image as primary
 func (i *ProductImage) SetPrimary(isPrimary bool) {
 	i.IsPrimary = isPrimary
@@ -419,6 +477,11 @@ type ProductVideo struct {
 	// Video type
 	Type VideoType `json:"type" gorm:"default:1"` // YouTube, Vimeo, Direct, etc.
 	
+	// Source
+	ExternalID string `json:"external_id,omitempty" gorm:"size:64"` // video ID on YouTube or Vimeo
+	EmbedURL   string `json:"embed_url,omitempty"`
+	StorageKey string `json:"storage_key,omitempty"` // key of an uploaded video in file storage
+	
 	// Display
 	SortOrder int `json:"sort_order" gorm:"default:0"`
 	
@@ -440,44 +503,89 @@ const (
 	VideoTypeOther
 )
 
-// NewProductVideo creates a new product video
-func NewProductVideo(productID uuid.UUID, url, title string, videoType VideoType) (*ProductVideo, error) {
+// NewProductVideo creates a new product video from a URL. YouTube and Vimeo URLs are
+// recognised and normalised; other URLs are kept as they are.
+func NewProductVideo(productID uuid.UUID, url, title string) (*ProductVideo, error) {
 	if productID == uuid.Nil {
 		return nil, errors.New("product ID is required")
 	}
 	
-	if strings.TrimSpace(url) == "" {
-		return nil, errors.New("video URL is required")
-	}
-	
 	video := &ProductVideo{
 		ID:        uuid.New(),
 		ProductID: productID,
-		URL:       strings.TrimSpace(url),
 		Title:     strings.TrimSpace(title),
-		Type:      videoType,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
+	if err := video.setSource(url); err != nil {
+		return nil, err
+	}
 	
 	return video, nil
 }
 
-// UpdateInfo updates video information
-func (v *ProductVideo) UpdateInfo(url, title, description, thumbnailURL string) error {
+// NewUploadedProductVideo creates a product video for a file in storage
+func NewUploadedProductVideo(productID uuid.UUID, url, storageKey, title string) (*ProductVideo, error) {
+	if productID == uuid.Nil {
+		return nil, errors.New("product ID is required")
+	}
+	
 	if strings.TrimSpace(url) == "" {
-		return errors.New("video URL is required")
+		return nil, errors.New("video URL is required")
+	}
+	
+	video := &ProductVideo{
+		ID:         uuid.New(),
+		ProductID:  productID,
+		URL:        strings.TrimSpace(url),
+		Title:      strings.TrimSpace(title),
+		Type:       VideoTypeDirect,
+		StorageKey: storageKey,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	
+	return video, nil
+}
+
+// UpdateInfo updates video information. A thumbnail URL, if given, replaces the one
+// derived from the video URL.
+func (v *ProductVideo) UpdateInfo(url, title, description, thumbnailURL string) error {
+	if v.StorageKey == "" {
+		if err := v.setSource(url); err != nil {
+			return err
+		}
 	}
 	
-	v.URL = strings.TrimSpace(url)
 	v.Title = strings.TrimSpace(title)
 	v.Description = strings.TrimSpace(description)
-	v.ThumbnailURL = strings.TrimSpace(thumbnailURL)
+	if thumbnailURL = strings.TrimSpace(thumbnailURL); thumbnailURL != "" {
+		v.ThumbnailURL = thumbnailURL
+	}
 	v.UpdatedAt = time.Now()
 	
 	return nil
 }
 
+// setSource parses a video URL and sets the type, IDs and URLs derived from it
+func (v *ProductVideo) setSource(rawURL string) error {
+	source, err := ParseVideoURL(rawURL)
+	if err != nil {
+		return err
+	}
+	
+	v.URL = source.URL
+	v.Type = source.Type
+	v.ExternalID = source.ExternalID
+	v.EmbedURL = source.EmbedURL
+	v.ThumbnailURL = source.ThumbnailURL
+	if source.MimeType != "" {
+		v.MimeType = source.MimeType
+	}
+	
+	return nil
+}
+
 // UpdateProperties updates video properties
 func (v *ProductVideo) UpdateProperties(duration int, fileSize int64, mimeType string) {
 	v.Duration = duration
@@ -0,0 +1,184 @@
+package entities
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SaleScope is what a sale applies to
+type SaleScope string
+
+const (
+	SaleScopeProduct  SaleScope = "product"  // one product, or one of its variants
+	SaleScopeCategory SaleScope = "category" // every product in a category and its subcategories
+	SaleScopeBrand    SaleScope = "brand"    // every product of a brand
+)
+
+// DiscountType is how a sale lowers a price
+type DiscountType string
+
+const (
+	DiscountTypePercent DiscountType = "percent" // a percentage off
+	DiscountTypeFixed   DiscountType = "fixed"   // a fixed amount off, in the base currency
+)
+
+// Sale is a time-boxed discount on a product, a variant, a category or a brand. Sales
+// are evaluated when products are read, so a sale starts and ends on its own.
+type Sale struct {
+	ID            uuid.UUID    `json:"id" gorm:"type:uuid;primary_key"`
+	Name          string       `json:"name" gorm:"not null"`
+	Scope         SaleScope    `json:"scope" gorm:"size:20;not null;index:idx_sales_target"`
+	TargetID      uuid.UUID    `json:"target_id" gorm:"type:uuid;not null;index:idx_sales_target"` // product, category or brand ID
+	VariantID     *uuid.UUID   `json:"variant_id,omitempty" gorm:"type:uuid"`                      // product sales only
+	DiscountType  DiscountType `json:"discount_type" gorm:"size:20;not null"`
+	DiscountValue float64      `json:"discount_value" gorm:"not null"`
+	StartsAt      time.Time    `json:"starts_at" gorm:"not null;index"`
+	EndsAt        time.Time    `json:"ends_at" gorm:"not null;index"`
+	IsActive      bool         `json:"is_active" gorm:"default:true"`
+
+	// Timestamps
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	CreatedBy string    `json:"created_by"`
+	UpdatedBy string    `json:"updated_by"`
+}
+
+// NewSale creates a new, active sale
+func NewSale(name string, scope SaleScope, targetID uuid.UUID, variantID *uuid.UUID) (*Sale, error) {
+	sale := &Sale{
+		ID:        uuid.New(),
+		IsActive:  true,
+		CreatedAt: time.Now(),
+	}
+	if err := sale.Rename(name); err != nil {
+		return nil, err
+	}
+	if err := sale.SetTarget(scope, targetID, variantID); err != nil {
+		return nil, err
+	}
+	return sale, nil
+}
+
+// SetTarget sets what the sale applies to
+func (s *Sale) SetTarget(scope SaleScope, targetID uuid.UUID, variantID *uuid.UUID) error {
+	if !IsValidSaleScope(scope) {
+		return errors.New("scope must be product, category or brand")
+	}
+	if targetID == uuid.Nil {
+		return errors.New("target ID is required")
+	}
+	if variantID != nil && scope != SaleScopeProduct {
+		return errors.New("only product sales can target a variant")
+	}
+
+	s.Scope = scope
+	s.TargetID = targetID
+	s.VariantID = variantID
+	s.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// Rename changes the name of a sale
+func (s *Sale) Rename(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return errors.New("sale name is required")
+	}
+
+	s.Name = strings.TrimSpace(name)
+	s.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// SetDiscount sets the discount and when the sale runs
+func (s *Sale) SetDiscount(discountType DiscountType, value float64, startsAt, endsAt time.Time) error {
+	switch discountType {
+	case DiscountTypePercent:
+		if value <= 0 || value >= 100 {
+			return errors.New("percentage discount must be between 0 and 100")
+		}
+	case DiscountTypeFixed:
+		if value <= 0 {
+			return errors.New("fixed discount must be positive")
+		}
+	default:
+		return errors.New("discount type must be percent or fixed")
+	}
+
+	if startsAt.IsZero() || endsAt.IsZero() {
+		return errors.New("sale start and end times are required")
+	}
+	if !startsAt.Before(endsAt) {
+		return errors.New("sale must start before it ends")
+	}
+
+	s.DiscountType = discountType
+	s.DiscountValue = value
+	s.StartsAt = startsAt
+	s.EndsAt = endsAt
+	s.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// SetActive sets whether the sale can run
+func (s *Sale) SetActive(active bool) {
+	s.IsActive = active
+	s.UpdatedAt = time.Now()
+}
+
+// IsRunningAt checks if the sale is active and at falls within [StartsAt, EndsAt)
+func (s *Sale) IsRunningAt(at time.Time) bool {
+	return s.IsActive && !at.Before(s.StartsAt) && at.Before(s.EndsAt)
+}
+
+// Apply returns the sale price of a price in a currency. Fixed discounts are in the base
+// currency, so they only apply to base currency prices; ok is false when the sale does
+// not lower the price.
+func (s *Sale) Apply(price float64, currency, baseCurrency string) (salePrice float64, ok bool) {
+	switch s.DiscountType {
+	case DiscountTypePercent:
+		salePrice = price * (1 - s.DiscountValue/100)
+	case DiscountTypeFixed:
+		if currency != baseCurrency {
+			return price, false
+		}
+		salePrice = math.Max(price-s.DiscountValue, 0)
+	default:
+		return price, false
+	}
+
+	salePrice = math.Round(salePrice*100) / 100
+	return salePrice, salePrice < price
+}
+
+// AppliesTo checks if the sale covers a product, or one of its variants when variant is
+// set. ancestorIDs are the product's category and its ancestors.
+func (s *Sale) AppliesTo(product *Product, variant *ProductVariant, ancestorIDs map[uuid.UUID]bool) bool {
+	switch s.Scope {
+	case SaleScopeProduct:
+		if s.TargetID != product.ID {
+			return false
+		}
+		return s.VariantID == nil || (variant != nil && *s.VariantID == variant.ID)
+	case SaleScopeCategory:
+		return ancestorIDs[s.TargetID]
+	case SaleScopeBrand:
+		return product.BrandID != nil && *product.BrandID == s.TargetID
+	}
+	return false
+}
+
+// IsValidSaleScope checks if a sale scope is known
+func IsValidSaleScope(scope SaleScope) bool {
+	switch scope {
+	case SaleScopeProduct, SaleScopeCategory, SaleScopeBrand:
+		return true
+	}
+	return false
+}
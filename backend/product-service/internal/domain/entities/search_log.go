@@ -0,0 +1,48 @@
+package entities
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SearchLog records a single product search for analytics
+type SearchLog struct {
+	ID              uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	Query           string    `json:"query"`
+	NormalizedQuery string    `json:"normalized_query" gorm:"index"`
+	Filters         string    `json:"filters" gorm:"type:jsonb"` // active filters as a JSON object
+	ResultCount     int64     `json:"result_count"`
+	LatencyMs       int64     `json:"latency_ms"`
+
+	// Click-through: the first product opened from the results
+	ClickedProductID *uuid.UUID `json:"clicked_product_id" gorm:"type:uuid"`
+	ClickedPosition  int        `json:"clicked_position"`
+	ClickedAt        *time.Time `json:"clicked_at"`
+
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+}
+
+// NewSearchLog creates a search log entry for an executed search
+func NewSearchLog(query, filters string, resultCount int64, latency time.Duration) *SearchLog {
+	if filters == "" {
+		filters = "{}"
+	}
+
+	return &SearchLog{
+		ID:              uuid.New(),
+		Query:           query,
+		NormalizedQuery: NormalizeSearchQuery(query),
+		Filters:         filters,
+		ResultCount:     resultCount,
+		LatencyMs:       latency.Milliseconds(),
+		CreatedAt:       time.Now(),
+	}
+}
+
+// NormalizeSearchQuery lower-cases a query and collapses whitespace so variants of the
+// same search are counted together
+func NormalizeSearchQuery(query string) string {
+	return strings.ToLower(strings.Join(strings.Fields(query), " "))
+}
@@ -0,0 +1,38 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SlugEntityType represents the kind of entity a slug belongs to
+type SlugEntityType string
+
+const (
+	SlugEntityProduct  SlugEntityType = "product"
+	SlugEntityCategory SlugEntityType = "category"
+	SlugEntityBrand    SlugEntityType = "brand"
+)
+
+// SlugRedirect records a slug an entity used to have, so links to it can be redirected
+// permanently to the entity's current slug. Each old slug points at one entity; when
+// another entity later gives up the same slug, the record moves to that entity.
+type SlugRedirect struct {
+	ID         uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	EntityType SlugEntityType `json:"entity_type" gorm:"size:20;not null;uniqueIndex:idx_slug_redirects_slug,priority:1;index:idx_slug_redirects_entity,priority:1"`
+	Slug       string         `json:"slug" gorm:"size:255;not null;uniqueIndex:idx_slug_redirects_slug,priority:2"`
+	EntityID   uuid.UUID      `json:"entity_id" gorm:"type:uuid;not null;index:idx_slug_redirects_entity,priority:2"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+// NewSlugRedirect creates a redirect from an entity's old slug
+func NewSlugRedirect(entityType SlugEntityType, entityID uuid.UUID, oldSlug string) *SlugRedirect {
+	return &SlugRedirect{
+		ID:         uuid.New(),
+		EntityType: entityType,
+		Slug:       oldSlug,
+		EntityID:   entityID,
+		CreatedAt:  time.Now(),
+	}
+}
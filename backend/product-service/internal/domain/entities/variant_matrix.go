@@ -0,0 +1,261 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MaxVariantCombinations caps the size of a generated variant matrix
+const MaxVariantCombinations = 1000
+
+var skuPlaceholder = regexp.MustCompile(`\{([^{}]+)\}`)
+var skuUnsafeChars = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// VariantOption is one axis of a variant matrix, e.g. Size: S, M, L
+type VariantOption struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// VariantOverride changes the price or weight of every variant with a given option value.
+// Price replaces the product price and PriceAdjustment is added to it; adjustments
+// from several matching overrides add up.
+type VariantOverride struct {
+	Option          string   `json:"option"`
+	Value           string   `json:"value"`
+	Price           *float64 `json:"price,omitempty"`
+	PriceAdjustment *float64 `json:"price_adjustment,omitempty"`
+	Weight          *float64 `json:"weight,omitempty"`
+}
+
+// VariantMatrix describes the variants of a product as the cartesian product of its
+// option axes. SKUPattern may reference {sku} (the product SKU) and {option name}
+// placeholders, e.g. "{sku}-{size}-{color}". An exclusion skips every combination
+// that matches all of its option values.
+type VariantMatrix struct {
+	Options    []VariantOption     `json:"options"`
+	SKUPattern string              `json:"sku_pattern"`
+	Overrides  []VariantOverride   `json:"overrides"`
+	Exclusions []map[string]string `json:"exclusions"`
+}
+
+// Validate checks the matrix is well formed
+func (m *VariantMatrix) Validate() error {
+	if len(m.Options) == 0 {
+		return errors.New("at least one option is required")
+	}
+
+	options := make(map[string]map[string]bool, len(m.Options))
+	combinations := 1
+	for i := range m.Options {
+		option := &m.Options[i]
+		option.Name = strings.TrimSpace(option.Name)
+		if option.Name == "" {
+			return errors.New("option name is required")
+		}
+		if _, ok := options[strings.ToLower(option.Name)]; ok {
+			return fmt.Errorf("duplicate option %q", option.Name)
+		}
+		if len(option.Values) == 0 {
+			return fmt.Errorf("option %q has no values", option.Name)
+		}
+
+		values := make(map[string]bool, len(option.Values))
+		for j, value := range option.Values {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				return fmt.Errorf("option %q has an empty value", option.Name)
+			}
+			if values[value] {
+				return fmt.Errorf("option %q has duplicate value %q", option.Name, value)
+			}
+			values[value] = true
+			option.Values[j] = value
+		}
+		options[strings.ToLower(option.Name)] = values
+
+		combinations *= len(option.Values)
+		if combinations > MaxVariantCombinations {
+			return fmt.Errorf("matrix exceeds %d combinations", MaxVariantCombinations)
+		}
+	}
+
+	for _, override := range m.Overrides {
+		values, ok := options[strings.ToLower(override.Option)]
+		if !ok {
+			return fmt.Errorf("override references unknown option %q", override.Option)
+		}
+		if !values[override.Value] {
+			return fmt.Errorf("override references unknown value %q of option %q", override.Value, override.Option)
+		}
+		if override.Price != nil && *override.Price < 0 {
+			return errors.New("override price cannot be negative")
+		}
+		if override.Weight != nil && *override.Weight < 0 {
+			return errors.New("override weight cannot be negative")
+		}
+	}
+
+	for _, exclusion := range m.Exclusions {
+		if len(exclusion) == 0 {
+			return errors.New("exclusion must name at least one option")
+		}
+		for name, value := range exclusion {
+			values, ok := options[strings.ToLower(name)]
+			if !ok {
+				return fmt.Errorf("exclusion references unknown option %q", name)
+			}
+			if !values[value] {
+				return fmt.Errorf("exclusion references unknown value %q of option %q", value, name)
+			}
+		}
+	}
+
+	for _, match := range skuPlaceholder.FindAllStringSubmatch(m.SKUPattern, -1) {
+		key := strings.ToLower(strings.TrimSpace(match[1]))
+		if _, ok := options[key]; !ok && key != "sku" {
+			return fmt.Errorf("SKU pattern references unknown option %q", match[1])
+		}
+	}
+
+	return nil
+}
+
+// Combinations returns every combination of option values, in axis order, minus the
+// excluded ones
+func (m *VariantMatrix) Combinations() []map[string]string {
+	combinations := []map[string]string{{}}
+	for _, option := range m.Options {
+		next := make([]map[string]string, 0, len(combinations)*len(option.Values))
+		for _, combination := range combinations {
+			for _, value := range option.Values {
+				extended := make(map[string]string, len(combination)+1)
+				for k, v := range combination {
+					extended[k] = v
+				}
+				extended[option.Name] = value
+				next = append(next, extended)
+			}
+		}
+		combinations = next
+	}
+
+	result := make([]map[string]string, 0, len(combinations))
+	for _, combination := range combinations {
+		if !m.IsExcluded(combination) {
+			result = append(result, combination)
+		}
+	}
+	return result
+}
+
+// IsExcluded checks if a combination matches any exclusion
+func (m *VariantMatrix) IsExcluded(combination map[string]string) bool {
+	for _, exclusion := range m.Exclusions {
+		matches := true
+		for name, value := range exclusion {
+			if m.optionValue(combination, name) != value {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// SKU builds the SKU of a combination. Without a pattern the option values are appended
+// to the product SKU in axis order.
+func (m *VariantMatrix) SKU(productSKU string, combination map[string]string) string {
+	pattern := m.SKUPattern
+	if strings.TrimSpace(pattern) == "" {
+		parts := []string{"{sku}"}
+		for _, option := range m.Options {
+			parts = append(parts, "{"+option.Name+"}")
+		}
+		pattern = strings.Join(parts, "-")
+	}
+
+	return skuPlaceholder.ReplaceAllStringFunc(pattern, func(placeholder string) string {
+		key := strings.TrimSpace(placeholder[1 : len(placeholder)-1])
+		if strings.EqualFold(key, "sku") {
+			return productSKU
+		}
+		return skuToken(m.optionValue(combination, key))
+	})
+}
+
+// Price returns the price override for a combination, or nil to use the product price
+func (m *VariantMatrix) Price(productPrice float64, combination map[string]string) *float64 {
+	var price *float64
+	adjustment := 0.0
+	adjusted := false
+	for _, override := range m.matchingOverrides(combination) {
+		if override.Price != nil {
+			value := *override.Price
+			price = &value
+		}
+		if override.PriceAdjustment != nil {
+			adjustment += *override.PriceAdjustment
+			adjusted = true
+		}
+	}
+
+	if !adjusted {
+		return price
+	}
+
+	base := productPrice
+	if price != nil {
+		base = *price
+	}
+	total := base + adjustment
+	if total < 0 {
+		total = 0
+	}
+	return &total
+}
+
+// Weight returns the weight override for a combination, or nil to use the product weight
+func (m *VariantMatrix) Weight(combination map[string]string) *float64 {
+	var weight *float64
+	for _, override := range m.matchingOverrides(combination) {
+		if override.Weight != nil {
+			value := *override.Weight
+			weight = &value
+		}
+	}
+	return weight
+}
+
+func (m *VariantMatrix) matchingOverrides(combination map[string]string) []VariantOverride {
+	var matching []VariantOverride
+	for _, override := range m.Overrides {
+		if m.optionValue(combination, override.Option) == override.Value {
+			matching = append(matching, override)
+		}
+	}
+	return matching
+}
+
+// optionValue looks up an option value by case-insensitive option name
+func (m *VariantMatrix) optionValue(combination map[string]string, name string) string {
+	if value, ok := combination[name]; ok {
+		return value
+	}
+	for key, value := range combination {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
+
+// skuToken turns an option value into an upper-case SKU segment, e.g. "Navy Blue" -> "NAVY-BLUE"
+func skuToken(value string) string {
+	return strings.Trim(skuUnsafeChars.ReplaceAllString(strings.ToUpper(value), "-"), "-")
+}
@@ -0,0 +1,125 @@
+package entities
+
+import (
+	"errors"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// VideoSource is what a video URL resolves to: the provider, the provider's ID for the
+// video, and the canonical, embed and thumbnail URLs derived from it
+type VideoSource struct {
+	Type         VideoType
+	ExternalID   string
+	URL          string
+	EmbedURL     string
+	ThumbnailURL string
+	MimeType     string
+}
+
+var (
+	youTubeIDPattern   = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+	vimeoIDPattern     = regexp.MustCompile(`^[0-9]+$`)
+	vimeoHashPattern   = regexp.MustCompile(`^[0-9a-f]+$`)
+	directVideoFormats = map[string]string{
+		".mp4":  "video/mp4",
+		".m4v":  "video/mp4",
+		".webm": "video/webm",
+		".ogv":  "video/ogg",
+		".ogg":  "video/ogg",
+		".mov":  "video/quicktime",
+	}
+)
+
+// ParseVideoURL recognises YouTube (watch, youtu.be, shorts, embed and live) and Vimeo
+// URLs and normalises them. Embed URLs use YouTube's no-cookie domain and Vimeo's
+// do-not-track flag. Vimeo offers no thumbnail URL derivable from the video ID, so
+// Vimeo sources have none. URLs of video files are direct videos; anything else is
+// kept as it is, as another kind of video.
+func ParseVideoURL(rawURL string) (*VideoSource, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return nil, errors.New("video URL is required")
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, errors.New("video URL must be an absolute http or https URL")
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	host = strings.TrimPrefix(host, "www.")
+	host = strings.TrimPrefix(host, "m.")
+	segments := strings.FieldsFunc(parsed.Path, func(r rune) bool { return r == '/' })
+
+	switch host {
+	case "youtube.com", "music.youtube.com", "youtube-nocookie.com", "youtu.be":
+		return parseYouTubeURL(host, parsed, segments)
+	case "vimeo.com", "player.vimeo.com":
+		return parseVimeoURL(parsed, segments)
+	}
+
+	if mimeType, ok := directVideoFormats[strings.ToLower(path.Ext(parsed.Path))]; ok {
+		return &VideoSource{Type: VideoTypeDirect, URL: parsed.String(), MimeType: mimeType}, nil
+	}
+	return &VideoSource{Type: VideoTypeOther, URL: parsed.String()}, nil
+}
+
+// parseYouTubeURL finds the video ID of a YouTube URL
+func parseYouTubeURL(host string, parsed *url.URL, segments []string) (*VideoSource, error) {
+	var id string
+	switch {
+	case host == "youtu.be" && len(segments) > 0:
+		id = segments[0]
+	case len(segments) == 1 && segments[0] == "watch":
+		id = parsed.Query().Get("v")
+	case len(segments) >= 2 && (segments[0] == "shorts" || segments[0] == "embed" || segments[0] == "live" || segments[0] == "v"):
+		id = segments[1]
+	}
+	if !youTubeIDPattern.MatchString(id) {
+		return nil, errors.New("invalid YouTube video URL")
+	}
+
+	return &VideoSource{
+		Type:         VideoTypeYouTube,
+		ExternalID:   id,
+		URL:          "https://www.youtube.com/watch?v=" + id,
+		EmbedURL:     "https://www.youtube-nocookie.com/embed/" + id,
+		ThumbnailURL: "https://i.ytimg.com/vi/" + id + "/hqdefault.jpg",
+	}, nil
+}
+
+// parseVimeoURL finds the video ID of a Vimeo URL, and the privacy hash of an
+// unlisted video, which is either the path segment after the ID or the h parameter
+func parseVimeoURL(parsed *url.URL, segments []string) (*VideoSource, error) {
+	var id, hash string
+	for i, segment := range segments {
+		if vimeoIDPattern.MatchString(segment) {
+			id = segment
+			if i+1 < len(segments) && vimeoHashPattern.MatchString(segments[i+1]) {
+				hash = segments[i+1]
+			}
+			break
+		}
+	}
+	if id == "" {
+		return nil, errors.New("invalid Vimeo video URL")
+	}
+	if h := parsed.Query().Get("h"); vimeoHashPattern.MatchString(h) {
+		hash = h
+	}
+
+	source := &VideoSource{
+		Type:       VideoTypeVimeo,
+		ExternalID: id,
+		URL:        "https://vimeo.com/" + id,
+		EmbedURL:   "https://player.vimeo.com/video/" + id + "?dnt=1",
+	}
+	if hash != "" {
+		source.URL += "/" + hash
+		source.EmbedURL += "&h=" + hash
+	}
+	return source, nil
+}
@@ -0,0 +1,41 @@
+package events
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// Event types published by product-service
+const (
+	ProductStatusChanged = "product.status_changed"
+)
+
+// Event types consumed by product-service
+const (
+	OrderCreated = "order.created"
+)
+
+// Event is a message announcing a change to other services
+type Event struct {
+	EventType string                 `json:"eventType"`
+	EventID   string                 `json:"eventId"`
+	Timestamp time.Time              `json:"timestamp"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+// NewEvent creates an event with a fresh ID and the current time
+func NewEvent(eventType string, data map[string]interface{}) *Event {
+	now := time.Now()
+	return &Event{
+		EventType: eventType,
+		EventID:   "evt_" + strconv.FormatInt(now.UnixNano(), 10),
+		Timestamp: now,
+		Data:      data,
+	}
+}
+
+// Publisher delivers events to other services
+type Publisher interface {
+	Publish(ctx context.Context, event *Event) error
+}
@@ -313,6 +313,75 @@ func (p *Product) CreatedAt() time.Time { return p.createdAt }
 func (p *Product) UpdatedAt() time.Time { return p.updatedAt }
 func (p *Product) Version() int { return p.version }
 
+func (i ProductInventory) Quantity() int        { return i.quantity }
+func (i ProductInventory) Reserved() int        { return i.reserved }
+func (i ProductInventory) Available() int       { return i.quantity - i.reserved }
+func (i ProductInventory) TrackStock() bool     { return i.trackStock }
+func (i ProductInventory) AllowBackorder() bool { return i.allowBackorder }
+
+func (i ProductInventory) IsInStock() bool {
+	return !i.trackStock || i.allowBackorder || i.Available() > 0
+}
+
+func (s ProductSEO) Title() string       { return s.title }
+func (s ProductSEO) Description() string { return s.description }
+func (s ProductSEO) Keywords() []string  { return s.keywords }
+func (s ProductSEO) Slug() string        { return s.slug }
+
+// ProductData carries persisted product state. RestoreProduct uses it to rebuild an
+// aggregate loaded from storage or an index without re-running creation rules.
+type ProductData struct {
+	ID             ProductID
+	Name           string
+	Description    string
+	SKU            string
+	Price          Money
+	ComparePrice   *Money
+	CategoryID     CategoryID
+	Brand          string
+	Tags           []string
+	Status         ProductStatus
+	IsFeatured     bool
+	Quantity       int
+	Reserved       int
+	LowStock       int
+	TrackStock     bool
+	AllowBackorder bool
+	Slug           string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Version        int
+}
+
+// RestoreProduct rebuilds a product from persisted state
+func RestoreProduct(data ProductData) *Product {
+	return &Product{
+		id:           data.ID,
+		name:         data.Name,
+		description:  data.Description,
+		sku:          data.SKU,
+		price:        data.Price,
+		comparePrice: data.ComparePrice,
+		categoryID:   data.CategoryID,
+		brand:        data.Brand,
+		tags:         data.Tags,
+		status:       data.Status,
+		isActive:     data.Status == ProductStatusActive,
+		isFeatured:   data.IsFeatured,
+		inventory: ProductInventory{
+			quantity:       data.Quantity,
+			reserved:       data.Reserved,
+			lowStock:       data.LowStock,
+			trackStock:     data.TrackStock,
+			allowBackorder: data.AllowBackorder,
+		},
+		seo:       ProductSEO{slug: data.Slug},
+		createdAt: data.CreatedAt,
+		updatedAt: data.UpdatedAt,
+		version:   data.Version,
+	}
+}
+
 // Business rules validation
 func validateProductName(name string) error {
 	if len(name) < 2 {
@@ -2,20 +2,55 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"product-service/internal/domain/entities"
 )
 
+// ErrVersionConflict is returned when a product was changed by someone else since it
+// was read
+var ErrVersionConflict = errors.New("product was modified by another request")
+
+// CatalogConflictError is returned when a catalog chunk could not be applied because
+// some of its products were changed by someone else since the chunk was prepared.
+// Nothing of the chunk is written.
+type CatalogConflictError struct {
+	ProductIDs []uuid.UUID
+}
+
+func (e *CatalogConflictError) Error() string {
+	return fmt.Sprintf("%d products were modified by another request", len(e.ProductIDs))
+}
+
+// Unwrap makes errors.Is match ErrVersionConflict
+func (e *CatalogConflictError) Unwrap() error {
+	return ErrVersionConflict
+}
+
+// TransactionManager runs work in a database transaction. Repository calls made with
+// the context passed to fn take part in the transaction, which commits when fn returns
+// nil and rolls back otherwise.
+type TransactionManager interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+	// AfterCommit runs fn once the transaction ctx carries has committed, or right away
+	// outside of one. fn is dropped if the transaction rolls back.
+	AfterCommit(ctx context.Context, fn func())
+}
+
 // ProductRepository defines the interface for product data access
 type ProductRepository interface {
-	// Basic CRUD operations
+	// Basic CRUD operations. Update and Delete only apply while the stored product is
+	// still at the version the caller read (product.Version for Update) and return
+	// ErrVersionConflict otherwise; Update bumps product.Version on success.
 	Create(ctx context.Context, product *entities.Product) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.Product, error)
 	GetBySKU(ctx context.Context, sku string) (*entities.Product, error)
 	GetBySlug(ctx context.Context, slug string) (*entities.Product, error)
 	Update(ctx context.Context, product *entities.Product) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID, version int) error
 	
 	// Bulk operations
 	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.Product, error)
@@ -29,7 +64,7 @@ type ProductRepository interface {
 	GetFeatured(ctx context.Context, limit, offset int) ([]*entities.Product, error)
 	
 	// Search and filtering
-	Search(ctx context.Context, query string, filters *ProductFilters, limit, offset int) ([]*entities.Product, error)
+	Search(ctx context.Context, query string, filters *ProductFilters, limit, offset int) ([]*ProductSearchHit, error)
 	GetByPriceRange(ctx context.Context, minPrice, maxPrice float64, limit, offset int) ([]*entities.Product, error)
 	GetByTags(ctx context.Context, tags []string, limit, offset int) ([]*entities.Product, error)
 	
@@ -39,10 +74,12 @@ type ProductRepository interface {
 	UpdateStock(ctx context.Context, id uuid.UUID, quantity int) error
 	BulkUpdateStock(ctx context.Context, updates []StockUpdate) error
 	
-	// Analytics and reporting
-	GetTopSelling(ctx context.Context, limit int, days int) ([]*entities.Product, error)
+	// Analytics and reporting. Rankings cover listed products with activity in the last
+	// days (today included), in categoryID only when it is set.
+	GetTopSelling(ctx context.Context, limit int, days int, categoryID *uuid.UUID) ([]*entities.Product, error)
 	GetRecentlyAdded(ctx context.Context, limit int, days int) ([]*entities.Product, error)
-	GetMostViewed(ctx context.Context, limit int, days int) ([]*entities.Product, error)
+	GetMostViewed(ctx context.Context, limit int, days int, categoryID *uuid.UUID) ([]*entities.Product, error)
+	GetTrending(ctx context.Context, limit int, days int, categoryID *uuid.UUID) ([]*entities.Product, error)
 	
 	// Counting
 	Count(ctx context.Context) (int64, error)
@@ -51,6 +88,18 @@ type ProductRepository interface {
 	CountByStatus(ctx context.Context, status entities.ProductStatus) (int64, error)
 	CountSearch(ctx context.Context, query string, filters *ProductFilters) (int64, error)
 	
+	// Faceting; attributeKeys limits attribute facets to those keys, nil computes every key
+	GetSearchFacets(ctx context.Context, query string, filters *ProductFilters, attributeKeys []string) (map[string][]FacetValue, error)
+	
+	// Typeahead
+	Suggest(ctx context.Context, query string, limit int) ([]*Suggestion, error)
+	
+	// Scheduled publishing. Each call claims up to limit due products with row locks that
+	// concurrent callers skip, applies the transition and returns the changed products, so
+	// every product transitions exactly once even with several replicas running.
+	PublishDue(ctx context.Context, now time.Time, limit int) ([]*entities.Product, error)
+	UnpublishExpired(ctx context.Context, now time.Time, limit int) ([]*entities.Product, error)
+	
 	// Existence checks
 	ExistsBySKU(ctx context.Context, sku string) (bool, error)
 	ExistsBySlug(ctx context.Context, slug string) (bool, error)
@@ -87,6 +136,8 @@ type CategoryRepository interface {
 	// Tree operations
 	GetTree(ctx context.Context) ([]*entities.Category, error)
 	GetSubTree(ctx context.Context, rootID uuid.UUID) ([]*entities.Category, error)
+	UpdateTree(ctx context.Context, categories []*entities.Category) error
+	LockHierarchy(ctx context.Context, rootID *uuid.UUID, pathTo *uuid.UUID) error
 	
 	// Counting
 	Count(ctx context.Context) (int64, error)
@@ -103,6 +154,19 @@ type CategoryRepository interface {
 	DecrementProductCount(ctx context.Context, id uuid.UUID) error
 }
 
+// CategoryAttributeRepository defines the interface for category attribute schema data access
+type CategoryAttributeRepository interface {
+	Create(ctx context.Context, attribute *entities.CategoryAttribute) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.CategoryAttribute, error)
+	Update(ctx context.Context, attribute *entities.CategoryAttribute) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	
+	// GetByCategories retrieves the attributes defined directly on any of the categories
+	GetByCategories(ctx context.Context, categoryIDs []uuid.UUID) ([]*entities.CategoryAttribute, error)
+	GetFilterable(ctx context.Context) ([]*entities.CategoryAttribute, error)
+	ExistsByName(ctx context.Context, categoryID uuid.UUID, name string) (bool, error)
+}
+
 // BrandRepository defines the interface for brand data access
 type BrandRepository interface {
 	// Basic CRUD operations
@@ -127,6 +191,9 @@ type BrandRepository interface {
 	ExistsBySlug(ctx context.Context, slug string) (bool, error)
 	HasProducts(ctx context.Context, id uuid.UUID) (bool, error)
 	
+	// ReassignProducts moves every product of a brand to another brand
+	ReassignProducts(ctx context.Context, fromID, toID uuid.UUID) (int64, error)
+	
 	// Product count management
 	UpdateProductCount(ctx context.Context, id uuid.UUID, count int) error
 	IncrementProductCount(ctx context.Context, id uuid.UUID) error
@@ -187,6 +254,10 @@ type ProductImageRepository interface {
 	GetPrimaryByProductID(ctx context.Context, productID uuid.UUID) (*entities.ProductImage, error)
 	GetPrimaryByVariantID(ctx context.Context, variantID uuid.UUID) (*entities.ProductImage, error)
 	
+	// GetByContentHash returns the image of a product, or of one of its variants when
+	// variantID is set, with the given content hash
+	GetByContentHash(ctx context.Context, productID uuid.UUID, variantID *uuid.UUID, hash string) (*entities.ProductImage, error)
+	
 	// Bulk operations
 	CreateBulk(ctx context.Context, images []*entities.ProductImage) error
 	UpdateBulk(ctx context.Context, images []*entities.ProductImage) error
@@ -226,14 +297,231 @@ type ProductVideoRepository interface {
 	CountByProductID(ctx context.Context, productID uuid.UUID) (int64, error)
 }
 
+// SearchAnalyticsRepository defines the interface for search analytics data access
+type SearchAnalyticsRepository interface {
+	// Logging
+	CreateBulk(ctx context.Context, logs []*entities.SearchLog) error
+	RecordClick(ctx context.Context, searchID, productID uuid.UUID, position int, clickedAt time.Time) error
+	
+	// Reporting
+	GetSummary(ctx context.Context, from, to time.Time) (*SearchAnalyticsSummary, error)
+	GetTopTerms(ctx context.Context, from, to time.Time, limit int) ([]*SearchTermStats, error)
+	GetZeroResultTerms(ctx context.Context, from, to time.Time, limit int) ([]*SearchTermStats, error)
+}
+
+// CatalogRepository defines the interface for bulk catalog import and export
+type CatalogRepository interface {
+	// Import jobs
+	CreateImportJob(ctx context.Context, job *entities.ImportJob) error
+	GetImportJob(ctx context.Context, id uuid.UUID) (*entities.ImportJob, error)
+	UpdateImportJob(ctx context.Context, job *entities.ImportJob) error
+	
+	// ApplyImportChunk writes a chunk and the job's progress in a single transaction.
+	// It returns a *CatalogConflictError, and writes nothing, when products the chunk
+	// updates are no longer at their expected versions.
+	ApplyImportChunk(ctx context.Context, job *entities.ImportJob, chunk *CatalogChunk) error
+	
+	// Export
+	StreamProducts(ctx context.Context, batchSize int, fn func(products []*entities.Product) error) error
+}
+
+// ProductRevisionRepository defines the interface for product revision history. Revisions
+// are append-only.
+type ProductRevisionRepository interface {
+	// Create appends revisions, numbering each product's after its latest revision
+	Create(ctx context.Context, revisions ...*entities.ProductRevision) error
+	GetByNumber(ctx context.Context, productID uuid.UUID, number int) (*entities.ProductRevision, error)
+	GetByProductID(ctx context.Context, productID uuid.UUID, limit, offset int) ([]*entities.ProductRevision, error)
+	CountByProductID(ctx context.Context, productID uuid.UUID) (int64, error)
+}
+
+// PriceListRepository defines the interface for price list data access
+type PriceListRepository interface {
+	// Price lists
+	Create(ctx context.Context, list *entities.PriceList) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.PriceList, error)
+	GetByKey(ctx context.Context, currency, channel, customerGroup string) (*entities.PriceList, error)
+	Update(ctx context.Context, list *entities.PriceList) error
+	Delete(ctx context.Context, id uuid.UUID) error // also deletes the list's entries
+	List(ctx context.Context) ([]*entities.PriceList, error)
+	
+	// Entries
+	CreateEntry(ctx context.Context, entry *entities.PriceListEntry) error
+	GetEntry(ctx context.Context, id uuid.UUID) (*entities.PriceListEntry, error)
+	UpdateEntry(ctx context.Context, entry *entities.PriceListEntry) error
+	DeleteEntry(ctx context.Context, id uuid.UUID) error
+	GetEntries(ctx context.Context, priceListID uuid.UUID, limit, offset int) ([]*entities.PriceListEntry, error)
+	CountEntries(ctx context.Context, priceListID uuid.UUID) (int64, error)
+	
+	// FindApplicable returns the entries, with their price list, of the given products and
+	// their variants that apply in a currency, channel and customer group at a time: from
+	// active lists for that currency whose channel and group are empty or match
+	FindApplicable(ctx context.Context, productIDs []uuid.UUID, currency, channel, customerGroup string, at time.Time) ([]*entities.PriceListEntry, error)
+}
+
+// SaleRepository defines the interface for sale data access
+type SaleRepository interface {
+	Create(ctx context.Context, sale *entities.Sale) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Sale, error)
+	Update(ctx context.Context, sale *entities.Sale) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context, filters SaleFilters, limit, offset int) ([]*entities.Sale, error)
+	Count(ctx context.Context, filters SaleFilters) (int64, error)
+	
+	// FindRunning returns the active sales running at a time that target any of the
+	// given products, categories or brands
+	FindRunning(ctx context.Context, productIDs, categoryIDs, brandIDs []uuid.UUID, at time.Time) ([]*entities.Sale, error)
+}
+
+// BundleRepository defines the interface for bundle component data access
+type BundleRepository interface {
+	// GetItems returns the components of a bundle in order, with their products and
+	// variants loaded
+	GetItems(ctx context.Context, bundleID uuid.UUID) ([]*entities.BundleItem, error)
+	// LockItems is GetItems for a transaction that changes the components: their
+	// products and variants stay locked until it ends
+	LockItems(ctx context.Context, bundleID uuid.UUID) ([]*entities.BundleItem, error)
+	ReplaceItems(ctx context.Context, bundleID uuid.UUID, items []*entities.BundleItem) error
+	
+	// GetBundleIDsByComponents returns the bundles containing any of the given products,
+	// or any of their variants
+	GetBundleIDsByComponents(ctx context.Context, productIDs []uuid.UUID) ([]uuid.UUID, error)
+	// LockBundles returns the bundles among the given products, locked for update until
+	// the transaction ends
+	LockBundles(ctx context.Context, productIDs []uuid.UUID) ([]*entities.Product, error)
+}
+
+// ProductRelationRepository defines the interface for hand-picked product relation data access
+type ProductRelationRepository interface {
+	// GetByProduct returns a product's relations of a type, or of every type if the type
+	// is empty, in order and with their related products loaded
+	GetByProduct(ctx context.Context, productID uuid.UUID, relationType entities.RelationType) ([]*entities.ProductRelation, error)
+	ReplaceRelations(ctx context.Context, productID uuid.UUID, relationType entities.RelationType, relations []*entities.ProductRelation) error
+}
+
+// RecommendationRepository defines the interface for order-based recommendation data access
+type RecommendationRepository interface {
+	// RecordOrder counts every pair of distinct products in an order as bought together.
+	// It returns false, and counts nothing, if the order was recorded before.
+	RecordOrder(ctx context.Context, orderID string, productIDs []uuid.UUID, orderedAt time.Time) (bool, error)
+	
+	// GetFrequentlyBoughtWith returns the products most often bought with a product
+	GetFrequentlyBoughtWith(ctx context.Context, productID uuid.UUID, limit int) ([]*entities.ProductCoOccurrence, error)
+}
+
+// ProductStatsRepository defines the interface for product view and sale counters
+type ProductStatsRepository interface {
+	// AddStats adds the views, units sold and orders of each stat to its product's bucket
+	AddStats(ctx context.Context, stats []*entities.ProductStat) error
+	
+	// RecordOrderSales adds an order's sales to the stats once. The order must have been
+	// recorded by RecommendationRepository.RecordOrder; it returns false, and adds
+	// nothing, if the order is unknown or its sales were counted before.
+	RecordOrderSales(ctx context.Context, orderID string, stats []*entities.ProductStat) (bool, error)
+}
+
+// ReviewRepository defines the interface for product review data access. Every write
+// that can change which reviews are approved recomputes the product's AverageRating and
+// ReviewCount in the same transaction.
+type ReviewRepository interface {
+	Create(ctx context.Context, review *entities.ProductReview) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.ProductReview, error)
+	GetByProductAndUser(ctx context.Context, productID, userID uuid.UUID) (*entities.ProductReview, error)
+	Update(ctx context.Context, review *entities.ProductReview) error
+	
+	// Listing; a nil ProductID in filters lists reviews of every product
+	List(ctx context.Context, filters *ReviewFilters, limit, offset int) ([]*entities.ProductReview, error)
+	Count(ctx context.Context, filters *ReviewFilters) (int64, error)
+	
+	// GetRatingHistogram counts a product's approved reviews by rating
+	GetRatingHistogram(ctx context.Context, productID uuid.UUID) (map[int]int64, error)
+	
+	// Vote records a user's helpful vote, replacing their earlier one, and recounts the
+	// review's votes
+	Vote(ctx context.Context, vote *entities.ReviewVote) error
+	
+	// Spam checks
+	CountByIPSince(ctx context.Context, ipAddress string, since time.Time) (int64, error)
+	CountByContentSince(ctx context.Context, content string, since time.Time) (int64, error)
+	
+	// Purchases. RecordPurchases ignores purchases recorded before and marks the buyers'
+	// existing reviews of the products as verified.
+	RecordPurchases(ctx context.Context, purchases []*entities.ProductPurchase) error
+	HasPurchased(ctx context.Context, userID, productID uuid.UUID) (bool, error)
+}
+
+// SlugRedirectRepository defines the interface for slug history data access
+type SlugRedirectRepository interface {
+	// Record stores old slugs, moving any recorded for another entity of the same type
+	Record(ctx context.Context, redirects ...*entities.SlugRedirect) error
+	Resolve(ctx context.Context, entityType entities.SlugEntityType, slug string) (*entities.SlugRedirect, error)
+}
+
+// SitemapRepository defines the interface for reading sitemap entries: active, visible
+// products and categories, in a stable order so pages do not shift between requests
+type SitemapRepository interface {
+	// GetPageLastMods returns the latest update time of each page of pageSize entries
+	GetPageLastMods(ctx context.Context, entityType entities.SlugEntityType, pageSize int) ([]time.Time, error)
+	GetEntries(ctx context.Context, entityType entities.SlugEntityType, limit, offset int) ([]*SitemapEntry, error)
+}
+
+// FeedRepository defines the interface for reading the listed catalog into product feeds
+type FeedRepository interface {
+	// StreamListedProducts loads active, visible products in batches with their brands,
+	// images and active variants preloaded
+	StreamListedProducts(ctx context.Context, batchSize int, fn func(products []*entities.Product) error) error
+	// GetFeedVersion summarizes everything a feed is built from, so an unchanged feed
+	// can be answered from cache
+	GetFeedVersion(ctx context.Context) (*FeedVersion, error)
+}
+
 // Supporting types and structures
 
+// FeedVersion identifies the state of the listed catalog a feed is built from. Items
+// counts listed products and their active variants; LastModified is the latest change
+// to any of them, their images or brands, or to the sales that price them.
+type FeedVersion struct {
+	Items        int64     `json:"items"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// SitemapEntry is a page listed in a sitemap
+type SitemapEntry struct {
+	Slug      string    `json:"slug"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Review sort orders
+const (
+	ReviewSortNewest      = "newest"
+	ReviewSortOldest      = "oldest"
+	ReviewSortHighest     = "highest"
+	ReviewSortLowest      = "lowest"
+	ReviewSortMostHelpful = "most_helpful"
+)
+
+// ReviewFilters represents criteria for listing reviews
+type ReviewFilters struct {
+	ProductID    *uuid.UUID             `json:"product_id"`
+	Status       *entities.ReviewStatus `json:"status"`
+	Rating       *int                   `json:"rating"`
+	VerifiedOnly bool                   `json:"verified_only"`
+	Sort         string                 `json:"sort"` // one of the ReviewSort orders; newest by default
+}
+
+// SaleFilters represents criteria for listing sales
+type SaleFilters struct {
+	Scope    entities.SaleScope `json:"scope"`
+	TargetID *uuid.UUID         `json:"target_id"`
+	Status   string             `json:"status"` // scheduled, running or ended, at the current time
+}
+
 // ProductFilters represents search and filter criteria
 type ProductFilters struct {
 	CategoryIDs []uuid.UUID `json:"category_ids"`
 	BrandIDs    []uuid.UUID `json:"brand_ids"`
 	MinPrice    *float64    `json:"min_price"`
-	MaxPrice    *float64    `json:"max_price"`
+	MaxPrice    *float64    `json:"max_price"` // exclusive, like the price facet's buckets
 	InStock     *bool       `json:"in_stock"`
 	Featured    *bool       `json:"featured"`
 	Status      *entities.ProductStatus `json:"status"`
@@ -241,8 +529,14 @@ type ProductFilters struct {
 	Tags        []string    `json:"tags"`
 	Attributes  map[string]string `json:"attributes"`
 	HasVariants *bool       `json:"has_variants"`
+	IsBundle    *bool       `json:"is_bundle"`
+	ListedOnly  bool        `json:"listed_only,omitempty"` // only active, non-hidden products
 	CreatedFrom *string     `json:"created_from"` // ISO date string
 	CreatedTo   *string     `json:"created_to"`   // ISO date string
+	
+	// IDs restricts results to these products; set by search backends that match the
+	// query text themselves. Searches without query text return them in this order.
+	IDs []uuid.UUID `json:"-"`
 }
 
 // StockUpdate represents a stock update operation
@@ -258,15 +552,77 @@ type SortOption struct {
 	Order string `json:"order"` // asc, desc
 }
 
+// ProductSearchHit represents a single ranked search match
+type ProductSearchHit struct {
+	Product    *entities.Product `json:"product"`
+	Rank       float64           `json:"rank"`
+	Highlights ProductHighlight  `json:"highlights"`
+}
+
+// ProductHighlight holds matched text fragments wrapped in <mark> tags
+type ProductHighlight struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// FacetValue represents one facet bucket and the number of matching products
+type FacetValue struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+	Count int64  `json:"count"`
+}
+
+// Facet names returned by GetSearchFacets; attribute facets use FacetAttributePrefix + key
+const (
+	FacetCategory        = "category"
+	FacetBrand           = "brand"
+	FacetPrice           = "price"
+	FacetStock           = "stock"
+	FacetTags            = "tags"
+	FacetAttributePrefix = "attributes."
+)
+
+// Suggestion types returned by Suggest
+const (
+	SuggestionTypeProduct  = "product"
+	SuggestionTypeBrand    = "brand"
+	SuggestionTypeCategory = "category"
+)
+
+// Suggestion represents a typeahead completion for a product, brand or category name
+type Suggestion struct {
+	Text  string  `json:"text"`
+	Type  string  `json:"type"`
+	ID    string  `json:"id"`
+	Slug  string  `json:"slug,omitempty"`
+	Score float64 `json:"score"`
+}
+
+// CatalogChunk is a validated batch of catalog changes that is applied atomically.
+// Products with an expected version, the version they were read at, are updated only
+// while still at it; other products are created. Variants are upserted by ID. Image
+// lists replace all existing images of the product or variant they are keyed by.
+type CatalogChunk struct {
+	Products         []*entities.Product
+	ExpectedVersions map[uuid.UUID]int
+	Variants      []*entities.ProductVariant
+	ProductImages map[uuid.UUID][]*entities.ProductImage
+	VariantImages map[uuid.UUID][]*entities.ProductImage
+	SlugRedirects []*entities.SlugRedirect
+}
+
 // ProductSearchResult represents search results with metadata
 type ProductSearchResult struct {
-	Products   []*entities.Product `json:"products"`
-	Total      int64               `json:"total"`
-	Page       int                 `json:"page"`
-	PageSize   int                 `json:"page_size"`
-	TotalPages int                 `json:"total_pages"`
-	HasNext    bool                `json:"has_next"`
-	HasPrev    bool                `json:"has_prev"`
+	SearchID   string                      `json:"search_id,omitempty"` // pass back when reporting a click
+	Products   []*entities.Product         `json:"products"`
+	Highlights map[string]ProductHighlight `json:"highlights,omitempty"` // keyed by product ID
+	Facets     map[string][]FacetValue     `json:"facets,omitempty"`
+	Total      int64                       `json:"total"`
+	Page       int                         `json:"page"`
+	PageSize   int                         `json:"page_size"`
+	TotalPages int                         `json:"total_pages"`
+	HasNext    bool                        `json:"has_next"`
+	HasPrev    bool                        `json:"has_prev"`
 }
 
 // CategoryTree represents a category tree structure
@@ -276,3 +632,31 @@ type CategoryTree struct {
 	Level    int                `json:"level"`
 	Path     []string           `json:"path"`
 }
+
+// SearchAnalyticsSummary aggregates search activity over a period
+type SearchAnalyticsSummary struct {
+	TotalSearches    int64   `json:"total_searches"`
+	UniqueQueries    int64   `json:"unique_queries"`
+	ZeroResultCount  int64   `json:"zero_result_count"`
+	ClickCount       int64   `json:"click_count"`
+	ClickThroughRate float64 `json:"click_through_rate"`
+	AverageResults   float64 `json:"average_results"`
+	AverageLatencyMs float64 `json:"average_latency_ms"`
+}
+
+// SearchTermStats summarises searches for one normalized query
+type SearchTermStats struct {
+	Term             string  `json:"term"`
+	Count            int64   `json:"count"`
+	AverageResults   float64 `json:"average_results"`
+	ClickThroughRate float64 `json:"click_through_rate"`
+}
+
+// SearchAnalyticsReport is the search analytics dashboard payload
+type SearchAnalyticsReport struct {
+	From              time.Time               `json:"from"`
+	To                time.Time               `json:"to"`
+	Summary           *SearchAnalyticsSummary `json:"summary"`
+	TopTerms          []*SearchTermStats      `json:"top_terms"`
+	ZeroResultQueries []*SearchTermStats      `json:"zero_result_queries"`
+}
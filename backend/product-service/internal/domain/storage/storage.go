@@ -0,0 +1,17 @@
+package storage
+
+import (
+	"context"
+	"io"
+)
+
+// Storage keeps uploaded files, such as product images, under slash-separated keys
+type Storage interface {
+	// Put stores the contents of r under key, replacing any existing file
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	Exists(ctx context.Context, key string) (bool, error)
+	Delete(ctx context.Context, key string) error
+
+	// URL returns the address clients fetch the file at
+	URL(key string) string
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+// Full-text search expressions. The per-field vectors must stay identical to the
+// expression indexes cmd/server creates on migration (idx_products_*_gin) so the
+// planner can use them for matching; the weighted document is only evaluated for ranking.
+const (
+	nameVectorSQL      = "to_tsvector('english', name)"
+	shortDescVectorSQL = "to_tsvector('english', short_desc)"
+	descVectorSQL      = "to_tsvector('english', description)"
+
+	// weightedDocumentSQL ranks name > short description > description > tags
+	weightedDocumentSQL = "setweight(to_tsvector('english', coalesce(name, '')), 'A') || " +
+		"setweight(to_tsvector('english', coalesce(short_desc, '')), 'B') || " +
+		"setweight(to_tsvector('english', coalesce(description, '')), 'C') || " +
+		"setweight(to_tsvector('english', coalesce(array_to_string(tags, ' '), '')), 'D')"
+
+	tsQuerySQL = "to_tsquery('english', ?)"
+
+	headlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2"
+)
+
+// searchTerms splits a free-text query into lower-cased alphanumeric terms
+func searchTerms(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// buildPrefixTSQuery converts search terms into a tsquery string where every term
+// must match as a prefix, so "wirel head" finds "Wireless Headphones".
+// The result is safe to pass to to_tsquery because terms contain no operators.
+func buildPrefixTSQuery(terms []string) string {
+	if len(terms) == 0 {
+		return ""
+	}
+
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		parts[i] = term + ":*"
+	}
+
+	return strings.Join(parts, " & ")
+}
+
+// applyTextSearch restricts db to rows matching the tsquery in any indexed field.
+// Search and CountSearch share it so totals always agree with the returned page.
+func applyTextSearch(db *gorm.DB, tsQuery string, terms []string) *gorm.DB {
+	return db.Where(
+		nameVectorSQL+" @@ "+tsQuerySQL+
+			" OR "+shortDescVectorSQL+" @@ "+tsQuerySQL+
+			" OR "+descVectorSQL+" @@ "+tsQuerySQL+
+			" OR tags && ARRAY[?]::text[]",
+		tsQuery, tsQuery, tsQuery, terms,
+	)
+}
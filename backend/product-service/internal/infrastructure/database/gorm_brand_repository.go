@@ -0,0 +1,190 @@
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// GormBrandRepository implements BrandRepository using GORM
+type GormBrandRepository struct {
+	db *gorm.DB
+}
+
+// NewGormBrandRepository creates a new GORM brand repository
+func NewGormBrandRepository(db *gorm.DB) repositories.BrandRepository {
+	return &GormBrandRepository{db: db}
+}
+
+// Create creates a new brand
+func (r *GormBrandRepository) Create(ctx context.Context, brand *entities.Brand) error {
+	return dbFor(ctx, r.db).Omit(clause.Associations).Create(brand).Error
+}
+
+// GetByID retrieves a brand by ID
+func (r *GormBrandRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Brand, error) {
+	return r.getWhere(ctx, "id = ?", id)
+}
+
+// GetBySlug retrieves a brand by slug
+func (r *GormBrandRepository) GetBySlug(ctx context.Context, slug string) (*entities.Brand, error) {
+	return r.getWhere(ctx, "slug = ?", slug)
+}
+
+// GetByName retrieves a brand by name, ignoring case
+func (r *GormBrandRepository) GetByName(ctx context.Context, name string) (*entities.Brand, error) {
+	return r.getWhere(ctx, "LOWER(name) = LOWER(?)", name)
+}
+
+// getWhere retrieves the first brand matching a condition
+func (r *GormBrandRepository) getWhere(ctx context.Context, query string, args ...interface{}) (*entities.Brand, error) {
+	var brand entities.Brand
+	err := dbFor(ctx, r.db).Where(query, args...).First(&brand).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &brand, nil
+}
+
+// Update updates a brand
+func (r *GormBrandRepository) Update(ctx context.Context, brand *entities.Brand) error {
+	return dbFor(ctx, r.db).Omit(clause.Associations).Save(brand).Error
+}
+
+// Delete deletes a brand
+func (r *GormBrandRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return dbFor(ctx, r.db).Delete(&entities.Brand{}, id).Error
+}
+
+// GetAll retrieves all brands
+func (r *GormBrandRepository) GetAll(ctx context.Context) ([]*entities.Brand, error) {
+	var brands []*entities.Brand
+	err := dbFor(ctx, r.db).
+		Order("sort_order, name").
+		Find(&brands).Error
+	return brands, err
+}
+
+// GetActive retrieves active brands
+func (r *GormBrandRepository) GetActive(ctx context.Context) ([]*entities.Brand, error) {
+	var brands []*entities.Brand
+	err := dbFor(ctx, r.db).
+		Where("is_active = ?", true).
+		Order("sort_order, name").
+		Find(&brands).Error
+	return brands, err
+}
+
+// GetVisible retrieves active, visible brands
+func (r *GormBrandRepository) GetVisible(ctx context.Context) ([]*entities.Brand, error) {
+	var brands []*entities.Brand
+	err := dbFor(ctx, r.db).
+		Where("is_active = ? AND is_visible = ?", true, true).
+		Order("sort_order, name").
+		Find(&brands).Error
+	return brands, err
+}
+
+// GetPaginated retrieves a page of brands
+func (r *GormBrandRepository) GetPaginated(ctx context.Context, limit, offset int) ([]*entities.Brand, error) {
+	var brands []*entities.Brand
+	err := dbFor(ctx, r.db).
+		Order("sort_order, name").
+		Limit(limit).
+		Offset(offset).
+		Find(&brands).Error
+	return brands, err
+}
+
+// Count counts all brands
+func (r *GormBrandRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	err := dbFor(ctx, r.db).Model(&entities.Brand{}).Count(&count).Error
+	return count, err
+}
+
+// ExistsByName checks if a brand exists by name, ignoring case
+func (r *GormBrandRepository) ExistsByName(ctx context.Context, name string) (bool, error) {
+	var count int64
+	err := dbFor(ctx, r.db).
+		Model(&entities.Brand{}).
+		Where("LOWER(name) = LOWER(?)", name).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ExistsBySlug checks if a brand exists by slug
+func (r *GormBrandRepository) ExistsBySlug(ctx context.Context, slug string) (bool, error) {
+	var count int64
+	err := dbFor(ctx, r.db).
+		Model(&entities.Brand{}).
+		Where("slug = ?", slug).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// HasProducts checks if any product is assigned to a brand
+func (r *GormBrandRepository) HasProducts(ctx context.Context, id uuid.UUID) (bool, error) {
+	var count int64
+	err := dbFor(ctx, r.db).
+		Model(&entities.Product{}).
+		Where("brand_id = ?", id).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ReassignProducts moves every product of one brand to another and moves the product
+// count with them
+func (r *GormBrandRepository) ReassignProducts(ctx context.Context, fromID, toID uuid.UUID) (int64, error) {
+	var moved int64
+	err := dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.Product{}).
+			Where("brand_id = ?", fromID).
+			Updates(map[string]interface{}{"brand_id": toID, "updated_at": gorm.Expr("NOW()"), "version": gorm.Expr("version + 1")})
+		if result.Error != nil {
+			return result.Error
+		}
+		moved = result.RowsAffected
+
+		if err := tx.Model(&entities.Brand{}).
+			Where("id = ?", toID).
+			Update("product_count", gorm.Expr("product_count + ?", moved)).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entities.Brand{}).
+			Where("id = ?", fromID).
+			Update("product_count", 0).Error
+	})
+	return moved, err
+}
+
+// UpdateProductCount sets a brand's product count
+func (r *GormBrandRepository) UpdateProductCount(ctx context.Context, id uuid.UUID, count int) error {
+	return dbFor(ctx, r.db).
+		Model(&entities.Brand{}).
+		Where("id = ?", id).
+		Update("product_count", count).Error
+}
+
+// IncrementProductCount increments a brand's product count
+func (r *GormBrandRepository) IncrementProductCount(ctx context.Context, id uuid.UUID) error {
+	return dbFor(ctx, r.db).
+		Model(&entities.Brand{}).
+		Where("id = ?", id).
+		Update("product_count", gorm.Expr("product_count + 1")).Error
+}
+
+// DecrementProductCount decrements a brand's product count
+func (r *GormBrandRepository) DecrementProductCount(ctx context.Context, id uuid.UUID) error {
+	return dbFor(ctx, r.db).
+		Model(&entities.Brand{}).
+		Where("id = ? AND product_count > 0", id).
+		Update("product_count", gorm.Expr("product_count - 1")).Error
+}
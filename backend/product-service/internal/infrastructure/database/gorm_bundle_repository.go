@@ -0,0 +1,93 @@
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// GormBundleRepository implements BundleRepository using GORM
+type GormBundleRepository struct {
+	db *gorm.DB
+}
+
+// NewGormBundleRepository creates a new GORM bundle repository
+func NewGormBundleRepository(db *gorm.DB) repositories.BundleRepository {
+	return &GormBundleRepository{db: db}
+}
+
+// GetItems returns the components of a bundle in order, with their products and variants
+func (r *GormBundleRepository) GetItems(ctx context.Context, bundleID uuid.UUID) ([]*entities.BundleItem, error) {
+	var items []*entities.BundleItem
+	err := dbFor(ctx, r.db).
+		Preload("Product").
+		Preload("Variant").
+		Where("bundle_id = ?", bundleID).
+		Order("sort_order, created_at").
+		Find(&items).Error
+	return items, err
+}
+
+// LockItems returns the components of a bundle like GetItems, locking their products and
+// variants for update. Rows are locked in ID order, so transactions locking overlapping
+// bundles wait for each other rather than deadlock.
+func (r *GormBundleRepository) LockItems(ctx context.Context, bundleID uuid.UUID) ([]*entities.BundleItem, error) {
+	forUpdate := func(db *gorm.DB) *gorm.DB {
+		return db.Clauses(clause.Locking{Strength: "UPDATE"}).Order("id")
+	}
+
+	var items []*entities.BundleItem
+	err := dbFor(ctx, r.db).
+		Preload("Product", forUpdate).
+		Preload("Variant", forUpdate).
+		Where("bundle_id = ?", bundleID).
+		Order("sort_order, created_at").
+		Find(&items).Error
+	return items, err
+}
+
+// ReplaceItems replaces the components of a bundle in a single transaction
+func (r *GormBundleRepository) ReplaceItems(ctx context.Context, bundleID uuid.UUID, items []*entities.BundleItem) error {
+	return dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("bundle_id = ?", bundleID).Delete(&entities.BundleItem{}).Error; err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		return tx.Omit("Product", "Variant").Create(items).Error
+	})
+}
+
+// GetBundleIDsByComponents returns the bundles containing any of the given products
+func (r *GormBundleRepository) GetBundleIDsByComponents(ctx context.Context, productIDs []uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if len(productIDs) == 0 {
+		return ids, nil
+	}
+	err := dbFor(ctx, r.db).
+		Model(&entities.BundleItem{}).
+		Distinct("bundle_id").
+		Where("product_id IN ?", productIDs).
+		Pluck("bundle_id", &ids).Error
+	return ids, err
+}
+
+// LockBundles returns the bundles among the given products, locking them for update in
+// ID order
+func (r *GormBundleRepository) LockBundles(ctx context.Context, productIDs []uuid.UUID) ([]*entities.Product, error) {
+	var bundles []*entities.Product
+	if len(productIDs) == 0 {
+		return bundles, nil
+	}
+	err := dbFor(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ? AND is_bundle = ?", productIDs, true).
+		Order("id").
+		Find(&bundles).Error
+	return bundles, err
+}
@@ -0,0 +1,129 @@
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// GormCatalogRepository implements CatalogRepository using GORM
+type GormCatalogRepository struct {
+	db *gorm.DB
+}
+
+// NewGormCatalogRepository creates a new GORM catalog repository
+func NewGormCatalogRepository(db *gorm.DB) repositories.CatalogRepository {
+	return &GormCatalogRepository{db: db}
+}
+
+// CreateImportJob creates a new import job
+func (r *GormCatalogRepository) CreateImportJob(ctx context.Context, job *entities.ImportJob) error {
+	return dbFor(ctx, r.db).Create(job).Error
+}
+
+// GetImportJob retrieves an import job by ID
+func (r *GormCatalogRepository) GetImportJob(ctx context.Context, id uuid.UUID) (*entities.ImportJob, error) {
+	var job entities.ImportJob
+	err := dbFor(ctx, r.db).Where("id = ?", id).First(&job).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// UpdateImportJob updates an import job
+func (r *GormCatalogRepository) UpdateImportJob(ctx context.Context, job *entities.ImportJob) error {
+	return dbFor(ctx, r.db).Save(job).Error
+}
+
+// ApplyImportChunk writes the chunk's products, variants and images and saves the job's
+// progress in the same transaction, so progress never runs ahead of the data. Existing
+// products are updated only while still at the version the chunk expects, as in
+// GormProductRepository.Update.
+func (r *GormCatalogRepository) ApplyImportChunk(ctx context.Context, job *entities.ImportJob, chunk *repositories.CatalogChunk) error {
+	return dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// Ratings are kept by the review repository, so an import never writes them
+		var conflicts []uuid.UUID
+		for _, product := range chunk.Products {
+			expected, exists := chunk.ExpectedVersions[product.ID]
+			if !exists {
+				if err := tx.Omit(clause.Associations, "average_rating", "review_count").Create(product).Error; err != nil {
+					return err
+				}
+				continue
+			}
+
+			product.Version = expected + 1
+			result := tx.Model(product).
+				Where("version = ?", expected).
+				Select("*").
+				Omit(clause.Associations, "created_at", "average_rating", "review_count").
+				Updates(product)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				conflicts = append(conflicts, product.ID)
+			}
+		}
+		if len(conflicts) > 0 {
+			return &repositories.CatalogConflictError{ProductIDs: conflicts}
+		}
+
+		for _, variant := range chunk.Variants {
+			if err := tx.Omit(clause.Associations).Save(variant).Error; err != nil {
+				return err
+			}
+		}
+
+		for productID, images := range chunk.ProductImages {
+			if err := tx.Where("product_id = ? AND variant_id IS NULL", productID).Delete(&entities.ProductImage{}).Error; err != nil {
+				return err
+			}
+			if len(images) > 0 {
+				if err := tx.Create(images).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		for variantID, images := range chunk.VariantImages {
+			if err := tx.Where("variant_id = ?", variantID).Delete(&entities.ProductImage{}).Error; err != nil {
+				return err
+			}
+			if len(images) > 0 {
+				if err := tx.Create(images).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := recordSlugRedirects(tx, chunk.SlugRedirects); err != nil {
+			return err
+		}
+
+		return tx.Save(job).Error
+	})
+}
+
+// StreamProducts loads the full catalog in batches (keyed by ID, as FindInBatches
+// requires) with categories, brands, images and variants preloaded
+func (r *GormCatalogRepository) StreamProducts(ctx context.Context, batchSize int, fn func(products []*entities.Product) error) error {
+	var batch []*entities.Product
+	return dbFor(ctx, r.db).
+		Preload("Category").
+		Preload("Brand").
+		Preload("Images", func(db *gorm.DB) *gorm.DB { return db.Order("sort_order") }).
+		Preload("Variants", func(db *gorm.DB) *gorm.DB { return db.Order("sort_order, sku") }).
+		Preload("Variants.Images", func(db *gorm.DB) *gorm.DB { return db.Order("sort_order") }).
+		FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+			return fn(batch)
+		}).Error
+}
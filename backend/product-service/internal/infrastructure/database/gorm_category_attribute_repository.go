@@ -0,0 +1,81 @@
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// GormCategoryAttributeRepository implements CategoryAttributeRepository using GORM
+type GormCategoryAttributeRepository struct {
+	db *gorm.DB
+}
+
+// NewGormCategoryAttributeRepository creates a new GORM category attribute repository
+func NewGormCategoryAttributeRepository(db *gorm.DB) repositories.CategoryAttributeRepository {
+	return &GormCategoryAttributeRepository{db: db}
+}
+
+// Create creates a new attribute definition
+func (r *GormCategoryAttributeRepository) Create(ctx context.Context, attribute *entities.CategoryAttribute) error {
+	return dbFor(ctx, r.db).Create(attribute).Error
+}
+
+// GetByID retrieves an attribute definition by ID
+func (r *GormCategoryAttributeRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.CategoryAttribute, error) {
+	var attribute entities.CategoryAttribute
+	err := dbFor(ctx, r.db).Where("id = ?", id).First(&attribute).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &attribute, nil
+}
+
+// Update updates an attribute definition
+func (r *GormCategoryAttributeRepository) Update(ctx context.Context, attribute *entities.CategoryAttribute) error {
+	return dbFor(ctx, r.db).Save(attribute).Error
+}
+
+// Delete deletes an attribute definition
+func (r *GormCategoryAttributeRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return dbFor(ctx, r.db).Delete(&entities.CategoryAttribute{}, id).Error
+}
+
+// GetByCategories retrieves the attributes defined directly on any of the categories
+func (r *GormCategoryAttributeRepository) GetByCategories(ctx context.Context, categoryIDs []uuid.UUID) ([]*entities.CategoryAttribute, error) {
+	var attributes []*entities.CategoryAttribute
+	if len(categoryIDs) == 0 {
+		return attributes, nil
+	}
+	err := dbFor(ctx, r.db).
+		Where("category_id IN ?", categoryIDs).
+		Order("sort_order, name").
+		Find(&attributes).Error
+	return attributes, err
+}
+
+// GetFilterable retrieves every attribute marked as filterable, in any category
+func (r *GormCategoryAttributeRepository) GetFilterable(ctx context.Context) ([]*entities.CategoryAttribute, error) {
+	var attributes []*entities.CategoryAttribute
+	err := dbFor(ctx, r.db).
+		Where("filterable = ?", true).
+		Order("sort_order, name").
+		Find(&attributes).Error
+	return attributes, err
+}
+
+// ExistsByName checks if a category defines an attribute directly
+func (r *GormCategoryAttributeRepository) ExistsByName(ctx context.Context, categoryID uuid.UUID, name string) (bool, error) {
+	var count int64
+	err := dbFor(ctx, r.db).
+		Model(&entities.CategoryAttribute{}).
+		Where("category_id = ? AND name = ?", categoryID, name).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,294 @@
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// maxCategoryDepth bounds ancestor walks so a corrupted parent chain cannot loop forever
+const maxCategoryDepth = 100
+
+// GormCategoryRepository implements CategoryRepository using GORM
+type GormCategoryRepository struct {
+	db *gorm.DB
+}
+
+// NewGormCategoryRepository creates a new GORM category repository
+func NewGormCategoryRepository(db *gorm.DB) repositories.CategoryRepository {
+	return &GormCategoryRepository{db: db}
+}
+
+// Create creates a new category
+func (r *GormCategoryRepository) Create(ctx context.Context, category *entities.Category) error {
+	return dbFor(ctx, r.db).Omit(clause.Associations).Create(category).Error
+}
+
+// GetByID retrieves a category by ID
+func (r *GormCategoryRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Category, error) {
+	var category entities.Category
+	err := dbFor(ctx, r.db).Where("id = ?", id).First(&category).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+// GetBySlug retrieves a category by slug
+func (r *GormCategoryRepository) GetBySlug(ctx context.Context, slug string) (*entities.Category, error) {
+	var category entities.Category
+	err := dbFor(ctx, r.db).Where("slug = ?", slug).First(&category).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+// Update updates a category
+func (r *GormCategoryRepository) Update(ctx context.Context, category *entities.Category) error {
+	return dbFor(ctx, r.db).Omit(clause.Associations).Save(category).Error
+}
+
+// Delete deletes a category together with its attribute schema
+func (r *GormCategoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("category_id = ?", id).Delete(&entities.CategoryAttribute{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entities.Category{}, id).Error
+	})
+}
+
+// GetRootCategories retrieves top-level categories
+func (r *GormCategoryRepository) GetRootCategories(ctx context.Context) ([]*entities.Category, error) {
+	var categories []*entities.Category
+	err := dbFor(ctx, r.db).
+		Where("parent_id IS NULL").
+		Order("sort_order, name").
+		Find(&categories).Error
+	return categories, err
+}
+
+// GetChildren retrieves the direct children of a category
+func (r *GormCategoryRepository) GetChildren(ctx context.Context, parentID uuid.UUID) ([]*entities.Category, error) {
+	var categories []*entities.Category
+	err := dbFor(ctx, r.db).
+		Where("parent_id = ?", parentID).
+		Order("sort_order, name").
+		Find(&categories).Error
+	return categories, err
+}
+
+// GetByParent retrieves the children of a category, or the root categories for a nil parent
+func (r *GormCategoryRepository) GetByParent(ctx context.Context, parentID *uuid.UUID) ([]*entities.Category, error) {
+	if parentID == nil {
+		return r.GetRootCategories(ctx)
+	}
+	return r.GetChildren(ctx, *parentID)
+}
+
+// GetByLevel retrieves categories at a depth of the tree
+func (r *GormCategoryRepository) GetByLevel(ctx context.Context, level int) ([]*entities.Category, error) {
+	var categories []*entities.Category
+	err := dbFor(ctx, r.db).
+		Where("level = ?", level).
+		Order("sort_order, name").
+		Find(&categories).Error
+	return categories, err
+}
+
+// GetPath retrieves a category and its ancestors, ordered from the root down
+func (r *GormCategoryRepository) GetPath(ctx context.Context, id uuid.UUID) ([]*entities.Category, error) {
+	var categories []*entities.Category
+	err := dbFor(ctx, r.db).Raw(`
+		WITH RECURSIVE ancestors AS (
+			SELECT categories.*, 0 AS depth FROM categories WHERE id = ?
+			UNION ALL
+			SELECT c.*, a.depth + 1 FROM categories c
+			JOIN ancestors a ON c.id = a.parent_id
+			WHERE a.depth < ?
+		)
+		SELECT * FROM ancestors ORDER BY depth DESC`, id, maxCategoryDepth).
+		Scan(&categories).Error
+	return categories, err
+}
+
+// GetAll retrieves all categories
+func (r *GormCategoryRepository) GetAll(ctx context.Context) ([]*entities.Category, error) {
+	var categories []*entities.Category
+	err := dbFor(ctx, r.db).
+		Order("level, sort_order, name").
+		Find(&categories).Error
+	return categories, err
+}
+
+// GetActive retrieves active categories
+func (r *GormCategoryRepository) GetActive(ctx context.Context) ([]*entities.Category, error) {
+	var categories []*entities.Category
+	err := dbFor(ctx, r.db).
+		Where("is_active = ?", true).
+		Order("level, sort_order, name").
+		Find(&categories).Error
+	return categories, err
+}
+
+// GetVisible retrieves active, visible categories
+func (r *GormCategoryRepository) GetVisible(ctx context.Context) ([]*entities.Category, error) {
+	var categories []*entities.Category
+	err := dbFor(ctx, r.db).
+		Where("is_active = ? AND is_visible = ?", true, true).
+		Order("level, sort_order, name").
+		Find(&categories).Error
+	return categories, err
+}
+
+// GetTree retrieves every category ordered parents-first, ready to be assembled into a tree
+func (r *GormCategoryRepository) GetTree(ctx context.Context) ([]*entities.Category, error) {
+	return r.GetAll(ctx)
+}
+
+// GetSubTree retrieves a category and all its descendants, ordered parents-first. It
+// follows parent_id rather than Path, so it is correct even when paths are stale.
+func (r *GormCategoryRepository) GetSubTree(ctx context.Context, rootID uuid.UUID) ([]*entities.Category, error) {
+	var categories []*entities.Category
+	err := dbFor(ctx, r.db).Raw(`
+		WITH RECURSIVE subtree AS (
+			SELECT categories.*, 0 AS depth FROM categories WHERE id = ?
+			UNION ALL
+			SELECT c.*, s.depth + 1 FROM categories c
+			JOIN subtree s ON c.parent_id = s.id
+			WHERE s.depth < ?
+		)
+		SELECT * FROM subtree ORDER BY depth, sort_order, name`, rootID, maxCategoryDepth).
+		Scan(&categories).Error
+	return categories, err
+}
+
+// UpdateTree saves a set of categories, such as a moved subtree, in one transaction
+func (r *GormCategoryRepository) UpdateTree(ctx context.Context, categories []*entities.Category) error {
+	return dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		for _, category := range categories {
+			if err := tx.Omit(clause.Associations).Save(category).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// LockHierarchy locks the subtree under rootID and the categories on the path down to
+// pathTo until the transaction ends, so tree reads made after it stay valid until the
+// tree is written back. Either may be nil. Rows are locked in ID order, so concurrent
+// moves wait for each other instead of deadlocking.
+func (r *GormCategoryRepository) LockHierarchy(ctx context.Context, rootID *uuid.UUID, pathTo *uuid.UUID) error {
+	var root, leaf interface{}
+	if rootID != nil {
+		root = *rootID
+	}
+	if pathTo != nil {
+		leaf = *pathTo
+	}
+	var ids []uuid.UUID
+	return dbFor(ctx, r.db).Raw(`
+		WITH RECURSIVE subtree AS (
+			SELECT id, 0 AS depth FROM categories WHERE id = ?
+			UNION ALL
+			SELECT c.id, s.depth + 1 FROM categories c
+			JOIN subtree s ON c.parent_id = s.id
+			WHERE s.depth < ?
+		), ancestors AS (
+			SELECT id, parent_id, 0 AS depth FROM categories WHERE id = ?
+			UNION ALL
+			SELECT c.id, c.parent_id, a.depth + 1 FROM categories c
+			JOIN ancestors a ON c.id = a.parent_id
+			WHERE a.depth < ?
+		)
+		SELECT id FROM categories
+		WHERE id IN (SELECT id FROM subtree UNION SELECT id FROM ancestors)
+		ORDER BY id FOR UPDATE`, root, maxCategoryDepth, leaf, maxCategoryDepth).
+		Scan(&ids).Error
+}
+
+// Count counts all categories
+func (r *GormCategoryRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	err := dbFor(ctx, r.db).Model(&entities.Category{}).Count(&count).Error
+	return count, err
+}
+
+// CountByParent counts the children of a category, or the root categories for a nil parent
+func (r *GormCategoryRepository) CountByParent(ctx context.Context, parentID *uuid.UUID) (int64, error) {
+	var count int64
+	query := dbFor(ctx, r.db).Model(&entities.Category{})
+	if parentID == nil {
+		query = query.Where("parent_id IS NULL")
+	} else {
+		query = query.Where("parent_id = ?", *parentID)
+	}
+	err := query.Count(&count).Error
+	return count, err
+}
+
+// ExistsBySlug checks if a category exists by slug
+func (r *GormCategoryRepository) ExistsBySlug(ctx context.Context, slug string) (bool, error) {
+	var count int64
+	err := dbFor(ctx, r.db).
+		Model(&entities.Category{}).
+		Where("slug = ?", slug).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// HasChildren checks if a category has subcategories
+func (r *GormCategoryRepository) HasChildren(ctx context.Context, id uuid.UUID) (bool, error) {
+	var count int64
+	err := dbFor(ctx, r.db).
+		Model(&entities.Category{}).
+		Where("parent_id = ?", id).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// HasProducts checks if any product is assigned to a category
+func (r *GormCategoryRepository) HasProducts(ctx context.Context, id uuid.UUID) (bool, error) {
+	var count int64
+	err := dbFor(ctx, r.db).
+		Model(&entities.Product{}).
+		Where("category_id = ?", id).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// UpdateProductCount sets a category's product count
+func (r *GormCategoryRepository) UpdateProductCount(ctx context.Context, id uuid.UUID, count int) error {
+	return dbFor(ctx, r.db).
+		Model(&entities.Category{}).
+		Where("id = ?", id).
+		Update("product_count", count).Error
+}
+
+// IncrementProductCount increments a category's product count
+func (r *GormCategoryRepository) IncrementProductCount(ctx context.Context, id uuid.UUID) error {
+	return dbFor(ctx, r.db).
+		Model(&entities.Category{}).
+		Where("id = ?", id).
+		Update("product_count", gorm.Expr("product_count + 1")).Error
+}
+
+// DecrementProductCount decrements a category's product count
+func (r *GormCategoryRepository) DecrementProductCount(ctx context.Context, id uuid.UUID) error {
+	return dbFor(ctx, r.db).
+		Model(&entities.Category{}).
+		Where("id = ? AND product_count > 0", id).
+		Update("product_count", gorm.Expr("product_count - 1")).Error
+}
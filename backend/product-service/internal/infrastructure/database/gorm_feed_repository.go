@@ -0,0 +1,91 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// GormFeedRepository implements FeedRepository using GORM
+type GormFeedRepository struct {
+	db *gorm.DB
+}
+
+// NewGormFeedRepository creates a new GORM feed repository
+func NewGormFeedRepository(db *gorm.DB) repositories.FeedRepository {
+	return &GormFeedRepository{db: db}
+}
+
+// StreamListedProducts loads listed products in batches (keyed by ID, as FindInBatches
+// requires)
+func (r *GormFeedRepository) StreamListedProducts(ctx context.Context, batchSize int, fn func(products []*entities.Product) error) error {
+	var batch []*entities.Product
+	return dbFor(ctx, r.db).
+		Where("status = ? AND visibility <> ? AND deleted_at IS NULL", entities.ProductStatusActive, entities.VisibilityHidden).
+		Preload("Brand").
+		Preload("Images", func(db *gorm.DB) *gorm.DB {
+			return db.Where("variant_id IS NULL AND deleted_at IS NULL").Order("is_primary DESC, sort_order")
+		}).
+		Preload("Variants", func(db *gorm.DB) *gorm.DB {
+			return db.Where("is_active = ? AND deleted_at IS NULL", true).Order("sort_order, sku")
+		}).
+		Preload("Variants.Images", func(db *gorm.DB) *gorm.DB {
+			return db.Where("deleted_at IS NULL").Order("is_primary DESC, sort_order")
+		}).
+		FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+			return fn(batch)
+		}).Error
+}
+
+// GetFeedVersion counts the listed items and finds the latest change among them. Every
+// product counts, as unlisting one changes the feed too. A sale changes prices when it
+// starts and ends as well as when it is edited, and a product's availability changes
+// when its availability window opens and closes, so passed start and end times count
+// as changes.
+func (r *GormFeedRepository) GetFeedVersion(ctx context.Context) (*repositories.FeedVersion, error) {
+	var row struct {
+		Items        int64
+		LastModified *time.Time
+	}
+	err := dbFor(ctx, r.db).Raw(`
+		WITH listed AS (
+			SELECT id, brand_id FROM products
+			WHERE status = @active AND visibility <> @hidden AND deleted_at IS NULL
+		)
+		SELECT
+			(SELECT COUNT(*) FROM listed) +
+			(SELECT COUNT(*) FROM product_variants v JOIN listed ON listed.id = v.product_id
+				WHERE v.is_active AND v.deleted_at IS NULL) AS items,
+			GREATEST(
+				(SELECT MAX(updated_at) FROM products),
+				(SELECT MAX(v.updated_at) FROM product_variants v JOIN listed ON listed.id = v.product_id),
+				(SELECT MAX(i.updated_at) FROM product_images i JOIN listed ON listed.id = i.product_id),
+				(SELECT MAX(b.updated_at) FROM brands b WHERE b.id IN (SELECT brand_id FROM listed)),
+				(SELECT MAX(GREATEST(
+					CASE WHEN available_from <= @now THEN available_from END,
+					CASE WHEN available_until <= @now THEN available_until END
+				)) FROM products WHERE deleted_at IS NULL),
+				(SELECT MAX(GREATEST(
+					updated_at,
+					CASE WHEN starts_at <= @now THEN starts_at END,
+					CASE WHEN ends_at <= @now THEN ends_at END
+				)) FROM sales)
+			) AS last_modified`,
+		map[string]interface{}{
+			"active": entities.ProductStatusActive,
+			"hidden": entities.VisibilityHidden,
+			"now":    time.Now(),
+		}).Scan(&row).Error
+	if err != nil {
+		return nil, err
+	}
+
+	version := &repositories.FeedVersion{Items: row.Items}
+	if row.LastModified != nil {
+		version.LastModified = *row.LastModified
+	}
+	return version, nil
+}
@@ -0,0 +1,147 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// GormPriceListRepository implements PriceListRepository using GORM
+type GormPriceListRepository struct {
+	db *gorm.DB
+}
+
+// NewGormPriceListRepository creates a new GORM price list repository
+func NewGormPriceListRepository(db *gorm.DB) repositories.PriceListRepository {
+	return &GormPriceListRepository{db: db}
+}
+
+// Create creates a new price list
+func (r *GormPriceListRepository) Create(ctx context.Context, list *entities.PriceList) error {
+	return dbFor(ctx, r.db).Create(list).Error
+}
+
+// GetByID retrieves a price list by ID
+func (r *GormPriceListRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.PriceList, error) {
+	var list entities.PriceList
+	err := dbFor(ctx, r.db).Where("id = ?", id).First(&list).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &list, nil
+}
+
+// GetByKey retrieves the price list for a currency, channel and customer group
+func (r *GormPriceListRepository) GetByKey(ctx context.Context, currency, channel, customerGroup string) (*entities.PriceList, error) {
+	var list entities.PriceList
+	err := dbFor(ctx, r.db).
+		Where("currency = ? AND channel = ? AND customer_group = ?", currency, channel, customerGroup).
+		First(&list).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &list, nil
+}
+
+// Update updates a price list
+func (r *GormPriceListRepository) Update(ctx context.Context, list *entities.PriceList) error {
+	return dbFor(ctx, r.db).Save(list).Error
+}
+
+// Delete deletes a price list and its entries
+func (r *GormPriceListRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("price_list_id = ?", id).Delete(&entities.PriceListEntry{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entities.PriceList{}, id).Error
+	})
+}
+
+// List retrieves all price lists
+func (r *GormPriceListRepository) List(ctx context.Context) ([]*entities.PriceList, error) {
+	var lists []*entities.PriceList
+	err := dbFor(ctx, r.db).
+		Order("currency, channel, customer_group").
+		Find(&lists).Error
+	return lists, err
+}
+
+// CreateEntry creates a new price list entry
+func (r *GormPriceListRepository) CreateEntry(ctx context.Context, entry *entities.PriceListEntry) error {
+	return dbFor(ctx, r.db).Create(entry).Error
+}
+
+// GetEntry retrieves a price list entry by ID
+func (r *GormPriceListRepository) GetEntry(ctx context.Context, id uuid.UUID) (*entities.PriceListEntry, error) {
+	var entry entities.PriceListEntry
+	err := dbFor(ctx, r.db).Where("id = ?", id).First(&entry).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// UpdateEntry updates a price list entry
+func (r *GormPriceListRepository) UpdateEntry(ctx context.Context, entry *entities.PriceListEntry) error {
+	return dbFor(ctx, r.db).Omit("PriceList").Save(entry).Error
+}
+
+// DeleteEntry deletes a price list entry
+func (r *GormPriceListRepository) DeleteEntry(ctx context.Context, id uuid.UUID) error {
+	return dbFor(ctx, r.db).Delete(&entities.PriceListEntry{}, id).Error
+}
+
+// GetEntries retrieves a price list's entries
+func (r *GormPriceListRepository) GetEntries(ctx context.Context, priceListID uuid.UUID, limit, offset int) ([]*entities.PriceListEntry, error) {
+	var entries []*entities.PriceListEntry
+	err := dbFor(ctx, r.db).
+		Where("price_list_id = ?", priceListID).
+		Order("product_id, variant_id NULLS FIRST, valid_from NULLS FIRST").
+		Limit(limit).
+		Offset(offset).
+		Find(&entries).Error
+	return entries, err
+}
+
+// CountEntries counts a price list's entries
+func (r *GormPriceListRepository) CountEntries(ctx context.Context, priceListID uuid.UUID) (int64, error) {
+	var count int64
+	err := dbFor(ctx, r.db).
+		Model(&entities.PriceListEntry{}).
+		Where("price_list_id = ?", priceListID).
+		Count(&count).Error
+	return count, err
+}
+
+// FindApplicable retrieves the entries that apply to products in a pricing context
+func (r *GormPriceListRepository) FindApplicable(ctx context.Context, productIDs []uuid.UUID, currency, channel, customerGroup string, at time.Time) ([]*entities.PriceListEntry, error) {
+	if len(productIDs) == 0 {
+		return nil, nil
+	}
+
+	var entries []*entities.PriceListEntry
+	err := dbFor(ctx, r.db).
+		Joins("PriceList").
+		Where("price_list_entries.product_id IN ?", productIDs).
+		Where(`"PriceList".is_active = ? AND "PriceList".currency = ?`, true, currency).
+		Where(`"PriceList".channel IN ?`, []string{"", channel}).
+		Where(`"PriceList".customer_group IN ?`, []string{"", customerGroup}).
+		Where("price_list_entries.valid_from IS NULL OR price_list_entries.valid_from <= ?", at).
+		Where("price_list_entries.valid_until IS NULL OR price_list_entries.valid_until > ?", at).
+		Find(&entries).Error
+	return entries, err
+}
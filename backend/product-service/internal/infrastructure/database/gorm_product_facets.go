@@ -0,0 +1,303 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// maxFacetValues caps the number of buckets returned for open-ended facets (tags, attributes)
+const maxFacetValues = 50
+
+// priceBucket is a half-open price range [Min, Max); Max of 0 means unbounded
+type priceBucket struct {
+	Min float64
+	Max float64
+}
+
+// priceFacetBuckets defines the price ranges offered in the price facet
+var priceFacetBuckets = []priceBucket{
+	{Min: 0, Max: 25},
+	{Min: 25, Max: 50},
+	{Min: 50, Max: 100},
+	{Min: 100, Max: 250},
+	{Min: 250, Max: 500},
+	{Min: 500},
+}
+
+// value encodes the bucket as "min-max" (or "min-" when unbounded) for use as a filter
+func (b priceBucket) value() string {
+	if b.Max == 0 {
+		return fmt.Sprintf("%g-", b.Min)
+	}
+	return fmt.Sprintf("%g-%g", b.Min, b.Max)
+}
+
+// label renders the bucket for display
+func (b priceBucket) label() string {
+	if b.Max == 0 {
+		return fmt.Sprintf("%g+", b.Min)
+	}
+	return fmt.Sprintf("%g - %g", b.Min, b.Max)
+}
+
+// facetRow is the scan target for facet aggregation queries
+type facetRow struct {
+	Key   string
+	Value string
+	Label string
+	Count int64
+}
+
+// GetSearchFacets computes facet counts for a search. Counts are disjunctive: each facet
+// is computed under every active filter except its own, so selecting "Nike" still shows
+// how many products the other brands would add. Attribute facets are limited to
+// attributeKeys unless it is nil.
+func (r *GormProductRepository) GetSearchFacets(ctx context.Context, query string, filters *repositories.ProductFilters, attributeKeys []string) (map[string][]repositories.FacetValue, error) {
+	if filters == nil {
+		filters = &repositories.ProductFilters{}
+	}
+
+	facets := make(map[string][]repositories.FacetValue)
+
+	// Category
+	withoutCategory := *filters
+	withoutCategory.CategoryIDs = nil
+	var rows []facetRow
+	err := dbFor(ctx, r.db).
+		Table("(?) AS f", r.matchingProducts(ctx, query, &withoutCategory).
+			Select("CAST(category_id AS text) AS value, COUNT(*) AS count").
+			Group("category_id")).
+		Select("f.value, f.count, c.name AS label").
+		Joins("LEFT JOIN categories c ON CAST(c.id AS text) = f.value").
+		Order("f.count DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute category facet: %w", err)
+	}
+	facets[repositories.FacetCategory] = toFacetValues(rows)
+
+	// Brand
+	withoutBrand := *filters
+	withoutBrand.BrandIDs = nil
+	rows = nil
+	err = dbFor(ctx, r.db).
+		Table("(?) AS f", r.matchingProducts(ctx, query, &withoutBrand).
+			Select("CAST(brand_id AS text) AS value, COUNT(*) AS count").
+			Where("brand_id IS NOT NULL").
+			Group("brand_id")).
+		Select("f.value, f.count, b.name AS label").
+		Joins("LEFT JOIN brands b ON CAST(b.id AS text) = f.value").
+		Order("f.count DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute brand facet: %w", err)
+	}
+	facets[repositories.FacetBrand] = toFacetValues(rows)
+
+	// Price
+	withoutPrice := *filters
+	withoutPrice.MinPrice = nil
+	withoutPrice.MaxPrice = nil
+	caseSQL, caseArgs := priceBucketCaseSQL()
+	rows = nil
+	err = r.matchingProducts(ctx, query, &withoutPrice).
+		Select(caseSQL+" AS value, COUNT(*) AS count", caseArgs...).
+		Group("value").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute price facet: %w", err)
+	}
+	facets[repositories.FacetPrice] = priceFacetValues(rows)
+
+	// Stock state
+	withoutStock := *filters
+	withoutStock.InStock = nil
+	rows = nil
+	err = r.matchingProducts(ctx, query, &withoutStock).
+		Select("CASE WHEN track_stock = false OR stock_quantity > 0 THEN 'in_stock' ELSE 'out_of_stock' END AS value, COUNT(*) AS count").
+		Group("value").
+		Order("value").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute stock facet: %w", err)
+	}
+	facets[repositories.FacetStock] = toFacetValues(rows)
+
+	// Tags
+	withoutTags := *filters
+	withoutTags.Tags = nil
+	rows = nil
+	err = dbFor(ctx, r.db).
+		Table("(?) AS t", r.matchingProducts(ctx, query, &withoutTags).Select("unnest(tags) AS value")).
+		Select("value, COUNT(*) AS count").
+		Group("value").
+		Order("count DESC, value").
+		Limit(maxFacetValues).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute tags facet: %w", err)
+	}
+	facets[repositories.FacetTags] = toFacetValues(rows)
+
+	// Attributes
+	attributeFacets, err := r.attributeFacets(ctx, query, filters, attributeKeys)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range attributeFacets {
+		facets[repositories.FacetAttributePrefix+key] = values
+	}
+
+	return facets, nil
+}
+
+// attributeFacets counts values for the JSONB attribute keys, or for every key when keys
+// is nil. Keys without an active filter share one aggregation under the full filter set;
+// each filtered key gets its own aggregation with only that key's filter removed.
+func (r *GormProductRepository) attributeFacets(ctx context.Context, query string, filters *repositories.ProductFilters, keys []string) (map[string][]repositories.FacetValue, error) {
+	if keys != nil && len(keys) == 0 {
+		return nil, nil
+	}
+
+	wanted := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		wanted[key] = true
+	}
+
+	filteredKeys := make([]string, 0, len(filters.Attributes))
+	for key := range filters.Attributes {
+		if keys == nil || wanted[key] {
+			filteredKeys = append(filteredKeys, key)
+		}
+	}
+
+	byKey := make(map[string][]facetRow)
+	var rows []facetRow
+	db := dbFor(ctx, r.db).
+		Table("(?) AS p, jsonb_each_text(p.attributes) AS kv", r.matchingProducts(ctx, query, filters).Select("products.attributes")).
+		Select("kv.key, kv.value, COUNT(*) AS count")
+	if keys != nil {
+		db = db.Where("kv.key IN ?", keys)
+	}
+	if len(filteredKeys) > 0 {
+		db = db.Where("kv.key NOT IN ?", filteredKeys)
+	}
+	err := db.Group("kv.key, kv.value").Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute attribute facets: %w", err)
+	}
+	for _, row := range rows {
+		byKey[row.Key] = append(byKey[row.Key], row)
+	}
+
+	for _, key := range filteredKeys {
+		withoutKey := *filters
+		withoutKey.Attributes = make(map[string]string, len(filters.Attributes)-1)
+		for k, v := range filters.Attributes {
+			if k != key {
+				withoutKey.Attributes[k] = v
+			}
+		}
+
+		rows = nil
+		err := dbFor(ctx, r.db).
+			Table("(?) AS p", r.matchingProducts(ctx, query, &withoutKey).Select("products.attributes")).
+			Select("? AS key, p.attributes ->> ? AS value, COUNT(*) AS count", key, key).
+			Where("p.attributes ->> ? IS NOT NULL", key).
+			Group("value").
+			Scan(&rows).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute %s attribute facet: %w", key, err)
+		}
+		byKey[key] = rows
+	}
+
+	facets := make(map[string][]repositories.FacetValue, len(byKey))
+	for key, keyRows := range byKey {
+		sort.Slice(keyRows, func(i, j int) bool {
+			if keyRows[i].Count != keyRows[j].Count {
+				return keyRows[i].Count > keyRows[j].Count
+			}
+			return keyRows[i].Value < keyRows[j].Value
+		})
+		if len(keyRows) > maxFacetValues {
+			keyRows = keyRows[:maxFacetValues]
+		}
+		facets[key] = toFacetValues(keyRows)
+	}
+
+	return facets, nil
+}
+
+// matchingProducts returns a products query restricted by the text query and filters,
+// using the same matching as Search and CountSearch
+func (r *GormProductRepository) matchingProducts(ctx context.Context, query string, filters *repositories.ProductFilters) *gorm.DB {
+	db := dbFor(ctx, r.db).Model(&entities.Product{})
+
+	terms := searchTerms(query)
+	if tsQuery := buildPrefixTSQuery(terms); tsQuery != "" {
+		db = applyTextSearch(db, tsQuery, terms)
+	}
+
+	return r.applyFilters(db, filters)
+}
+
+// priceBucketCaseSQL builds a CASE expression mapping price to its bucket value
+func priceBucketCaseSQL() (string, []interface{}) {
+	var sql strings.Builder
+	args := make([]interface{}, 0, len(priceFacetBuckets)*3)
+
+	sql.WriteString("CASE")
+	for _, bucket := range priceFacetBuckets {
+		if bucket.Max == 0 {
+			sql.WriteString(" WHEN price >= ? THEN ?")
+			args = append(args, bucket.Min, bucket.value())
+		} else {
+			sql.WriteString(" WHEN price >= ? AND price < ? THEN ?")
+			args = append(args, bucket.Min, bucket.Max, bucket.value())
+		}
+	}
+	sql.WriteString(" END")
+
+	return sql.String(), args
+}
+
+// priceFacetValues orders price rows by bucket and fills in display labels
+func priceFacetValues(rows []facetRow) []repositories.FacetValue {
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Value] = row.Count
+	}
+
+	values := make([]repositories.FacetValue, 0, len(priceFacetBuckets))
+	for _, bucket := range priceFacetBuckets {
+		if count := counts[bucket.value()]; count > 0 {
+			values = append(values, repositories.FacetValue{
+				Value: bucket.value(),
+				Label: bucket.label(),
+				Count: count,
+			})
+		}
+	}
+
+	return values
+}
+
+// toFacetValues converts scanned rows into facet values
+func toFacetValues(rows []facetRow) []repositories.FacetValue {
+	values := make([]repositories.FacetValue, len(rows))
+	for i, row := range rows {
+		values[i] = repositories.FacetValue{
+			Value: row.Value,
+			Label: row.Label,
+			Count: row.Count,
+		}
+	}
+	return values
+}
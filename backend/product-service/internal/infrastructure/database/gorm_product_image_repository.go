@@ -0,0 +1,210 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// GormProductImageRepository implements ProductImageRepository using GORM. Product
+// images are those with a product ID and no variant ID; variant images also carry the
+// ID of their product.
+type GormProductImageRepository struct {
+	db *gorm.DB
+}
+
+// NewGormProductImageRepository creates a new GORM product image repository
+func NewGormProductImageRepository(db *gorm.DB) repositories.ProductImageRepository {
+	return &GormProductImageRepository{db: db}
+}
+
+// Create creates a new product image
+func (r *GormProductImageRepository) Create(ctx context.Context, image *entities.ProductImage) error {
+	return dbFor(ctx, r.db).Create(image).Error
+}
+
+// GetByID retrieves a product image by ID
+func (r *GormProductImageRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.ProductImage, error) {
+	return r.first(dbFor(ctx, r.db).Where("id = ?", id))
+}
+
+// Update updates a product image
+func (r *GormProductImageRepository) Update(ctx context.Context, image *entities.ProductImage) error {
+	return dbFor(ctx, r.db).Save(image).Error
+}
+
+// Delete deletes a product image
+func (r *GormProductImageRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return dbFor(ctx, r.db).Delete(&entities.ProductImage{}, id).Error
+}
+
+// GetByProductID retrieves the images of a product, excluding variant images
+func (r *GormProductImageRepository) GetByProductID(ctx context.Context, productID uuid.UUID) ([]*entities.ProductImage, error) {
+	var images []*entities.ProductImage
+	err := r.productImages(dbFor(ctx, r.db), productID).
+		Order("sort_order, created_at").
+		Find(&images).Error
+	return images, err
+}
+
+// GetByVariantID retrieves the images of a variant
+func (r *GormProductImageRepository) GetByVariantID(ctx context.Context, variantID uuid.UUID) ([]*entities.ProductImage, error) {
+	var images []*entities.ProductImage
+	err := dbFor(ctx, r.db).
+		Where("variant_id = ?", variantID).
+		Order("sort_order, created_at").
+		Find(&images).Error
+	return images, err
+}
+
+// GetPrimaryByProductID retrieves the primary image of a product
+func (r *GormProductImageRepository) GetPrimaryByProductID(ctx context.Context, productID uuid.UUID) (*entities.ProductImage, error) {
+	return r.first(r.productImages(dbFor(ctx, r.db), productID).Where("is_primary = ?", true))
+}
+
+// GetPrimaryByVariantID retrieves the primary image of a variant
+func (r *GormProductImageRepository) GetPrimaryByVariantID(ctx context.Context, variantID uuid.UUID) (*entities.ProductImage, error) {
+	return r.first(dbFor(ctx, r.db).Where("variant_id = ? AND is_primary = ?", variantID, true))
+}
+
+// GetByContentHash retrieves the image of a product or variant with a content hash
+func (r *GormProductImageRepository) GetByContentHash(ctx context.Context, productID uuid.UUID, variantID *uuid.UUID, hash string) (*entities.ProductImage, error) {
+	query := dbFor(ctx, r.db).Where("content_hash = ?", hash)
+	if variantID != nil {
+		query = query.Where("variant_id = ?", *variantID)
+	} else {
+		query = r.productImages(query, productID)
+	}
+	return r.first(query)
+}
+
+// CreateBulk creates multiple product images in a single transaction
+func (r *GormProductImageRepository) CreateBulk(ctx context.Context, images []*entities.ProductImage) error {
+	if len(images) == 0 {
+		return nil
+	}
+	return dbFor(ctx, r.db).CreateInBatches(images, 100).Error
+}
+
+// UpdateBulk updates multiple product images in a single transaction
+func (r *GormProductImageRepository) UpdateBulk(ctx context.Context, images []*entities.ProductImage) error {
+	return dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		for _, image := range images {
+			if err := tx.Save(image).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteByProductID deletes all images of a product, including its variant images
+func (r *GormProductImageRepository) DeleteByProductID(ctx context.Context, productID uuid.UUID) error {
+	return dbFor(ctx, r.db).
+		Where("product_id = ?", productID).
+		Delete(&entities.ProductImage{}).Error
+}
+
+// DeleteByVariantID deletes all images of a variant
+func (r *GormProductImageRepository) DeleteByVariantID(ctx context.Context, variantID uuid.UUID) error {
+	return dbFor(ctx, r.db).
+		Where("variant_id = ?", variantID).
+		Delete(&entities.ProductImage{}).Error
+}
+
+// UpdateSortOrder sets the position of an image
+func (r *GormProductImageRepository) UpdateSortOrder(ctx context.Context, id uuid.UUID, sortOrder int) error {
+	return dbFor(ctx, r.db).
+		Model(&entities.ProductImage{}).
+		Where("id = ?", id).
+		Update("sort_order", sortOrder).Error
+}
+
+// ReorderImages sets the positions of the images of a product or variant to the order
+// of imageIDs, which must list each of its images exactly once
+func (r *GormProductImageRepository) ReorderImages(ctx context.Context, productID *uuid.UUID, variantID *uuid.UUID, imageIDs []uuid.UUID) error {
+	return dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&entities.ProductImage{})
+		switch {
+		case variantID != nil:
+			query = query.Where("variant_id = ?", *variantID)
+		case productID != nil:
+			query = r.productImages(query, *productID)
+		default:
+			return errors.New("either product ID or variant ID is required")
+		}
+
+		var ids []uuid.UUID
+		if err := query.Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if !sameIDs(ids, imageIDs) {
+			return errors.New("image IDs must list every image exactly once")
+		}
+
+		for i, id := range imageIDs {
+			if err := tx.Model(&entities.ProductImage{}).Where("id = ?", id).Update("sort_order", i).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CountByProductID counts the images of a product, excluding variant images
+func (r *GormProductImageRepository) CountByProductID(ctx context.Context, productID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.productImages(dbFor(ctx, r.db).Model(&entities.ProductImage{}), productID).
+		Count(&count).Error
+	return count, err
+}
+
+// CountByVariantID counts the images of a variant
+func (r *GormProductImageRepository) CountByVariantID(ctx context.Context, variantID uuid.UUID) (int64, error) {
+	var count int64
+	err := dbFor(ctx, r.db).
+		Model(&entities.ProductImage{}).
+		Where("variant_id = ?", variantID).
+		Count(&count).Error
+	return count, err
+}
+
+// productImages narrows a query to a product's own images
+func (r *GormProductImageRepository) productImages(query *gorm.DB, productID uuid.UUID) *gorm.DB {
+	return query.Where("product_id = ? AND variant_id IS NULL", productID)
+}
+
+// first returns the first image a query finds, or nil
+func (r *GormProductImageRepository) first(query *gorm.DB) (*entities.ProductImage, error) {
+	var image entities.ProductImage
+	err := query.First(&image).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &image, nil
+}
+
+// sameIDs checks if two lists hold the same IDs, each once
+func sameIDs(a, b []uuid.UUID) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[uuid.UUID]int, len(a))
+	for _, id := range a {
+		counts[id]++
+	}
+	for _, id := range b {
+		counts[id]--
+		if counts[id] < 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// GormProductRelationRepository implements ProductRelationRepository using GORM
+type GormProductRelationRepository struct {
+	db *gorm.DB
+}
+
+// NewGormProductRelationRepository creates a new GORM product relation repository
+func NewGormProductRelationRepository(db *gorm.DB) repositories.ProductRelationRepository {
+	return &GormProductRelationRepository{db: db}
+}
+
+// GetByProduct returns a product's relations of a type, or of every type, in order
+func (r *GormProductRelationRepository) GetByProduct(ctx context.Context, productID uuid.UUID, relationType entities.RelationType) ([]*entities.ProductRelation, error) {
+	query := dbFor(ctx, r.db).
+		Preload("RelatedProduct").
+		Where("product_id = ?", productID)
+	if relationType != "" {
+		query = query.Where("type = ?", relationType)
+	}
+
+	var relations []*entities.ProductRelation
+	err := query.Order("type, sort_order").Find(&relations).Error
+	return relations, err
+}
+
+// ReplaceRelations replaces a product's relations of a type in a single transaction
+func (r *GormProductRelationRepository) ReplaceRelations(ctx context.Context, productID uuid.UUID, relationType entities.RelationType, relations []*entities.ProductRelation) error {
+	return dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("product_id = ? AND type = ?", productID, relationType).Delete(&entities.ProductRelation{}).Error; err != nil {
+			return err
+		}
+		if len(relations) == 0 {
+			return nil
+		}
+		return tx.Omit("Product", "RelatedProduct").Create(relations).Error
+	})
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"product-service/internal/domain/entities"
 	"product-service/internal/domain/repositories"
 )
@@ -14,6 +17,9 @@ import (
 // GormProductRepository implements ProductRepository using GORM
 type GormProductRepository struct {
 	db *gorm.DB
+
+	trigramsMu sync.Mutex
+	trigrams   *bool
 }
 
 // NewGormProductRepository creates a new GORM product repository
@@ -23,13 +29,13 @@ func NewGormProductRepository(db *gorm.DB) repositories.ProductRepository {
 
 // Create creates a new product
 func (r *GormProductRepository) Create(ctx context.Context, product *entities.Product) error {
-	return r.db.WithContext(ctx).Create(product).Error
+	return dbFor(ctx, r.db).Create(product).Error
 }
 
 // GetByID retrieves a product by ID
 func (r *GormProductRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Product, error) {
 	var product entities.Product
-	err := r.db.WithContext(ctx).Where("id = ?", id).First(&product).Error
+	err := dbFor(ctx, r.db).Where("id = ?", id).First(&product).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
@@ -42,7 +48,7 @@ func (r *GormProductRepository) GetByID(ctx context.Context, id uuid.UUID) (*ent
 // GetBySKU retrieves a product by SKU
 func (r *GormProductRepository) GetBySKU(ctx context.Context, sku string) (*entities.Product, error) {
 	var product entities.Product
-	err := r.db.WithContext(ctx).Where("sku = ?", sku).First(&product).Error
+	err := dbFor(ctx, r.db).Where("sku = ?", sku).First(&product).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
@@ -55,7 +61,7 @@ func (r *GormProductRepository) GetBySKU(ctx context.Context, sku string) (*enti
 // GetBySlug retrieves a product by slug
 func (r *GormProductRepository) GetBySlug(ctx context.Context, slug string) (*entities.Product, error) {
 	var product entities.Product
-	err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&product).Error
+	err := dbFor(ctx, r.db).Where("slug = ?", slug).First(&product).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
@@ -67,32 +73,60 @@ func (r *GormProductRepository) GetBySlug(ctx context.Context, slug string) (*en
 
 // Update updates a product
 func (r *GormProductRepository) Update(ctx context.Context, product *entities.Product) error {
-	return r.db.WithContext(ctx).Save(product).Error
+	// The version check and bump happen in the UPDATE itself, so of two concurrent
+	// writers that read the same version exactly one succeeds. The rating is kept by the
+	// review repository and never written from a product read before it changed.
+	expected := product.Version
+	product.Version = expected + 1
+	
+	result := dbFor(ctx, r.db).
+		Model(product).
+		Where("version = ?", expected).
+		Select("*").
+		Omit(clause.Associations, "created_at", "average_rating", "review_count").
+		Updates(product)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = repositories.ErrVersionConflict
+	}
+	if result.Error != nil {
+		product.Version = expected
+		return result.Error
+	}
+	return nil
 }
 
-// Delete deletes a product
-func (r *GormProductRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Delete(&entities.Product{}, id).Error
+// Delete deletes a product if it is still at the given version
+func (r *GormProductRepository) Delete(ctx context.Context, id uuid.UUID, version int) error {
+	result := dbFor(ctx, r.db).
+		Where("version = ?", version).
+		Delete(&entities.Product{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrVersionConflict
+	}
+	return nil
 }
 
 // GetByIDs retrieves products by IDs
 func (r *GormProductRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.Product, error) {
 	var products []*entities.Product
-	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&products).Error
+	err := dbFor(ctx, r.db).Where("id IN ?", ids).Find(&products).Error
 	return products, err
 }
 
 // GetBySKUs retrieves products by SKUs
 func (r *GormProductRepository) GetBySKUs(ctx context.Context, skus []string) ([]*entities.Product, error) {
 	var products []*entities.Product
-	err := r.db.WithContext(ctx).Where("sku IN ?", skus).Find(&products).Error
+	err := dbFor(ctx, r.db).Where("sku IN ?", skus).Find(&products).Error
 	return products, err
 }
 
 // GetAll retrieves all products with pagination
 func (r *GormProductRepository) GetAll(ctx context.Context, limit, offset int) ([]*entities.Product, error) {
 	var products []*entities.Product
-	err := r.db.WithContext(ctx).
+	err := dbFor(ctx, r.db).
 		Limit(limit).
 		Offset(offset).
 		Order("created_at DESC").
@@ -103,7 +137,7 @@ func (r *GormProductRepository) GetAll(ctx context.Context, limit, offset int) (
 // GetByCategory retrieves products by category
 func (r *GormProductRepository) GetByCategory(ctx context.Context, categoryID uuid.UUID, limit, offset int) ([]*entities.Product, error) {
 	var products []*entities.Product
-	err := r.db.WithContext(ctx).
+	err := dbFor(ctx, r.db).
 		Where("category_id = ?", categoryID).
 		Limit(limit).
 		Offset(offset).
@@ -115,7 +149,7 @@ func (r *GormProductRepository) GetByCategory(ctx context.Context, categoryID uu
 // GetByBrand retrieves products by brand
 func (r *GormProductRepository) GetByBrand(ctx context.Context, brandID uuid.UUID, limit, offset int) ([]*entities.Product, error) {
 	var products []*entities.Product
-	err := r.db.WithContext(ctx).
+	err := dbFor(ctx, r.db).
 		Where("brand_id = ?", brandID).
 		Limit(limit).
 		Offset(offset).
@@ -127,7 +161,7 @@ func (r *GormProductRepository) GetByBrand(ctx context.Context, brandID uuid.UUI
 // GetByStatus retrieves products by status
 func (r *GormProductRepository) GetByStatus(ctx context.Context, status entities.ProductStatus, limit, offset int) ([]*entities.Product, error) {
 	var products []*entities.Product
-	err := r.db.WithContext(ctx).
+	err := dbFor(ctx, r.db).
 		Where("status = ?", status).
 		Limit(limit).
 		Offset(offset).
@@ -139,7 +173,7 @@ func (r *GormProductRepository) GetByStatus(ctx context.Context, status entities
 // GetFeatured retrieves featured products
 func (r *GormProductRepository) GetFeatured(ctx context.Context, limit, offset int) ([]*entities.Product, error) {
 	var products []*entities.Product
-	err := r.db.WithContext(ctx).
+	err := dbFor(ctx, r.db).
 		Where("featured = ? AND status = ? AND visibility IN ?", true, entities.ProductStatusActive, []entities.Visibility{entities.VisibilityVisible, entities.VisibilityFeatured}).
 		Limit(limit).
 		Offset(offset).
@@ -148,33 +182,70 @@ func (r *GormProductRepository) GetFeatured(ctx context.Context, limit, offset i
 	return products, err
 }
 
-// Search searches for products
-func (r *GormProductRepository) Search(ctx context.Context, query string, filters *repositories.ProductFilters, limit, offset int) ([]*entities.Product, error) {
-	db := r.db.WithContext(ctx)
+// Search searches for products using weighted full-text matching ranked by relevance
+func (r *GormProductRepository) Search(ctx context.Context, query string, filters *repositories.ProductFilters, limit, offset int) ([]*repositories.ProductSearchHit, error) {
+	db := dbFor(ctx, r.db).Model(&entities.Product{})
+	
+	terms := searchTerms(query)
+	tsQuery := buildPrefixTSQuery(terms)
 	
 	// Apply text search
-	if query != "" {
-		searchQuery := "%" + strings.ToLower(query) + "%"
-		db = db.Where("LOWER(name) LIKE ? OR LOWER(description) LIKE ? OR LOWER(short_desc) LIKE ?", 
-			searchQuery, searchQuery, searchQuery)
+	if tsQuery != "" {
+		db = applyTextSearch(db, tsQuery, terms).
+			Select(fmt.Sprintf(
+				"products.*, ts_rank(%s, %s) AS search_rank, "+
+					"ts_headline('english', coalesce(name, ''), %s, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS name_highlight, "+
+					"ts_headline('english', coalesce(description, ''), %s, ?) AS description_highlight",
+				weightedDocumentSQL, tsQuerySQL, tsQuerySQL, tsQuerySQL,
+			), tsQuery, tsQuery, tsQuery, headlineOptions).
+			Order("search_rank DESC")
 	}
 	
 	// Apply filters
 	db = r.applyFilters(db, filters)
 	
-	var products []*entities.Product
+	// Without query text, products restricted by IDs keep the order of IDs
+	if tsQuery == "" && filters != nil && len(filters.IDs) > 0 {
+		db = db.Joins("JOIN unnest(CAST(? AS uuid[])) WITH ORDINALITY AS matched(id, position) ON matched.id = products.id", uuidArray(filters.IDs)).
+			Order("matched.position")
+	}
+	
+	var rows []productSearchRow
 	err := db.Limit(limit).
 		Offset(offset).
 		Order("created_at DESC").
-		Find(&products).Error
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
 	
-	return products, err
+	hits := make([]*repositories.ProductSearchHit, len(rows))
+	for i := range rows {
+		hits[i] = &repositories.ProductSearchHit{
+			Product: &rows[i].Product,
+			Rank:    rows[i].SearchRank,
+			Highlights: repositories.ProductHighlight{
+				Name:        rows[i].NameHighlight,
+				Description: rows[i].DescriptionHighlight,
+			},
+		}
+	}
+	
+	return hits, nil
+}
+
+// productSearchRow is a product row with the ranking columns selected by Search
+type productSearchRow struct {
+	entities.Product
+	SearchRank           float64
+	NameHighlight        string
+	DescriptionHighlight string
 }
 
 // GetByPriceRange retrieves products by price range
 func (r *GormProductRepository) GetByPriceRange(ctx context.Context, minPrice, maxPrice float64, limit, offset int) ([]*entities.Product, error) {
 	var products []*entities.Product
-	err := r.db.WithContext(ctx).
+	err := dbFor(ctx, r.db).
 		Where("price BETWEEN ? AND ?", minPrice, maxPrice).
 		Limit(limit).
 		Offset(offset).
@@ -188,7 +259,7 @@ func (r *GormProductRepository) GetByTags(ctx context.Context, tags []string, li
 	var products []*entities.Product
 	
 	// PostgreSQL array contains query
-	err := r.db.WithContext(ctx).
+	err := dbFor(ctx, r.db).
 		Where("tags && ?", tags).
 		Limit(limit).
 		Offset(offset).
@@ -201,7 +272,7 @@ func (r *GormProductRepository) GetByTags(ctx context.Context, tags []string, li
 // GetLowStock retrieves products with low stock
 func (r *GormProductRepository) GetLowStock(ctx context.Context, threshold int, limit, offset int) ([]*entities.Product, error) {
 	var products []*entities.Product
-	err := r.db.WithContext(ctx).
+	err := dbFor(ctx, r.db).
 		Where("track_stock = ? AND stock_quantity <= ?", true, threshold).
 		Limit(limit).
 		Offset(offset).
@@ -213,7 +284,7 @@ func (r *GormProductRepository) GetLowStock(ctx context.Context, threshold int,
 // GetOutOfStock retrieves out of stock products
 func (r *GormProductRepository) GetOutOfStock(ctx context.Context, limit, offset int) ([]*entities.Product, error) {
 	var products []*entities.Product
-	err := r.db.WithContext(ctx).
+	err := dbFor(ctx, r.db).
 		Where("track_stock = ? AND stock_quantity = 0", true).
 		Limit(limit).
 		Offset(offset).
@@ -224,20 +295,26 @@ func (r *GormProductRepository) GetOutOfStock(ctx context.Context, limit, offset
 
 // UpdateStock updates product stock
 func (r *GormProductRepository) UpdateStock(ctx context.Context, id uuid.UUID, quantity int) error {
-	return r.db.WithContext(ctx).
+	return dbFor(ctx, r.db).
 		Model(&entities.Product{}).
 		Where("id = ?", id).
-		Update("stock_quantity", quantity).Error
+		Updates(map[string]interface{}{
+			"stock_quantity": quantity,
+			"version":        gorm.Expr("version + 1"),
+		}).Error
 }
 
 // BulkUpdateStock updates multiple products' stock
 func (r *GormProductRepository) BulkUpdateStock(ctx context.Context, updates []repositories.StockUpdate) error {
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	return dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
 		for _, update := range updates {
 			if !update.IsVariant {
 				err := tx.Model(&entities.Product{}).
 					Where("id = ?", update.ID).
-					Update("stock_quantity", update.Quantity).Error
+					Updates(map[string]interface{}{
+						"stock_quantity": update.Quantity,
+						"version":        gorm.Expr("version + 1"),
+					}).Error
 				if err != nil {
 					return err
 				}
@@ -247,23 +324,15 @@ func (r *GormProductRepository) BulkUpdateStock(ctx context.Context, updates []r
 	})
 }
 
-// GetTopSelling retrieves top selling products
-func (r *GormProductRepository) GetTopSelling(ctx context.Context, limit int, days int) ([]*entities.Product, error) {
-	// This would typically join with order items table
-	// For now, return products ordered by some criteria
-	var products []*entities.Product
-	err := r.db.WithContext(ctx).
-		Where("status = ?", entities.ProductStatusActive).
-		Limit(limit).
-		Order("created_at DESC").
-		Find(&products).Error
-	return products, err
+// GetTopSelling retrieves the products with the most units sold in the last days
+func (r *GormProductRepository) GetTopSelling(ctx context.Context, limit int, days int, categoryID *uuid.UUID) ([]*entities.Product, error) {
+	return r.rankByStats(ctx, limit, days, categoryID, "SUM(units_sold)")
 }
 
 // GetRecentlyAdded retrieves recently added products
 func (r *GormProductRepository) GetRecentlyAdded(ctx context.Context, limit int, days int) ([]*entities.Product, error) {
 	var products []*entities.Product
-	err := r.db.WithContext(ctx).
+	err := dbFor(ctx, r.db).
 		Where("created_at >= NOW() - INTERVAL ? DAY", days).
 		Limit(limit).
 		Order("created_at DESC").
@@ -271,24 +340,55 @@ func (r *GormProductRepository) GetRecentlyAdded(ctx context.Context, limit int,
 	return products, err
 }
 
-// GetMostViewed retrieves most viewed products
-func (r *GormProductRepository) GetMostViewed(ctx context.Context, limit int, days int) ([]*entities.Product, error) {
-	// This would typically use analytics data
-	// For now, return featured products
-	return r.GetFeatured(ctx, limit, 0)
+// GetMostViewed retrieves the products with the most views in the last days
+func (r *GormProductRepository) GetMostViewed(ctx context.Context, limit int, days int, categoryID *uuid.UUID) ([]*entities.Product, error) {
+	return r.rankByStats(ctx, limit, days, categoryID, "SUM(views)")
+}
+
+// GetTrending retrieves the products with the most recent activity in the last days:
+// views and weighted sales, each day's halved every entities.TrendingHalfLifeDays
+func (r *GormProductRepository) GetTrending(ctx context.Context, limit int, days int, categoryID *uuid.UUID) ([]*entities.Product, error) {
+	score := fmt.Sprintf(
+		"SUM((units_sold * %d + views) * POWER(0.5, EXTRACT(EPOCH FROM (NOW() - bucket)) / 86400.0 / %d))",
+		entities.TrendingSaleWeight, entities.TrendingHalfLifeDays,
+	)
+	return r.rankByStats(ctx, limit, days, categoryID, score)
+}
+
+// rankByStats ranks listed products by a score summed over their stat buckets in the
+// last days, highest first; products without a positive score are left out
+func (r *GormProductRepository) rankByStats(ctx context.Context, limit int, days int, categoryID *uuid.UUID, score string) ([]*entities.Product, error) {
+	scores := r.db.Model(&entities.ProductStat{}).
+		Select("product_id, "+score+" AS score").
+		Where("bucket >= ?", entities.StatWindowStart(time.Now(), days)).
+		Group("product_id").
+		Having(score + " > 0")
+	
+	db := dbFor(ctx, r.db).
+		Joins("JOIN (?) AS stats ON stats.product_id = products.id", scores).
+		Where("products.status = ? AND products.visibility <> ?", entities.ProductStatusActive, entities.VisibilityHidden)
+	if categoryID != nil {
+		db = db.Where("products.category_id = ?", *categoryID)
+	}
+	
+	var products []*entities.Product
+	err := db.Order("stats.score DESC, products.created_at DESC").
+		Limit(limit).
+		Find(&products).Error
+	return products, err
 }
 
 // Count counts all products
 func (r *GormProductRepository) Count(ctx context.Context) (int64, error) {
 	var count int64
-	err := r.db.WithContext(ctx).Model(&entities.Product{}).Count(&count).Error
+	err := dbFor(ctx, r.db).Model(&entities.Product{}).Count(&count).Error
 	return count, err
 }
 
 // CountByCategory counts products by category
 func (r *GormProductRepository) CountByCategory(ctx context.Context, categoryID uuid.UUID) (int64, error) {
 	var count int64
-	err := r.db.WithContext(ctx).
+	err := dbFor(ctx, r.db).
 		Model(&entities.Product{}).
 		Where("category_id = ?", categoryID).
 		Count(&count).Error
@@ -298,7 +398,7 @@ func (r *GormProductRepository) CountByCategory(ctx context.Context, categoryID
 // CountByBrand counts products by brand
 func (r *GormProductRepository) CountByBrand(ctx context.Context, brandID uuid.UUID) (int64, error) {
 	var count int64
-	err := r.db.WithContext(ctx).
+	err := dbFor(ctx, r.db).
 		Model(&entities.Product{}).
 		Where("brand_id = ?", brandID).
 		Count(&count).Error
@@ -308,36 +408,24 @@ func (r *GormProductRepository) CountByBrand(ctx context.Context, brandID uuid.U
 // CountByStatus counts products by status
 func (r *GormProductRepository) CountByStatus(ctx context.Context, status entities.ProductStatus) (int64, error) {
 	var count int64
-	err := r.db.WithContext(ctx).
+	err := dbFor(ctx, r.db).
 		Model(&entities.Product{}).
 		Where("status = ?", status).
 		Count(&count).Error
 	return count, err
 }
 
-// CountSearch counts search results
+// CountSearch counts search results using the same matching as Search
 func (r *GormProductRepository) CountSearch(ctx context.Context, query string, filters *repositories.ProductFilters) (int64, error) {
-	db := r.db.WithContext(ctx).Model(&entities.Product{})
-	
-	// Apply text search
-	if query != "" {
-		searchQuery := "%" + strings.ToLower(query) + "%"
-		db = db.Where("LOWER(name) LIKE ? OR LOWER(description) LIKE ? OR LOWER(short_desc) LIKE ?", 
-			searchQuery, searchQuery, searchQuery)
-	}
-	
-	// Apply filters
-	db = r.applyFilters(db, filters)
-	
 	var count int64
-	err := db.Count(&count).Error
+	err := r.matchingProducts(ctx, query, filters).Count(&count).Error
 	return count, err
 }
 
 // ExistsBySKU checks if product exists by SKU
 func (r *GormProductRepository) ExistsBySKU(ctx context.Context, sku string) (bool, error) {
 	var count int64
-	err := r.db.WithContext(ctx).
+	err := dbFor(ctx, r.db).
 		Model(&entities.Product{}).
 		Where("sku = ?", sku).
 		Count(&count).Error
@@ -347,7 +435,7 @@ func (r *GormProductRepository) ExistsBySKU(ctx context.Context, sku string) (bo
 // ExistsBySlug checks if product exists by slug
 func (r *GormProductRepository) ExistsBySlug(ctx context.Context, slug string) (bool, error) {
 	var count int64
-	err := r.db.WithContext(ctx).
+	err := dbFor(ctx, r.db).
 		Model(&entities.Product{}).
 		Where("slug = ?", slug).
 		Count(&count).Error
@@ -357,7 +445,7 @@ func (r *GormProductRepository) ExistsBySlug(ctx context.Context, slug string) (
 // LoadWithImages loads product with images
 func (r *GormProductRepository) LoadWithImages(ctx context.Context, id uuid.UUID) (*entities.Product, error) {
 	var product entities.Product
-	err := r.db.WithContext(ctx).
+	err := dbFor(ctx, r.db).
 		Preload("Images").
 		Where("id = ?", id).
 		First(&product).Error
@@ -373,7 +461,7 @@ func (r *GormProductRepository) LoadWithImages(ctx context.Context, id uuid.UUID
 // LoadWithVariants loads product with variants
 func (r *GormProductRepository) LoadWithVariants(ctx context.Context, id uuid.UUID) (*entities.Product, error) {
 	var product entities.Product
-	err := r.db.WithContext(ctx).
+	err := dbFor(ctx, r.db).
 		Preload("Variants").
 		Where("id = ?", id).
 		First(&product).Error
@@ -389,7 +477,7 @@ func (r *GormProductRepository) LoadWithVariants(ctx context.Context, id uuid.UU
 // LoadWithCategory loads product with category
 func (r *GormProductRepository) LoadWithCategory(ctx context.Context, id uuid.UUID) (*entities.Product, error) {
 	var product entities.Product
-	err := r.db.WithContext(ctx).
+	err := dbFor(ctx, r.db).
 		Preload("Category").
 		Where("id = ?", id).
 		First(&product).Error
@@ -405,7 +493,7 @@ func (r *GormProductRepository) LoadWithCategory(ctx context.Context, id uuid.UU
 // LoadWithBrand loads product with brand
 func (r *GormProductRepository) LoadWithBrand(ctx context.Context, id uuid.UUID) (*entities.Product, error) {
 	var product entities.Product
-	err := r.db.WithContext(ctx).
+	err := dbFor(ctx, r.db).
 		Preload("Brand").
 		Where("id = ?", id).
 		First(&product).Error
@@ -421,13 +509,20 @@ func (r *GormProductRepository) LoadWithBrand(ctx context.Context, id uuid.UUID)
 // LoadComplete loads product with all related data
 func (r *GormProductRepository) LoadComplete(ctx context.Context, id uuid.UUID) (*entities.Product, error) {
 	var product entities.Product
-	err := r.db.WithContext(ctx).
+	err := dbFor(ctx, r.db).
 		Preload("Category").
 		Preload("Brand").
 		Preload("Images").
-		Preload("Videos").
+		Preload("Videos", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sort_order, created_at")
+		}).
 		Preload("Variants").
 		Preload("Variants.Images").
+		Preload("BundleItems", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sort_order, created_at")
+		}).
+		Preload("BundleItems.Product").
+		Preload("BundleItems.Variant").
 		Where("id = ?", id).
 		First(&product).Error
 	if err != nil {
@@ -439,12 +534,29 @@ func (r *GormProductRepository) LoadComplete(ctx context.Context, id uuid.UUID)
 	return &product, nil
 }
 
+// uuidArray formats ids as a Postgres array literal
+func uuidArray(ids []uuid.UUID) string {
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = id.String()
+	}
+	return "{" + strings.Join(values, ",") + "}"
+}
+
 // applyFilters applies search filters to the query
 func (r *GormProductRepository) applyFilters(db *gorm.DB, filters *repositories.ProductFilters) *gorm.DB {
 	if filters == nil {
 		return db
 	}
 	
+	if filters.IDs != nil {
+		if len(filters.IDs) == 0 {
+			return db.Where("1 = 0")
+		}
+		// One array parameter, as the list can exceed Postgres's bind parameter limit
+		db = db.Where("products.id = ANY(CAST(? AS uuid[]))", uuidArray(filters.IDs))
+	}
+	
 	if len(filters.CategoryIDs) > 0 {
 		db = db.Where("category_id IN ?", filters.CategoryIDs)
 	}
@@ -457,8 +569,10 @@ func (r *GormProductRepository) applyFilters(db *gorm.DB, filters *repositories.
 		db = db.Where("price >= ?", *filters.MinPrice)
 	}
 	
+	// The upper bound is exclusive, like the price facet's buckets, so selecting a
+	// bucket returns exactly the products it counted
 	if filters.MaxPrice != nil {
-		db = db.Where("price <= ?", *filters.MaxPrice)
+		db = db.Where("price < ?", *filters.MaxPrice)
 	}
 	
 	if filters.InStock != nil {
@@ -489,6 +603,14 @@ func (r *GormProductRepository) applyFilters(db *gorm.DB, filters *repositories.
 		db = db.Where("has_variants = ?", *filters.HasVariants)
 	}
 	
+	if filters.IsBundle != nil {
+		db = db.Where("is_bundle = ?", *filters.IsBundle)
+	}
+	
+	if filters.ListedOnly {
+		db = db.Where("status = ? AND visibility <> ?", entities.ProductStatusActive, entities.VisibilityHidden)
+	}
+	
 	if filters.CreatedFrom != nil {
 		db = db.Where("created_at >= ?", *filters.CreatedFrom)
 	}
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"product-service/internal/domain/entities"
+)
+
+// PublishDue activates drafts whose publish time has come
+func (r *GormProductRepository) PublishDue(ctx context.Context, now time.Time, limit int) ([]*entities.Product, error) {
+	return r.claimTransitions(ctx, limit,
+		func(db *gorm.DB) *gorm.DB {
+			return db.Where("status = ? AND publish_at IS NOT NULL AND publish_at <= ?", entities.ProductStatusDraft, now).
+				Order("publish_at")
+		},
+		func(product *entities.Product) {
+			product.SetStatus(entities.ProductStatusActive)
+		},
+	)
+}
+
+// UnpublishExpired deactivates active products whose availability window has ended
+func (r *GormProductRepository) UnpublishExpired(ctx context.Context, now time.Time, limit int) ([]*entities.Product, error) {
+	return r.claimTransitions(ctx, limit,
+		func(db *gorm.DB) *gorm.DB {
+			return db.Where("status = ? AND available_until IS NOT NULL AND available_until <= ?", entities.ProductStatusActive, now).
+				Order("available_until")
+		},
+		func(product *entities.Product) {
+			product.SetStatus(entities.ProductStatusInactive)
+		},
+	)
+}
+
+// claimTransitions locks up to limit matching products with FOR UPDATE SKIP LOCKED, so
+// replicas running the same transition concurrently claim disjoint rows, and saves the
+// status change in the same transaction. Rows stop matching once transitioned.
+func (r *GormProductRepository) claimTransitions(ctx context.Context, limit int, due func(*gorm.DB) *gorm.DB, transition func(*entities.Product)) ([]*entities.Product, error) {
+	var products []*entities.Product
+	err := dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		products = nil
+		err := due(tx.Model(&entities.Product{})).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Limit(limit).
+			Find(&products).Error
+		if err != nil {
+			return err
+		}
+
+		for _, product := range products {
+			transition(product)
+			product.Version++
+			err := tx.Model(product).
+				Select("status", "publish_at", "published_at", "updated_at", "version").
+				Updates(product).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return products, nil
+}
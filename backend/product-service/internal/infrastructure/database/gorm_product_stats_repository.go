@@ -0,0 +1,62 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// GormProductStatsRepository implements ProductStatsRepository using GORM
+type GormProductStatsRepository struct {
+	db *gorm.DB
+}
+
+// NewGormProductStatsRepository creates a new GORM product stats repository
+func NewGormProductStatsRepository(db *gorm.DB) repositories.ProductStatsRepository {
+	return &GormProductStatsRepository{db: db}
+}
+
+// AddStats upserts each stat, adding its counters to those already in its bucket
+func (r *GormProductStatsRepository) AddStats(ctx context.Context, stats []*entities.ProductStat) error {
+	if len(stats) == 0 {
+		return nil
+	}
+	return addStats(dbFor(ctx, r.db), stats)
+}
+
+// RecordOrderSales marks an ingested order's sales as counted and adds them, in a
+// single transaction
+func (r *GormProductStatsRepository) RecordOrderSales(ctx context.Context, orderID string, stats []*entities.ProductStat) (bool, error) {
+	recorded := false
+	err := dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.IngestedOrder{}).
+			Where("order_id = ? AND sales_counted_at IS NULL", orderID).
+			Update("sales_counted_at", time.Now())
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		recorded = true
+
+		if len(stats) == 0 {
+			return nil
+		}
+		return addStats(tx, stats)
+	})
+	return recorded, err
+}
+
+// addStats upserts stats with db
+func addStats(db *gorm.DB, stats []*entities.ProductStat) error {
+	return db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "product_id"}, {Name: "bucket"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"views":      gorm.Expr("product_stats.views + excluded.views"),
+			"units_sold": gorm.Expr("product_stats.units_sold + excluded.units_sold"),
+			"orders":     gorm.Expr("product_stats.orders + excluded.orders"),
+		}),
+	}).CreateInBatches(stats, 500).Error
+}
@@ -0,0 +1,186 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// suggestSimilarityThreshold is the minimum pg_trgm word similarity for a name to count
+// as a typo-tolerant match. The extension default (0.6) rejects most misspellings.
+const suggestSimilarityThreshold = 0.35
+
+// suggestPopularitySQL turns a count into a bounded bonus so popularity reorders
+// comparable matches without outranking a better textual match
+const suggestPopularitySQL = "LEAST(LN(1 + %s) / 10, 0.3)"
+
+// suggestActivitySQL is suggestPopularitySQL for product activity, which runs into the
+// thousands of views where product counts stay in the tens
+const suggestActivitySQL = "LEAST(LN(1 + %s) / 30, 0.3)"
+
+// suggestStatsDays is how many days of view and sale stats count towards a product's
+// suggestion popularity
+const suggestStatsDays = 30
+
+// Suggest returns typeahead completions across product, brand and category names.
+// Names starting with the query rank first, then names with a word starting with it;
+// trigram word similarity catches typos when pg_trgm is installed. Popularity (recent
+// views and sales, reviews and the featured flag for products, active product count for
+// brands and categories) breaks ties.
+func (r *GormProductRepository) Suggest(ctx context.Context, query string, limit int) ([]*repositories.Suggestion, error) {
+	query = strings.TrimSpace(query)
+	if query == "" || limit <= 0 {
+		return []*repositories.Suggestion{}, nil
+	}
+
+	trigrams, err := r.hasTrigrams(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var suggestions []*repositories.Suggestion
+	err = dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if trigrams {
+			// SET LOCAL does not accept bind parameters; the threshold is a constant
+			if err := tx.Exec(fmt.Sprintf("SET LOCAL pg_trgm.word_similarity_threshold = %g", suggestSimilarityThreshold)).Error; err != nil {
+				return fmt.Errorf("failed to set similarity threshold: %w", err)
+			}
+		}
+
+		score, scoreArgs := suggestScoreSQL("products.name", query, trigrams)
+		match, matchArgs := suggestMatchSQL("products.name", query, trigrams)
+		// A sale counts as much as TrendingSaleWeight views, as in the trending ranking
+		activity := tx.Model(&entities.ProductStat{}).
+			Select(fmt.Sprintf("product_id, SUM(views + units_sold * %d) AS activity", entities.TrendingSaleWeight)).
+			Where("bucket >= ?", entities.StatWindowStart(time.Now(), suggestStatsDays)).
+			Group("product_id")
+
+		var products []*repositories.Suggestion
+		err := tx.Model(&entities.Product{}).
+			Select("CAST(products.id AS text) AS id, products.name AS text, products.slug, ? AS type, "+
+				score+" + "+fmt.Sprintf(suggestActivitySQL, "COALESCE(stats.activity, 0) + products.review_count")+
+				" + CASE WHEN products.featured THEN 0.2 ELSE 0 END AS score",
+				append([]interface{}{repositories.SuggestionTypeProduct}, scoreArgs...)...).
+			Joins("LEFT JOIN (?) AS stats ON stats.product_id = products.id", activity).
+			Where("products.status = ? AND products.visibility <> ?", entities.ProductStatusActive, entities.VisibilityHidden).
+			Where(match, matchArgs...).
+			Order("score DESC, products.name").
+			Limit(limit).
+			Scan(&products).Error
+		if err != nil {
+			return fmt.Errorf("failed to suggest products: %w", err)
+		}
+
+		brands, err := suggestGroups(tx, "brands", "brand_id", repositories.SuggestionTypeBrand, query, limit, trigrams)
+		if err != nil {
+			return err
+		}
+
+		categories, err := suggestGroups(tx, "categories", "category_id", repositories.SuggestionTypeCategory, query, limit, trigrams)
+		if err != nil {
+			return err
+		}
+
+		suggestions = append(append(products, brands...), categories...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].Text < suggestions[j].Text
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	return suggestions, nil
+}
+
+// suggestGroups suggests names from a brands or categories table, scoring popularity
+// by the number of active products referencing each row
+func suggestGroups(tx *gorm.DB, table, foreignKey, suggestionType, query string, limit int, trigrams bool) ([]*repositories.Suggestion, error) {
+	score, scoreArgs := suggestScoreSQL("g.name", query, trigrams)
+	match, matchArgs := suggestMatchSQL("g.name", query, trigrams)
+
+	var suggestions []*repositories.Suggestion
+	err := tx.Table(table+" AS g").
+		Select("CAST(g.id AS text) AS id, g.name AS text, g.slug, ? AS type, "+
+			score+" + "+fmt.Sprintf(suggestPopularitySQL, "COUNT(p.id)")+" AS score",
+			append([]interface{}{suggestionType}, scoreArgs...)...).
+		Joins("LEFT JOIN products p ON p."+foreignKey+" = g.id AND p.status = ?", entities.ProductStatusActive).
+		Where("g.is_active = true AND g.is_visible = true AND g.deleted_at IS NULL").
+		Where(match, matchArgs...).
+		Group("g.id, g.name, g.slug").
+		Order("score DESC, g.name").
+		Limit(limit).
+		Scan(&suggestions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest %s: %w", table, err)
+	}
+
+	return suggestions, nil
+}
+
+// suggestMatchSQL matches names that start with the query, contain a word starting with
+// it or, with trigrams, are similar enough to absorb typos. ILIKE and <% both use
+// gin_trgm_ops indexes.
+func suggestMatchSQL(column, query string, trigrams bool) (string, []interface{}) {
+	prefix := escapeLike(query) + "%"
+	if !trigrams {
+		return "(" + column + " ILIKE ? OR " + column + " ILIKE ?)", []interface{}{prefix, "% " + prefix}
+	}
+	return "(" + column + " ILIKE ? OR " + column + " ILIKE ? OR ? <% " + column + ")",
+		[]interface{}{prefix, "% " + prefix, query}
+}
+
+// suggestScoreSQL scores a name: a bonus for prefix matches plus, with trigrams, word
+// similarity
+func suggestScoreSQL(column, query string, trigrams bool) (string, []interface{}) {
+	prefix := escapeLike(query) + "%"
+	bonus := "CASE WHEN " + column + " ILIKE ? THEN 1 WHEN " + column + " ILIKE ? THEN 0.5 ELSE 0 END"
+	if !trigrams {
+		return "(" + bonus + ")", []interface{}{prefix, "% " + prefix}
+	}
+	return "(word_similarity(?, " + column + ") + " + bonus + ")",
+		[]interface{}{query, prefix, "% " + prefix}
+}
+
+// TrigramsAvailable reports whether the pg_trgm extension is installed
+func TrigramsAvailable(db *gorm.DB) (bool, error) {
+	var installed bool
+	err := db.Raw("SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'pg_trgm')").Scan(&installed).Error
+	return installed, err
+}
+
+// hasTrigrams reports whether suggestions can use pg_trgm. The server only warns when it
+// cannot create the extension, e.g. for a role without the privilege, and suggestions
+// then fall back to prefix matching. The answer is cached once the check succeeds.
+func (r *GormProductRepository) hasTrigrams(ctx context.Context) (bool, error) {
+	r.trigramsMu.Lock()
+	defer r.trigramsMu.Unlock()
+
+	if r.trigrams == nil {
+		installed, err := TrigramsAvailable(r.db.WithContext(ctx))
+		if err != nil {
+			return false, fmt.Errorf("failed to check for pg_trgm: %w", err)
+		}
+		r.trigrams = &installed
+	}
+	return *r.trigrams, nil
+}
+
+// escapeLike escapes LIKE wildcards so user input matches literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,251 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// GormProductVariantRepository implements ProductVariantRepository using GORM
+type GormProductVariantRepository struct {
+	db *gorm.DB
+}
+
+// NewGormProductVariantRepository creates a new GORM product variant repository
+func NewGormProductVariantRepository(db *gorm.DB) repositories.ProductVariantRepository {
+	return &GormProductVariantRepository{db: db}
+}
+
+// Create creates a new product variant
+func (r *GormProductVariantRepository) Create(ctx context.Context, variant *entities.ProductVariant) error {
+	return dbFor(ctx, r.db).Create(variant).Error
+}
+
+// GetByID retrieves a product variant by ID
+func (r *GormProductVariantRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.ProductVariant, error) {
+	var variant entities.ProductVariant
+	err := dbFor(ctx, r.db).Where("id = ?", id).First(&variant).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &variant, nil
+}
+
+// GetBySKU retrieves a product variant by SKU
+func (r *GormProductVariantRepository) GetBySKU(ctx context.Context, sku string) (*entities.ProductVariant, error) {
+	var variant entities.ProductVariant
+	err := dbFor(ctx, r.db).Where("sku = ?", sku).First(&variant).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &variant, nil
+}
+
+// Update updates a product variant
+func (r *GormProductVariantRepository) Update(ctx context.Context, variant *entities.ProductVariant) error {
+	return dbFor(ctx, r.db).Save(variant).Error
+}
+
+// Delete deletes a product variant
+func (r *GormProductVariantRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return dbFor(ctx, r.db).Delete(&entities.ProductVariant{}, id).Error
+}
+
+// GetByProductID retrieves all variants of a product
+func (r *GormProductVariantRepository) GetByProductID(ctx context.Context, productID uuid.UUID) ([]*entities.ProductVariant, error) {
+	var variants []*entities.ProductVariant
+	err := dbFor(ctx, r.db).
+		Where("product_id = ?", productID).
+		Order("sort_order, created_at").
+		Find(&variants).Error
+	return variants, err
+}
+
+// GetActiveByProductID retrieves the active variants of a product
+func (r *GormProductVariantRepository) GetActiveByProductID(ctx context.Context, productID uuid.UUID) ([]*entities.ProductVariant, error) {
+	var variants []*entities.ProductVariant
+	err := dbFor(ctx, r.db).
+		Where("product_id = ? AND is_active = ?", productID, true).
+		Order("sort_order, created_at").
+		Find(&variants).Error
+	return variants, err
+}
+
+// GetDefaultByProductID retrieves the default variant of a product
+func (r *GormProductVariantRepository) GetDefaultByProductID(ctx context.Context, productID uuid.UUID) (*entities.ProductVariant, error) {
+	var variant entities.ProductVariant
+	err := dbFor(ctx, r.db).
+		Where("product_id = ? AND is_default = ?", productID, true).
+		First(&variant).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &variant, nil
+}
+
+// GetByIDs retrieves product variants by IDs
+func (r *GormProductVariantRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.ProductVariant, error) {
+	var variants []*entities.ProductVariant
+	err := dbFor(ctx, r.db).Where("id IN ?", ids).Find(&variants).Error
+	return variants, err
+}
+
+// GetBySKUs retrieves product variants by SKUs
+func (r *GormProductVariantRepository) GetBySKUs(ctx context.Context, skus []string) ([]*entities.ProductVariant, error) {
+	var variants []*entities.ProductVariant
+	err := dbFor(ctx, r.db).Where("sku IN ?", skus).Find(&variants).Error
+	return variants, err
+}
+
+// CreateBulk creates multiple product variants in a single transaction
+func (r *GormProductVariantRepository) CreateBulk(ctx context.Context, variants []*entities.ProductVariant) error {
+	if len(variants) == 0 {
+		return nil
+	}
+	return dbFor(ctx, r.db).CreateInBatches(variants, 100).Error
+}
+
+// UpdateBulk updates multiple product variants in a single transaction
+func (r *GormProductVariantRepository) UpdateBulk(ctx context.Context, variants []*entities.ProductVariant) error {
+	return dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		for _, variant := range variants {
+			if err := tx.Save(variant).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateStock updates variant stock
+func (r *GormProductVariantRepository) UpdateStock(ctx context.Context, id uuid.UUID, quantity int) error {
+	return dbFor(ctx, r.db).
+		Model(&entities.ProductVariant{}).
+		Where("id = ?", id).
+		Update("stock_quantity", quantity).Error
+}
+
+// BulkUpdateStock updates multiple variants' stock
+func (r *GormProductVariantRepository) BulkUpdateStock(ctx context.Context, updates []repositories.StockUpdate) error {
+	return dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		for _, update := range updates {
+			if update.IsVariant {
+				err := tx.Model(&entities.ProductVariant{}).
+					Where("id = ?", update.ID).
+					Update("stock_quantity", update.Quantity).Error
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// GetLowStock retrieves tracked variants at or below the stock threshold
+func (r *GormProductVariantRepository) GetLowStock(ctx context.Context, threshold int) ([]*entities.ProductVariant, error) {
+	var variants []*entities.ProductVariant
+	err := dbFor(ctx, r.db).
+		Where("track_stock = ? AND stock_quantity <= ? AND stock_quantity > 0", true, threshold).
+		Order("stock_quantity ASC").
+		Find(&variants).Error
+	return variants, err
+}
+
+// GetOutOfStock retrieves tracked variants with no stock
+func (r *GormProductVariantRepository) GetOutOfStock(ctx context.Context) ([]*entities.ProductVariant, error) {
+	var variants []*entities.ProductVariant
+	err := dbFor(ctx, r.db).
+		Where("track_stock = ? AND stock_quantity <= 0", true).
+		Order("updated_at DESC").
+		Find(&variants).Error
+	return variants, err
+}
+
+// GetByAttributes retrieves a product's variants whose attributes include all the given
+// pairs. Option names and values match case-insensitively, so "?size=m" finds Size: M.
+func (r *GormProductVariantRepository) GetByAttributes(ctx context.Context, productID uuid.UUID, attributes map[string]string) ([]*entities.ProductVariant, error) {
+	filter, err := json.Marshal(attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	var variants []*entities.ProductVariant
+	err = dbFor(ctx, r.db).
+		Where("product_id = ?", productID).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM jsonb_each_text(?::jsonb) AS wanted
+			WHERE NOT EXISTS (
+				SELECT 1 FROM jsonb_each_text(product_variants.attributes) AS attr
+				WHERE lower(attr.key) = lower(wanted.key) AND lower(attr.value) = lower(wanted.value)
+			)
+		)`, string(filter)).
+		Order("sort_order, created_at").
+		Find(&variants).Error
+	return variants, err
+}
+
+// CountByProductID counts the variants of a product
+func (r *GormProductVariantRepository) CountByProductID(ctx context.Context, productID uuid.UUID) (int64, error) {
+	var count int64
+	err := dbFor(ctx, r.db).
+		Model(&entities.ProductVariant{}).
+		Where("product_id = ?", productID).
+		Count(&count).Error
+	return count, err
+}
+
+// ExistsBySKU checks if a variant exists by SKU
+func (r *GormProductVariantRepository) ExistsBySKU(ctx context.Context, sku string) (bool, error) {
+	var count int64
+	err := dbFor(ctx, r.db).
+		Model(&entities.ProductVariant{}).
+		Where("sku = ?", sku).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// LoadWithImages loads variant with images
+func (r *GormProductVariantRepository) LoadWithImages(ctx context.Context, id uuid.UUID) (*entities.ProductVariant, error) {
+	var variant entities.ProductVariant
+	err := dbFor(ctx, r.db).
+		Preload("Images").
+		Where("id = ?", id).
+		First(&variant).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &variant, nil
+}
+
+// LoadWithProduct loads variant with its product
+func (r *GormProductVariantRepository) LoadWithProduct(ctx context.Context, id uuid.UUID) (*entities.ProductVariant, error) {
+	var variant entities.ProductVariant
+	err := dbFor(ctx, r.db).
+		Preload("Product").
+		Where("id = ?", id).
+		First(&variant).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &variant, nil
+}
@@ -0,0 +1,125 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// GormProductVideoRepository implements ProductVideoRepository using GORM
+type GormProductVideoRepository struct {
+	db *gorm.DB
+}
+
+// NewGormProductVideoRepository creates a new GORM product video repository
+func NewGormProductVideoRepository(db *gorm.DB) repositories.ProductVideoRepository {
+	return &GormProductVideoRepository{db: db}
+}
+
+// Create creates a new product video
+func (r *GormProductVideoRepository) Create(ctx context.Context, video *entities.ProductVideo) error {
+	return dbFor(ctx, r.db).Create(video).Error
+}
+
+// GetByID retrieves a product video by ID
+func (r *GormProductVideoRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.ProductVideo, error) {
+	var video entities.ProductVideo
+	err := dbFor(ctx, r.db).Where("id = ?", id).First(&video).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &video, nil
+}
+
+// Update updates a product video
+func (r *GormProductVideoRepository) Update(ctx context.Context, video *entities.ProductVideo) error {
+	return dbFor(ctx, r.db).Save(video).Error
+}
+
+// Delete deletes a product video
+func (r *GormProductVideoRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return dbFor(ctx, r.db).Delete(&entities.ProductVideo{}, id).Error
+}
+
+// GetByProductID retrieves the videos of a product in display order
+func (r *GormProductVideoRepository) GetByProductID(ctx context.Context, productID uuid.UUID) ([]*entities.ProductVideo, error) {
+	var videos []*entities.ProductVideo
+	err := dbFor(ctx, r.db).
+		Where("product_id = ?", productID).
+		Order("sort_order, created_at").
+		Find(&videos).Error
+	return videos, err
+}
+
+// CreateBulk creates multiple product videos in a single transaction
+func (r *GormProductVideoRepository) CreateBulk(ctx context.Context, videos []*entities.ProductVideo) error {
+	if len(videos) == 0 {
+		return nil
+	}
+	return dbFor(ctx, r.db).CreateInBatches(videos, 100).Error
+}
+
+// UpdateBulk updates multiple product videos in a single transaction
+func (r *GormProductVideoRepository) UpdateBulk(ctx context.Context, videos []*entities.ProductVideo) error {
+	return dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		for _, video := range videos {
+			if err := tx.Save(video).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteByProductID deletes all videos of a product
+func (r *GormProductVideoRepository) DeleteByProductID(ctx context.Context, productID uuid.UUID) error {
+	return dbFor(ctx, r.db).
+		Where("product_id = ?", productID).
+		Delete(&entities.ProductVideo{}).Error
+}
+
+// UpdateSortOrder sets the position of a video
+func (r *GormProductVideoRepository) UpdateSortOrder(ctx context.Context, id uuid.UUID, sortOrder int) error {
+	return dbFor(ctx, r.db).
+		Model(&entities.ProductVideo{}).
+		Where("id = ?", id).
+		Update("sort_order", sortOrder).Error
+}
+
+// ReorderVideos sets the positions of the videos of a product to the order of
+// videoIDs, which must list each of its videos exactly once
+func (r *GormProductVideoRepository) ReorderVideos(ctx context.Context, productID uuid.UUID, videoIDs []uuid.UUID) error {
+	return dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var ids []uuid.UUID
+		if err := tx.Model(&entities.ProductVideo{}).Where("product_id = ?", productID).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if !sameIDs(ids, videoIDs) {
+			return errors.New("video IDs must list every video exactly once")
+		}
+
+		for i, id := range videoIDs {
+			if err := tx.Model(&entities.ProductVideo{}).Where("id = ?", id).Update("sort_order", i).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CountByProductID counts the videos of a product
+func (r *GormProductVideoRepository) CountByProductID(ctx context.Context, productID uuid.UUID) (int64, error) {
+	var count int64
+	err := dbFor(ctx, r.db).
+		Model(&entities.ProductVideo{}).
+		Where("product_id = ?", productID).
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// GormRecommendationRepository implements RecommendationRepository using GORM
+type GormRecommendationRepository struct {
+	db *gorm.DB
+}
+
+// NewGormRecommendationRepository creates a new GORM recommendation repository
+func NewGormRecommendationRepository(db *gorm.DB) repositories.RecommendationRepository {
+	return &GormRecommendationRepository{db: db}
+}
+
+// RecordOrder marks an order as ingested and adds one to the count of every pair of
+// its products, in both directions, in a single transaction
+func (r *GormRecommendationRepository) RecordOrder(ctx context.Context, orderID string, productIDs []uuid.UUID, orderedAt time.Time) (bool, error) {
+	recorded := false
+	err := dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&entities.IngestedOrder{
+			OrderID:    orderID,
+			ItemCount:  len(productIDs),
+			IngestedAt: time.Now(),
+		})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		recorded = true
+
+		var pairs []*entities.ProductCoOccurrence
+		for _, a := range productIDs {
+			for _, b := range productIDs {
+				if a != b {
+					pairs = append(pairs, &entities.ProductCoOccurrence{ProductID: a, OtherProductID: b, Count: 1, LastOrderedAt: orderedAt})
+				}
+			}
+		}
+		if len(pairs) == 0 {
+			return nil
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "product_id"}, {Name: "other_product_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"count":           gorm.Expr("product_co_occurrences.count + 1"),
+				"last_ordered_at": gorm.Expr("GREATEST(product_co_occurrences.last_ordered_at, excluded.last_ordered_at)"),
+			}),
+		}).CreateInBatches(pairs, 500).Error
+	})
+	return recorded, err
+}
+
+// GetFrequentlyBoughtWith returns the products most often bought with a product, most
+// frequent first and most recent among equals
+func (r *GormRecommendationRepository) GetFrequentlyBoughtWith(ctx context.Context, productID uuid.UUID, limit int) ([]*entities.ProductCoOccurrence, error) {
+	var pairs []*entities.ProductCoOccurrence
+	err := dbFor(ctx, r.db).
+		Where("product_id = ?", productID).
+		Order("count DESC, last_ordered_at DESC").
+		Limit(limit).
+		Find(&pairs).Error
+	return pairs, err
+}
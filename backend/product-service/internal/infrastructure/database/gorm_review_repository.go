@@ -0,0 +1,250 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// GormReviewRepository implements ReviewRepository using GORM
+type GormReviewRepository struct {
+	db *gorm.DB
+}
+
+// NewGormReviewRepository creates a new GORM review repository
+func NewGormReviewRepository(db *gorm.DB) repositories.ReviewRepository {
+	return &GormReviewRepository{db: db}
+}
+
+// Create creates a review and recomputes its product's rating
+func (r *GormReviewRepository) Create(ctx context.Context, review *entities.ProductReview) error {
+	return dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := lockProductForRating(tx, review.ProductID); err != nil {
+			return err
+		}
+		if err := tx.Omit(clause.Associations).Create(review).Error; err != nil {
+			return err
+		}
+		return recomputeRating(tx, review.ProductID)
+	})
+}
+
+// GetByID retrieves a review by ID
+func (r *GormReviewRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.ProductReview, error) {
+	var review entities.ProductReview
+	err := dbFor(ctx, r.db).Where("id = ?", id).First(&review).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &review, nil
+}
+
+// GetByProductAndUser retrieves a user's review of a product
+func (r *GormReviewRepository) GetByProductAndUser(ctx context.Context, productID, userID uuid.UUID) (*entities.ProductReview, error) {
+	var review entities.ProductReview
+	err := dbFor(ctx, r.db).
+		Where("product_id = ? AND user_id = ?", productID, userID).
+		First(&review).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &review, nil
+}
+
+// Update updates a review and recomputes its product's rating
+func (r *GormReviewRepository) Update(ctx context.Context, review *entities.ProductReview) error {
+	return dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := lockProductForRating(tx, review.ProductID); err != nil {
+			return err
+		}
+		// Votes and verification are written by their own paths, never from a review read
+		// before them
+		err := tx.Model(review).
+			Select("*").
+			Omit(clause.Associations, "created_at", "helpful_count", "unhelpful_count", "is_verified").
+			Updates(review).Error
+		if err != nil {
+			return err
+		}
+		return recomputeRating(tx, review.ProductID)
+	})
+}
+
+// List lists reviews matching the filters
+func (r *GormReviewRepository) List(ctx context.Context, filters *repositories.ReviewFilters, limit, offset int) ([]*entities.ProductReview, error) {
+	var reviews []*entities.ProductReview
+	err := r.applyReviewFilters(dbFor(ctx, r.db), filters).
+		Order(reviewOrder(filters.Sort)).
+		Limit(limit).
+		Offset(offset).
+		Find(&reviews).Error
+	return reviews, err
+}
+
+// Count counts reviews matching the filters
+func (r *GormReviewRepository) Count(ctx context.Context, filters *repositories.ReviewFilters) (int64, error) {
+	var count int64
+	err := r.applyReviewFilters(dbFor(ctx, r.db).Model(&entities.ProductReview{}), filters).
+		Count(&count).Error
+	return count, err
+}
+
+// GetRatingHistogram counts a product's approved reviews for every rating from 1 to 5
+func (r *GormReviewRepository) GetRatingHistogram(ctx context.Context, productID uuid.UUID) (map[int]int64, error) {
+	var rows []struct {
+		Rating int
+		Count  int64
+	}
+	err := dbFor(ctx, r.db).
+		Model(&entities.ProductReview{}).
+		Select("rating, COUNT(*) AS count").
+		Where("product_id = ? AND status = ?", productID, entities.ReviewStatusApproved).
+		Group("rating").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	histogram := map[int]int64{1: 0, 2: 0, 3: 0, 4: 0, 5: 0}
+	for _, row := range rows {
+		histogram[row.Rating] = row.Count
+	}
+	return histogram, nil
+}
+
+// Vote upserts a vote and recounts the review's helpful and unhelpful votes. The review
+// row is locked first so concurrent votes cannot recount from stale data.
+func (r *GormReviewRepository) Vote(ctx context.Context, vote *entities.ReviewVote) error {
+	return dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT 1 FROM product_reviews WHERE id = ? FOR UPDATE", vote.ReviewID).Error; err != nil {
+			return err
+		}
+
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "review_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"helpful", "updated_at"}),
+		}).Omit(clause.Associations).Create(vote).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Exec(`UPDATE product_reviews SET
+			helpful_count = (SELECT COUNT(*) FROM review_votes WHERE review_id = @id AND helpful),
+			unhelpful_count = (SELECT COUNT(*) FROM review_votes WHERE review_id = @id AND NOT helpful)
+			WHERE id = @id`, map[string]interface{}{"id": vote.ReviewID}).Error
+	})
+}
+
+// CountByIPSince counts the reviews submitted from an IP address since a time
+func (r *GormReviewRepository) CountByIPSince(ctx context.Context, ipAddress string, since time.Time) (int64, error) {
+	var count int64
+	err := dbFor(ctx, r.db).
+		Model(&entities.ProductReview{}).
+		Where("ip_address = ? AND created_at >= ?", ipAddress, since).
+		Count(&count).Error
+	return count, err
+}
+
+// CountByContentSince counts the reviews with exactly the given content since a time
+func (r *GormReviewRepository) CountByContentSince(ctx context.Context, content string, since time.Time) (int64, error) {
+	var count int64
+	err := dbFor(ctx, r.db).
+		Model(&entities.ProductReview{}).
+		Where("content = ? AND created_at >= ?", content, since).
+		Count(&count).Error
+	return count, err
+}
+
+// RecordPurchases stores new purchases and verifies the buyers' existing reviews
+func (r *GormReviewRepository) RecordPurchases(ctx context.Context, purchases []*entities.ProductPurchase) error {
+	if len(purchases) == 0 {
+		return nil
+	}
+
+	return dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(purchases, 500).Error
+		if err != nil {
+			return err
+		}
+
+		for _, purchase := range purchases {
+			err := tx.Model(&entities.ProductReview{}).
+				Where("user_id = ? AND product_id = ? AND is_verified = ?", purchase.UserID, purchase.ProductID, false).
+				Update("is_verified", true).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// HasPurchased checks if a user bought a product
+func (r *GormReviewRepository) HasPurchased(ctx context.Context, userID, productID uuid.UUID) (bool, error) {
+	var count int64
+	err := dbFor(ctx, r.db).
+		Model(&entities.ProductPurchase{}).
+		Where("user_id = ? AND product_id = ?", userID, productID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// applyReviewFilters applies review listing filters to the query
+func (r *GormReviewRepository) applyReviewFilters(db *gorm.DB, filters *repositories.ReviewFilters) *gorm.DB {
+	if filters.ProductID != nil {
+		db = db.Where("product_id = ?", *filters.ProductID)
+	}
+	if filters.Status != nil {
+		db = db.Where("status = ?", *filters.Status)
+	}
+	if filters.Rating != nil {
+		db = db.Where("rating = ?", *filters.Rating)
+	}
+	if filters.VerifiedOnly {
+		db = db.Where("is_verified = ?", true)
+	}
+	return db
+}
+
+// reviewOrder returns the ORDER BY clause for a review sort order
+func reviewOrder(sort string) string {
+	switch sort {
+	case repositories.ReviewSortOldest:
+		return "created_at ASC"
+	case repositories.ReviewSortHighest:
+		return "rating DESC, created_at DESC"
+	case repositories.ReviewSortLowest:
+		return "rating ASC, created_at DESC"
+	case repositories.ReviewSortMostHelpful:
+		return "helpful_count DESC, unhelpful_count ASC, created_at DESC"
+	default:
+		return "created_at DESC"
+	}
+}
+
+// lockProductForRating locks a product's row until the transaction ends. Review writes
+// take it before changing reviews, so of two concurrent writes the second recomputes
+// the rating only after the first has committed.
+func lockProductForRating(tx *gorm.DB, productID uuid.UUID) error {
+	return tx.Exec("SELECT 1 FROM products WHERE id = ? FOR UPDATE", productID).Error
+}
+
+// recomputeRating sets a product's average rating and review count from its approved
+// reviews
+func recomputeRating(tx *gorm.DB, productID uuid.UUID) error {
+	return tx.Exec(`UPDATE products SET
+		average_rating = COALESCE((SELECT ROUND(AVG(rating)::numeric, 2) FROM product_reviews WHERE product_id = @id AND status = @status), 0),
+		review_count = (SELECT COUNT(*) FROM product_reviews WHERE product_id = @id AND status = @status)
+		WHERE id = @id`, map[string]interface{}{"id": productID, "status": entities.ReviewStatusApproved}).Error
+}
@@ -0,0 +1,116 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// GormSaleRepository implements SaleRepository using GORM
+type GormSaleRepository struct {
+	db *gorm.DB
+}
+
+// NewGormSaleRepository creates a new GORM sale repository
+func NewGormSaleRepository(db *gorm.DB) repositories.SaleRepository {
+	return &GormSaleRepository{db: db}
+}
+
+// Create creates a new sale
+func (r *GormSaleRepository) Create(ctx context.Context, sale *entities.Sale) error {
+	return dbFor(ctx, r.db).Create(sale).Error
+}
+
+// GetByID retrieves a sale by ID
+func (r *GormSaleRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Sale, error) {
+	var sale entities.Sale
+	err := dbFor(ctx, r.db).Where("id = ?", id).First(&sale).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &sale, nil
+}
+
+// Update updates a sale
+func (r *GormSaleRepository) Update(ctx context.Context, sale *entities.Sale) error {
+	return dbFor(ctx, r.db).Save(sale).Error
+}
+
+// Delete deletes a sale
+func (r *GormSaleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return dbFor(ctx, r.db).Delete(&entities.Sale{}, id).Error
+}
+
+// List retrieves sales matching filters, soonest ending first
+func (r *GormSaleRepository) List(ctx context.Context, filters repositories.SaleFilters, limit, offset int) ([]*entities.Sale, error) {
+	var sales []*entities.Sale
+	err := r.applyFilters(dbFor(ctx, r.db), filters).
+		Order("ends_at, starts_at").
+		Limit(limit).
+		Offset(offset).
+		Find(&sales).Error
+	return sales, err
+}
+
+// Count counts sales matching filters
+func (r *GormSaleRepository) Count(ctx context.Context, filters repositories.SaleFilters) (int64, error) {
+	var count int64
+	err := r.applyFilters(dbFor(ctx, r.db).Model(&entities.Sale{}), filters).
+		Count(&count).Error
+	return count, err
+}
+
+// FindRunning retrieves the active sales running at a time that target any of the
+// given products, categories or brands
+func (r *GormSaleRepository) FindRunning(ctx context.Context, productIDs, categoryIDs, brandIDs []uuid.UUID, at time.Time) ([]*entities.Sale, error) {
+	if len(productIDs) == 0 && len(categoryIDs) == 0 && len(brandIDs) == 0 {
+		return nil, nil
+	}
+
+	targets := r.db.Where("1 = 0")
+	if len(productIDs) > 0 {
+		targets = targets.Or("scope = ? AND target_id IN ?", entities.SaleScopeProduct, productIDs)
+	}
+	if len(categoryIDs) > 0 {
+		targets = targets.Or("scope = ? AND target_id IN ?", entities.SaleScopeCategory, categoryIDs)
+	}
+	if len(brandIDs) > 0 {
+		targets = targets.Or("scope = ? AND target_id IN ?", entities.SaleScopeBrand, brandIDs)
+	}
+
+	var sales []*entities.Sale
+	err := dbFor(ctx, r.db).
+		Where("is_active = ? AND starts_at <= ? AND ends_at > ?", true, at, at).
+		Where(targets).
+		Find(&sales).Error
+	return sales, err
+}
+
+// applyFilters narrows a sale query by filters
+func (r *GormSaleRepository) applyFilters(query *gorm.DB, filters repositories.SaleFilters) *gorm.DB {
+	if filters.Scope != "" {
+		query = query.Where("scope = ?", filters.Scope)
+	}
+	if filters.TargetID != nil {
+		query = query.Where("target_id = ?", *filters.TargetID)
+	}
+
+	now := time.Now()
+	switch filters.Status {
+	case "scheduled":
+		query = query.Where("starts_at > ?", now)
+	case "running":
+		query = query.Where("is_active = ? AND starts_at <= ? AND ends_at > ?", true, now, now)
+	case "ended":
+		query = query.Where("ends_at <= ?", now)
+	}
+
+	return query
+}
@@ -0,0 +1,92 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// GormSearchAnalyticsRepository implements SearchAnalyticsRepository using GORM
+type GormSearchAnalyticsRepository struct {
+	db *gorm.DB
+}
+
+// NewGormSearchAnalyticsRepository creates a new GORM search analytics repository
+func NewGormSearchAnalyticsRepository(db *gorm.DB) repositories.SearchAnalyticsRepository {
+	return &GormSearchAnalyticsRepository{db: db}
+}
+
+// CreateBulk inserts search logs in batches
+func (r *GormSearchAnalyticsRepository) CreateBulk(ctx context.Context, logs []*entities.SearchLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	return dbFor(ctx, r.db).CreateInBatches(logs, 100).Error
+}
+
+// RecordClick stores the first product clicked from a search; later clicks are ignored
+func (r *GormSearchAnalyticsRepository) RecordClick(ctx context.Context, searchID, productID uuid.UUID, position int, clickedAt time.Time) error {
+	return dbFor(ctx, r.db).
+		Model(&entities.SearchLog{}).
+		Where("id = ? AND clicked_product_id IS NULL", searchID).
+		Updates(map[string]interface{}{
+			"clicked_product_id": productID,
+			"clicked_position":   position,
+			"clicked_at":         clickedAt,
+		}).Error
+}
+
+// GetSummary aggregates all searches in [from, to)
+func (r *GormSearchAnalyticsRepository) GetSummary(ctx context.Context, from, to time.Time) (*repositories.SearchAnalyticsSummary, error) {
+	var summary repositories.SearchAnalyticsSummary
+	err := dbFor(ctx, r.db).
+		Model(&entities.SearchLog{}).
+		Select("COUNT(*) AS total_searches, " +
+			"COUNT(DISTINCT normalized_query) AS unique_queries, " +
+			"COUNT(*) FILTER (WHERE result_count = 0) AS zero_result_count, " +
+			"COUNT(clicked_product_id) AS click_count, " +
+			"COALESCE(COUNT(clicked_product_id)::float / NULLIF(COUNT(*), 0), 0) AS click_through_rate, " +
+			"COALESCE(AVG(result_count), 0) AS average_results, " +
+			"COALESCE(AVG(latency_ms), 0) AS average_latency_ms").
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Scan(&summary).Error
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// GetTopTerms returns the most frequent non-empty queries in [from, to)
+func (r *GormSearchAnalyticsRepository) GetTopTerms(ctx context.Context, from, to time.Time, limit int) ([]*repositories.SearchTermStats, error) {
+	return r.termStats(ctx, from, to, limit, false)
+}
+
+// GetZeroResultTerms returns the most frequent queries that found nothing in [from, to)
+func (r *GormSearchAnalyticsRepository) GetZeroResultTerms(ctx context.Context, from, to time.Time, limit int) ([]*repositories.SearchTermStats, error) {
+	return r.termStats(ctx, from, to, limit, true)
+}
+
+func (r *GormSearchAnalyticsRepository) termStats(ctx context.Context, from, to time.Time, limit int, zeroResultsOnly bool) ([]*repositories.SearchTermStats, error) {
+	db := dbFor(ctx, r.db).
+		Model(&entities.SearchLog{}).
+		Select("normalized_query AS term, " +
+			"COUNT(*) AS count, " +
+			"AVG(result_count) AS average_results, " +
+			"COUNT(clicked_product_id)::float / COUNT(*) AS click_through_rate").
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Where("normalized_query <> ''")
+	if zeroResultsOnly {
+		db = db.Where("result_count = 0")
+	}
+
+	var stats []*repositories.SearchTermStats
+	err := db.Group("normalized_query").
+		Order("count DESC, term").
+		Limit(limit).
+		Scan(&stats).Error
+	return stats, err
+}
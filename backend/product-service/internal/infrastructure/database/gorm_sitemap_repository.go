@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// GormSitemapRepository implements SitemapRepository using GORM
+type GormSitemapRepository struct {
+	db *gorm.DB
+}
+
+// NewGormSitemapRepository creates a new GORM sitemap repository
+func NewGormSitemapRepository(db *gorm.DB) repositories.SitemapRepository {
+	return &GormSitemapRepository{db: db}
+}
+
+// GetPageLastMods numbers the entries in sitemap order and returns the latest update
+// time within each page
+func (r *GormSitemapRepository) GetPageLastMods(ctx context.Context, entityType entities.SlugEntityType, pageSize int) ([]time.Time, error) {
+	listed, err := r.listed(r.db, entityType)
+	if err != nil {
+		return nil, err
+	}
+	numbered := listed.Select("updated_at, (ROW_NUMBER() OVER (ORDER BY created_at, id) - 1) / ? AS page", pageSize)
+
+	var pages []struct {
+		LastMod time.Time
+	}
+	err = dbFor(ctx, r.db).
+		Table("(?) AS numbered", numbered).
+		Select("MAX(updated_at) AS last_mod").
+		Group("page").
+		Order("page").
+		Scan(&pages).Error
+	if err != nil {
+		return nil, err
+	}
+
+	lastMods := make([]time.Time, len(pages))
+	for i, page := range pages {
+		lastMods[i] = page.LastMod
+	}
+	return lastMods, nil
+}
+
+// GetEntries retrieves a page of entries in sitemap order
+func (r *GormSitemapRepository) GetEntries(ctx context.Context, entityType entities.SlugEntityType, limit, offset int) ([]*repositories.SitemapEntry, error) {
+	listed, err := r.listed(dbFor(ctx, r.db), entityType)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*repositories.SitemapEntry
+	err = listed.
+		Select("slug, updated_at").
+		Order("created_at, id").
+		Limit(limit).
+		Offset(offset).
+		Scan(&entries).Error
+	return entries, err
+}
+
+// listed scopes db to the entities of a type that belong in the sitemap
+func (r *GormSitemapRepository) listed(db *gorm.DB, entityType entities.SlugEntityType) (*gorm.DB, error) {
+	switch entityType {
+	case entities.SlugEntityProduct:
+		return db.Model(&entities.Product{}).
+			Where("status = ? AND visibility <> ? AND deleted_at IS NULL", entities.ProductStatusActive, entities.VisibilityHidden), nil
+	case entities.SlugEntityCategory:
+		return db.Model(&entities.Category{}).
+			Where("is_active = ? AND is_visible = ? AND deleted_at IS NULL", true, true), nil
+	}
+	return nil, fmt.Errorf("no sitemap for %s entities", entityType)
+}
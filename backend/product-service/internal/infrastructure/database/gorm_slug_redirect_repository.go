@@ -0,0 +1,52 @@
+package database
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// GormSlugRedirectRepository implements SlugRedirectRepository using GORM
+type GormSlugRedirectRepository struct {
+	db *gorm.DB
+}
+
+// NewGormSlugRedirectRepository creates a new GORM slug redirect repository
+func NewGormSlugRedirectRepository(db *gorm.DB) repositories.SlugRedirectRepository {
+	return &GormSlugRedirectRepository{db: db}
+}
+
+// Record upserts old slugs
+func (r *GormSlugRedirectRepository) Record(ctx context.Context, redirects ...*entities.SlugRedirect) error {
+	return recordSlugRedirects(dbFor(ctx, r.db), redirects)
+}
+
+// Resolve retrieves the redirect recorded for an old slug
+func (r *GormSlugRedirectRepository) Resolve(ctx context.Context, entityType entities.SlugEntityType, slug string) (*entities.SlugRedirect, error) {
+	var redirect entities.SlugRedirect
+	err := dbFor(ctx, r.db).
+		Where("entity_type = ? AND slug = ?", entityType, slug).
+		First(&redirect).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &redirect, nil
+}
+
+// recordSlugRedirects upserts old slugs with db, pointing a slug recorded before at the
+// entity that gave it up last
+func recordSlugRedirects(db *gorm.DB, redirects []*entities.SlugRedirect) error {
+	if len(redirects) == 0 {
+		return nil
+	}
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "entity_type"}, {Name: "slug"}},
+		DoUpdates: clause.AssignmentColumns([]string{"entity_id", "created_at"}),
+	}).Create(redirects).Error
+}
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"product-service/internal/domain/repositories"
+)
+
+// transactionKey is the context key a transaction is carried under
+type transactionKey struct{}
+
+// transaction is an open transaction and the work waiting for it to commit
+type transaction struct {
+	tx          *gorm.DB
+	afterCommit []func()
+}
+
+// GormTransactionManager implements TransactionManager using GORM
+type GormTransactionManager struct {
+	db *gorm.DB
+}
+
+// NewGormTransactionManager creates a new GORM transaction manager
+func NewGormTransactionManager(db *gorm.DB) repositories.TransactionManager {
+	return &GormTransactionManager{db: db}
+}
+
+// WithinTransaction runs fn in a transaction. Inside another transaction it runs in a
+// savepoint of that one instead, and its after-commit work waits for the outer commit.
+func (m *GormTransactionManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	outer, _ := ctx.Value(transactionKey{}).(*transaction)
+	current := &transaction{}
+	err := dbFor(ctx, m.db).Transaction(func(tx *gorm.DB) error {
+		current.tx = tx
+		return fn(context.WithValue(ctx, transactionKey{}, current))
+	})
+	if err != nil {
+		return err
+	}
+
+	if outer != nil {
+		outer.afterCommit = append(outer.afterCommit, current.afterCommit...)
+		return nil
+	}
+	for _, run := range current.afterCommit {
+		run()
+	}
+	return nil
+}
+
+// AfterCommit runs fn once the transaction ctx carries has committed, or right away
+// outside of one
+func (m *GormTransactionManager) AfterCommit(ctx context.Context, fn func()) {
+	if current, ok := ctx.Value(transactionKey{}).(*transaction); ok {
+		current.afterCommit = append(current.afterCommit, fn)
+		return
+	}
+	fn()
+}
+
+// dbFor returns the transaction ctx carries, or db outside of one, bound to ctx.
+// Repositories use it so their calls join the caller's transaction.
+func dbFor(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if current, ok := ctx.Value(transactionKey{}).(*transaction); ok {
+		return current.tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
@@ -0,0 +1,28 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"product-service/internal/domain/events"
+)
+
+// LogPublisher writes events to the service log. It stands in for a message broker
+// until one is wired up.
+type LogPublisher struct{}
+
+// NewLogPublisher creates a new log event publisher
+func NewLogPublisher() events.Publisher {
+	return &LogPublisher{}
+}
+
+// Publish logs the event as JSON
+func (p *LogPublisher) Publish(ctx context.Context, event *events.Event) error {
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	log.Printf("[Product Service] Publishing %s event: %s", event.EventType, eventJSON)
+	return nil
+}
@@ -0,0 +1,966 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"product-service/internal/domain"
+	"product-service/internal/ports"
+)
+
+// Indexed fields
+const (
+	fieldName        = "name"
+	fieldSKU         = "sku"
+	fieldBrand       = "brand"
+	fieldTags        = "tags"
+	fieldDescription = "description"
+)
+
+var indexedFields = []string{fieldName, fieldSKU, fieldBrand, fieldTags, fieldDescription}
+
+// defaultBoosts weight matches per field; SearchQuery.Boost overrides them per query
+var defaultBoosts = map[string]float64{
+	fieldName:        3,
+	fieldSKU:         4,
+	fieldBrand:       2,
+	fieldTags:        1.5,
+	fieldDescription: 1,
+}
+
+// Facet names supported by Search
+const (
+	FacetCategory = "category"
+	FacetBrand    = "brand"
+	FacetStatus   = "status"
+	FacetTags     = "tags"
+	FacetPrice    = "price"
+	FacetStock    = "stock"
+	FacetFeatured = "featured"
+)
+
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+
+	defaultPageSize      = 20
+	prefixExpansionLimit = 50
+	prefixMatchWeight    = 0.8
+	maxSearchLogEntries  = 10000
+	maxNoResultQueries   = 50
+	topSearchTermsLimit  = 10
+)
+
+// priceRanges are the price facet buckets as [min, max) pairs; max 0 means unbounded
+var priceRanges = [][2]float64{{0, 25}, {25, 50}, {50, 100}, {100, 250}, {250, 500}, {500, 0}}
+
+// EmbeddedSearchRepository is an in-process inverted index implementing
+// ports.SearchRepository. It ranks with BM25 across weighted fields, supports prefix
+// and typo-tolerant matching, facets, suggestions and search analytics, so dev and
+// CI environments can run real search without an Elasticsearch cluster.
+type EmbeddedSearchRepository struct {
+	mu           sync.RWMutex
+	docs         map[string]*document
+	postings     map[string]map[string]map[string]int // field -> term -> doc ID -> term frequency
+	fieldLengths map[string]map[string]int            // field -> doc ID -> number of terms
+	totalLengths map[string]int                       // field -> sum of field lengths
+	vocabulary   map[string]int                       // term -> number of postings across fields
+	sortedTerms  []string                             // vocabulary in order for prefix lookups, rebuilt after writes
+	termsDirty   bool
+
+	logMu     sync.Mutex
+	searchLog []searchEvent
+}
+
+var _ ports.SearchRepository = (*EmbeddedSearchRepository)(nil)
+
+// document is the indexed representation of a product
+type document struct {
+	ID             string
+	Name           string
+	Description    string
+	SKU            string
+	Brand          string
+	CategoryID     string
+	Tags           []string
+	PriceCents     int64
+	Currency       string
+	CompareCents   *int64
+	Status         int
+	Featured       bool
+	Quantity       int
+	Reserved       int
+	TrackStock     bool
+	AllowBackorder bool
+	Slug           string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Version        int
+}
+
+// searchEvent records a single executed search for analytics
+type searchEvent struct {
+	Query   string
+	Results int64
+	At      time.Time
+}
+
+// NewEmbeddedSearchRepository creates an empty embedded search index
+func NewEmbeddedSearchRepository() *EmbeddedSearchRepository {
+	r := &EmbeddedSearchRepository{}
+	r.reset()
+	return r
+}
+
+func (r *EmbeddedSearchRepository) reset() {
+	r.docs = make(map[string]*document)
+	r.postings = make(map[string]map[string]map[string]int, len(indexedFields))
+	r.fieldLengths = make(map[string]map[string]int, len(indexedFields))
+	r.totalLengths = make(map[string]int, len(indexedFields))
+	r.vocabulary = make(map[string]int)
+	for _, field := range indexedFields {
+		r.postings[field] = make(map[string]map[string]int)
+		r.fieldLengths[field] = make(map[string]int)
+	}
+	r.sortedTerms = nil
+	r.termsDirty = true
+}
+
+// IndexProduct adds a product to the index, replacing any previous version
+func (r *EmbeddedSearchRepository) IndexProduct(ctx context.Context, product *domain.Product) error {
+	if product == nil {
+		return fmt.Errorf("product is required")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.add(newDocument(product))
+	r.refreshSortedTerms()
+	return nil
+}
+
+// UpdateProduct re-indexes a product
+func (r *EmbeddedSearchRepository) UpdateProduct(ctx context.Context, product *domain.Product) error {
+	return r.IndexProduct(ctx, product)
+}
+
+// DeleteProduct removes a product from the index
+func (r *EmbeddedSearchRepository) DeleteProduct(ctx context.Context, id domain.ProductID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.remove(id.String())
+	r.refreshSortedTerms()
+	return nil
+}
+
+// BulkIndex adds or replaces many products under a single lock
+func (r *EmbeddedSearchRepository) BulkIndex(ctx context.Context, products []*domain.Product) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	defer r.refreshSortedTerms()
+
+	for _, product := range products {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if product != nil {
+			r.add(newDocument(product))
+		}
+	}
+	return nil
+}
+
+// BulkDelete removes many products under a single lock
+func (r *EmbeddedSearchRepository) BulkDelete(ctx context.Context, ids []domain.ProductID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range ids {
+		r.remove(id.String())
+	}
+	r.refreshSortedTerms()
+	return nil
+}
+
+// Count returns the number of indexed products
+func (r *EmbeddedSearchRepository) Count() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.docs)
+}
+
+// Search runs a ranked query with filters, facets, pagination and "did you mean" suggestions
+func (r *EmbeddedSearchRepository) Search(ctx context.Context, query ports.SearchQuery) (*ports.SearchResult, error) {
+	start := time.Now()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	words := queryWords(query.Query)
+	matches, corrections := r.match(words, query.Fuzzy, query.Boost)
+
+	// Facets are disjunctive: each is counted under every filter except its own
+	var facets map[string][]ports.Facet
+	if len(query.Facets) > 0 {
+		facets = make(map[string][]ports.Facet, len(query.Facets))
+		for _, name := range query.Facets {
+			facets[name] = r.facet(name, matches, query.Filters)
+		}
+	}
+
+	ids := make([]string, 0, len(matches))
+	for id := range matches {
+		if matchesFilter(r.docs[id], query.Filters, "") {
+			ids = append(ids, id)
+		}
+	}
+	r.sortResults(ids, matches, query.Filters, len(words) > 0)
+
+	total := int64(len(ids))
+	limit := query.Filters.Limit
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	page := query.Filters.Page
+	if page <= 0 {
+		page = 1
+	}
+	from := (page - 1) * limit
+	if from > len(ids) {
+		from = len(ids)
+	}
+	to := from + limit
+	if to > len(ids) {
+		to = len(ids)
+	}
+
+	products := make([]*domain.Product, 0, to-from)
+	for _, id := range ids[from:to] {
+		products = append(products, r.docs[id].toDomain())
+	}
+
+	var suggestions []string
+	if len(corrections) > 0 {
+		suggestions = []string{applyCorrections(words, corrections)}
+	}
+
+	r.logSearch(query.Query, total)
+
+	return &ports.SearchResult{
+		Products:    products,
+		Total:       total,
+		Facets:      facets,
+		Suggestions: suggestions,
+		Took:        time.Since(start).Milliseconds(),
+	}, nil
+}
+
+// Suggest returns product name and brand completions for a partially typed query,
+// tolerating typos in the last word
+func (r *EmbeddedSearchRepository) Suggest(ctx context.Context, query string, limit int) ([]string, error) {
+	words := splitWords(query)
+	if len(words) == 0 {
+		return []string{}, nil
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	scores := make(map[string]float64)
+	brandCounts := make(map[string]int)
+	for _, doc := range r.docs {
+		if doc.Brand != "" {
+			brandCounts[doc.Brand]++
+		}
+
+		if score := completionScore(words, doc.Name); score > 0 {
+			popularity := 0.0
+			if doc.Featured {
+				popularity += 0.5
+			}
+			if doc.inStock() {
+				popularity += 0.25
+			}
+			if score+popularity > scores[doc.Name] {
+				scores[doc.Name] = score + popularity
+			}
+		}
+	}
+
+	for brand, count := range brandCounts {
+		if score := completionScore(words, brand); score > 0 {
+			scores[brand] = math.Max(scores[brand], score+math.Log1p(float64(count)))
+		}
+	}
+
+	return topSuggestions(scores, limit), nil
+}
+
+// Match is a product matching a query, with its relevance score
+type Match struct {
+	ProductID string
+	Score     float64
+}
+
+// Rank matches query against the index without filters and returns the matches best
+// first, for callers that filter and page the results elsewhere. Searches through Rank
+// are not logged for analytics.
+func (r *EmbeddedSearchRepository) Rank(query string, fuzzy bool) []Match {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	words := queryWords(query)
+	if len(words) == 0 {
+		return []Match{}
+	}
+	scores, _ := r.match(words, fuzzy, nil)
+
+	ids := make([]string, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+	r.sortResults(ids, scores, ports.ProductFilter{}, true)
+
+	matches := make([]Match, len(ids))
+	for i, id := range ids {
+		matches[i] = Match{ProductID: id, Score: scores[id]}
+	}
+	return matches
+}
+
+// GetSearchAnalytics summarises searches executed within the period (e.g. "24h", "7d", "all")
+func (r *EmbeddedSearchRepository) GetSearchAnalytics(ctx context.Context, period string) (*ports.SearchAnalytics, error) {
+	window, err := ports.ParsePeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	events := r.searchEvents(window)
+
+	analytics := &ports.SearchAnalytics{
+		TopSearchTerms:   []ports.SearchTerm{},
+		NoResultsQueries: []string{},
+	}
+
+	counts := make(map[string]int64)
+	noResults := make(map[string]struct{})
+	var totalResults int64
+	for _, event := range events {
+		analytics.TotalSearches++
+		totalResults += event.Results
+		counts[event.Query]++
+		if event.Results == 0 {
+			if _, seen := noResults[event.Query]; !seen && len(noResults) < maxNoResultQueries {
+				noResults[event.Query] = struct{}{}
+				analytics.NoResultsQueries = append(analytics.NoResultsQueries, event.Query)
+			}
+		}
+	}
+
+	analytics.UniqueSearches = int64(len(counts))
+	if analytics.TotalSearches > 0 {
+		analytics.AverageResults = float64(totalResults) / float64(analytics.TotalSearches)
+	}
+	analytics.TopSearchTerms = topTerms(counts, topSearchTermsLimit)
+
+	return analytics, nil
+}
+
+// GetPopularSearchTerms returns the most frequently searched terms
+func (r *EmbeddedSearchRepository) GetPopularSearchTerms(ctx context.Context, limit int) ([]string, error) {
+	counts := make(map[string]int64)
+	for _, event := range r.searchEvents(0) {
+		counts[event.Query]++
+	}
+
+	terms := topTerms(counts, limit)
+	popular := make([]string, len(terms))
+	for i, term := range terms {
+		popular[i] = term.Term
+	}
+	return popular, nil
+}
+
+// add indexes a document; callers must hold the write lock
+func (r *EmbeddedSearchRepository) add(doc *document) {
+	r.remove(doc.ID)
+	r.docs[doc.ID] = doc
+
+	for field, terms := range doc.fieldTerms() {
+		r.fieldLengths[field][doc.ID] = len(terms)
+		r.totalLengths[field] += len(terms)
+		for _, term := range terms {
+			postings := r.postings[field][term]
+			if postings == nil {
+				postings = make(map[string]int)
+				r.postings[field][term] = postings
+			}
+			if postings[doc.ID] == 0 {
+				r.vocabulary[term]++
+			}
+			postings[doc.ID]++
+		}
+	}
+	r.termsDirty = true
+}
+
+// remove drops a document from the index; callers must hold the write lock
+func (r *EmbeddedSearchRepository) remove(id string) {
+	doc, ok := r.docs[id]
+	if !ok {
+		return
+	}
+
+	for field, terms := range doc.fieldTerms() {
+		r.totalLengths[field] -= r.fieldLengths[field][id]
+		delete(r.fieldLengths[field], id)
+		for _, term := range terms {
+			postings := r.postings[field][term]
+			if _, ok := postings[id]; !ok {
+				continue
+			}
+			delete(postings, id)
+			if len(postings) == 0 {
+				delete(r.postings[field], term)
+			}
+			if r.vocabulary[term]--; r.vocabulary[term] <= 0 {
+				delete(r.vocabulary, term)
+			}
+		}
+	}
+	delete(r.docs, id)
+	r.termsDirty = true
+}
+
+// refreshSortedTerms rebuilds the ordered vocabulary once a write has changed it, so
+// searches only need the read lock; callers must hold the write lock
+func (r *EmbeddedSearchRepository) refreshSortedTerms() {
+	if !r.termsDirty {
+		return
+	}
+	r.sortedTerms = make([]string, 0, len(r.vocabulary))
+	for term := range r.vocabulary {
+		r.sortedTerms = append(r.sortedTerms, term)
+	}
+	sort.Strings(r.sortedTerms)
+	r.termsDirty = false
+}
+
+// match scores every document containing all query words. Each word expands to its
+// exact term, prefix completions (last word only) and, when fuzzy is set, terms within
+// the allowed edit distance. It also returns spelling corrections for unknown words.
+func (r *EmbeddedSearchRepository) match(words []string, fuzzy bool, boosts map[string]float64) (map[string]float64, map[int]string) {
+	corrections := make(map[int]string)
+
+	if len(words) == 0 {
+		all := make(map[string]float64, len(r.docs))
+		for id := range r.docs {
+			all[id] = 0
+		}
+		return all, corrections
+	}
+
+	var scores map[string]float64
+	for i, word := range words {
+		candidates := make(map[string]float64)
+		term := stem(word)
+
+		// The last word may still be being typed, so it also matches as a prefix
+		var completions []string
+		if i == len(words)-1 && len(word) >= 2 {
+			completions = r.termsWithPrefix(word, prefixExpansionLimit)
+		}
+
+		if _, ok := r.vocabulary[term]; ok {
+			candidates[term] = 1
+		} else if len(completions) == 0 {
+			if correction, distance := r.closestTerm(term); correction != "" {
+				// Keep the plural the user typed so the suggestion reads naturally
+				corrections[i] = correction
+				if strings.HasPrefix(word, term) {
+					corrections[i] += word[len(term):]
+				}
+				if fuzzy {
+					candidates[correction] = 1 / float64(1+distance)
+				}
+			}
+		}
+		for _, completion := range completions {
+			if _, ok := candidates[completion]; !ok {
+				candidates[completion] = prefixMatchWeight
+			}
+		}
+		if len(candidates) == 0 {
+			return map[string]float64{}, corrections
+		}
+
+		wordScores := r.scoreCandidates(candidates, boosts)
+		if scores == nil {
+			scores = wordScores
+			continue
+		}
+		for id := range scores {
+			if s, ok := wordScores[id]; ok {
+				scores[id] += s
+			} else {
+				delete(scores, id)
+			}
+		}
+		if len(scores) == 0 {
+			break
+		}
+	}
+
+	return scores, corrections
+}
+
+// scoreCandidates computes each document's best BM25 score for a set of weighted
+// candidate terms, summed across boosted fields
+func (r *EmbeddedSearchRepository) scoreCandidates(candidates map[string]float64, boosts map[string]float64) map[string]float64 {
+	scores := make(map[string]float64)
+	n := float64(len(r.docs))
+
+	for _, field := range indexedFields {
+		boost := defaultBoosts[field]
+		if b, ok := boosts[field]; ok {
+			boost = b
+		}
+		if boost <= 0 || len(r.fieldLengths[field]) == 0 {
+			continue
+		}
+		avgLength := float64(r.totalLengths[field]) / float64(len(r.fieldLengths[field]))
+		if avgLength == 0 {
+			avgLength = 1
+		}
+
+		best := make(map[string]float64)
+		for term, weight := range candidates {
+			postings := r.postings[field][term]
+			if len(postings) == 0 {
+				continue
+			}
+			df := float64(len(postings))
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			for id, tf := range postings {
+				length := float64(r.fieldLengths[field][id])
+				norm := float64(tf) * (bm25K1 + 1) / (float64(tf) + bm25K1*(1-bm25B+bm25B*length/avgLength))
+				if s := weight * idf * norm; s > best[id] {
+					best[id] = s
+				}
+			}
+		}
+		for id, s := range best {
+			scores[id] += boost * s
+		}
+	}
+
+	return scores
+}
+
+// termsWithPrefix returns up to limit vocabulary terms starting with prefix
+func (r *EmbeddedSearchRepository) termsWithPrefix(prefix string, limit int) []string {
+	var terms []string
+	for i := sort.SearchStrings(r.sortedTerms, prefix); i < len(r.sortedTerms) && len(terms) < limit; i++ {
+		if !strings.HasPrefix(r.sortedTerms[i], prefix) {
+			break
+		}
+		terms = append(terms, r.sortedTerms[i])
+	}
+	return terms
+}
+
+// closestTerm finds the most frequent vocabulary term within the allowed edit distance
+func (r *EmbeddedSearchRepository) closestTerm(term string) (string, int) {
+	limit := maxEdits(term)
+	if limit == 0 {
+		return "", 0
+	}
+
+	best, bestDistance, bestFreq := "", limit+1, 0
+	for candidate, freq := range r.vocabulary {
+		d := editDistance(term, candidate, limit)
+		if d < bestDistance || (d == bestDistance && freq > bestFreq) ||
+			(d == bestDistance && freq == bestFreq && candidate < best) {
+			best, bestDistance, bestFreq = candidate, d, freq
+		}
+	}
+	if bestDistance > limit {
+		return "", 0
+	}
+	return best, bestDistance
+}
+
+// facet counts values of one facet over matches, applying every filter but the facet's own
+func (r *EmbeddedSearchRepository) facet(name string, matches map[string]float64, filter ports.ProductFilter) []ports.Facet {
+	counts := make(map[string]int64)
+	for id := range matches {
+		doc := r.docs[id]
+		if !matchesFilter(doc, filter, name) {
+			continue
+		}
+		for _, value := range doc.facetValues(name) {
+			counts[value]++
+		}
+	}
+
+	facets := make([]ports.Facet, 0, len(counts))
+	for value, count := range counts {
+		facets = append(facets, ports.Facet{Value: value, Count: count})
+	}
+	sort.Slice(facets, func(i, j int) bool {
+		if facets[i].Count != facets[j].Count {
+			return facets[i].Count > facets[j].Count
+		}
+		return facets[i].Value < facets[j].Value
+	})
+	return facets
+}
+
+// sortResults orders ids by the requested sort, defaulting to relevance for text queries
+func (r *EmbeddedSearchRepository) sortResults(ids []string, scores map[string]float64, filter ports.ProductFilter, hasQuery bool) {
+	desc := !strings.EqualFold(filter.SortOrder, "asc")
+	sortBy := strings.ToLower(filter.SortBy)
+	if sortBy == "" {
+		if hasQuery {
+			sortBy = "relevance"
+		} else {
+			sortBy = "created_at"
+		}
+	}
+
+	sort.SliceStable(ids, func(i, j int) bool {
+		a, b := r.docs[ids[i]], r.docs[ids[j]]
+		var less, equal bool
+		switch sortBy {
+		case "price":
+			less, equal = a.PriceCents < b.PriceCents, a.PriceCents == b.PriceCents
+		case "name":
+			less, equal = strings.ToLower(a.Name) < strings.ToLower(b.Name), strings.EqualFold(a.Name, b.Name)
+		case "created_at":
+			less, equal = a.CreatedAt.Before(b.CreatedAt), a.CreatedAt.Equal(b.CreatedAt)
+		default:
+			// Relevance always ranks best first regardless of SortOrder
+			sa, sb := scores[ids[i]], scores[ids[j]]
+			if sa != sb {
+				return sa > sb
+			}
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		if equal {
+			return a.ID < b.ID
+		}
+		return less != desc
+	})
+}
+
+// logSearch records a query for analytics, keeping a bounded history
+func (r *EmbeddedSearchRepository) logSearch(query string, results int64) {
+	normalized := strings.Join(splitWords(query), " ")
+	if normalized == "" {
+		return
+	}
+
+	r.logMu.Lock()
+	defer r.logMu.Unlock()
+
+	r.searchLog = append(r.searchLog, searchEvent{Query: normalized, Results: results, At: time.Now().UTC()})
+	if len(r.searchLog) > maxSearchLogEntries {
+		r.searchLog = r.searchLog[len(r.searchLog)-maxSearchLogEntries:]
+	}
+}
+
+// searchEvents returns logged searches within window (all when window is 0)
+func (r *EmbeddedSearchRepository) searchEvents(window time.Duration) []searchEvent {
+	r.logMu.Lock()
+	defer r.logMu.Unlock()
+
+	if window <= 0 {
+		return append([]searchEvent(nil), r.searchLog...)
+	}
+
+	cutoff := time.Now().UTC().Add(-window)
+	i := sort.Search(len(r.searchLog), func(i int) bool { return !r.searchLog[i].At.Before(cutoff) })
+	return append([]searchEvent(nil), r.searchLog[i:]...)
+}
+
+// newDocument converts a domain product into its indexed form
+func newDocument(product *domain.Product) *document {
+	inventory := product.Inventory()
+	doc := &document{
+		ID:             product.ID().String(),
+		Name:           product.Name(),
+		Description:    product.Description(),
+		SKU:            product.SKU(),
+		Brand:          product.Brand(),
+		CategoryID:     product.CategoryID().String(),
+		Tags:           append([]string(nil), product.Tags()...),
+		PriceCents:     int64(math.Round(product.Price().Amount() * 100)),
+		Currency:       product.Price().Currency(),
+		Status:         int(product.Status()),
+		Featured:       product.IsFeatured(),
+		Quantity:       inventory.Quantity(),
+		Reserved:       inventory.Reserved(),
+		TrackStock:     inventory.TrackStock(),
+		AllowBackorder: inventory.AllowBackorder(),
+		Slug:           product.SEO().Slug(),
+		CreatedAt:      product.CreatedAt(),
+		UpdatedAt:      product.UpdatedAt(),
+		Version:        product.Version(),
+	}
+	if compare := product.ComparePrice(); compare != nil {
+		cents := int64(math.Round(compare.Amount() * 100))
+		doc.CompareCents = &cents
+	}
+	return doc
+}
+
+// toDomain rebuilds the domain product for search results
+func (d *document) toDomain() *domain.Product {
+	id, _ := domain.ProductIDFromString(d.ID)
+	categoryID, _ := domain.CategoryIDFromString(d.CategoryID)
+
+	var comparePrice *domain.Money
+	if d.CompareCents != nil {
+		money := domain.NewMoney(float64(*d.CompareCents)/100, d.Currency)
+		comparePrice = &money
+	}
+
+	return domain.RestoreProduct(domain.ProductData{
+		ID:             id,
+		Name:           d.Name,
+		Description:    d.Description,
+		SKU:            d.SKU,
+		Price:          domain.NewMoney(float64(d.PriceCents)/100, d.Currency),
+		ComparePrice:   comparePrice,
+		CategoryID:     categoryID,
+		Brand:          d.Brand,
+		Tags:           append([]string(nil), d.Tags...),
+		Status:         domain.ProductStatus(d.Status),
+		IsFeatured:     d.Featured,
+		Quantity:       d.Quantity,
+		Reserved:       d.Reserved,
+		TrackStock:     d.TrackStock,
+		AllowBackorder: d.AllowBackorder,
+		Slug:           d.Slug,
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+		Version:        d.Version,
+	})
+}
+
+// fieldTerms tokenizes each indexed field
+func (d *document) fieldTerms() map[string][]string {
+	return map[string][]string{
+		fieldName:        tokenize(d.Name),
+		fieldSKU:         tokenize(d.SKU),
+		fieldBrand:       tokenize(d.Brand),
+		fieldTags:        tokenize(strings.Join(d.Tags, " ")),
+		fieldDescription: tokenize(d.Description),
+	}
+}
+
+func (d *document) inStock() bool {
+	return !d.TrackStock || d.AllowBackorder || d.Quantity-d.Reserved > 0
+}
+
+// facetValues returns the document's values for a facet
+func (d *document) facetValues(name string) []string {
+	switch name {
+	case FacetCategory:
+		return []string{d.CategoryID}
+	case FacetBrand:
+		if d.Brand == "" {
+			return nil
+		}
+		return []string{d.Brand}
+	case FacetStatus:
+		return []string{strconv.Itoa(d.Status)}
+	case FacetTags:
+		return d.Tags
+	case FacetPrice:
+		price := float64(d.PriceCents) / 100
+		for _, bucket := range priceRanges {
+			if price >= bucket[0] && (bucket[1] == 0 || price < bucket[1]) {
+				return []string{priceRangeValue(bucket)}
+			}
+		}
+	case FacetStock:
+		if d.inStock() {
+			return []string{"in_stock"}
+		}
+		return []string{"out_of_stock"}
+	case FacetFeatured:
+		return []string{strconv.FormatBool(d.Featured)}
+	}
+	return nil
+}
+
+// matchesFilter reports whether doc satisfies filter, ignoring the filter belonging to skipFacet
+func matchesFilter(doc *document, filter ports.ProductFilter, skipFacet string) bool {
+	if filter.Status != nil && skipFacet != FacetStatus && doc.Status != int(*filter.Status) {
+		return false
+	}
+	if filter.CategoryID != nil && skipFacet != FacetCategory && doc.CategoryID != filter.CategoryID.String() {
+		return false
+	}
+	if filter.Brand != nil && skipFacet != FacetBrand && !strings.EqualFold(doc.Brand, *filter.Brand) {
+		return false
+	}
+	if skipFacet != FacetPrice {
+		price := float64(doc.PriceCents) / 100
+		if filter.MinPrice != nil && price < filter.MinPrice.Amount() {
+			return false
+		}
+		if filter.MaxPrice != nil && price >= filter.MaxPrice.Amount() {
+			return false
+		}
+	}
+	if filter.InStock != nil && skipFacet != FacetStock && doc.inStock() != *filter.InStock {
+		return false
+	}
+	if filter.Featured != nil && skipFacet != FacetFeatured && doc.Featured != *filter.Featured {
+		return false
+	}
+	if len(filter.Tags) > 0 && skipFacet != FacetTags && !hasAnyTag(doc.Tags, filter.Tags) {
+		return false
+	}
+	return true
+}
+
+func hasAnyTag(tags, wanted []string) bool {
+	for _, tag := range tags {
+		for _, w := range wanted {
+			if strings.EqualFold(tag, w) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func priceRangeValue(bucket [2]float64) string {
+	if bucket[1] == 0 {
+		return fmt.Sprintf("%g-", bucket[0])
+	}
+	return fmt.Sprintf("%g-%g", bucket[0], bucket[1])
+}
+
+// queryWords splits a query into words, dropping stop words
+func queryWords(query string) []string {
+	words := splitWords(query)
+	kept := words[:0]
+	for _, word := range words {
+		if _, stop := stopWords[word]; !stop {
+			kept = append(kept, word)
+		}
+	}
+	return kept
+}
+
+// applyCorrections rebuilds the query with misspelled words replaced
+func applyCorrections(words []string, corrections map[int]string) string {
+	corrected := make([]string, len(words))
+	for i, word := range words {
+		if c, ok := corrections[i]; ok {
+			corrected[i] = c
+		} else {
+			corrected[i] = word
+		}
+	}
+	return strings.Join(corrected, " ")
+}
+
+// completionScore rates how well typed words complete text: 3 when text starts with the
+// query, 2 when every word matches a word of text with the last one as a prefix, 1 when
+// the last word is a prefix within typo tolerance, and 0 when it does not match
+func completionScore(words []string, text string) float64 {
+	textWords := splitWords(text)
+	if len(textWords) == 0 {
+		return 0
+	}
+
+	if strings.HasPrefix(strings.Join(textWords, " "), strings.Join(words, " ")) {
+		return 3
+	}
+
+	last := words[len(words)-1]
+	for _, word := range words[:len(words)-1] {
+		if !containsWord(textWords, word) {
+			return 0
+		}
+	}
+	for _, tw := range textWords {
+		if strings.HasPrefix(tw, last) {
+			return 2
+		}
+	}
+
+	limit := maxEdits(last)
+	for _, tw := range textWords {
+		candidate := tw
+		if runes := []rune(tw); len(runes) > len([]rune(last)) {
+			candidate = string(runes[:len([]rune(last))])
+		}
+		if limit > 0 && editDistance(last, candidate, limit) <= limit {
+			return 1
+		}
+	}
+	return 0
+}
+
+func containsWord(words []string, word string) bool {
+	for _, w := range words {
+		if w == word {
+			return true
+		}
+	}
+	return false
+}
+
+// topSuggestions returns the highest scoring suggestions, ties broken alphabetically
+func topSuggestions(scores map[string]float64, limit int) []string {
+	suggestions := make([]string, 0, len(scores))
+	for text := range scores {
+		suggestions = append(suggestions, text)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if scores[suggestions[i]] != scores[suggestions[j]] {
+			return scores[suggestions[i]] > scores[suggestions[j]]
+		}
+		return suggestions[i] < suggestions[j]
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// topTerms returns the most frequent terms, ties broken alphabetically
+func topTerms(counts map[string]int64, limit int) []ports.SearchTerm {
+	terms := make([]ports.SearchTerm, 0, len(counts))
+	for term, count := range counts {
+		terms = append(terms, ports.SearchTerm{Term: term, Count: count})
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].Count != terms[j].Count {
+			return terms[i].Count > terms[j].Count
+		}
+		return terms[i].Term < terms[j].Term
+	})
+	if limit > 0 && len(terms) > limit {
+		terms = terms[:limit]
+	}
+	return terms
+}
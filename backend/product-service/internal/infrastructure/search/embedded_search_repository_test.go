@@ -0,0 +1,440 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"product-service/internal/domain"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+	"product-service/internal/ports"
+)
+
+var (
+	laptops     = domain.NewCategoryID()
+	accessories = domain.NewCategoryID()
+)
+
+type testProduct struct {
+	name        string
+	description string
+	sku         string
+	brand       string
+	category    domain.CategoryID
+	tags        []string
+	price       float64
+	quantity    int
+	featured    bool
+}
+
+// newTestIndex indexes products, created a minute apart in order, and returns the index
+// with their IDs
+func newTestIndex(t *testing.T, products ...testProduct) (*EmbeddedSearchRepository, []domain.ProductID) {
+	t.Helper()
+
+	index := NewEmbeddedSearchRepository()
+	ids := make([]domain.ProductID, len(products))
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, p := range products {
+		id, err := domain.ProductIDFromString(uuid.NewString())
+		if err != nil {
+			t.Fatalf("failed to create product ID: %v", err)
+		}
+		ids[i] = id
+
+		product := domain.RestoreProduct(domain.ProductData{
+			ID:          id,
+			Name:        p.name,
+			Description: p.description,
+			SKU:         p.sku,
+			Price:       domain.NewMoney(p.price, "USD"),
+			CategoryID:  p.category,
+			Brand:       p.brand,
+			Tags:        p.tags,
+			Status:      domain.ProductStatusActive,
+			IsFeatured:  p.featured,
+			Quantity:    p.quantity,
+			TrackStock:  true,
+			CreatedAt:   created.Add(time.Duration(i) * time.Minute),
+			UpdatedAt:   created.Add(time.Duration(i) * time.Minute),
+			Version:     1,
+		})
+		if err := index.IndexProduct(context.Background(), product); err != nil {
+			t.Fatalf("failed to index %q: %v", p.name, err)
+		}
+	}
+	return index, ids
+}
+
+func catalog() []testProduct {
+	return []testProduct{
+		{name: "Ultrabook Laptop 13", description: "Thin and light laptop", sku: "LAP-13", brand: "Acme", category: laptops, tags: []string{"portable"}, price: 999, quantity: 5, featured: true},
+		{name: "Gaming Laptop 17", description: "Laptop with a fast graphics card", sku: "LAP-17", brand: "Zenith", category: laptops, tags: []string{"gaming"}, price: 1799, quantity: 0},
+		{name: "Laptop Sleeve", description: "Neoprene sleeve", sku: "SLV-13", brand: "Acme", category: accessories, tags: []string{"portable"}, price: 29, quantity: 40},
+		{name: "Wireless Mouse", description: "Compact mouse for any laptop", sku: "MSE-01", brand: "Zenith", category: accessories, price: 25, quantity: 12},
+	}
+}
+
+func resultNames(result *ports.SearchResult) []string {
+	names := make([]string, len(result.Products))
+	for i, product := range result.Products {
+		names[i] = product.Name()
+	}
+	return names
+}
+
+func TestSearchRanksNameMatchesAboveDescriptionMatches(t *testing.T) {
+	index, _ := newTestIndex(t, catalog()...)
+
+	result, err := index.Search(context.Background(), ports.SearchQuery{Query: "laptop"})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+
+	names := resultNames(result)
+	if result.Total != 4 || len(names) != 4 {
+		t.Fatalf("expected 4 matches, got %d: %v", result.Total, names)
+	}
+	if names[3] != "Wireless Mouse" {
+		t.Errorf("expected the description-only match last, got %v", names)
+	}
+}
+
+func TestSearchMatchesSKUAndStemmedPlurals(t *testing.T) {
+	index, _ := newTestIndex(t, catalog()...)
+
+	result, err := index.Search(context.Background(), ports.SearchQuery{Query: "sleeves"})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if names := resultNames(result); len(names) != 1 || names[0] != "Laptop Sleeve" {
+		t.Errorf("expected the plural to match Laptop Sleeve, got %v", names)
+	}
+
+	result, err = index.Search(context.Background(), ports.SearchQuery{Query: "MSE-01"})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if names := resultNames(result); len(names) == 0 || names[0] != "Wireless Mouse" {
+		t.Errorf("expected the SKU to match Wireless Mouse first, got %v", names)
+	}
+}
+
+func TestSearchCompletesLastWordAsPrefix(t *testing.T) {
+	index, _ := newTestIndex(t, catalog()...)
+
+	result, err := index.Search(context.Background(), ports.SearchQuery{Query: "wireless mou"})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if names := resultNames(result); len(names) != 1 || names[0] != "Wireless Mouse" {
+		t.Errorf("expected the prefix to match Wireless Mouse, got %v", names)
+	}
+}
+
+func TestSearchCorrectsTypos(t *testing.T) {
+	index, _ := newTestIndex(t, catalog()...)
+
+	result, err := index.Search(context.Background(), ports.SearchQuery{Query: "gamng laptop", Fuzzy: true})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if names := resultNames(result); len(names) != 1 || names[0] != "Gaming Laptop 17" {
+		t.Errorf("expected the typo to match Gaming Laptop 17, got %v", names)
+	}
+	if len(result.Suggestions) != 1 || result.Suggestions[0] != "gaming laptop" {
+		t.Errorf("expected the suggestion \"gaming laptop\", got %v", result.Suggestions)
+	}
+
+	result, err = index.Search(context.Background(), ports.SearchQuery{Query: "gamng laptop"})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if result.Total != 0 || len(result.Suggestions) != 1 {
+		t.Errorf("expected no matches and a suggestion without fuzzy matching, got %d and %v", result.Total, result.Suggestions)
+	}
+}
+
+func TestSearchAppliesFiltersAndCountsFacetsDisjunctively(t *testing.T) {
+	index, _ := newTestIndex(t, catalog()...)
+
+	brand := "Acme"
+	inStock := true
+	result, err := index.Search(context.Background(), ports.SearchQuery{
+		Query:   "laptop",
+		Filters: ports.ProductFilter{Brand: &brand, InStock: &inStock},
+		Facets:  []string{FacetBrand, FacetStock},
+	})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+
+	if result.Total != 2 {
+		t.Errorf("expected 2 in-stock Acme matches, got %d: %v", result.Total, resultNames(result))
+	}
+
+	brands := facetCounts(result.Facets[FacetBrand])
+	if brands["Acme"] != 2 || brands["Zenith"] != 1 {
+		t.Errorf("expected brand counts ignoring the brand filter, got %v", brands)
+	}
+	stock := facetCounts(result.Facets[FacetStock])
+	if stock["in_stock"] != 2 || stock["out_of_stock"] != 0 {
+		t.Errorf("expected stock counts ignoring the stock filter, got %v", stock)
+	}
+}
+
+func facetCounts(facets []ports.Facet) map[string]int64 {
+	counts := make(map[string]int64, len(facets))
+	for _, facet := range facets {
+		counts[facet.Value] = facet.Count
+	}
+	return counts
+}
+
+func TestSearchPagesResults(t *testing.T) {
+	index, _ := newTestIndex(t, catalog()...)
+
+	result, err := index.Search(context.Background(), ports.SearchQuery{
+		Filters: ports.ProductFilter{Page: 2, Limit: 3, SortBy: "price", SortOrder: "asc"},
+	})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if result.Total != 4 {
+		t.Errorf("expected all 4 products without a query, got %d", result.Total)
+	}
+	if names := resultNames(result); len(names) != 1 || names[0] != "Gaming Laptop 17" {
+		t.Errorf("expected the most expensive product alone on page 2, got %v", names)
+	}
+}
+
+func TestDeleteProductRemovesItsTerms(t *testing.T) {
+	index, ids := newTestIndex(t, catalog()...)
+
+	if err := index.DeleteProduct(context.Background(), ids[3]); err != nil {
+		t.Fatalf("DeleteProduct failed: %v", err)
+	}
+
+	if matches := index.Rank("mou", false); len(matches) != 0 {
+		t.Errorf("expected no prefix matches after delete, got %v", matches)
+	}
+	if count := index.Count(); count != 3 {
+		t.Errorf("expected 3 indexed products, got %d", count)
+	}
+}
+
+func TestSuggestCompletesNamesAndBrands(t *testing.T) {
+	index, _ := newTestIndex(t, catalog()...)
+
+	suggestions, err := index.Suggest(context.Background(), "lapt", 10)
+	if err != nil {
+		t.Fatalf("Suggest failed: %v", err)
+	}
+	if len(suggestions) != 3 {
+		t.Errorf("expected the three laptop names, got %v", suggestions)
+	}
+
+	suggestions, err = index.Suggest(context.Background(), "zen", 10)
+	if err != nil {
+		t.Fatalf("Suggest failed: %v", err)
+	}
+	if len(suggestions) == 0 || suggestions[0] != "Zenith" {
+		t.Errorf("expected the brand Zenith first, got %v", suggestions)
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	index, _ := newTestIndex(t, catalog()...)
+
+	var buf bytes.Buffer
+	if err := index.Save(&buf); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded := NewEmbeddedSearchRepository()
+	if err := loaded.Load(&buf); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if loaded.Count() != index.Count() {
+		t.Errorf("expected %d products after load, got %d", index.Count(), loaded.Count())
+	}
+	want, got := index.Rank("laptop slee", false), loaded.Rank("laptop slee", false)
+	if len(got) != 1 || len(want) != 1 || got[0] != want[0] {
+		t.Errorf("expected the loaded index to rank like the saved one, got %v, want %v", got, want)
+	}
+}
+
+func TestLoadRejectsTruncatedSnapshots(t *testing.T) {
+	var buf bytes.Buffer
+	index := NewEmbeddedSearchRepository()
+	if err := index.Save(&buf); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	data := buf.Bytes()
+
+	if err := index.Load(bytes.NewReader(data[:len(data)/2])); err == nil {
+		t.Error("expected a truncated snapshot to fail")
+	}
+}
+
+func TestSearchAnalyticsPeriods(t *testing.T) {
+	index, _ := newTestIndex(t, catalog()...)
+
+	for _, query := range []string{"laptop", "laptop", "tablet"} {
+		if _, err := index.Search(context.Background(), ports.SearchQuery{Query: query}); err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+	}
+
+	analytics, err := index.GetSearchAnalytics(context.Background(), "24h")
+	if err != nil {
+		t.Fatalf("GetSearchAnalytics failed: %v", err)
+	}
+	if analytics.TotalSearches != 3 || analytics.UniqueSearches != 2 {
+		t.Errorf("expected 3 searches of 2 queries, got %d of %d", analytics.TotalSearches, analytics.UniqueSearches)
+	}
+	if len(analytics.NoResultsQueries) != 1 || analytics.NoResultsQueries[0] != "tablet" {
+		t.Errorf("expected tablet as the only query without results, got %v", analytics.NoResultsQueries)
+	}
+
+	if _, err := index.GetSearchAnalytics(context.Background(), "fortnight"); err == nil {
+		t.Error("expected an invalid period to fail")
+	}
+}
+
+// stubProductRepository pages through the products named by the IDs filter in order,
+// as the database does for searches without query text
+type stubProductRepository struct {
+	repositories.ProductRepository
+	filters *repositories.ProductFilters
+}
+
+func (r *stubProductRepository) Search(ctx context.Context, query string, filters *repositories.ProductFilters, limit, offset int) ([]*repositories.ProductSearchHit, error) {
+	r.filters = filters
+	hits := []*repositories.ProductSearchHit{}
+	for i := offset; i < len(filters.IDs) && len(hits) < limit; i++ {
+		hits = append(hits, &repositories.ProductSearchHit{Product: &entities.Product{ID: filters.IDs[i]}})
+	}
+	return hits, nil
+}
+
+func (r *stubProductRepository) CountSearch(ctx context.Context, query string, filters *repositories.ProductFilters) (int64, error) {
+	r.filters = filters
+	return int64(len(filters.IDs)), nil
+}
+
+func (r *stubProductRepository) Update(ctx context.Context, product *entities.Product) error {
+	return nil
+}
+
+func (r *stubProductRepository) Delete(ctx context.Context, id uuid.UUID, version int) error {
+	return nil
+}
+
+// stubTransactions holds after-commit work until the test commits or rolls back
+type stubTransactions struct {
+	pending []func()
+}
+
+func (m *stubTransactions) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (m *stubTransactions) AfterCommit(ctx context.Context, fn func()) {
+	m.pending = append(m.pending, fn)
+}
+
+func (m *stubTransactions) commit() {
+	for _, fn := range m.pending {
+		fn()
+	}
+	m.pending = nil
+}
+
+func (m *stubTransactions) rollback() {
+	m.pending = nil
+}
+
+func TestIndexedProductRepositoryPagesMatchesInRankOrder(t *testing.T) {
+	index, ids := newTestIndex(t, catalog()...)
+	products := &stubProductRepository{}
+	repo := NewIndexedProductRepository(products, nil, &stubTransactions{}, index)
+
+	ranked := index.Rank("laptop", true)
+	hits, err := repo.Search(context.Background(), "laptop", &repositories.ProductFilters{}, 2, 1)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(hits) != 2 {
+		t.Fatalf("expected a page of 2, got %d", len(hits))
+	}
+	for i, hit := range hits {
+		if want := ranked[i+1]; hit.Product.ID.String() != want.ProductID || hit.Rank != want.Score {
+			t.Errorf("hit %d: expected %s scored %g, got %s scored %g", i, want.ProductID, want.Score, hit.Product.ID, hit.Rank)
+		}
+	}
+	for i, id := range products.filters.IDs {
+		if id.String() != ranked[i].ProductID {
+			t.Fatalf("expected the repository to be restricted to the matches in rank order, got %v", products.filters.IDs)
+		}
+	}
+
+	count, err := repo.CountSearch(context.Background(), "wireless", nil)
+	if err != nil {
+		t.Fatalf("CountSearch failed: %v", err)
+	}
+	if count != 1 || !strings.EqualFold(products.filters.IDs[0].String(), ids[3].String()) {
+		t.Errorf("expected one match for wireless, got %d: %v", count, products.filters.IDs)
+	}
+
+	count, err = repo.CountSearch(context.Background(), "tablet", nil)
+	if err != nil {
+		t.Fatalf("CountSearch failed: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("expected no matches for tablet, got %d", count)
+	}
+}
+
+func TestIndexedProductRepositoryIndexesOnlyCommittedWrites(t *testing.T) {
+	index, ids := newTestIndex(t, catalog()...)
+	transactions := &stubTransactions{}
+	repo := NewIndexedProductRepository(&stubProductRepository{}, nil, transactions, index)
+
+	product := &entities.Product{ID: uuid.New(), CategoryID: uuid.New(), Name: "Bluetooth Speaker", Status: entities.ProductStatusActive}
+	if err := repo.Update(context.Background(), product); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if matches := index.Rank("speaker", false); len(matches) != 0 {
+		t.Errorf("expected nothing indexed before commit, got %v", matches)
+	}
+	transactions.rollback()
+	if matches := index.Rank("speaker", false); len(matches) != 0 {
+		t.Errorf("expected a rolled back write to stay out of the index, got %v", matches)
+	}
+
+	if err := repo.Update(context.Background(), product); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	transactions.commit()
+	if matches := index.Rank("speaker", false); len(matches) != 1 {
+		t.Errorf("expected the committed write to be indexed, got %v", matches)
+	}
+
+	deleted, err := uuid.Parse(ids[3].String())
+	if err != nil {
+		t.Fatalf("invalid product id: %v", err)
+	}
+	if err := repo.Delete(context.Background(), deleted, 1); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	transactions.commit()
+	if matches := index.Rank("wireless", false); len(matches) != 0 {
+		t.Errorf("expected the deleted product to leave the index, got %v", matches)
+	}
+}
@@ -0,0 +1,170 @@
+package search
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+	"product-service/internal/domain"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// IndexedProductRepository serves product search from an embedded index. The index
+// matches and ranks the query text; the wrapped repository loads the matches and
+// applies filters, paging and facets, so results honour every filter it supports.
+// Everything else goes straight to the wrapped repository.
+//
+// Products created, updated or deleted through the repository are re-indexed once the
+// write commits. Changes made by other writers, such as catalog imports, are searchable
+// after the next reindex.
+type IndexedProductRepository struct {
+	repositories.ProductRepository
+	brands       repositories.BrandRepository
+	transactions repositories.TransactionManager
+	index        *EmbeddedSearchRepository
+}
+
+// NewIndexedProductRepository wraps products so that searches go through index
+func NewIndexedProductRepository(
+	products repositories.ProductRepository,
+	brands repositories.BrandRepository,
+	transactions repositories.TransactionManager,
+	index *EmbeddedSearchRepository,
+) repositories.ProductRepository {
+	return &IndexedProductRepository{
+		ProductRepository: products,
+		brands:            brands,
+		transactions:      transactions,
+		index:             index,
+	}
+}
+
+// Create saves a product and indexes it once saved
+func (r *IndexedProductRepository) Create(ctx context.Context, product *entities.Product) error {
+	if err := r.ProductRepository.Create(ctx, product); err != nil {
+		return err
+	}
+	r.reindex(ctx, product)
+	return nil
+}
+
+// Update saves a product and re-indexes it once saved
+func (r *IndexedProductRepository) Update(ctx context.Context, product *entities.Product) error {
+	if err := r.ProductRepository.Update(ctx, product); err != nil {
+		return err
+	}
+	r.reindex(ctx, product)
+	return nil
+}
+
+// Delete deletes a product and removes it from the index once deleted
+func (r *IndexedProductRepository) Delete(ctx context.Context, id uuid.UUID, version int) error {
+	if err := r.ProductRepository.Delete(ctx, id, version); err != nil {
+		return err
+	}
+	r.transactions.AfterCommit(ctx, func() {
+		productID, err := domain.ProductIDFromString(id.String())
+		if err == nil {
+			err = r.index.DeleteProduct(context.Background(), productID)
+		}
+		if err != nil {
+			log.Printf("Warning: failed to remove product %s from search: %v", id, err)
+		}
+	})
+	return nil
+}
+
+// Search ranks text queries with the index and loads a page of the matches passing
+// filters, best first. Queries without text are left to the wrapped repository.
+func (r *IndexedProductRepository) Search(ctx context.Context, query string, filters *repositories.ProductFilters, limit, offset int) ([]*repositories.ProductSearchHit, error) {
+	if strings.TrimSpace(query) == "" {
+		return r.ProductRepository.Search(ctx, query, filters, limit, offset)
+	}
+
+	matches := r.index.Rank(query, true)
+	if len(matches) == 0 {
+		return []*repositories.ProductSearchHit{}, nil
+	}
+
+	restricted, scores := restrictToMatches(filters, matches)
+	hits, err := r.ProductRepository.Search(ctx, "", restricted, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	for _, hit := range hits {
+		hit.Rank = scores[hit.Product.ID]
+	}
+	return hits, nil
+}
+
+// CountSearch counts the matches of a text query passing filters
+func (r *IndexedProductRepository) CountSearch(ctx context.Context, query string, filters *repositories.ProductFilters) (int64, error) {
+	if strings.TrimSpace(query) == "" {
+		return r.ProductRepository.CountSearch(ctx, query, filters)
+	}
+
+	matches := r.index.Rank(query, true)
+	if len(matches) == 0 {
+		return 0, nil
+	}
+	restricted, _ := restrictToMatches(filters, matches)
+	return r.ProductRepository.CountSearch(ctx, "", restricted)
+}
+
+// GetSearchFacets counts facet values over the matches of a text query
+func (r *IndexedProductRepository) GetSearchFacets(ctx context.Context, query string, filters *repositories.ProductFilters, attributeKeys []string) (map[string][]repositories.FacetValue, error) {
+	if strings.TrimSpace(query) == "" {
+		return r.ProductRepository.GetSearchFacets(ctx, query, filters, attributeKeys)
+	}
+
+	restricted, _ := restrictToMatches(filters, r.index.Rank(query, true))
+	return r.ProductRepository.GetSearchFacets(ctx, "", restricted, attributeKeys)
+}
+
+// reindex updates the index once a product save commits, so rolled back writes never
+// reach it. The saved product is the source of truth, so a failure only leaves search
+// stale and is logged.
+func (r *IndexedProductRepository) reindex(ctx context.Context, product *entities.Product) {
+	indexed := *product
+	if indexed.Brand == nil && indexed.BrandID != nil {
+		brand, err := r.brands.GetByID(ctx, *indexed.BrandID)
+		if err != nil {
+			log.Printf("Warning: failed to load brand of product %s for search: %v", product.ID, err)
+		}
+		indexed.Brand = brand
+	}
+
+	document, err := ProductFromEntity(&indexed)
+	if err != nil {
+		log.Printf("Warning: failed to index product %s: %v", product.ID, err)
+		return
+	}
+	r.transactions.AfterCommit(ctx, func() {
+		if err := r.index.IndexProduct(context.Background(), document); err != nil {
+			log.Printf("Warning: failed to index product %s: %v", product.ID, err)
+		}
+	})
+}
+
+// restrictToMatches narrows filters to the matched products, in ranking order, and
+// returns each match's score
+func restrictToMatches(filters *repositories.ProductFilters, matches []Match) (*repositories.ProductFilters, map[uuid.UUID]float64) {
+	restricted := repositories.ProductFilters{}
+	if filters != nil {
+		restricted = *filters
+	}
+
+	restricted.IDs = make([]uuid.UUID, 0, len(matches))
+	scores := make(map[uuid.UUID]float64, len(matches))
+	for _, match := range matches {
+		id, err := uuid.Parse(match.ProductID)
+		if err != nil {
+			continue
+		}
+		restricted.IDs = append(restricted.IDs, id)
+		scores[id] = match.Score
+	}
+	return &restricted, scores
+}
@@ -0,0 +1,92 @@
+package search
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"product-service/internal/domain"
+	"product-service/internal/domain/entities"
+	"product-service/internal/ports"
+)
+
+// defaultCurrency is used for catalog prices, which are stored without a currency
+const defaultCurrency = "USD"
+
+// Reindex streams every product from Postgres into index in batches and returns the
+// number of products indexed. Soft-deleted products are skipped.
+func Reindex(ctx context.Context, db *gorm.DB, index ports.SearchRepository, batchSize int) (int, error) {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	var batch []entities.Product
+	indexed := 0
+	err := db.WithContext(ctx).
+		Preload("Brand").
+		Where("deleted_at IS NULL").
+		FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+			products := make([]*domain.Product, 0, len(batch))
+			for i := range batch {
+				product, err := ProductFromEntity(&batch[i])
+				if err != nil {
+					return err
+				}
+				products = append(products, product)
+			}
+			if err := index.BulkIndex(ctx, products); err != nil {
+				return err
+			}
+			indexed += len(products)
+			return nil
+		}).Error
+	if err != nil {
+		return indexed, fmt.Errorf("failed to reindex products: %w", err)
+	}
+
+	return indexed, nil
+}
+
+// ProductFromEntity converts a persisted product into the domain aggregate the search
+// port works with
+func ProductFromEntity(p *entities.Product) (*domain.Product, error) {
+	id, err := domain.ProductIDFromString(p.ID.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid product id %s: %w", p.ID, err)
+	}
+	categoryID, err := domain.CategoryIDFromString(p.CategoryID.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid category id for product %s: %w", p.ID, err)
+	}
+
+	var comparePrice *domain.Money
+	if p.ComparePrice > 0 {
+		money := domain.NewMoney(p.ComparePrice, defaultCurrency)
+		comparePrice = &money
+	}
+
+	brand := ""
+	if p.Brand != nil {
+		brand = p.Brand.Name
+	}
+
+	return domain.RestoreProduct(domain.ProductData{
+		ID:             id,
+		Name:           p.Name,
+		Description:    p.Description,
+		SKU:            p.SKU,
+		Price:          domain.NewMoney(p.Price, defaultCurrency),
+		ComparePrice:   comparePrice,
+		CategoryID:     categoryID,
+		Brand:          brand,
+		Tags:           p.Tags,
+		Status:         domain.ProductStatus(p.Status),
+		IsFeatured:     p.Featured,
+		Quantity:       p.StockQuantity,
+		TrackStock:     p.TrackStock,
+		AllowBackorder: p.AllowBackorder,
+		Slug:           p.Slug,
+		CreatedAt:      p.CreatedAt,
+		UpdatedAt:      p.UpdatedAt,
+	}), nil
+}
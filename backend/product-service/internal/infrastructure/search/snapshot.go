@@ -0,0 +1,53 @@
+package search
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+)
+
+// snapshotVersion is bumped whenever the snapshot layout changes
+const snapshotVersion = 1
+
+type snapshot struct {
+	Version   int
+	Documents []*document
+}
+
+// Save writes the indexed documents to w. The inverted index itself is rebuilt on
+// Load, so snapshots stay valid across tokenizer changes.
+func (r *EmbeddedSearchRepository) Save(w io.Writer) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snap := snapshot{Version: snapshotVersion, Documents: make([]*document, 0, len(r.docs))}
+	for _, doc := range r.docs {
+		snap.Documents = append(snap.Documents, doc)
+	}
+
+	if err := gob.NewEncoder(w).Encode(&snap); err != nil {
+		return fmt.Errorf("failed to encode search snapshot: %w", err)
+	}
+	return nil
+}
+
+// Load replaces the index contents with a snapshot written by Save
+func (r *EmbeddedSearchRepository) Load(rd io.Reader) error {
+	var snap snapshot
+	if err := gob.NewDecoder(rd).Decode(&snap); err != nil {
+		return fmt.Errorf("failed to decode search snapshot: %w", err)
+	}
+	if snap.Version != snapshotVersion {
+		return fmt.Errorf("unsupported search snapshot version %d", snap.Version)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reset()
+	for _, doc := range snap.Documents {
+		r.add(doc)
+	}
+	r.refreshSortedTerms()
+	return nil
+}
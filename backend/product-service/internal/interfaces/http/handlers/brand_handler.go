@@ -0,0 +1,360 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"product-service/internal/application/services"
+)
+
+// BrandHandler handles HTTP requests for brands
+type BrandHandler struct {
+	brandService *services.BrandService
+}
+
+// NewBrandHandler creates a new brand handler
+func NewBrandHandler(brandService *services.BrandService) *BrandHandler {
+	return &BrandHandler{
+		brandService: brandService,
+	}
+}
+
+// CreateBrand creates a new brand
+// @Summary Create a new brand
+// @Description Create a brand; its slug is generated from the name
+// @Tags brands
+// @Accept json
+// @Produce json
+// @Param brand body services.CreateBrandRequest true "Brand information"
+// @Success 201 {object} APIResponse{data=entities.Brand}
+// @Failure 400 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /brands [post]
+func (h *BrandHandler) CreateBrand(c *gin.Context) {
+	var req services.CreateBrandRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	brand, err := h.brandService.CreateBrand(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), NewErrorResponse("Failed to create brand", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, NewSuccessResponse("Brand created successfully", brand))
+}
+
+// ListBrands lists brands
+// @Summary List brands
+// @Description List brands ordered by sort order and name. With active=true only active, visible brands are listed
+// @Tags brands
+// @Produce json
+// @Param active query bool false "Only active, visible brands"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} APIResponse{data=services.BrandListResult}
+// @Failure 500 {object} APIResponse
+// @Router /brands [get]
+func (h *BrandHandler) ListBrands(c *gin.Context) {
+	page, pageSize := parsePagination(c)
+
+	result, err := h.brandService.ListBrands(c.Request.Context(), c.Query("active") == "true", page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to list brands", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Brands retrieved successfully", result))
+}
+
+// GetBrand retrieves a brand by ID
+// @Summary Get a brand by ID
+// @Description Get a brand by its ID
+// @Tags brands
+// @Produce json
+// @Param id path string true "Brand ID"
+// @Success 200 {object} APIResponse{data=entities.Brand}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /brands/{id} [get]
+func (h *BrandHandler) GetBrand(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid brand ID", err.Error()))
+		return
+	}
+
+	brand, err := h.brandService.GetBrand(c.Request.Context(), id)
+	if err != nil {
+		if err.Error() == "brand not found" {
+			c.JSON(http.StatusNotFound, NewErrorResponse("Brand not found", ""))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to get brand", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Brand retrieved successfully", brand))
+}
+
+// GetBrandBySlug retrieves a brand by slug
+// @Summary Get a brand by slug
+// @Description Get a brand by its slug. A slug the brand used to have is answered with a 301 to its current slug.
+// @Tags brands
+// @Produce json
+// @Param slug path string true "Brand slug"
+// @Success 200 {object} APIResponse{data=entities.Brand}
+// @Success 301 {object} APIResponse{data=entities.Brand}
+// @Header 301 {string} Location "The brand under its current slug"
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /brands/slug/{slug} [get]
+func (h *BrandHandler) GetBrandBySlug(c *gin.Context) {
+	slug := c.Param("slug")
+	brand, err := h.brandService.GetBrandBySlug(c.Request.Context(), slug)
+	if err != nil {
+		if err.Error() == "brand not found" {
+			c.JSON(http.StatusNotFound, NewErrorResponse("Brand not found", ""))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to get brand", err.Error()))
+		return
+	}
+
+	if redirectToCanonicalSlug(c, slug, brand.Slug, brand) {
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Brand retrieved successfully", brand))
+}
+
+// GetBrandPage retrieves a brand landing page
+// @Summary Get brand landing page
+// @Description Get an active, visible brand with a page of its listed products and facet counts. Products can be narrowed with the same filters as product search
+// @Tags brands
+// @Produce json
+// @Param slug path string true "Brand slug"
+// @Param category_ids query []string false "Category IDs"
+// @Param min_price query number false "Minimum price"
+// @Param max_price query number false "Maximum price, exclusive"
+// @Param in_stock query bool false "In stock only"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} APIResponse{data=services.BrandPage}
+// @Success 301 {object} APIResponse{data=services.BrandPage}
+// @Header 301 {string} Location "The landing page under the brand's current slug"
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /brands/slug/{slug}/page [get]
+func (h *BrandHandler) GetBrandPage(c *gin.Context) {
+	req := &services.BrandPageRequest{}
+	req.Page, req.PageSize = parsePagination(c)
+
+	for _, idStr := range c.QueryArray("category_ids") {
+		if id, err := uuid.Parse(idStr); err == nil {
+			req.CategoryIDs = append(req.CategoryIDs, id)
+		}
+	}
+
+	if minPriceStr := c.Query("min_price"); minPriceStr != "" {
+		if minPrice, err := strconv.ParseFloat(minPriceStr, 64); err == nil {
+			req.MinPrice = &minPrice
+		}
+	}
+
+	if maxPriceStr := c.Query("max_price"); maxPriceStr != "" {
+		if maxPrice, err := strconv.ParseFloat(maxPriceStr, 64); err == nil {
+			req.MaxPrice = &maxPrice
+		}
+	}
+
+	if inStockStr := c.Query("in_stock"); inStockStr != "" {
+		if inStock, err := strconv.ParseBool(inStockStr); err == nil {
+			req.InStock = &inStock
+		}
+	}
+
+	// Attribute filters (attr[color]=Red)
+	if attributes := c.QueryMap("attr"); len(attributes) > 0 {
+		req.Attributes = attributes
+	}
+
+	page, err := h.brandService.GetBrandPage(c.Request.Context(), c.Param("slug"), req)
+	if err != nil {
+		if err.Error() == "brand not found" {
+			c.JSON(http.StatusNotFound, NewErrorResponse("Brand not found", ""))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to get brand page", err.Error()))
+		return
+	}
+
+	if redirectToCanonicalSlug(c, c.Param("slug"), page.Brand.Slug, page) {
+		return
+	}
+	c.JSON(http.StatusOK, NewSuccessResponse("Brand page retrieved successfully", page))
+}
+
+// UpdateBrand updates a brand
+// @Summary Update a brand
+// @Description Update a brand's details. Renaming also changes its slug
+// @Tags brands
+// @Accept json
+// @Produce json
+// @Param id path string true "Brand ID"
+// @Param brand body services.UpdateBrandRequest true "Brand information"
+// @Success 200 {object} APIResponse{data=entities.Brand}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /brands/{id} [put]
+func (h *BrandHandler) UpdateBrand(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid brand ID", err.Error()))
+		return
+	}
+
+	var req services.UpdateBrandRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	brand, err := h.brandService.UpdateBrand(c.Request.Context(), id, &req)
+	if err != nil {
+		if err.Error() == "brand not found" {
+			c.JSON(http.StatusNotFound, NewErrorResponse("Brand not found", ""))
+			return
+		}
+		c.JSON(serviceErrorStatus(err), NewErrorResponse("Failed to update brand", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Brand updated successfully", brand))
+}
+
+// ActivateBrand activates a brand
+// @Summary Activate a brand
+// @Description Make a deactivated brand available again
+// @Tags brands
+// @Produce json
+// @Param id path string true "Brand ID"
+// @Success 200 {object} APIResponse{data=entities.Brand}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /brands/{id}/activate [post]
+func (h *BrandHandler) ActivateBrand(c *gin.Context) {
+	h.setBrandActive(c, true)
+}
+
+// DeactivateBrand deactivates a brand
+// @Summary Deactivate a brand
+// @Description Hide a brand from storefront listings and its landing page. Its products are not changed
+// @Tags brands
+// @Produce json
+// @Param id path string true "Brand ID"
+// @Success 200 {object} APIResponse{data=entities.Brand}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /brands/{id}/deactivate [post]
+func (h *BrandHandler) DeactivateBrand(c *gin.Context) {
+	h.setBrandActive(c, false)
+}
+
+func (h *BrandHandler) setBrandActive(c *gin.Context, active bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid brand ID", err.Error()))
+		return
+	}
+
+	brand, err := h.brandService.SetBrandActive(c.Request.Context(), id, active)
+	if err != nil {
+		if err.Error() == "brand not found" {
+			c.JSON(http.StatusNotFound, NewErrorResponse("Brand not found", ""))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to update brand", err.Error()))
+		return
+	}
+
+	message := "Brand deactivated successfully"
+	if active {
+		message = "Brand activated successfully"
+	}
+	c.JSON(http.StatusOK, NewSuccessResponse(message, brand))
+}
+
+// DeleteBrand deletes a brand
+// @Summary Delete a brand
+// @Description Delete a brand. A brand with products cannot be deleted unless reassign_to names a brand to move its products to
+// @Tags brands
+// @Produce json
+// @Param id path string true "Brand ID"
+// @Param reassign_to query string false "Brand ID to move the products to"
+// @Success 200 {object} APIResponse
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 409 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /brands/{id} [delete]
+func (h *BrandHandler) DeleteBrand(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid brand ID", err.Error()))
+		return
+	}
+
+	var reassignTo *uuid.UUID
+	if reassignStr := c.Query("reassign_to"); reassignStr != "" {
+		target, err := uuid.Parse(reassignStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid reassignment brand ID", err.Error()))
+			return
+		}
+		reassignTo = &target
+	}
+
+	err = h.brandService.DeleteBrand(c.Request.Context(), id, reassignTo)
+	if err != nil {
+		switch err.Error() {
+		case "brand not found":
+			c.JSON(http.StatusNotFound, NewErrorResponse("Brand not found", ""))
+		case "brand has products":
+			c.JSON(http.StatusConflict, NewErrorResponse("Failed to delete brand", "brand has products; pass reassign_to to move them to another brand"))
+		default:
+			c.JSON(serviceErrorStatus(err), NewErrorResponse("Failed to delete brand", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Brand deleted successfully", nil))
+}
+
+// parsePagination reads page and page_size, defaulting to the first page of 20
+func parsePagination(c *gin.Context) (int, int) {
+	page := 1
+	pageSize := 20
+
+	if pageStr := c.Query("page"); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+			page = p
+		}
+	}
+
+	if pageSizeStr := c.Query("page_size"); pageSizeStr != "" {
+		if ps, err := strconv.Atoi(pageSizeStr); err == nil && ps > 0 && ps <= 100 {
+			pageSize = ps
+		}
+	}
+
+	return page, pageSize
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"product-service/internal/application/services"
+)
+
+// BundleHandler handles HTTP requests for bundle products
+type BundleHandler struct {
+	bundleService *services.BundleService
+}
+
+// NewBundleHandler creates a new bundle handler
+func NewBundleHandler(bundleService *services.BundleService) *BundleHandler {
+	return &BundleHandler{
+		bundleService: bundleService,
+	}
+}
+
+// SetProductBundle makes a product a bundle
+// @Summary Set bundle components
+// @Description Make a product a bundle of component products or variants, or replace the components of a bundle. A fixed bundle keeps its own price; a computed one costs the sum of its components less a percent or fixed discount. Stock is the number of bundles the components can build, and selling a bundle through the stock endpoint deducts its components.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param bundle body services.BundleRequest true "Bundle"
+// @Success 200 {object} APIResponse{data=entities.Product}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /products/{id}/bundle [put]
+func (h *BundleHandler) SetProductBundle(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid product ID", err.Error()))
+		return
+	}
+
+	var req services.BundleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	product, err := h.bundleService.SetBundle(c.Request.Context(), productID, &req)
+	if err != nil {
+		c.JSON(bundleErrorStatus(err), NewErrorResponse("Failed to set bundle", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Bundle updated successfully", product))
+}
+
+// RemoveProductBundle turns a bundle back into a regular product
+// @Summary Remove bundle components
+// @Description Turn a bundle back into a regular product. It keeps the stock and price last derived from its components.
+// @Tags products
+// @Produce json
+// @Param id path string true "Product ID"
+// @Success 200 {object} APIResponse{data=entities.Product}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /products/{id}/bundle [delete]
+func (h *BundleHandler) RemoveProductBundle(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid product ID", err.Error()))
+		return
+	}
+
+	product, err := h.bundleService.RemoveBundle(c.Request.Context(), productID)
+	if err != nil {
+		c.JSON(bundleErrorStatus(err), NewErrorResponse("Failed to remove bundle", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Bundle removed successfully", product))
+}
+
+// bundleErrorStatus maps bundle service errors to HTTP status codes
+func bundleErrorStatus(err error) int {
+	if err.Error() == "product not found" {
+		return http.StatusNotFound
+	}
+	if strings.HasPrefix(err.Error(), "component ") && strings.HasSuffix(err.Error(), " not found") {
+		return http.StatusNotFound
+	}
+	return serviceErrorStatus(err)
+}
@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"product-service/internal/application/services"
+	"product-service/internal/domain/entities"
+)
+
+// CatalogHandler handles HTTP requests for bulk catalog import and export
+type CatalogHandler struct {
+	catalogService *services.CatalogService
+}
+
+// NewCatalogHandler creates a new catalog handler
+func NewCatalogHandler(catalogService *services.CatalogService) *CatalogHandler {
+	return &CatalogHandler{
+		catalogService: catalogService,
+	}
+}
+
+// ImportCatalog imports products and variants from a CSV or JSONL file
+// @Summary Import catalog
+// @Description Create or upsert (by SKU) products, variants, images and attributes from a CSV or JSONL file, sent as the multipart field "file" or as the request body. Rows with a parent_sku are variants. With dry_run=true every row is validated and a per-row error report is returned without writing anything; otherwise an import job is started and applied in chunks
+// @Tags catalog
+// @Accept multipart/form-data,text/csv,application/x-ndjson
+// @Produce json
+// @Param file formData file false "Import file"
+// @Param format query string false "File format: csv or jsonl (defaults to the file extension, then csv)"
+// @Param mode query string false "create rejects existing SKUs; upsert updates them" default(create)
+// @Param dry_run query bool false "Validate only"
+// @Success 200 {object} APIResponse{data=services.ImportReport}
+// @Success 202 {object} APIResponse{data=entities.ImportJob}
+// @Failure 400 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /catalog/import [post]
+func (h *CatalogHandler) ImportCatalog(c *gin.Context) {
+	body, fileName, err := importFile(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid import file", err.Error()))
+		return
+	}
+	defer body.Close()
+
+	format, err := catalogFormat(c.Query("format"), fileName)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid import format", err.Error()))
+		return
+	}
+
+	mode := c.DefaultQuery("mode", entities.ImportModeCreate)
+	if mode != entities.ImportModeCreate && mode != entities.ImportModeUpsert {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid import mode", "mode must be create or upsert"))
+		return
+	}
+
+	if c.Query("dry_run") == "true" {
+		report, err := h.catalogService.ValidateImport(c.Request.Context(), format, mode, body)
+		if err != nil {
+			c.JSON(serviceErrorStatus(err), NewErrorResponse("Failed to validate import", err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, NewSuccessResponse("Import validated successfully", report))
+		return
+	}
+
+	job, err := h.catalogService.StartImport(c.Request.Context(), format, mode, fileName, body)
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), NewErrorResponse("Failed to start import", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, NewSuccessResponse("Import started", job))
+}
+
+// GetImportJob reports the progress of an import job
+// @Summary Get import job
+// @Description Get the status, progress counters and row errors of an import job
+// @Tags catalog
+// @Produce json
+// @Param id path string true "Import job ID"
+// @Success 200 {object} APIResponse{data=entities.ImportJob}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /catalog/import/{id} [get]
+func (h *CatalogHandler) GetImportJob(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid import job ID", err.Error()))
+		return
+	}
+
+	job, err := h.catalogService.GetImportJob(c.Request.Context(), id)
+	if err != nil {
+		if err.Error() == "import job not found" {
+			c.JSON(http.StatusNotFound, NewErrorResponse("Import job not found", ""))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to get import job", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Import job retrieved successfully", job))
+}
+
+// ResumeImport resumes a failed or interrupted import job
+// @Summary Resume import job
+// @Description Continue an import job after the last chunk it committed
+// @Tags catalog
+// @Produce json
+// @Param id path string true "Import job ID"
+// @Success 202 {object} APIResponse{data=entities.ImportJob}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 409 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /catalog/import/{id}/resume [post]
+func (h *CatalogHandler) ResumeImport(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid import job ID", err.Error()))
+		return
+	}
+
+	job, err := h.catalogService.ResumeImport(c.Request.Context(), id)
+	if err != nil {
+		switch err.Error() {
+		case "import job not found":
+			c.JSON(http.StatusNotFound, NewErrorResponse("Import job not found", ""))
+		case "import job cannot be resumed", "import job is already running", "import file has changed since the job was created":
+			c.JSON(http.StatusConflict, NewErrorResponse("Failed to resume import", err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to resume import", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, NewSuccessResponse("Import resumed", job))
+}
+
+// ExportCatalog streams the catalog as CSV or JSONL
+// @Summary Export catalog
+// @Description Stream every product followed by its variants, in the same format the import accepts
+// @Tags catalog
+// @Produce text/csv,application/x-ndjson
+// @Param format query string false "csv or jsonl" default(csv)
+// @Success 200 {file} file
+// @Failure 400 {object} APIResponse
+// @Router /catalog/export [get]
+func (h *CatalogHandler) ExportCatalog(c *gin.Context) {
+	format, err := catalogFormat(c.DefaultQuery("format", entities.CatalogFormatCSV), "")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid export format", err.Error()))
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if format == entities.CatalogFormatJSONL {
+		contentType = "application/x-ndjson"
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="catalog.%s"`, format))
+	c.Status(http.StatusOK)
+
+	// Headers are already sent, so a failure part-way can only be logged
+	if err := h.catalogService.ExportCatalog(c.Request.Context(), format, c.Writer); err != nil {
+		log.Printf("Catalog export failed: %v", err)
+	}
+}
+
+// importFile returns the uploaded multipart file, or the raw request body
+func importFile(c *gin.Context) (io.ReadCloser, string, error) {
+	if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		header, err := c.FormFile("file")
+		if err != nil {
+			return nil, "", err
+		}
+		file, err := header.Open()
+		if err != nil {
+			return nil, "", err
+		}
+		return file, header.Filename, nil
+	}
+
+	if c.Request.ContentLength == 0 {
+		return nil, "", fmt.Errorf("request body is empty")
+	}
+	return c.Request.Body, "", nil
+}
+
+// catalogFormat resolves the file format from the query, falling back to the file extension
+func catalogFormat(format, fileName string) (string, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(fileName)) {
+		case ".jsonl", ".ndjson":
+			format = entities.CatalogFormatJSONL
+		default:
+			format = entities.CatalogFormatCSV
+		}
+	}
+
+	format = strings.ToLower(format)
+	if format != entities.CatalogFormatCSV && format != entities.CatalogFormatJSONL {
+		return "", fmt.Errorf("format must be csv or jsonl")
+	}
+	return format, nil
+}
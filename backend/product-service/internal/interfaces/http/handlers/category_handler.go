@@ -0,0 +1,486 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"product-service/internal/application/services"
+)
+
+// CategoryHandler handles HTTP requests for categories
+type CategoryHandler struct {
+	categoryService        *services.CategoryService
+	attributeSchemaService *services.AttributeSchemaService
+}
+
+// NewCategoryHandler creates a new category handler
+func NewCategoryHandler(
+	categoryService *services.CategoryService,
+	attributeSchemaService *services.AttributeSchemaService,
+) *CategoryHandler {
+	return &CategoryHandler{
+		categoryService:        categoryService,
+		attributeSchemaService: attributeSchemaService,
+	}
+}
+
+// CreateCategory creates a new category
+// @Summary Create a new category
+// @Description Create a category, optionally below a parent category
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param category body services.CreateCategoryRequest true "Category information"
+// @Success 201 {object} APIResponse{data=entities.Category}
+// @Failure 400 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /categories [post]
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	var req services.CreateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	category, err := h.categoryService.CreateCategory(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), NewErrorResponse("Failed to create category", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, NewSuccessResponse("Category created successfully", category))
+}
+
+// ListCategories lists categories
+// @Summary List categories
+// @Description List all categories as a flat list, parents first
+// @Tags categories
+// @Produce json
+// @Param active query bool false "Only active categories"
+// @Success 200 {object} APIResponse{data=[]entities.Category}
+// @Failure 500 {object} APIResponse
+// @Router /categories [get]
+func (h *CategoryHandler) ListCategories(c *gin.Context) {
+	categories, err := h.categoryService.ListCategories(c.Request.Context(), c.Query("active") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to list categories", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Categories retrieved successfully", categories))
+}
+
+// GetCategoryTree retrieves the category hierarchy
+// @Summary Get category tree
+// @Description Get the full category hierarchy. With active=true, inactive categories and everything below them are left out
+// @Tags categories
+// @Produce json
+// @Param active query bool false "Only active categories"
+// @Success 200 {object} APIResponse{data=[]repositories.CategoryTree}
+// @Failure 500 {object} APIResponse
+// @Router /categories/tree [get]
+func (h *CategoryHandler) GetCategoryTree(c *gin.Context) {
+	tree, err := h.categoryService.GetCategoryTree(c.Request.Context(), c.Query("active") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to get category tree", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Category tree retrieved successfully", tree))
+}
+
+// GetCategory retrieves a category by ID
+// @Summary Get a category by ID
+// @Description Get a category by its ID
+// @Tags categories
+// @Produce json
+// @Param id path string true "Category ID"
+// @Success 200 {object} APIResponse{data=entities.Category}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /categories/{id} [get]
+func (h *CategoryHandler) GetCategory(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid category ID", err.Error()))
+		return
+	}
+
+	category, err := h.categoryService.GetCategory(c.Request.Context(), id)
+	if err != nil {
+		if err.Error() == "category not found" {
+			c.JSON(http.StatusNotFound, NewErrorResponse("Category not found", ""))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to get category", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Category retrieved successfully", category))
+}
+
+// GetCategoryBySlug retrieves a category by slug
+// @Summary Get a category by slug
+// @Description Get a category by its slug. A slug the category used to have is answered with a 301 to its current slug.
+// @Tags categories
+// @Produce json
+// @Param slug path string true "Category slug"
+// @Success 200 {object} APIResponse{data=entities.Category}
+// @Success 301 {object} APIResponse{data=entities.Category}
+// @Header 301 {string} Location "The category under its current slug"
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /categories/slug/{slug} [get]
+func (h *CategoryHandler) GetCategoryBySlug(c *gin.Context) {
+	slug := c.Param("slug")
+	category, err := h.categoryService.GetCategoryBySlug(c.Request.Context(), slug)
+	if err != nil {
+		if err.Error() == "category not found" {
+			c.JSON(http.StatusNotFound, NewErrorResponse("Category not found", ""))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to get category", err.Error()))
+		return
+	}
+
+	if redirectToCanonicalSlug(c, slug, category.Slug, category) {
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Category retrieved successfully", category))
+}
+
+// UpdateCategory updates a category
+// @Summary Update a category
+// @Description Update a category's details. Renaming changes its slug and the paths of all its descendants
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path string true "Category ID"
+// @Param category body services.UpdateCategoryRequest true "Category information"
+// @Success 200 {object} APIResponse{data=entities.Category}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /categories/{id} [put]
+func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid category ID", err.Error()))
+		return
+	}
+
+	var req services.UpdateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	category, err := h.categoryService.UpdateCategory(c.Request.Context(), id, &req)
+	if err != nil {
+		if err.Error() == "category not found" {
+			c.JSON(http.StatusNotFound, NewErrorResponse("Category not found", ""))
+			return
+		}
+		c.JSON(serviceErrorStatus(err), NewErrorResponse("Failed to update category", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Category updated successfully", category))
+}
+
+// DeleteCategory deletes a category
+// @Summary Delete a category
+// @Description Delete a category. Categories with subcategories or products cannot be deleted
+// @Tags categories
+// @Produce json
+// @Param id path string true "Category ID"
+// @Success 200 {object} APIResponse
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 409 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /categories/{id} [delete]
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid category ID", err.Error()))
+		return
+	}
+
+	err = h.categoryService.DeleteCategory(c.Request.Context(), id)
+	if err != nil {
+		switch err.Error() {
+		case "category not found":
+			c.JSON(http.StatusNotFound, NewErrorResponse("Category not found", ""))
+		case "category has subcategories", "category has products":
+			c.JSON(http.StatusConflict, NewErrorResponse("Failed to delete category", err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to delete category", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Category deleted successfully", nil))
+}
+
+// MoveCategory moves a category and its subtree
+// @Summary Move a category
+// @Description Move a category, with all its descendants, under another parent (or to the top level with a null parent_id). Level and path are recomputed for the whole subtree in one transaction; moves into the category's own subtree are rejected
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path string true "Category ID"
+// @Param move body services.MoveCategoryRequest true "New parent"
+// @Success 200 {object} APIResponse{data=repositories.CategoryTree}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /categories/{id}/move [post]
+func (h *CategoryHandler) MoveCategory(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid category ID", err.Error()))
+		return
+	}
+
+	var req services.MoveCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	tree, err := h.categoryService.MoveCategory(c.Request.Context(), id, &req)
+	if err != nil {
+		if err.Error() == "category not found" {
+			c.JSON(http.StatusNotFound, NewErrorResponse("Category not found", ""))
+			return
+		}
+		c.JSON(serviceErrorStatus(err), NewErrorResponse("Failed to move category", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Category moved successfully", tree))
+}
+
+// GetCategorySubTree retrieves the hierarchy below a category
+// @Summary Get category subtree
+// @Description Get a category with all its descendants
+// @Tags categories
+// @Produce json
+// @Param id path string true "Category ID"
+// @Success 200 {object} APIResponse{data=repositories.CategoryTree}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /categories/{id}/tree [get]
+func (h *CategoryHandler) GetCategorySubTree(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid category ID", err.Error()))
+		return
+	}
+
+	tree, err := h.categoryService.GetCategorySubTree(c.Request.Context(), id)
+	if err != nil {
+		if err.Error() == "category not found" {
+			c.JSON(http.StatusNotFound, NewErrorResponse("Category not found", ""))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to get category tree", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Category tree retrieved successfully", tree))
+}
+
+// GetCategoryBreadcrumbs retrieves the path from the top level to a category
+// @Summary Get category breadcrumbs
+// @Description Get the categories from the top level down to this one
+// @Tags categories
+// @Produce json
+// @Param id path string true "Category ID"
+// @Success 200 {object} APIResponse{data=[]services.CategoryBreadcrumb}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /categories/{id}/breadcrumbs [get]
+func (h *CategoryHandler) GetCategoryBreadcrumbs(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid category ID", err.Error()))
+		return
+	}
+
+	breadcrumbs, err := h.categoryService.GetBreadcrumbs(c.Request.Context(), id)
+	if err != nil {
+		if err.Error() == "category not found" {
+			c.JSON(http.StatusNotFound, NewErrorResponse("Category not found", ""))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to get breadcrumbs", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Breadcrumbs retrieved successfully", breadcrumbs))
+}
+
+// GetAttributeSchema retrieves a category's attribute schema
+// @Summary Get category attribute schema
+// @Description Get the attributes products in this category must follow, including those inherited from parent categories. Each attribute's category_id shows where it is defined
+// @Tags categories
+// @Produce json
+// @Param id path string true "Category ID"
+// @Success 200 {object} APIResponse{data=entities.AttributeSchema}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /categories/{id}/attributes [get]
+func (h *CategoryHandler) GetAttributeSchema(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid category ID", err.Error()))
+		return
+	}
+
+	schema, err := h.attributeSchemaService.GetSchema(c.Request.Context(), id)
+	if err != nil {
+		if err.Error() == "category not found" {
+			c.JSON(http.StatusNotFound, NewErrorResponse("Category not found", ""))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to get attribute schema", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Attribute schema retrieved successfully", schema))
+}
+
+// CreateAttribute adds an attribute to a category's schema
+// @Summary Add a category attribute
+// @Description Define an attribute for products in this category and its subcategories. Types are text, number, enum (requires allowed_values), boolean and measure (a number with one of units). Defining an attribute a parent category already has overrides it for this subtree. Filterable attributes become search facets
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path string true "Category ID"
+// @Param attribute body services.CreateCategoryAttributeRequest true "Attribute definition"
+// @Success 201 {object} APIResponse{data=entities.CategoryAttribute}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 409 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /categories/{id}/attributes [post]
+func (h *CategoryHandler) CreateAttribute(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid category ID", err.Error()))
+		return
+	}
+
+	var req services.CreateCategoryAttributeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	attribute, err := h.attributeSchemaService.CreateAttribute(c.Request.Context(), id, &req)
+	if err != nil {
+		switch err.Error() {
+		case "category not found":
+			c.JSON(http.StatusNotFound, NewErrorResponse("Category not found", ""))
+		case "attribute with this name already exists":
+			c.JSON(http.StatusConflict, NewErrorResponse("Failed to create attribute", err.Error()))
+		default:
+			c.JSON(serviceErrorStatus(err), NewErrorResponse("Failed to create attribute", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, NewSuccessResponse("Attribute created successfully", attribute))
+}
+
+// UpdateAttribute changes an attribute of a category's schema
+// @Summary Update a category attribute
+// @Description Change an attribute defined on this category. The name and type are fixed; existing products are checked against the new definition the next time their attributes change
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path string true "Category ID"
+// @Param attributeId path string true "Attribute ID"
+// @Param attribute body services.UpdateCategoryAttributeRequest true "Attribute definition"
+// @Success 200 {object} APIResponse{data=entities.CategoryAttribute}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /categories/{id}/attributes/{attributeId} [put]
+func (h *CategoryHandler) UpdateAttribute(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid category ID", err.Error()))
+		return
+	}
+
+	attributeID, err := uuid.Parse(c.Param("attributeId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid attribute ID", err.Error()))
+		return
+	}
+
+	var req services.UpdateCategoryAttributeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	attribute, err := h.attributeSchemaService.UpdateAttribute(c.Request.Context(), id, attributeID, &req)
+	if err != nil {
+		if err.Error() == "attribute not found" {
+			c.JSON(http.StatusNotFound, NewErrorResponse("Attribute not found", ""))
+			return
+		}
+		c.JSON(serviceErrorStatus(err), NewErrorResponse("Failed to update attribute", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Attribute updated successfully", attribute))
+}
+
+// DeleteAttribute removes an attribute from a category's schema
+// @Summary Delete a category attribute
+// @Description Remove an attribute defined on this category. Product values are kept; they follow the parent category's definition if there is one and are free-form otherwise
+// @Tags categories
+// @Produce json
+// @Param id path string true "Category ID"
+// @Param attributeId path string true "Attribute ID"
+// @Success 200 {object} APIResponse
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /categories/{id}/attributes/{attributeId} [delete]
+func (h *CategoryHandler) DeleteAttribute(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid category ID", err.Error()))
+		return
+	}
+
+	attributeID, err := uuid.Parse(c.Param("attributeId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid attribute ID", err.Error()))
+		return
+	}
+
+	err = h.attributeSchemaService.DeleteAttribute(c.Request.Context(), id, attributeID)
+	if err != nil {
+		if err.Error() == "attribute not found" {
+			c.JSON(http.StatusNotFound, NewErrorResponse("Attribute not found", ""))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to delete attribute", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Attribute deleted successfully", nil))
+}
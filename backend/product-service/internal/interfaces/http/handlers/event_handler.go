@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"product-service/internal/application/services"
+	"product-service/internal/domain/events"
+)
+
+// EventHandler receives events from other services
+type EventHandler struct {
+	orderEventService *services.OrderEventService
+}
+
+// NewEventHandler creates a new event handler
+func NewEventHandler(orderEventService *services.OrderEventService) *EventHandler {
+	return &EventHandler{
+		orderEventService: orderEventService,
+	}
+}
+
+// ReceiveEvent processes an event delivered by another service
+// @Summary Receive an event
+// @Description Deliver an event from another service. order.created events feed the frequently bought together recommendations, the product sales stats and verified-purchase reviews; other event types are acknowledged and ignored. Redelivered orders are counted once. The body must be signed with the shared EVENT_SIGNING_SECRET.
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param X-Event-Signature header string true "sha256= followed by the hex HMAC-SHA256 of the body"
+// @Param event body events.Event true "Event"
+// @Success 200 {object} APIResponse
+// @Failure 400 {object} APIResponse
+// @Failure 401 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Failure 503 {object} APIResponse
+// @Router /events [post]
+func (h *EventHandler) ReceiveEvent(c *gin.Context) {
+	var event events.Event
+	if err := c.ShouldBindJSON(&event); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	handled, err := h.orderEventService.HandleEvent(c.Request.Context(), &event)
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), NewErrorResponse("Failed to process event", err.Error()))
+		return
+	}
+	if !handled {
+		c.JSON(http.StatusOK, NewSuccessResponse("Event ignored", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Event processed successfully", nil))
+}
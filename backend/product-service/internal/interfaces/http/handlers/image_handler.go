@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"product-service/internal/application/services"
+)
+
+// ImageHandler handles HTTP requests for product images
+type ImageHandler struct {
+	imageService *services.ImageService
+}
+
+// NewImageHandler creates a new image handler
+func NewImageHandler(imageService *services.ImageService) *ImageHandler {
+	return &ImageHandler{
+		imageService: imageService,
+	}
+}
+
+// UploadProductImages uploads images of a product or variant
+// @Summary Upload product images
+// @Description Upload JPEG, PNG or GIF images of a product, or of one of its variants. The real file type is checked, EXIF and other metadata are stripped, and thumbnail, medium and zoom renditions are made. Files the product already has, by content, are reported as duplicates; files that are not supported images are reported as rejected.
+// @Tags products
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param images formData file true "Image files"
+// @Param variant_id formData string false "Variant ID"
+// @Param alt_text formData string false "Alt text for the images"
+// @Success 201 {object} APIResponse{data=services.ImageUploadResult}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /products/{id}/images [post]
+func (h *ImageHandler) UploadProductImages(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid product ID", err.Error()))
+		return
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Failed to parse multipart form", err.Error()))
+		return
+	}
+
+	files := form.File["images"]
+	if len(files) == 0 {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("No images provided", ""))
+		return
+	}
+
+	opts := services.ImageUploadOptions{AltText: c.PostForm("alt_text")}
+	if raw := c.PostForm("variant_id"); raw != "" {
+		variantID, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid variant ID", err.Error()))
+			return
+		}
+		opts.VariantID = &variantID
+	}
+
+	uploads := make([]services.ImageUpload, 0, len(files))
+	for _, header := range files {
+		data, err := readUpload(header)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, NewErrorResponse("Failed to read image", err.Error()))
+			return
+		}
+		uploads = append(uploads, services.ImageUpload{FileName: header.Filename, Data: data})
+	}
+
+	result, err := h.imageService.UploadImages(c.Request.Context(), productID, uploads, opts)
+	if err != nil {
+		switch err.Error() {
+		case "product not found", "variant not found":
+			c.JSON(http.StatusNotFound, NewErrorResponse("Failed to upload images", err.Error()))
+		default:
+			c.JSON(serviceErrorStatus(err), NewErrorResponse("Failed to upload images", err.Error()))
+		}
+		return
+	}
+
+	if len(result.Created) == 0 && len(result.Duplicates) == 0 {
+		c.JSON(http.StatusBadRequest, APIResponse{Success: false, Message: "No valid images provided", Data: result})
+		return
+	}
+
+	c.JSON(http.StatusCreated, NewSuccessResponse("Images uploaded successfully", result))
+}
+
+// readUpload reads an uploaded file, up to one byte past the size limit so oversized
+// files can be rejected
+func readUpload(header *multipart.FileHeader) ([]byte, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return io.ReadAll(io.LimitReader(file, services.MaxImageUploadSize+1))
+}
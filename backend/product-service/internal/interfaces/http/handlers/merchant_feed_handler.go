@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"product-service/internal/application/services"
+	"product-service/internal/domain/repositories"
+)
+
+// merchantFeedCacheControl lets Merchant Center and caches reuse a feed for an hour
+const merchantFeedCacheControl = "public, max-age=3600"
+
+// MerchantFeedHandler handles HTTP requests for the Google Merchant Center feed
+type MerchantFeedHandler struct {
+	feedService *services.MerchantFeedService
+}
+
+// NewMerchantFeedHandler creates a new merchant feed handler
+func NewMerchantFeedHandler(feedService *services.MerchantFeedService) *MerchantFeedHandler {
+	return &MerchantFeedHandler{
+		feedService: feedService,
+	}
+}
+
+// GetGoogleFeed streams the Google Merchant Center product feed
+// @Summary Get Google Merchant feed
+// @Description Stream active, visible products, one item per active variant for products with variants, as Google Merchant RSS/XML or TSV. Items missing required fields are left out; see the feed report. The feed carries an ETag and Last-Modified, and conditional requests are answered with 304 while the catalog is unchanged.
+// @Tags feeds
+// @Produce xml,text/tab-separated-values
+// @Param format query string false "xml or tsv" default(xml)
+// @Param If-None-Match header string false "ETag of a feed already fetched"
+// @Param If-Modified-Since header string false "Last-Modified of a feed already fetched"
+// @Success 200 {file} file
+// @Success 304 "Not modified"
+// @Failure 400 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /feeds/google [get]
+func (h *MerchantFeedHandler) GetGoogleFeed(c *gin.Context) {
+	format := c.DefaultQuery("format", services.MerchantFeedFormatXML)
+	contentType := "application/xml; charset=utf-8"
+	switch format {
+	case services.MerchantFeedFormatXML:
+	case services.MerchantFeedFormatTSV:
+		contentType = "text/tab-separated-values; charset=utf-8"
+	default:
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid feed format", "format must be xml or tsv"))
+		return
+	}
+
+	version, err := h.feedService.GetFeedVersion(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to get feed", err.Error()))
+		return
+	}
+
+	etag := merchantFeedETag(version, format)
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", merchantFeedCacheControl)
+	if !version.LastModified.IsZero() {
+		c.Header("Last-Modified", version.LastModified.UTC().Format(http.TimeFormat))
+	}
+	if feedNotModified(c, etag, version.LastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="google-products.%s"`, format))
+	c.Status(http.StatusOK)
+
+	// Headers are already sent, so a failure part-way can only be logged
+	if err := h.feedService.WriteFeed(c.Request.Context(), format, c.Writer); err != nil {
+		log.Printf("Google Merchant feed failed: %v", err)
+	}
+}
+
+// GetGoogleFeedReport validates the Google Merchant Center feed
+// @Summary Get Google Merchant feed report
+// @Description Validate every feed item and list the ones with errors, which leave them out of the feed, or warnings, such as values cut to Merchant Center's length limits or an invalid GTIN
+// @Tags feeds
+// @Produce json
+// @Success 200 {object} APIResponse{data=services.MerchantFeedReport}
+// @Failure 500 {object} APIResponse
+// @Router /feeds/google/report [get]
+func (h *MerchantFeedHandler) GetGoogleFeedReport(c *gin.Context) {
+	report, err := h.feedService.BuildReport(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to build feed report", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Feed report built successfully", report))
+}
+
+// merchantFeedETag identifies a feed by its format and the catalog state it is built from
+func merchantFeedETag(version *repositories.FeedVersion, format string) string {
+	return fmt.Sprintf(`"%s-%d-%d"`, format, version.Items, version.LastModified.UnixNano())
+}
+
+// feedNotModified checks the request's conditional headers against the feed. If-None-Match
+// takes precedence over If-Modified-Since, as HTTP requires.
+func feedNotModified(c *gin.Context, etag string, lastModified time.Time) bool {
+	if ifNoneMatch := c.GetHeader("If-None-Match"); ifNoneMatch != "" {
+		for _, tag := range strings.Split(ifNoneMatch, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == etag || tag == "*" {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ifModifiedSince := c.GetHeader("If-Modified-Since"); ifModifiedSince != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(ifModifiedSince)
+		if err == nil && !lastModified.Truncate(time.Second).After(since) {
+			return true
+		}
+	}
+	return false
+}
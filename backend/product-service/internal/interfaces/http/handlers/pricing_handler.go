@@ -0,0 +1,389 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"product-service/internal/application/services"
+)
+
+// PricingHandler handles HTTP requests for price lists and price resolution
+type PricingHandler struct {
+	pricingService *services.PricingService
+}
+
+// NewPricingHandler creates a new pricing handler
+func NewPricingHandler(pricingService *services.PricingService) *PricingHandler {
+	return &PricingHandler{
+		pricingService: pricingService,
+	}
+}
+
+// CreatePriceList creates a new price list
+// @Summary Create a price list
+// @Description Create a price list for a currency, sales channel and customer group. An empty channel or group applies to all; there is at most one list per combination.
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param priceList body services.PriceListRequest true "Price list"
+// @Success 201 {object} APIResponse{data=entities.PriceList}
+// @Failure 400 {object} APIResponse
+// @Failure 409 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /price-lists [post]
+func (h *PricingHandler) CreatePriceList(c *gin.Context) {
+	var req services.PriceListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	list, err := h.pricingService.CreatePriceList(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(priceListErrorStatus(err), NewErrorResponse("Failed to create price list", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, NewSuccessResponse("Price list created successfully", list))
+}
+
+// ListPriceLists lists price lists
+// @Summary List price lists
+// @Description List all price lists
+// @Tags pricing
+// @Produce json
+// @Success 200 {object} APIResponse{data=[]entities.PriceList}
+// @Failure 500 {object} APIResponse
+// @Router /price-lists [get]
+func (h *PricingHandler) ListPriceLists(c *gin.Context) {
+	lists, err := h.pricingService.ListPriceLists(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to list price lists", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Price lists retrieved successfully", lists))
+}
+
+// GetPriceList retrieves a price list
+// @Summary Get a price list
+// @Description Get a price list by its ID
+// @Tags pricing
+// @Produce json
+// @Param id path string true "Price list ID"
+// @Success 200 {object} APIResponse{data=entities.PriceList}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /price-lists/{id} [get]
+func (h *PricingHandler) GetPriceList(c *gin.Context) {
+	id, ok := parsePriceListID(c)
+	if !ok {
+		return
+	}
+
+	list, err := h.pricingService.GetPriceList(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(priceListErrorStatus(err), NewErrorResponse("Failed to get price list", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Price list retrieved successfully", list))
+}
+
+// UpdatePriceList updates a price list
+// @Summary Update a price list
+// @Description Change a price list's name, currency, channel, customer group or active state
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param id path string true "Price list ID"
+// @Param priceList body services.PriceListRequest true "Price list"
+// @Success 200 {object} APIResponse{data=entities.PriceList}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 409 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /price-lists/{id} [put]
+func (h *PricingHandler) UpdatePriceList(c *gin.Context) {
+	id, ok := parsePriceListID(c)
+	if !ok {
+		return
+	}
+
+	var req services.PriceListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	list, err := h.pricingService.UpdatePriceList(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(priceListErrorStatus(err), NewErrorResponse("Failed to update price list", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Price list updated successfully", list))
+}
+
+// DeletePriceList deletes a price list
+// @Summary Delete a price list
+// @Description Delete a price list and all its prices
+// @Tags pricing
+// @Produce json
+// @Param id path string true "Price list ID"
+// @Success 200 {object} APIResponse
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /price-lists/{id} [delete]
+func (h *PricingHandler) DeletePriceList(c *gin.Context) {
+	id, ok := parsePriceListID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.pricingService.DeletePriceList(c.Request.Context(), id); err != nil {
+		c.JSON(priceListErrorStatus(err), NewErrorResponse("Failed to delete price list", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Price list deleted successfully", nil))
+}
+
+// AddPrice adds a price to a price list
+// @Summary Add a price
+// @Description Add a product price, or a variant price when variant_id is set, to a price list, optionally valid only between valid_from and valid_until
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param id path string true "Price list ID"
+// @Param price body services.PriceListEntryRequest true "Price"
+// @Success 201 {object} APIResponse{data=entities.PriceListEntry}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /price-lists/{id}/prices [post]
+func (h *PricingHandler) AddPrice(c *gin.Context) {
+	id, ok := parsePriceListID(c)
+	if !ok {
+		return
+	}
+
+	var req services.PriceListEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	entry, err := h.pricingService.AddPrice(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(priceListErrorStatus(err), NewErrorResponse("Failed to add price", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, NewSuccessResponse("Price added successfully", entry))
+}
+
+// ListPrices lists the prices in a price list
+// @Summary List prices
+// @Description List the prices in a price list
+// @Tags pricing
+// @Produce json
+// @Param id path string true "Price list ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} APIResponse{data=services.PriceListEntryListResult}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /price-lists/{id}/prices [get]
+func (h *PricingHandler) ListPrices(c *gin.Context) {
+	id, ok := parsePriceListID(c)
+	if !ok {
+		return
+	}
+
+	page, pageSize := parsePagination(c)
+	result, err := h.pricingService.ListPrices(c.Request.Context(), id, page, pageSize)
+	if err != nil {
+		c.JSON(priceListErrorStatus(err), NewErrorResponse("Failed to list prices", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Prices retrieved successfully", result))
+}
+
+// UpdatePrice updates a price in a price list
+// @Summary Update a price
+// @Description Change the amount or validity of a price list entry
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param id path string true "Price list ID"
+// @Param priceId path string true "Price ID"
+// @Param price body services.UpdatePriceListEntryRequest true "Price"
+// @Success 200 {object} APIResponse{data=entities.PriceListEntry}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /price-lists/{id}/prices/{priceId} [put]
+func (h *PricingHandler) UpdatePrice(c *gin.Context) {
+	id, entryID, ok := parsePriceEntryIDs(c)
+	if !ok {
+		return
+	}
+
+	var req services.UpdatePriceListEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	entry, err := h.pricingService.UpdatePrice(c.Request.Context(), id, entryID, &req)
+	if err != nil {
+		c.JSON(priceListErrorStatus(err), NewErrorResponse("Failed to update price", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Price updated successfully", entry))
+}
+
+// DeletePrice removes a price from a price list
+// @Summary Delete a price
+// @Description Remove a price list entry
+// @Tags pricing
+// @Produce json
+// @Param id path string true "Price list ID"
+// @Param priceId path string true "Price ID"
+// @Success 200 {object} APIResponse
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /price-lists/{id}/prices/{priceId} [delete]
+func (h *PricingHandler) DeletePrice(c *gin.Context) {
+	id, entryID, ok := parsePriceEntryIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.pricingService.DeletePrice(c.Request.Context(), id, entryID); err != nil {
+		c.JSON(priceListErrorStatus(err), NewErrorResponse("Failed to delete price", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Price deleted successfully", nil))
+}
+
+// ResolvePrice resolves the price of a product for a shopper
+// @Summary Resolve a product price
+// @Description Resolve the price of a product, or of one of its variants, in a currency, sales channel and customer group. The lowest applicable price list price wins, variant prices before product prices; the base price is the fallback.
+// @Tags pricing
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param variant_id query string false "Variant ID"
+// @Param currency query string false "Currency, defaults to the base currency"
+// @Param channel query string false "Sales channel"
+// @Param group query string false "Customer group"
+// @Success 200 {object} APIResponse{data=entities.ResolvedPrice}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /products/{id}/price [get]
+func (h *PricingHandler) ResolvePrice(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid product ID", err.Error()))
+		return
+	}
+
+	var variantID *uuid.UUID
+	if raw := c.Query("variant_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid variant ID", err.Error()))
+			return
+		}
+		variantID = &id
+	}
+
+	pc := services.PriceContext{
+		Currency:      c.Query("currency"),
+		Channel:       c.Query("channel"),
+		CustomerGroup: c.Query("group"),
+	}
+
+	price, err := h.pricingService.ResolvePrice(c.Request.Context(), productID, variantID, pc)
+	if err != nil {
+		c.JSON(priceListErrorStatus(err), NewErrorResponse("Failed to resolve price", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Price resolved successfully", price))
+}
+
+// QuotePrices prices a list of SKUs and quantities
+// @Summary Quote prices
+// @Description Price a list of product or variant SKUs and quantities in the base currency. Each line reports its unit price, line total and the quantity break that applied; breaks are chosen by the total quantity of a SKU across the request. Running sales are applied on top.
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param quote body services.QuoteRequest true "Lines to price"
+// @Success 200 {object} APIResponse{data=services.Quote}
+// @Failure 400 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /price-quotes [post]
+func (h *PricingHandler) QuotePrices(c *gin.Context) {
+	var req services.QuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	quote, err := h.pricingService.Quote(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), NewErrorResponse("Failed to quote prices", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Prices quoted successfully", quote))
+}
+
+// parsePriceListID parses the price list ID path parameter, writing a 400 response when
+// it is invalid
+func parsePriceListID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid price list ID", err.Error()))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// parsePriceEntryIDs parses the price list and price ID path parameters
+func parsePriceEntryIDs(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	id, ok := parsePriceListID(c)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	entryID, err := uuid.Parse(c.Param("priceId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid price ID", err.Error()))
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return id, entryID, true
+}
+
+// priceListErrorStatus maps pricing service errors to HTTP statuses
+func priceListErrorStatus(err error) int {
+	switch err.Error() {
+	case "price list not found", "price not found", "product not found", "variant not found":
+		return http.StatusNotFound
+	case "price list for this currency, channel and customer group already exists":
+		return http.StatusConflict
+	}
+	return serviceErrorStatus(err)
+}
@@ -1,30 +1,36 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"product-service/internal/application/services"
 	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
 )
 
 // ProductHandler handles HTTP requests for products
 type ProductHandler struct {
 	productService *services.ProductService
+	pricingService *services.PricingService
 }
 
 // NewProductHandler creates a new product handler
-func NewProductHandler(productService *services.ProductService) *ProductHandler {
+func NewProductHandler(productService *services.ProductService, pricingService *services.PricingService) *ProductHandler {
 	return &ProductHandler{
 		productService: productService,
+		pricingService: pricingService,
 	}
 }
 
 // CreateProduct creates a new product
 // @Summary Create a new product
-// @Description Create a new product with the provided information
+// @Description Create a new product with the provided information. Attributes are validated against the category's attribute schema; invalid ones are listed in fields
 // @Tags products
 // @Accept json
 // @Produce json
@@ -42,10 +48,16 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 	
 	product, err := h.productService.CreateProduct(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to create product", err.Error()))
+		var validationErr *entities.AttributeValidationError
+		if errors.As(err, &validationErr) {
+			c.JSON(http.StatusBadRequest, NewValidationErrorResponse("Invalid product attributes", validationErr))
+			return
+		}
+		c.JSON(serviceErrorStatus(err), NewErrorResponse("Failed to create product", err.Error()))
 		return
 	}
 	
+	setProductETag(c, product)
 	c.JSON(http.StatusCreated, NewSuccessResponse("Product created successfully", product))
 }
 
@@ -56,7 +68,11 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 // @Produce json
 // @Param id path string true "Product ID"
 // @Param include_related query bool false "Include related data (images, variants, etc.)"
+// @Param currency query string false "Currency to resolve prices in, e.g. EUR; defaults to the base currency"
+// @Param channel query string false "Sales channel to resolve prices for"
+// @Param group query string false "Customer group to resolve prices for"
 // @Success 200 {object} APIResponse{data=entities.Product}
+// @Header 200 {string} ETag "Product version, to send back in If-Match"
 // @Failure 400 {object} APIResponse
 // @Failure 404 {object} APIResponse
 // @Failure 500 {object} APIResponse
@@ -82,6 +98,11 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 		return
 	}
 	
+	if !h.applyPricing(c, product) {
+		return
+	}
+	
+	setProductETag(c, product)
 	c.JSON(http.StatusOK, NewSuccessResponse("Product retrieved successfully", product))
 }
 
@@ -92,7 +113,11 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 // @Produce json
 // @Param sku path string true "Product SKU"
 // @Param include_related query bool false "Include related data (images, variants, etc.)"
+// @Param currency query string false "Currency to resolve prices in, e.g. EUR; defaults to the base currency"
+// @Param channel query string false "Sales channel to resolve prices for"
+// @Param group query string false "Customer group to resolve prices for"
 // @Success 200 {object} APIResponse{data=entities.Product}
+// @Header 200 {string} ETag "Product version, to send back in If-Match"
 // @Failure 400 {object} APIResponse
 // @Failure 404 {object} APIResponse
 // @Failure 500 {object} APIResponse
@@ -117,17 +142,28 @@ func (h *ProductHandler) GetProductBySKU(c *gin.Context) {
 		return
 	}
 	
+	if !h.applyPricing(c, product) {
+		return
+	}
+	
+	setProductETag(c, product)
 	c.JSON(http.StatusOK, NewSuccessResponse("Product retrieved successfully", product))
 }
 
 // GetProductBySlug retrieves a product by slug
 // @Summary Get a product by slug
-// @Description Get a product by its slug with optional related data
+// @Description Get a product by its slug with optional related data. A slug the product used to have is answered with a 301 to its current slug.
 // @Tags products
 // @Produce json
 // @Param slug path string true "Product slug"
 // @Param include_related query bool false "Include related data (images, variants, etc.)"
+// @Param currency query string false "Currency to resolve prices in, e.g. EUR; defaults to the base currency"
+// @Param channel query string false "Sales channel to resolve prices for"
+// @Param group query string false "Customer group to resolve prices for"
 // @Success 200 {object} APIResponse{data=entities.Product}
+// @Header 200 {string} ETag "Product version, to send back in If-Match"
+// @Success 301 {object} APIResponse{data=entities.Product}
+// @Header 301 {string} Location "The product under its current slug"
 // @Failure 400 {object} APIResponse
 // @Failure 404 {object} APIResponse
 // @Failure 500 {object} APIResponse
@@ -152,20 +188,32 @@ func (h *ProductHandler) GetProductBySlug(c *gin.Context) {
 		return
 	}
 	
+	if !h.applyPricing(c, product) {
+		return
+	}
+	
+	setProductETag(c, product)
+	if redirectToCanonicalSlug(c, slug, product.Slug, product) {
+		return
+	}
 	c.JSON(http.StatusOK, NewSuccessResponse("Product retrieved successfully", product))
 }
 
 // UpdateProduct updates an existing product
 // @Summary Update a product
-// @Description Update an existing product with the provided information
+// @Description Update an existing product with the provided information. When attributes or the category change, the product's attributes are validated against the category's attribute schema; invalid ones are listed in fields. If-Match must carry the ETag the product was read with; the update is refused with 412 if the product has changed since.
 // @Tags products
 // @Accept json
 // @Produce json
 // @Param id path string true "Product ID"
+// @Param If-Match header string true "ETag of the product being updated, or * to skip the check"
 // @Param product body services.UpdateProductRequest true "Product information"
 // @Success 200 {object} APIResponse{data=entities.Product}
+// @Header 200 {string} ETag "New product version"
 // @Failure 400 {object} APIResponse
 // @Failure 404 {object} APIResponse
+// @Failure 412 {object} APIResponse
+// @Failure 428 {object} APIResponse
 // @Failure 500 {object} APIResponse
 // @Router /products/{id} [put]
 func (h *ProductHandler) UpdateProduct(c *gin.Context) {
@@ -176,34 +224,52 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 		return
 	}
 	
+	version, ok := requireIfMatch(c)
+	if !ok {
+		return
+	}
+	
 	var req services.UpdateProductRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid request body", err.Error()))
 		return
 	}
 	
-	product, err := h.productService.UpdateProduct(c.Request.Context(), id, &req)
+	product, err := h.productService.UpdateProduct(c.Request.Context(), id, version, &req)
 	if err != nil {
 		if err.Error() == "product not found" {
 			c.JSON(http.StatusNotFound, NewErrorResponse("Product not found", ""))
 			return
 		}
+		if errors.Is(err, repositories.ErrVersionConflict) {
+			c.JSON(http.StatusPreconditionFailed, NewErrorResponse("Product has been modified", err.Error()))
+			return
+		}
+		var validationErr *entities.AttributeValidationError
+		if errors.As(err, &validationErr) {
+			c.JSON(http.StatusBadRequest, NewValidationErrorResponse("Invalid product attributes", validationErr))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to update product", err.Error()))
 		return
 	}
 	
+	setProductETag(c, product)
 	c.JSON(http.StatusOK, NewSuccessResponse("Product updated successfully", product))
 }
 
 // DeleteProduct deletes a product
 // @Summary Delete a product
-// @Description Delete a product by its ID
+// @Description Delete a product by its ID. If-Match must carry the ETag the product was read with; the delete is refused with 412 if the product has changed since.
 // @Tags products
 // @Produce json
 // @Param id path string true "Product ID"
+// @Param If-Match header string true "ETag of the product being deleted, or * to skip the check"
 // @Success 200 {object} APIResponse
 // @Failure 400 {object} APIResponse
 // @Failure 404 {object} APIResponse
+// @Failure 412 {object} APIResponse
+// @Failure 428 {object} APIResponse
 // @Failure 500 {object} APIResponse
 // @Router /products/{id} [delete]
 func (h *ProductHandler) DeleteProduct(c *gin.Context) {
@@ -214,12 +280,25 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 		return
 	}
 	
-	err = h.productService.DeleteProduct(c.Request.Context(), id)
+	version, ok := requireIfMatch(c)
+	if !ok {
+		return
+	}
+	
+	err = h.productService.DeleteProduct(c.Request.Context(), id, version)
 	if err != nil {
 		if err.Error() == "product not found" {
 			c.JSON(http.StatusNotFound, NewErrorResponse("Product not found", ""))
 			return
 		}
+		if errors.Is(err, repositories.ErrVersionConflict) {
+			c.JSON(http.StatusPreconditionFailed, NewErrorResponse("Product has been modified", err.Error()))
+			return
+		}
+		if err.Error() == "product is a component of a bundle" {
+			c.JSON(http.StatusConflict, NewErrorResponse("Failed to delete product", err.Error()))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to delete product", err.Error()))
 		return
 	}
@@ -229,20 +308,27 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 
 // SearchProducts searches for products
 // @Summary Search products
-// @Description Search for products with various filters and pagination
+// @Description Full-text search (prefix matching, ranked by relevance) with various filters and pagination
 // @Tags products
 // @Produce json
 // @Param q query string false "Search query"
 // @Param category_ids query []string false "Category IDs"
 // @Param brand_ids query []string false "Brand IDs"
 // @Param min_price query number false "Minimum price"
-// @Param max_price query number false "Maximum price"
+// @Param max_price query number false "Maximum price, exclusive"
 // @Param in_stock query bool false "In stock filter"
 // @Param featured query bool false "Featured filter"
+// @Param is_bundle query bool false "Bundle filter"
 // @Param status query int false "Product status"
 // @Param visibility query int false "Product visibility"
+// @Param tags query []string false "Tags"
+// @Param attr query object false "Attribute filters, e.g. attr[color]=Red"
+// @Param facets query bool false "Include facet counts" default(true)
 // @Param page query int false "Page number" default(1)
 // @Param page_size query int false "Page size" default(20)
+// @Param currency query string false "Currency to resolve prices in, e.g. EUR; defaults to the base currency"
+// @Param channel query string false "Sales channel to resolve prices for"
+// @Param group query string false "Customer group to resolve prices for"
 // @Success 200 {object} APIResponse{data=repositories.ProductSearchResult}
 // @Failure 400 {object} APIResponse
 // @Failure 500 {object} APIResponse
@@ -312,6 +398,12 @@ func (h *ProductHandler) SearchProducts(c *gin.Context) {
 		}
 	}
 	
+	if isBundleStr := c.Query("is_bundle"); isBundleStr != "" {
+		if isBundle, err := strconv.ParseBool(isBundleStr); err == nil {
+			req.IsBundle = &isBundle
+		}
+	}
+	
 	// Parse status
 	if statusStr := c.Query("status"); statusStr != "" {
 		if status, err := strconv.Atoi(statusStr); err == nil {
@@ -333,12 +425,24 @@ func (h *ProductHandler) SearchProducts(c *gin.Context) {
 		req.Tags = tags
 	}
 	
+	// Parse attribute filters (attr[color]=Red)
+	if attributes := c.QueryMap("attr"); len(attributes) > 0 {
+		req.Attributes = attributes
+	}
+	
+	// Facets are returned unless explicitly disabled
+	req.IncludeFacets = c.Query("facets") != "false"
+	
 	result, err := h.productService.SearchProducts(c.Request.Context(), req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to search products", err.Error()))
 		return
 	}
 	
+	if !h.applyPricing(c, result.Products...) {
+		return
+	}
+	
 	c.JSON(http.StatusOK, NewSuccessResponse("Products retrieved successfully", result))
 }
 
@@ -350,6 +454,9 @@ func (h *ProductHandler) SearchProducts(c *gin.Context) {
 // @Param category_id path string true "Category ID"
 // @Param page query int false "Page number" default(1)
 // @Param page_size query int false "Page size" default(20)
+// @Param currency query string false "Currency to resolve prices in, e.g. EUR; defaults to the base currency"
+// @Param channel query string false "Sales channel to resolve prices for"
+// @Param group query string false "Customer group to resolve prices for"
 // @Success 200 {object} APIResponse{data=[]entities.Product}
 // @Failure 400 {object} APIResponse
 // @Failure 500 {object} APIResponse
@@ -383,6 +490,10 @@ func (h *ProductHandler) GetProductsByCategory(c *gin.Context) {
 		return
 	}
 	
+	if !h.applyPricing(c, products...) {
+		return
+	}
+	
 	c.JSON(http.StatusOK, NewSuccessResponse("Products retrieved successfully", products))
 }
 
@@ -394,6 +505,9 @@ func (h *ProductHandler) GetProductsByCategory(c *gin.Context) {
 // @Param brand_id path string true "Brand ID"
 // @Param page query int false "Page number" default(1)
 // @Param page_size query int false "Page size" default(20)
+// @Param currency query string false "Currency to resolve prices in, e.g. EUR; defaults to the base currency"
+// @Param channel query string false "Sales channel to resolve prices for"
+// @Param group query string false "Customer group to resolve prices for"
 // @Success 200 {object} APIResponse{data=[]entities.Product}
 // @Failure 400 {object} APIResponse
 // @Failure 500 {object} APIResponse
@@ -427,15 +541,56 @@ func (h *ProductHandler) GetProductsByBrand(c *gin.Context) {
 		return
 	}
 	
+	if !h.applyPricing(c, products...) {
+		return
+	}
+	
 	c.JSON(http.StatusOK, NewSuccessResponse("Products retrieved successfully", products))
 }
 
+// SuggestProducts returns typeahead suggestions
+// @Summary Typeahead suggestions
+// @Description Completions for product, brand and category names as the user types. Tolerates typos and ranks popular items first
+// @Tags products
+// @Produce json
+// @Param q query string true "Partial search query"
+// @Param limit query int false "Number of suggestions to return" default(10)
+// @Success 200 {object} APIResponse{data=[]repositories.Suggestion}
+// @Failure 400 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /products/suggest [get]
+func (h *ProductHandler) SuggestProducts(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Query is required", "q parameter is empty"))
+		return
+	}
+	
+	limit := 10
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 20 {
+			limit = l
+		}
+	}
+	
+	suggestions, err := h.productService.SuggestProducts(c.Request.Context(), query, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to get suggestions", err.Error()))
+		return
+	}
+	
+	c.JSON(http.StatusOK, NewSuccessResponse("Suggestions retrieved successfully", suggestions))
+}
+
 // GetFeaturedProducts gets featured products
 // @Summary Get featured products
 // @Description Get a list of featured products
 // @Tags products
 // @Produce json
 // @Param limit query int false "Number of products to return" default(10)
+// @Param currency query string false "Currency to resolve prices in, e.g. EUR; defaults to the base currency"
+// @Param channel query string false "Sales channel to resolve prices for"
+// @Param group query string false "Customer group to resolve prices for"
 // @Success 200 {object} APIResponse{data=[]entities.Product}
 // @Failure 500 {object} APIResponse
 // @Router /products/featured [get]
@@ -454,9 +609,114 @@ func (h *ProductHandler) GetFeaturedProducts(c *gin.Context) {
 		return
 	}
 	
+	if !h.applyPricing(c, products...) {
+		return
+	}
+	
 	c.JSON(http.StatusOK, NewSuccessResponse("Featured products retrieved successfully", products))
 }
 
+// GetTopSellingProducts gets top selling products
+// @Summary Get top selling products
+// @Description Get the listed products with the most units sold over the last days
+// @Tags products
+// @Produce json
+// @Param limit query int false "Number of products to return" default(10)
+// @Param days query int false "Number of days, today included, to rank over" default(7)
+// @Param category_id query string false "Only rank products in this category"
+// @Param currency query string false "Currency to resolve prices in, e.g. EUR; defaults to the base currency"
+// @Param channel query string false "Sales channel to resolve prices for"
+// @Param group query string false "Customer group to resolve prices for"
+// @Success 200 {object} APIResponse{data=[]entities.Product}
+// @Failure 400 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /products/top-selling [get]
+func (h *ProductHandler) GetTopSellingProducts(c *gin.Context) {
+	h.getRankedProducts(c, services.RankingTopSelling, "Top selling products retrieved successfully")
+}
+
+// GetMostViewedProducts gets most viewed products
+// @Summary Get most viewed products
+// @Description Get the listed products with the most views over the last days
+// @Tags products
+// @Produce json
+// @Param limit query int false "Number of products to return" default(10)
+// @Param days query int false "Number of days, today included, to rank over" default(7)
+// @Param category_id query string false "Only rank products in this category"
+// @Param currency query string false "Currency to resolve prices in, e.g. EUR; defaults to the base currency"
+// @Param channel query string false "Sales channel to resolve prices for"
+// @Param group query string false "Customer group to resolve prices for"
+// @Success 200 {object} APIResponse{data=[]entities.Product}
+// @Failure 400 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /products/most-viewed [get]
+func (h *ProductHandler) GetMostViewedProducts(c *gin.Context) {
+	h.getRankedProducts(c, services.RankingMostViewed, "Most viewed products retrieved successfully")
+}
+
+// GetTrendingProducts gets trending products
+// @Summary Get trending products
+// @Description Get the listed products with the most recent views and sales over the last days. Recent days weigh more than earlier ones, and a sale more than a view.
+// @Tags products
+// @Produce json
+// @Param limit query int false "Number of products to return" default(10)
+// @Param days query int false "Number of days, today included, to rank over" default(7)
+// @Param category_id query string false "Only rank products in this category"
+// @Param currency query string false "Currency to resolve prices in, e.g. EUR; defaults to the base currency"
+// @Param channel query string false "Sales channel to resolve prices for"
+// @Param group query string false "Customer group to resolve prices for"
+// @Success 200 {object} APIResponse{data=[]entities.Product}
+// @Failure 400 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /products/trending [get]
+func (h *ProductHandler) GetTrendingProducts(c *gin.Context) {
+	h.getRankedProducts(c, services.RankingTrending, "Trending products retrieved successfully")
+}
+
+// getRankedProducts writes the products of a ranking for the limit, days and category
+// in the query
+func (h *ProductHandler) getRankedProducts(c *gin.Context, ranking services.ProductRanking, message string) {
+	limit := 10
+	days := 7
+	
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+	
+	if daysStr := c.Query("days"); daysStr != "" {
+		d, err := strconv.Atoi(daysStr)
+		if err != nil || d < 1 || d > 365 {
+			c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid days", "days must be between 1 and 365"))
+			return
+		}
+		days = d
+	}
+	
+	var categoryID *uuid.UUID
+	if categoryIDStr := c.Query("category_id"); categoryIDStr != "" {
+		id, err := uuid.Parse(categoryIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid category ID", err.Error()))
+			return
+		}
+		categoryID = &id
+	}
+	
+	products, err := h.productService.GetRankedProducts(c.Request.Context(), ranking, limit, days, categoryID)
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), NewErrorResponse("Failed to rank products", err.Error()))
+		return
+	}
+	
+	if !h.applyPricing(c, products...) {
+		return
+	}
+	
+	c.JSON(http.StatusOK, NewSuccessResponse(message, products))
+}
+
 // UpdateProductStock updates product stock
 // @Summary Update product stock
 // @Description Update the stock quantity of a product
@@ -490,13 +750,93 @@ func (h *ProductHandler) UpdateProductStock(c *gin.Context) {
 			c.JSON(http.StatusNotFound, NewErrorResponse("Product not found", ""))
 			return
 		}
-		c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to update stock", err.Error()))
+		c.JSON(serviceErrorStatus(err), NewErrorResponse("Failed to update stock", err.Error()))
 		return
 	}
 	
 	c.JSON(http.StatusOK, NewSuccessResponse("Stock updated successfully", nil))
 }
 
+// UpdateProductSchedule replaces a product's publishing schedule
+// @Summary Update product schedule
+// @Description Replace when a draft product is published and the window in which it can be bought. Omitted or null dates are cleared. Drafts are published, and products unpublished when available_until passes, by the publishing scheduler.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param schedule body services.ProductScheduleRequest true "Schedule"
+// @Success 200 {object} APIResponse{data=entities.Product}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /products/{id}/schedule [put]
+func (h *ProductHandler) UpdateProductSchedule(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid product ID", err.Error()))
+		return
+	}
+	
+	var req services.ProductScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+	
+	product, err := h.productService.UpdateSchedule(c.Request.Context(), id, &req)
+	if err != nil {
+		if err.Error() == "product not found" {
+			c.JSON(http.StatusNotFound, NewErrorResponse("Product not found", ""))
+			return
+		}
+		c.JSON(serviceErrorStatus(err), NewErrorResponse("Failed to update schedule", err.Error()))
+		return
+	}
+	
+	c.JSON(http.StatusOK, NewSuccessResponse("Schedule updated successfully", product))
+}
+
+// UpdateProductPriceTiers replaces a product's quantity breaks
+// @Summary Update product price tiers
+// @Description Replace the quantity breaks of a product, e.g. 10+ units at one price and 50+ at a lower one. Below the first tier the regular price applies; an empty list removes the tiers. Variants that use the product price inherit these tiers unless they have their own.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param tiers body services.PriceTiersRequest true "Price tiers"
+// @Success 200 {object} APIResponse{data=entities.Product}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /products/{id}/price-tiers [put]
+func (h *ProductHandler) UpdateProductPriceTiers(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid product ID", err.Error()))
+		return
+	}
+	
+	var req services.PriceTiersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+	
+	product, err := h.productService.SetPriceTiers(c.Request.Context(), id, &req)
+	if err != nil {
+		if err.Error() == "product not found" {
+			c.JSON(http.StatusNotFound, NewErrorResponse("Product not found", ""))
+			return
+		}
+		c.JSON(serviceErrorStatus(err), NewErrorResponse("Failed to update price tiers", err.Error()))
+		return
+	}
+	
+	c.JSON(http.StatusOK, NewSuccessResponse("Price tiers updated successfully", product))
+}
+
 // Supporting types
 
 // UpdateStockRequest represents a stock update request
@@ -506,10 +846,11 @@ type UpdateStockRequest struct {
 
 // APIResponse represents a standard API response
 type APIResponse struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   string      `json:"error,omitempty"`
+	Success bool                  `json:"success"`
+	Message string                `json:"message"`
+	Data    interface{}           `json:"data,omitempty"`
+	Error   string                `json:"error,omitempty"`
+	Fields  []entities.FieldError `json:"fields,omitempty"` // field-level validation errors
 }
 
 // NewSuccessResponse creates a success response
@@ -529,3 +870,91 @@ func NewErrorResponse(message, error string) *APIResponse {
 		Error:   error,
 	}
 }
+
+// NewValidationErrorResponse creates an error response listing the invalid fields
+func NewValidationErrorResponse(message string, err *entities.AttributeValidationError) *APIResponse {
+	return &APIResponse{
+		Success: false,
+		Message: message,
+		Error:   err.Error(),
+		Fields:  err.Fields,
+	}
+}
+
+// serviceErrorStatus maps a service error to 500 for server failures, which services
+// report as "failed to ...", and to 400 for invalid input
+func serviceErrorStatus(err error) int {
+	if errors.Is(err, repositories.ErrVersionConflict) {
+		return http.StatusConflict
+	}
+	if strings.HasPrefix(err.Error(), "failed to") {
+		return http.StatusInternalServerError
+	}
+	return http.StatusBadRequest
+}
+
+// redirectToCanonicalSlug answers a lookup by a slug the entity no longer has with a
+// 301 to the same route under its current slug, carrying the entity so clients that do
+// not follow redirects still get it. Returns false when the slug is already canonical.
+func redirectToCanonicalSlug(c *gin.Context, slug, canonical string, data interface{}) bool {
+	if slug == canonical {
+		return false
+	}
+	
+	location := strings.Replace(c.FullPath(), ":slug", url.PathEscape(canonical), 1)
+	if c.Request.URL.RawQuery != "" {
+		location += "?" + c.Request.URL.RawQuery
+	}
+	c.Header("Location", location)
+	c.JSON(http.StatusMovedPermanently, NewSuccessResponse("Moved permanently to "+canonical, data))
+	return true
+}
+
+// setProductETag sets the ETag header to the product's version
+func setProductETag(c *gin.Context, product *entities.Product) {
+	c.Header("ETag", strconv.Quote(strconv.Itoa(product.Version)))
+}
+
+// requireIfMatch reads the product version a write is conditional on from If-Match.
+// A nil version (If-Match: *) matches any version. Writes a 428 or 400 response and
+// returns false when the header is missing or is not a product ETag.
+func requireIfMatch(c *gin.Context) (*int, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		c.JSON(http.StatusPreconditionRequired, NewErrorResponse("If-Match header is required", "send the ETag the product was read with"))
+		return nil, false
+	}
+	if header == "*" {
+		return nil, true
+	}
+	
+	tag := strings.TrimPrefix(header, "W/")
+	if unquoted, err := strconv.Unquote(tag); err == nil {
+		tag = unquoted
+	}
+	version, err := strconv.Atoi(tag)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid If-Match header", header))
+		return nil, false
+	}
+	
+	return &version, true
+}
+
+// applyPricing fills in the resolved price of products for the currency, channel and
+// customer group in the query, and their sale prices. Writes an error response and
+// returns false when prices cannot be resolved.
+func (h *ProductHandler) applyPricing(c *gin.Context, products ...*entities.Product) bool {
+	pc := services.PriceContext{
+		Currency:      c.Query("currency"),
+		Channel:       c.Query("channel"),
+		CustomerGroup: c.Query("group"),
+	}
+	
+	if err := h.pricingService.ApplyPrices(c.Request.Context(), products, pc); err != nil {
+		c.JSON(serviceErrorStatus(err), NewErrorResponse("Failed to resolve prices", err.Error()))
+		return false
+	}
+	
+	return true
+}
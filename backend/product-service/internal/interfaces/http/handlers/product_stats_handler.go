@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"product-service/internal/application/services"
+)
+
+// ProductStatsHandler handles HTTP requests that feed product stats
+type ProductStatsHandler struct {
+	statsService *services.ProductStatsService
+}
+
+// NewProductStatsHandler creates a new product stats handler
+func NewProductStatsHandler(statsService *services.ProductStatsService) *ProductStatsHandler {
+	return &ProductStatsHandler{
+		statsService: statsService,
+	}
+}
+
+// RecordProductView counts a view of a product
+// @Summary Record a product view
+// @Description Count a shopper viewing a product page. Views are written in the background, feed the most viewed and trending rankings, and are discarded for products that do not exist.
+// @Tags products
+// @Produce json
+// @Param id path string true "Product ID"
+// @Success 202 {object} APIResponse
+// @Failure 400 {object} APIResponse
+// @Router /products/{id}/views [post]
+func (h *ProductStatsHandler) RecordProductView(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid product ID", err.Error()))
+		return
+	}
+
+	if err := h.statsService.RecordView(productID); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Failed to record view", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, NewSuccessResponse("View recorded", nil))
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"product-service/internal/application/services"
+	"product-service/internal/domain/entities"
+)
+
+// RecommendationHandler handles HTTP requests for product relations and recommendations
+type RecommendationHandler struct {
+	recommendationService *services.RecommendationService
+}
+
+// NewRecommendationHandler creates a new recommendation handler
+func NewRecommendationHandler(recommendationService *services.RecommendationService) *RecommendationHandler {
+	return &RecommendationHandler{
+		recommendationService: recommendationService,
+	}
+}
+
+// ListRelations lists the hand-picked relations of a product
+// @Summary List product relations
+// @Description List the related, up-sell, cross-sell and accessory products picked for a product, by type and in display order
+// @Tags products
+// @Produce json
+// @Param id path string true "Product ID"
+// @Success 200 {object} APIResponse{data=[]entities.ProductRelation}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /products/{id}/relations [get]
+func (h *RecommendationHandler) ListRelations(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid product ID", err.Error()))
+		return
+	}
+
+	relations, err := h.recommendationService.ListRelations(c.Request.Context(), productID)
+	if err != nil {
+		c.JSON(recommendationErrorStatus(err), NewErrorResponse("Failed to get relations", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Relations retrieved successfully", relations))
+}
+
+// SetRelations replaces the relations of a product of one type
+// @Summary Set product relations
+// @Description Replace a product's related, up_sell, cross_sell or accessory products with the given ones, in display order. An empty list removes them.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param type path string true "Relation type" Enums(related, up_sell, cross_sell, accessory)
+// @Param relations body services.SetRelationsRequest true "Related product IDs"
+// @Success 200 {object} APIResponse{data=[]entities.ProductRelation}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /products/{id}/relations/{type} [put]
+func (h *RecommendationHandler) SetRelations(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid product ID", err.Error()))
+		return
+	}
+
+	var req services.SetRelationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	relationType := entities.RelationType(c.Param("type"))
+	relations, err := h.recommendationService.SetRelations(c.Request.Context(), productID, relationType, &req)
+	if err != nil {
+		c.JSON(recommendationErrorStatus(err), NewErrorResponse("Failed to set relations", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Relations updated successfully", relations))
+}
+
+// GetRecommendations recommends products for a product
+// @Summary Get product recommendations
+// @Description Recommend products for a product: its hand-picked related, up_sell, cross_sell or accessory products, or the products most often bought with it (frequently_bought_together). Only products that can be bought now are included.
+// @Tags products
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param type query string false "Recommendation type" Enums(related, up_sell, cross_sell, accessory, frequently_bought_together) default(related)
+// @Param limit query int false "Maximum number of products" default(10)
+// @Success 200 {object} APIResponse{data=services.Recommendations}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /products/{id}/recommendations [get]
+func (h *RecommendationHandler) GetRecommendations(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid product ID", err.Error()))
+		return
+	}
+
+	limit := 10
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 50 {
+			limit = l
+		}
+	}
+
+	relationType := entities.RelationType(c.Query("type"))
+	recommendations, err := h.recommendationService.GetRecommendations(c.Request.Context(), productID, relationType, limit)
+	if err != nil {
+		c.JSON(recommendationErrorStatus(err), NewErrorResponse("Failed to get recommendations", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Recommendations retrieved successfully", recommendations))
+}
+
+// recommendationErrorStatus maps recommendation service errors to HTTP status codes
+func recommendationErrorStatus(err error) int {
+	if err.Error() == "product not found" {
+		return http.StatusNotFound
+	}
+	if strings.HasPrefix(err.Error(), "related product ") && strings.HasSuffix(err.Error(), " not found") {
+		return http.StatusNotFound
+	}
+	return serviceErrorStatus(err)
+}
@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"product-service/internal/application/services"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// ReviewHandler handles HTTP requests for product reviews
+type ReviewHandler struct {
+	reviewService *services.ReviewService
+}
+
+// NewReviewHandler creates a new review handler
+func NewReviewHandler(reviewService *services.ReviewService) *ReviewHandler {
+	return &ReviewHandler{
+		reviewService: reviewService,
+	}
+}
+
+// SubmitReview submits a review of a product
+// @Summary Submit a product review
+// @Description Review a product as the signed-in user (X-User-ID). Reviews wait for moderation; ones that look like spam are flagged as spam. Reviews by users who bought the product are marked as verified purchases.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param review body services.SubmitReviewRequest true "Review"
+// @Success 201 {object} APIResponse{data=entities.ProductReview}
+// @Failure 400 {object} APIResponse
+// @Failure 401 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 409 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /products/{id}/reviews [post]
+func (h *ReviewHandler) SubmitReview(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid product ID", err.Error()))
+		return
+	}
+
+	var req services.SubmitReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	source := services.ReviewSource{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+	review, err := h.reviewService.SubmitReview(c.Request.Context(), productID, &req, source)
+	if err != nil {
+		c.JSON(reviewErrorStatus(err), NewErrorResponse("Failed to submit review", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, NewSuccessResponse("Review submitted successfully", review))
+}
+
+// ListProductReviews lists the approved reviews of a product
+// @Summary List product reviews
+// @Description List a product's approved reviews with its average rating, review count and the number of reviews for each star rating
+// @Tags reviews
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param sort query string false "Sort order" Enums(newest, oldest, highest, lowest, most_helpful) default(newest)
+// @Param rating query int false "Only reviews with this rating"
+// @Param verified query bool false "Only verified purchase reviews"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} APIResponse{data=services.ProductReviewsResult}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /products/{id}/reviews [get]
+func (h *ReviewHandler) ListProductReviews(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid product ID", err.Error()))
+		return
+	}
+
+	filters, ok := parseReviewFilters(c)
+	if !ok {
+		return
+	}
+
+	page, pageSize := parsePagination(c)
+	result, err := h.reviewService.ListProductReviews(c.Request.Context(), productID, filters, page, pageSize)
+	if err != nil {
+		c.JSON(reviewErrorStatus(err), NewErrorResponse("Failed to list reviews", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Reviews retrieved successfully", result))
+}
+
+// ListReviews lists reviews for moderation
+// @Summary List reviews for moderation
+// @Description List reviews of every product by status, pending ones by default
+// @Tags reviews
+// @Produce json
+// @Param status query string false "Review status" Enums(pending, approved, rejected, spam) default(pending)
+// @Param product_id query string false "Only reviews of this product"
+// @Param sort query string false "Sort order" Enums(newest, oldest, highest, lowest, most_helpful) default(newest)
+// @Param rating query int false "Only reviews with this rating"
+// @Param verified query bool false "Only verified purchase reviews"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} APIResponse{data=services.ReviewListResult}
+// @Failure 400 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /reviews [get]
+func (h *ReviewHandler) ListReviews(c *gin.Context) {
+	filters, ok := parseReviewFilters(c)
+	if !ok {
+		return
+	}
+
+	if status := c.Query("status"); status != "" {
+		reviewStatus := entities.ReviewStatus(status)
+		filters.Status = &reviewStatus
+	}
+
+	if productIDStr := c.Query("product_id"); productIDStr != "" {
+		productID, err := uuid.Parse(productIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid product ID", err.Error()))
+			return
+		}
+		filters.ProductID = &productID
+	}
+
+	page, pageSize := parsePagination(c)
+	result, err := h.reviewService.ListReviews(c.Request.Context(), filters, page, pageSize)
+	if err != nil {
+		c.JSON(reviewErrorStatus(err), NewErrorResponse("Failed to list reviews", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Reviews retrieved successfully", result))
+}
+
+// ModerateReview sets the moderation status of a review
+// @Summary Moderate a review
+// @Description Approve, reject or mark a review as spam. The product's average rating and review count are recomputed from its approved reviews.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param reviewId path string true "Review ID"
+// @Param moderation body services.ModerateReviewRequest true "Moderation decision"
+// @Success 200 {object} APIResponse{data=entities.ProductReview}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /reviews/{reviewId}/moderation [put]
+func (h *ReviewHandler) ModerateReview(c *gin.Context) {
+	reviewID, err := uuid.Parse(c.Param("reviewId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid review ID", err.Error()))
+		return
+	}
+
+	var req services.ModerateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	review, err := h.reviewService.ModerateReview(c.Request.Context(), reviewID, &req)
+	if err != nil {
+		c.JSON(reviewErrorStatus(err), NewErrorResponse("Failed to moderate review", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Review moderated successfully", review))
+}
+
+// VoteReview records whether a review was helpful
+// @Summary Vote on a review
+// @Description Record whether the signed-in user (X-User-ID) found an approved review helpful. Voting again replaces the earlier vote.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param reviewId path string true "Review ID"
+// @Param vote body services.ReviewVoteRequest true "Vote"
+// @Success 200 {object} APIResponse{data=entities.ProductReview}
+// @Failure 400 {object} APIResponse
+// @Failure 401 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /reviews/{reviewId}/votes [post]
+func (h *ReviewHandler) VoteReview(c *gin.Context) {
+	reviewID, err := uuid.Parse(c.Param("reviewId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid review ID", err.Error()))
+		return
+	}
+
+	var req services.ReviewVoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	review, err := h.reviewService.VoteReview(c.Request.Context(), reviewID, &req)
+	if err != nil {
+		c.JSON(reviewErrorStatus(err), NewErrorResponse("Failed to vote on review", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Vote recorded successfully", review))
+}
+
+// parseReviewFilters reads the sort, rating and verified review filters. Writes an error
+// response and returns false when they are invalid.
+func parseReviewFilters(c *gin.Context) (*repositories.ReviewFilters, bool) {
+	filters := &repositories.ReviewFilters{Sort: c.Query("sort")}
+
+	if ratingStr := c.Query("rating"); ratingStr != "" {
+		rating, err := strconv.Atoi(ratingStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid rating", err.Error()))
+			return nil, false
+		}
+		filters.Rating = &rating
+	}
+
+	if verifiedStr := c.Query("verified"); verifiedStr != "" {
+		verified, err := strconv.ParseBool(verifiedStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid verified filter", err.Error()))
+			return nil, false
+		}
+		filters.VerifiedOnly = verified
+	}
+
+	return filters, true
+}
+
+// reviewErrorStatus maps review service errors to HTTP status codes
+func reviewErrorStatus(err error) int {
+	switch err.Error() {
+	case "product not found", "review not found":
+		return http.StatusNotFound
+	case "a signed-in user is required":
+		return http.StatusUnauthorized
+	case "you have already reviewed this product":
+		return http.StatusConflict
+	}
+	return serviceErrorStatus(err)
+}
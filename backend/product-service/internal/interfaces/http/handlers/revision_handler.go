@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"product-service/internal/application/services"
+)
+
+// RevisionHandler handles HTTP requests for product revision history
+type RevisionHandler struct {
+	revisionService *services.RevisionService
+	productService  *services.ProductService
+}
+
+// NewRevisionHandler creates a new revision handler
+func NewRevisionHandler(revisionService *services.RevisionService, productService *services.ProductService) *RevisionHandler {
+	return &RevisionHandler{
+		revisionService: revisionService,
+		productService:  productService,
+	}
+}
+
+// ListRevisions lists a product's revisions
+// @Summary List product revisions
+// @Description List the revisions of a product and its variants and images, newest first, with who made each change, when, and the fields it changed
+// @Tags revisions
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} APIResponse{data=services.RevisionListResult}
+// @Failure 400 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /products/{id}/revisions [get]
+func (h *RevisionHandler) ListRevisions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid product ID", err.Error()))
+		return
+	}
+
+	page, pageSize := parsePagination(c)
+	result, err := h.revisionService.ListRevisions(c.Request.Context(), id, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to list revisions", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Revisions retrieved successfully", result))
+}
+
+// GetRevision retrieves a product revision
+// @Summary Get a product revision
+// @Description Get a revision by number, including the full state it left the record in
+// @Tags revisions
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param number path int true "Revision number"
+// @Success 200 {object} APIResponse{data=entities.ProductRevision}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /products/{id}/revisions/{number} [get]
+func (h *RevisionHandler) GetRevision(c *gin.Context) {
+	id, number, ok := parseRevisionParams(c)
+	if !ok {
+		return
+	}
+
+	revision, err := h.revisionService.GetRevision(c.Request.Context(), id, number)
+	if err != nil {
+		if err.Error() == "revision not found" {
+			c.JSON(http.StatusNotFound, NewErrorResponse("Revision not found", ""))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to get revision", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Revision retrieved successfully", revision))
+}
+
+// DiffRevisions compares two product revisions
+// @Summary Diff two product revisions
+// @Description List the fields that differ between the states two revisions of the same product, variant or image left it in
+// @Tags revisions
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param from query int true "Older revision number"
+// @Param to query int true "Newer revision number"
+// @Success 200 {object} APIResponse{data=services.RevisionDiff}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /products/{id}/revisions/diff [get]
+func (h *RevisionHandler) DiffRevisions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid product ID", err.Error()))
+		return
+	}
+
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid from revision", err.Error()))
+		return
+	}
+	to, err := strconv.Atoi(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid to revision", err.Error()))
+		return
+	}
+
+	diff, err := h.revisionService.DiffRevisions(c.Request.Context(), id, from, to)
+	if err != nil {
+		if err.Error() == "revision not found" {
+			c.JSON(http.StatusNotFound, NewErrorResponse("Revision not found", ""))
+			return
+		}
+		c.JSON(serviceErrorStatus(err), NewErrorResponse("Failed to diff revisions", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Revisions compared successfully", diff))
+}
+
+// RollbackRevision rolls a product back to a revision
+// @Summary Roll back to a revision
+// @Description Restore the state a revision left a product, variant or image in. The rollback is recorded as a new revision, which is returned.
+// @Tags revisions
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param number path int true "Revision number"
+// @Success 200 {object} APIResponse{data=entities.ProductRevision}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /products/{id}/revisions/{number}/rollback [post]
+func (h *RevisionHandler) RollbackRevision(c *gin.Context) {
+	id, number, ok := parseRevisionParams(c)
+	if !ok {
+		return
+	}
+
+	revision, err := h.productService.RollbackProduct(c.Request.Context(), id, number)
+	if err != nil {
+		switch err.Error() {
+		case "revision not found", "product not found", "variant not found", "image not found":
+			c.JSON(http.StatusNotFound, NewErrorResponse("Failed to roll back", err.Error()))
+			return
+		case "slug is taken by another product", "SKU is taken by another variant":
+			c.JSON(http.StatusConflict, NewErrorResponse("Failed to roll back", err.Error()))
+			return
+		}
+		c.JSON(serviceErrorStatus(err), NewErrorResponse("Failed to roll back", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Rolled back successfully", revision))
+}
+
+// parseRevisionParams parses the product ID and revision number path parameters,
+// writing a 400 response when either is invalid
+func parseRevisionParams(c *gin.Context) (uuid.UUID, int, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid product ID", err.Error()))
+		return uuid.Nil, 0, false
+	}
+
+	number, err := strconv.Atoi(c.Param("number"))
+	if err != nil || number < 1 {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid revision number", c.Param("number")))
+		return uuid.Nil, 0, false
+	}
+
+	return id, number, true
+}
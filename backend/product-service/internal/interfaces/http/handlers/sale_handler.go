@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"product-service/internal/application/services"
+	"product-service/internal/domain/entities"
+	"product-service/internal/domain/repositories"
+)
+
+// SaleHandler handles HTTP requests for sales
+type SaleHandler struct {
+	saleService *services.SaleService
+}
+
+// NewSaleHandler creates a new sale handler
+func NewSaleHandler(saleService *services.SaleService) *SaleHandler {
+	return &SaleHandler{
+		saleService: saleService,
+	}
+}
+
+// CreateSale creates a new sale
+// @Summary Create a sale
+// @Description Schedule a sale on a product, one of its variants, a category (including its subcategories) or a brand. The discount is a percentage or a fixed amount off in the base currency, between starts_at and ends_at. Sale prices are worked out when products are read.
+// @Tags sales
+// @Accept json
+// @Produce json
+// @Param sale body services.SaleRequest true "Sale"
+// @Success 201 {object} APIResponse{data=entities.Sale}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /sales [post]
+func (h *SaleHandler) CreateSale(c *gin.Context) {
+	var req services.SaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	sale, err := h.saleService.CreateSale(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(saleErrorStatus(err), NewErrorResponse("Failed to create sale", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, NewSuccessResponse("Sale created successfully", sale))
+}
+
+// ListSales lists sales
+// @Summary List sales
+// @Description List sales, soonest ending first, optionally narrowed by scope, target and whether they are scheduled, running or ended
+// @Tags sales
+// @Produce json
+// @Param scope query string false "Scope: product, category or brand"
+// @Param target_id query string false "Product, category or brand ID"
+// @Param status query string false "Status: scheduled, running or ended"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} APIResponse{data=services.SaleListResult}
+// @Failure 400 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /sales [get]
+func (h *SaleHandler) ListSales(c *gin.Context) {
+	filters := repositories.SaleFilters{
+		Scope:  entities.SaleScope(c.Query("scope")),
+		Status: c.Query("status"),
+	}
+	if raw := c.Query("target_id"); raw != "" {
+		targetID, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid target ID", err.Error()))
+			return
+		}
+		filters.TargetID = &targetID
+	}
+
+	page, pageSize := parsePagination(c)
+	result, err := h.saleService.ListSales(c.Request.Context(), filters, page, pageSize)
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), NewErrorResponse("Failed to list sales", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Sales retrieved successfully", result))
+}
+
+// GetSale retrieves a sale
+// @Summary Get a sale
+// @Description Get a sale by its ID
+// @Tags sales
+// @Produce json
+// @Param id path string true "Sale ID"
+// @Success 200 {object} APIResponse{data=entities.Sale}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /sales/{id} [get]
+func (h *SaleHandler) GetSale(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid sale ID", err.Error()))
+		return
+	}
+
+	sale, err := h.saleService.GetSale(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(saleErrorStatus(err), NewErrorResponse("Failed to get sale", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Sale retrieved successfully", sale))
+}
+
+// UpdateSale updates a sale
+// @Summary Update a sale
+// @Description Change a sale's target, discount, schedule or active state
+// @Tags sales
+// @Accept json
+// @Produce json
+// @Param id path string true "Sale ID"
+// @Param sale body services.SaleRequest true "Sale"
+// @Success 200 {object} APIResponse{data=entities.Sale}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /sales/{id} [put]
+func (h *SaleHandler) UpdateSale(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid sale ID", err.Error()))
+		return
+	}
+
+	var req services.SaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	sale, err := h.saleService.UpdateSale(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(saleErrorStatus(err), NewErrorResponse("Failed to update sale", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Sale updated successfully", sale))
+}
+
+// DeleteSale deletes a sale
+// @Summary Delete a sale
+// @Description Delete a sale, ending it immediately if it is running
+// @Tags sales
+// @Produce json
+// @Param id path string true "Sale ID"
+// @Success 200 {object} APIResponse
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /sales/{id} [delete]
+func (h *SaleHandler) DeleteSale(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid sale ID", err.Error()))
+		return
+	}
+
+	if err := h.saleService.DeleteSale(c.Request.Context(), id); err != nil {
+		c.JSON(saleErrorStatus(err), NewErrorResponse("Failed to delete sale", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Sale deleted successfully", nil))
+}
+
+// saleErrorStatus maps sale service errors to HTTP statuses
+func saleErrorStatus(err error) int {
+	switch err.Error() {
+	case "sale not found", "product not found", "variant not found", "category not found", "brand not found":
+		return http.StatusNotFound
+	}
+	return serviceErrorStatus(err)
+}
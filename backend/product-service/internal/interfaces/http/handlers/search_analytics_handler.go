@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"product-service/internal/application/services"
+	"product-service/internal/ports"
+)
+
+// SearchAnalyticsHandler handles HTTP requests for search analytics
+type SearchAnalyticsHandler struct {
+	searchAnalyticsService *services.SearchAnalyticsService
+}
+
+// NewSearchAnalyticsHandler creates a new search analytics handler
+func NewSearchAnalyticsHandler(searchAnalyticsService *services.SearchAnalyticsService) *SearchAnalyticsHandler {
+	return &SearchAnalyticsHandler{
+		searchAnalyticsService: searchAnalyticsService,
+	}
+}
+
+// GetSearchAnalytics reports on recent searches
+// @Summary Get search analytics
+// @Description Top search terms, zero-result queries, click-through rate and average results over a period. Use either period or from/to
+// @Tags search
+// @Produce json
+// @Param period query string false "Look-back window, e.g. 24h, 7d, 30d, or all" default(7d)
+// @Param from query string false "Period start (RFC3339)"
+// @Param to query string false "Period end (RFC3339), defaults to now"
+// @Param limit query int false "Number of terms per list" default(20)
+// @Success 200 {object} APIResponse{data=repositories.SearchAnalyticsReport}
+// @Failure 400 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /search/analytics [get]
+func (h *SearchAnalyticsHandler) GetSearchAnalytics(c *gin.Context) {
+	from, to, err := parseAnalyticsRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid analytics period", err.Error()))
+		return
+	}
+
+	limit := 20
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+
+	report, err := h.searchAnalyticsService.GetAnalytics(c.Request.Context(), from, to, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to get search analytics", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Search analytics retrieved successfully", report))
+}
+
+// RecordSearchClick records a click on a search result
+// @Summary Record a search result click
+// @Description Record which product a shopper opened from search results, using the search_id returned by /products/search. Position is the zero-based rank across all pages
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param click body services.RecordSearchClickRequest true "Click information"
+// @Success 202 {object} APIResponse
+// @Failure 400 {object} APIResponse
+// @Router /search/clicks [post]
+func (h *SearchAnalyticsHandler) RecordSearchClick(c *gin.Context) {
+	var req services.RecordSearchClickRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	if err := h.searchAnalyticsService.RecordClick(c.Request.Context(), &req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Failed to record click", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, NewSuccessResponse("Click recorded", nil))
+}
+
+// parseAnalyticsRange reads from/to (RFC3339) or a look-back period such as "7d"
+func parseAnalyticsRange(c *gin.Context) (time.Time, time.Time, error) {
+	to := time.Now()
+	if toStr := c.Query("to"); toStr != "" {
+		t, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to: %w", err)
+		}
+		to = t
+	}
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from: %w", err)
+		}
+		return from, to, nil
+	}
+
+	window, err := ports.ParsePeriod(c.DefaultQuery("period", "7d"))
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if window == 0 {
+		// "all" reaches back to the first recorded search
+		return time.Time{}, to, nil
+	}
+	return to.Add(-window), to, nil
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"product-service/internal/application/services"
+)
+
+// sitemapCacheControl lets crawlers and caches reuse a sitemap for an hour
+const sitemapCacheControl = "public, max-age=3600"
+
+// SitemapHandler handles HTTP requests for sitemaps
+type SitemapHandler struct {
+	sitemapService *services.SitemapService
+}
+
+// NewSitemapHandler creates a new sitemap handler
+func NewSitemapHandler(sitemapService *services.SitemapService) *SitemapHandler {
+	return &SitemapHandler{
+		sitemapService: sitemapService,
+	}
+}
+
+// GetSitemapIndex serves the sitemap index
+// @Summary Get the sitemap index
+// @Description Get the sitemap index listing the product and category sitemap pages, each with the latest update time of the pages it lists
+// @Tags sitemaps
+// @Produce xml
+// @Success 200 {object} services.SitemapIndex
+// @Failure 500 {object} APIResponse
+// @Router /sitemap.xml [get]
+func (h *SitemapHandler) GetSitemapIndex(c *gin.Context) {
+	index, err := h.sitemapService.GetSitemapIndex(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to get sitemap index", err.Error()))
+		return
+	}
+
+	writeSitemap(c, index)
+}
+
+// GetSitemapPage serves a sitemap page
+// @Summary Get a sitemap page
+// @Description Get a page of active, visible products or categories by its name in the sitemap index, such as products-1.xml
+// @Tags sitemaps
+// @Produce xml
+// @Param name path string true "Sitemap page name"
+// @Success 200 {object} services.SitemapURLSet
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /sitemaps/{name} [get]
+func (h *SitemapHandler) GetSitemapPage(c *gin.Context) {
+	urlSet, err := h.sitemapService.GetSitemapPage(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		if err.Error() == "sitemap not found" {
+			c.JSON(http.StatusNotFound, NewErrorResponse("Sitemap not found", ""))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to get sitemap", err.Error()))
+		return
+	}
+
+	writeSitemap(c, urlSet)
+}
+
+// writeSitemap writes a sitemap document as cacheable XML
+func writeSitemap(c *gin.Context, document interface{}) {
+	body, err := xml.Marshal(document)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to encode sitemap", err.Error()))
+		return
+	}
+
+	c.Header("Cache-Control", sitemapCacheControl)
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", append([]byte(xml.Header), body...))
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"product-service/internal/application/services"
+)
+
+// VariantHandler handles HTTP requests for product variants
+type VariantHandler struct {
+	variantService *services.VariantService
+}
+
+// NewVariantHandler creates a new variant handler
+func NewVariantHandler(variantService *services.VariantService) *VariantHandler {
+	return &VariantHandler{
+		variantService: variantService,
+	}
+}
+
+// GenerateVariants generates a product's variants from option axes
+// @Summary Generate variant matrix
+// @Description Create a variant for every combination of the option axes (e.g. Size × Color), minus exclusions. SKUs come from sku_pattern, which may use {sku} and {option} placeholders. Overrides set the price, a price adjustment or the weight for variants with a given option value. Regenerating keeps existing variants and their stock, and deactivates variants no longer in the matrix
+// @Tags variants
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param matrix body services.GenerateVariantsRequest true "Option axes, SKU pattern, overrides and exclusions"
+// @Success 201 {object} APIResponse{data=services.GenerateVariantsResult}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /products/{id}/variants/generate [post]
+func (h *VariantHandler) GenerateVariants(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid product ID", err.Error()))
+		return
+	}
+
+	var req services.GenerateVariantsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	result, err := h.variantService.GenerateVariants(c.Request.Context(), id, &req)
+	if err != nil {
+		if err.Error() == "product not found" {
+			c.JSON(http.StatusNotFound, NewErrorResponse("Product not found", ""))
+			return
+		}
+		c.JSON(serviceErrorStatus(err), NewErrorResponse("Failed to generate variants", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, NewSuccessResponse("Variants generated successfully", result))
+}
+
+// ResolveVariant maps a shopper's option picks to a variant
+// @Summary Resolve variant from options
+// @Description Find the active variant matching the selected options, given as query parameters named after the options (e.g. ?size=M&color=Red; names and values are case-insensitive). Returns the variant with its effective price, compare price and weight
+// @Tags variants
+// @Produce json
+// @Param id path string true "Product ID"
+// @Success 200 {object} APIResponse{data=services.ResolvedVariant}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /products/{id}/variants/resolve [get]
+func (h *VariantHandler) ResolveVariant(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid product ID", err.Error()))
+		return
+	}
+
+	selection := make(map[string]string)
+	for name, values := range c.Request.URL.Query() {
+		if len(values) > 0 && values[0] != "" {
+			selection[name] = values[0]
+		}
+	}
+
+	resolved, err := h.variantService.ResolveVariant(c.Request.Context(), id, selection)
+	if err != nil {
+		switch err.Error() {
+		case "product not found":
+			c.JSON(http.StatusNotFound, NewErrorResponse("Product not found", ""))
+		case "variant not found":
+			c.JSON(http.StatusNotFound, NewErrorResponse("Variant not found", "no active variant matches the selected options"))
+		default:
+			c.JSON(serviceErrorStatus(err), NewErrorResponse("Failed to resolve variant", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Variant resolved successfully", resolved))
+}
+
+// UpdateVariantPriceTiers replaces a variant's quantity breaks
+// @Summary Update variant price tiers
+// @Description Replace the variant's own quantity breaks. An empty list removes them; the variant then uses the product's tiers if it uses the product price, and no tiers if it has its own price.
+// @Tags variants
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param variantId path string true "Variant ID"
+// @Param tiers body services.PriceTiersRequest true "Price tiers"
+// @Success 200 {object} APIResponse{data=entities.ProductVariant}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /products/{id}/variants/{variantId}/price-tiers [put]
+func (h *VariantHandler) UpdateVariantPriceTiers(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid product ID", err.Error()))
+		return
+	}
+	variantID, err := uuid.Parse(c.Param("variantId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid variant ID", err.Error()))
+		return
+	}
+
+	var req services.PriceTiersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	variant, err := h.variantService.SetPriceTiers(c.Request.Context(), id, variantID, &req)
+	if err != nil {
+		if err.Error() == "variant not found" {
+			c.JSON(http.StatusNotFound, NewErrorResponse("Variant not found", ""))
+			return
+		}
+		c.JSON(serviceErrorStatus(err), NewErrorResponse("Failed to update price tiers", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Price tiers updated successfully", variant))
+}
+
+// GetVariantMatrix reports which option combinations can be purchased
+// @Summary Get variant availability matrix
+// @Description For each option value, whether it can be purchased and which values of the other options are purchasable with it, so storefronts can grey out unavailable combinations
+// @Tags variants
+// @Produce json
+// @Param id path string true "Product ID"
+// @Success 200 {object} APIResponse{data=services.VariantAvailabilityMatrix}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /products/{id}/variants/matrix [get]
+func (h *VariantHandler) GetVariantMatrix(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid product ID", err.Error()))
+		return
+	}
+
+	matrix, err := h.variantService.GetVariantMatrix(c.Request.Context(), id)
+	if err != nil {
+		if err.Error() == "product not found" {
+			c.JSON(http.StatusNotFound, NewErrorResponse("Product not found", ""))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, NewErrorResponse("Failed to get variant matrix", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Variant matrix retrieved successfully", matrix))
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"product-service/internal/application/services"
+)
+
+// VideoHandler handles HTTP requests for product videos
+type VideoHandler struct {
+	videoService *services.VideoService
+}
+
+// NewVideoHandler creates a new video handler
+func NewVideoHandler(videoService *services.VideoService) *VideoHandler {
+	return &VideoHandler{
+		videoService: videoService,
+	}
+}
+
+// ListProductVideos lists the videos of a product
+// @Summary List product videos
+// @Description List the videos of a product in display order
+// @Tags products
+// @Produce json
+// @Param id path string true "Product ID"
+// @Success 200 {object} APIResponse{data=[]entities.ProductVideo}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /products/{id}/videos [get]
+func (h *VideoHandler) ListProductVideos(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid product ID", err.Error()))
+		return
+	}
+
+	videos, err := h.videoService.ListVideos(c.Request.Context(), productID)
+	if err != nil {
+		c.JSON(videoErrorStatus(err), NewErrorResponse("Failed to get videos", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Videos retrieved successfully", videos))
+}
+
+// AddProductVideo links a video to a product
+// @Summary Add a product video
+// @Description Link a video to a product by URL. YouTube (watch, youtu.be, shorts) and Vimeo URLs are detected and normalised, and get privacy-friendly embed URLs; YouTube videos also get a thumbnail URL. URLs of MP4, WebM, Ogg or QuickTime files are direct videos; any other URL is kept as it is.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param video body services.AddVideoRequest true "Video"
+// @Success 201 {object} APIResponse{data=entities.ProductVideo}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /products/{id}/videos [post]
+func (h *VideoHandler) AddProductVideo(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid product ID", err.Error()))
+		return
+	}
+
+	var req services.AddVideoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	video, err := h.videoService.AddVideo(c.Request.Context(), productID, &req)
+	if err != nil {
+		c.JSON(videoErrorStatus(err), NewErrorResponse("Failed to add video", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, NewSuccessResponse("Video added successfully", video))
+}
+
+// UploadProductVideo uploads a video file of a product
+// @Summary Upload a product video
+// @Description Upload an MP4, WebM or Ogg video file of a product. The real file type is checked.
+// @Tags products
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param video formData file true "Video file"
+// @Param title formData string false "Video title"
+// @Param description formData string false "Video description"
+// @Success 201 {object} APIResponse{data=entities.ProductVideo}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /products/{id}/videos/upload [post]
+func (h *VideoHandler) UploadProductVideo(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid product ID", err.Error()))
+		return
+	}
+
+	header, err := c.FormFile("video")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("No video provided", err.Error()))
+		return
+	}
+	if header.Size > services.MaxVideoUploadSize {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Failed to upload video", fmt.Sprintf("file is larger than %d MB", services.MaxVideoUploadSize>>20)))
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Failed to read video", err.Error()))
+		return
+	}
+	defer file.Close()
+
+	video, err := h.videoService.UploadVideo(c.Request.Context(), productID, services.VideoUpload{
+		FileName:    header.Filename,
+		Reader:      file,
+		Title:       c.PostForm("title"),
+		Description: c.PostForm("description"),
+	})
+	if err != nil {
+		c.JSON(videoErrorStatus(err), NewErrorResponse("Failed to upload video", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, NewSuccessResponse("Video uploaded successfully", video))
+}
+
+// ReorderProductVideos sets the display order of a product's videos
+// @Summary Reorder product videos
+// @Description Set the display order of a product's videos. The list must hold every video of the product exactly once.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param order body services.ReorderVideosRequest true "Video IDs in display order"
+// @Success 200 {object} APIResponse{data=[]entities.ProductVideo}
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /products/{id}/videos/order [put]
+func (h *VideoHandler) ReorderProductVideos(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid product ID", err.Error()))
+		return
+	}
+
+	var req services.ReorderVideosRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid request body", err.Error()))
+		return
+	}
+
+	videos, err := h.videoService.ReorderVideos(c.Request.Context(), productID, req.VideoIDs)
+	if err != nil {
+		c.JSON(videoErrorStatus(err), NewErrorResponse("Failed to reorder videos", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Videos reordered successfully", videos))
+}
+
+// DeleteProductVideo removes a video from a product
+// @Summary Delete a product video
+// @Description Remove a video from a product; the file of an uploaded video is deleted too
+// @Tags products
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param videoId path string true "Video ID"
+// @Success 200 {object} APIResponse
+// @Failure 400 {object} APIResponse
+// @Failure 404 {object} APIResponse
+// @Failure 500 {object} APIResponse
+// @Router /products/{id}/videos/{videoId} [delete]
+func (h *VideoHandler) DeleteProductVideo(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid product ID", err.Error()))
+		return
+	}
+
+	videoID, err := uuid.Parse(c.Param("videoId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse("Invalid video ID", err.Error()))
+		return
+	}
+
+	if err := h.videoService.DeleteVideo(c.Request.Context(), productID, videoID); err != nil {
+		c.JSON(videoErrorStatus(err), NewErrorResponse("Failed to delete video", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewSuccessResponse("Video deleted successfully", nil))
+}
+
+// videoErrorStatus maps video service errors to HTTP status codes
+func videoErrorStatus(err error) int {
+	switch err.Error() {
+	case "product not found", "video not found":
+		return http.StatusNotFound
+	}
+	return serviceErrorStatus(err)
+}
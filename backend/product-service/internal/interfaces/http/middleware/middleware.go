@@ -1,10 +1,19 @@
 package middleware
 
 import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"product-service/internal/application/services"
 )
 
 // RequestID adds a unique request ID to each request
@@ -21,6 +30,74 @@ func RequestID() gin.HandlerFunc {
 	}
 }
 
+// Actor records the user a request acts for, as forwarded by the API gateway in the
+// X-User-ID header, so changes can be attributed to them
+func Actor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if userID := c.GetHeader("X-User-ID"); userID != "" {
+			c.Request = c.Request.WithContext(services.WithActor(c.Request.Context(), userID))
+		}
+		c.Next()
+	}
+}
+
+// eventSignaturePrefix prefixes the hex HMAC in the X-Event-Signature header
+const eventSignaturePrefix = "sha256="
+
+// EventSignature only lets through requests whose body is signed with secret: the
+// X-Event-Signature header must carry "sha256=" and the hex HMAC-SHA256 of the body.
+// Events are trusted as coming from other services, so without a secret none are
+// accepted.
+func EventSignature(secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if secret == "" {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"success": false,
+				"message": "Event delivery is not configured",
+				"error":   "EVENT_SIGNING_SECRET is not set",
+			})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Invalid request body",
+				"error":   err.Error(),
+			})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		if !validEventSignature(secret, c.GetHeader("X-Event-Signature"), body) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"message": "Invalid event signature",
+				"error":   "X-Event-Signature does not match the request body",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// validEventSignature checks a "sha256=<hex>" signature header against body
+func validEventSignature(secret, header string, body []byte) bool {
+	if !strings.HasPrefix(header, eventSignaturePrefix) {
+		return false
+	}
+	signature, err := hex.DecodeString(strings.TrimPrefix(header, eventSignaturePrefix))
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(signature, mac.Sum(nil))
+}
+
 // Logger provides structured logging for requests
 func Logger() gin.HandlerFunc {
 	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
@@ -0,0 +1,34 @@
+package ports
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParsePeriod parses analytics periods such as "24h", "7d", "2w" or "all": day and week
+// units as well as anything time.ParseDuration accepts. "all" and "" mean no limit and
+// return 0.
+func ParsePeriod(period string) (time.Duration, error) {
+	period = strings.TrimSpace(strings.ToLower(period))
+	if period == "" || period == "all" {
+		return 0, nil
+	}
+
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if strings.HasSuffix(period, suffix) {
+			n, err := strconv.Atoi(strings.TrimSuffix(period, suffix))
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid period %q", period)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid period %q", period)
+	}
+	return d, nil
+}
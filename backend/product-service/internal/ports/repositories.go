@@ -2,7 +2,7 @@ package ports
 
 import (
 	"context"
-	"ecommerce-product-service/internal/domain"
+	"product-service/internal/domain"
 )
 
 // Repository interfaces (Ports) - Hexagonal Architecture
@@ -106,7 +106,7 @@ type ProductFilter struct {
 	CategoryID *domain.CategoryID
 	Brand      *string
 	MinPrice   *domain.Money
-	MaxPrice   *domain.Money
+	MaxPrice   *domain.Money // exclusive, like the price facet's buckets
 	InStock    *bool
 	Featured   *bool
 	Tags       []string
@@ -195,6 +195,8 @@ func createIndexes() error {
 		// Product indexes
 		"CREATE INDEX IF NOT EXISTS idx_products_name_gin ON products USING gin(to_tsvector('english', name))",
 		"CREATE INDEX IF NOT EXISTS idx_products_description_gin ON products USING gin(to_tsvector('english', description))",
+		"CREATE INDEX IF NOT EXISTS idx_products_short_desc_gin ON products USING gin(to_tsvector('english', short_desc))",
+		"CREATE INDEX IF NOT EXISTS idx_products_tags_gin ON products USING gin(tags)",
 		"CREATE INDEX IF NOT EXISTS idx_products_brand_status ON products(brand, status)",
 		"CREATE INDEX IF NOT EXISTS idx_products_category_status ON products(category_id, status)",
 		"CREATE INDEX IF NOT EXISTS idx_products_price_range ON products(price) WHERE status = 'active'",